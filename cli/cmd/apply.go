@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/escalation"
+	"github.com/sestinj/agentduty/cli/internal/manifest"
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make your account match an agentduty.yaml file",
+	Long: `Reconcile escalation policies, priority routes, quiet hours, timezone and
+notification preferences with a YAML file.
+
+The plan is printed first and applied after confirmation (or with --yes,
+which is required when the manifest is read from stdin).
+Sections left out of the file are not touched; a section that is present is
+authoritative, so policies or routes missing from it are removed.
+
+Start from your current setup with: agentduty export > agentduty.yaml`,
+	RunE: runApply,
+}
+
+func init() {
+	applyCmd.Flags().StringP("file", "f", "agentduty.yaml", "Manifest to apply (- for stdin)")
+	applyCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+	applyCmd.Flags().Bool("plan", false, "Only print the plan")
+
+	rootCmd.AddCommand(applyCmd)
+}
+
+type planEntry struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	yes, _ := cmd.Flags().GetBool("yes")
+	planOnly, _ := cmd.Flags().GetBool("plan")
+
+	// The manifest uses up stdin, leaving nothing to read a confirmation from.
+	if file == "-" && !yes && !planOnly && !jsonFlag {
+		return fmt.Errorf("pass --yes (or --plan) when reading the manifest from stdin")
+	}
+
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return fmt.Errorf("read manifest: %w", err)
+	}
+
+	desired, err := manifest.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	current, err := fetchManifestState()
+	if err != nil {
+		return err
+	}
+
+	plan := manifest.Diff(current, desired)
+
+	if jsonFlag {
+		entries := make([]planEntry, len(plan))
+		for i, c := range plan {
+			entries[i] = planEntry{Action: string(c.Action), Resource: c.Resource, Name: c.Name, Detail: c.Detail}
+		}
+		applied := false
+		if yes && !planOnly && len(plan) > 0 {
+			if err := applyPlan(plan, io.Discard); err != nil {
+				return err
+			}
+			applied = true
+		}
+		output.PrintJSON(map[string]any{"changes": entries, "applied": applied})
+		return nil
+	}
+
+	if len(plan) == 0 {
+		fmt.Println("No changes. Your account matches the manifest.")
+		return nil
+	}
+
+	fmt.Println("Plan:")
+	for _, c := range plan {
+		fmt.Printf("  %s\n", c)
+	}
+	fmt.Println()
+
+	if planOnly {
+		fmt.Printf("%d change(s). Run without --plan to apply.\n", len(plan))
+		return nil
+	}
+
+	if !yes {
+		fmt.Printf("Apply %d change(s)? [y/N]: ", len(plan))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := applyPlan(plan, os.Stdout); err != nil {
+		return err
+	}
+	fmt.Printf("Applied %d change(s).\n", len(plan))
+	return nil
+}
+
+// applyPlan runs each change in order, stopping at the first failure so the
+// rest of the plan isn't applied against an unexpected state.
+func applyPlan(plan []manifest.Change, log io.Writer) error {
+	for _, c := range plan {
+		if err := applyChange(c); err != nil {
+			return fmt.Errorf("%s: %w", c, err)
+		}
+		fmt.Fprintf(log, "  done %s %s %s\n", c.Action, c.Resource, c.Name)
+	}
+	return nil
+}
+
+func applyChange(c manifest.Change) error {
+	switch {
+	case c.Resource == "settings":
+		return applySettings(c.Settings)
+
+	case c.Resource == "policy" && c.Action == manifest.Create:
+		_, err := gqlClient.Do(`mutation CreateEscalationPolicy($name: String!, $steps: [EscalationStepInput!]!, $isDefault: Boolean) {
+			createEscalationPolicy(name: $name, steps: $steps, isDefault: $isDefault) { id }
+		}`, map[string]any{
+			"name":      c.Policy.Name,
+			"steps":     stepVariables(c.Policy.Steps),
+			"isDefault": c.Policy.Default,
+		})
+		return err
+
+	case c.Resource == "policy" && c.Action == manifest.Update:
+		_, err := gqlClient.Do(`mutation UpdateEscalationPolicy($id: String!, $steps: [EscalationStepInput!], $isDefault: Boolean) {
+			updateEscalationPolicy(id: $id, steps: $steps, isDefault: $isDefault) { id }
+		}`, map[string]any{
+			"id":        c.Policy.Name,
+			"steps":     stepVariables(c.Policy.Steps),
+			"isDefault": c.Policy.Default,
+		})
+		return err
+
+	case c.Resource == "policy" && c.Action == manifest.Delete:
+		_, err := gqlClient.Do(`mutation DeleteEscalationPolicy($id: String!) {
+			deleteEscalationPolicy(id: $id)
+		}`, map[string]any{"id": c.Name})
+		return err
+
+	case c.Resource == "route":
+		vars := map[string]any{"priority": c.Priority}
+		if c.Action != manifest.Delete {
+			vars["policy"] = c.Policy.Name
+		}
+		_, err := gqlClient.Do(`mutation SetPriorityRoute($priority: Int!, $policy: String) {
+			setPriorityRoute(priority: $priority, policy: $policy) { priority }
+		}`, vars)
+		return err
+	}
+	return fmt.Errorf("unsupported change")
+}
+
+func applySettings(s *manifest.Settings) error {
+	vars := map[string]any{}
+	if s.Timezone != "" {
+		vars["timezone"] = s.Timezone
+	}
+	if q := s.QuietHours; q != nil {
+		if q.Start == "" {
+			vars["clearQuietHours"] = true
+		} else {
+			vars["quietHoursStart"] = q.Start
+			vars["quietHoursEnd"] = q.End
+		}
+	}
+	if p := s.Preferences; p != nil {
+		if p.SMSMinPriority != 0 {
+			vars["smsMinPriority"] = p.SMSMinPriority
+		}
+		if p.BreakthroughPriority != 0 {
+			vars["breakthroughPriority"] = p.BreakthroughPriority
+		}
+	}
+
+	_, err := gqlClient.Do(`mutation UpdateSettings(
+		$timezone: String,
+		$quietHoursStart: String,
+		$quietHoursEnd: String,
+		$clearQuietHours: Boolean,
+		$smsMinPriority: Int,
+		$breakthroughPriority: Int
+	) {
+		updateSettings(
+			timezone: $timezone,
+			quietHoursStart: $quietHoursStart,
+			quietHoursEnd: $quietHoursEnd,
+			clearQuietHours: $clearQuietHours,
+			smsMinPriority: $smsMinPriority,
+			breakthroughPriority: $breakthroughPriority
+		) { id }
+	}`, vars)
+	return err
+}
+
+// fetchManifestState reads the account's current configuration in manifest
+// form, for diffing and export.
+func fetchManifestState() (*manifest.Manifest, error) {
+	const query = `query {
+		me {
+			timezone
+			quietHoursStart
+			quietHoursEnd
+			preferences {
+				smsMinPriority
+				breakthroughPriority
+			}
+		}
+	}`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get settings: %w", err)
+	}
+
+	var result struct {
+		Me *struct {
+			Timezone        *string `json:"timezone"`
+			QuietHoursStart *string `json:"quietHoursStart"`
+			QuietHoursEnd   *string `json:"quietHoursEnd"`
+			Preferences     struct {
+				SMSMinPriority       int `json:"smsMinPriority"`
+				BreakthroughPriority int `json:"breakthroughPriority"`
+			} `json:"preferences"`
+		} `json:"me"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	if result.Me == nil {
+		return nil, fmt.Errorf("not logged in (run: agentduty login)")
+	}
+
+	settings := &manifest.Settings{
+		Preferences: &manifest.Preferences{
+			SMSMinPriority:       result.Me.Preferences.SMSMinPriority,
+			BreakthroughPriority: result.Me.Preferences.BreakthroughPriority,
+		},
+	}
+	if result.Me.Timezone != nil {
+		settings.Timezone = *result.Me.Timezone
+	}
+	if result.Me.QuietHoursStart != nil && result.Me.QuietHoursEnd != nil {
+		settings.QuietHours = &manifest.QuietHours{
+			Start: clockMinutes(*result.Me.QuietHoursStart),
+			End:   clockMinutes(*result.Me.QuietHoursEnd),
+		}
+	}
+
+	policies, err := fetchEscalationPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []manifest.Policy
+	routes := map[int]string{}
+	for _, p := range policies {
+		steps := make([]escalation.Step, len(p.Steps))
+		for i, s := range p.Steps {
+			steps[i] = escalation.Step{Channel: s.Channel, Delay: time.Duration(s.DelaySeconds) * time.Second}
+		}
+		defs = append(defs, manifest.Policy{Name: p.Name, Default: p.IsDefault, Steps: steps})
+		for _, priority := range p.Priorities {
+			routes[priority] = p.Name
+		}
+	}
+
+	return manifest.FromState(settings, defs, routes), nil
+}
+
+// clockMinutes trims a database time ("22:00:00") to HH:MM.
+func clockMinutes(t string) string {
+	if len(t) > 5 {
+		return t[:5]
+	}
+	return t
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var channelsCmd = &cobra.Command{
+	Use:   "channels",
+	Short: "List connected delivery channels",
+	RunE:  runChannels,
+}
+
+func init() {
+	rootCmd.AddCommand(channelsCmd)
+}
+
+func runChannels(cmd *cobra.Command, args []string) error {
+	query := `query { channels {` + channelFields + `} }`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return fmt.Errorf("list channels: %w", err)
+	}
+
+	var result struct {
+		Channels []output.Channel `json:"channels"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.Channels)
+	} else {
+		output.PrintChannels(result.Channels)
+	}
+	return nil
+}
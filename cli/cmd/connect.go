@@ -1,22 +1,46 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/sestinj/agentduty/cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
+const channelFields = `
+	channel
+	address
+	verified
+	verifiedAt`
+
 var connectCmd = &cobra.Command{
 	Use:   "connect <service>",
 	Short: "Connect an external service to your account",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runConnect,
+	Long: `Connect a delivery channel:
+
+  agentduty connect slack|discord|teams
+  agentduty connect sms --phone +14155550123
+  agentduty connect email [--address you@example.com]
+  agentduty connect webhook --url https://example.com/hook --secret <secret>
+
+Chat apps are linked by sending the bot a one-time code.
+Phones and non-account email addresses are sent a verification code. Enter it
+when prompted, or later with --code. Webhooks are verified by a signed ping.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConnect,
 }
 
 func init() {
+	connectCmd.Flags().String("phone", "", "Phone number for sms, in international format")
+	connectCmd.Flags().String("address", "", "Email address (default: your account email)")
+	connectCmd.Flags().String("url", "", "Webhook URL")
+	connectCmd.Flags().String("secret", "", "Shared secret for signing webhook requests (at least 16 characters)")
+	connectCmd.Flags().String("code", "", "Verification code for a pending sms or email channel")
 	rootCmd.AddCommand(connectCmd)
 }
 
@@ -24,48 +48,180 @@ func runConnect(cmd *cobra.Command, args []string) error {
 	service := args[0]
 
 	switch service {
-	case "slack":
-		return connectSlack()
+	case "slack", "discord", "teams":
+		return connectChat(chatApps[service])
+	case "sms", "email", "webhook":
+		return connectContact(cmd, service)
 	default:
-		return fmt.Errorf("unknown service: %s (supported: slack)", service)
+		return fmt.Errorf("unknown service: %s (supported: slack, discord, teams, sms, email, webhook)", service)
 	}
 }
 
-func connectSlack() error {
-	// Check if already connected
-	checkQuery := `query { slackConnected }`
-	data, err := gqlClient.Do(checkQuery, nil)
+func connectContact(cmd *cobra.Command, service string) error {
+	if code, _ := cmd.Flags().GetString("code"); code != "" {
+		return verifyChannel(service, code)
+	}
+
+	var query string
+	vars := map[string]any{}
+	switch service {
+	case "sms":
+		phone, _ := cmd.Flags().GetString("phone")
+		if phone == "" {
+			return fmt.Errorf("--phone is required (e.g. --phone +14155550123)")
+		}
+		query = `mutation ConnectSms($phone: String!) { connectSms(phone: $phone) {` + channelFields + `} }`
+		vars["phone"] = phone
+	case "email":
+		query = `mutation ConnectEmail($address: String) { connectEmail(address: $address) {` + channelFields + `} }`
+		if address, _ := cmd.Flags().GetString("address"); address != "" {
+			vars["address"] = address
+		}
+	case "webhook":
+		url, _ := cmd.Flags().GetString("url")
+		secret, _ := cmd.Flags().GetString("secret")
+		if url == "" || secret == "" {
+			return fmt.Errorf("--url and --secret are required")
+		}
+		query = `mutation ConnectWebhook($url: String!, $secret: String!) { connectWebhook(url: $url, secret: $secret) {` + channelFields + `} }`
+		vars["url"] = url
+		vars["secret"] = secret
+	}
+
+	data, err := gqlClient.Do(query, vars)
 	if err != nil {
-		return fmt.Errorf("check connection: %w", err)
+		return fmt.Errorf("connect %s: %w", service, err)
 	}
 
-	var checkResult struct {
-		SlackConnected bool `json:"slackConnected"`
+	// The response has a single field named after the mutation.
+	var result map[string]output.Channel
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	var channel output.Channel
+	for _, c := range result {
+		channel = c
+	}
+
+	if channel.Verified {
+		fmt.Printf("Connected %s (%s).\n", service, channel.Address)
+		return nil
+	}
+	if service == "webhook" {
+		return fmt.Errorf("webhook saved but the ping to %s failed; check the URL and run connect again", channel.Address)
 	}
-	if err := json.Unmarshal(data, &checkResult); err != nil {
+
+	fmt.Printf("Sent a verification code to %s.\n", channel.Address)
+	fmt.Print("Enter the code: ")
+	code, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	code = strings.TrimSpace(code)
+	if code == "" {
+		fmt.Printf("No code entered. Finish later with: agentduty connect %s --code <code>\n", service)
+		return nil
+	}
+	return verifyChannel(service, code)
+}
+
+func verifyChannel(service, code string) error {
+	query := `mutation VerifyChannel($channel: String!, $code: String!) {
+		verifyChannel(channel: $channel, code: $code) {` + channelFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"channel": service, "code": code})
+	if err != nil {
+		return fmt.Errorf("verify %s: %w", service, err)
+	}
+
+	var result struct {
+		VerifyChannel output.Channel `json:"verifyChannel"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
 		return fmt.Errorf("parse response: %w", err)
 	}
+	fmt.Printf("Verified! Notifications will also go to %s.\n", result.VerifyChannel.Address)
+	return nil
+}
+
+// chatApp describes how to link a chat app with a one-time code that the
+// user sends to the AgentDuty bot, and how to tell when that has happened.
+type chatApp struct {
+	service        string // argument to connect, e.g. "slack"
+	name           string // display name
+	connectedQuery string // Boolean query field, e.g. slackConnected
+	generateCode   string // String mutation field, e.g. generateSlackLinkCode
+	installPath    string // web path that installs or opens the bot
+	installStep    string
+	sendStep       string
+	connectedMsg   string
+}
+
+var chatApps = map[string]chatApp{
+	"slack": {
+		service:        "slack",
+		name:           "Slack",
+		connectedQuery: "slackConnected",
+		generateCode:   "generateSlackLinkCode",
+		installPath:    "/auth/slack/install",
+		installStep:    "Install the AgentDuty Slack app in your workspace\n  (skip if already installed)",
+		sendStep:       "DM this code to the AgentDuty bot in Slack:",
+		connectedMsg:   "Connected! You'll now receive notifications via Slack DM.",
+	},
+	"discord": {
+		service:        "discord",
+		name:           "Discord",
+		connectedQuery: "discordConnected",
+		generateCode:   "generateDiscordLinkCode",
+		installPath:    "/auth/discord/install",
+		installStep:    "Add the AgentDuty app to your Discord account\n  (skip if already added)",
+		sendStep:       "In any Discord chat, run /link with this code:",
+		connectedMsg:   "Connected! You'll now receive notifications via Discord DM.",
+	},
+	"teams": {
+		service:        "teams",
+		name:           "Teams",
+		connectedQuery: "teamsConnected",
+		generateCode:   "generateTeamsLinkCode",
+		installPath:    "/auth/teams/install",
+		installStep:    "Open a chat with the AgentDuty bot in Microsoft Teams\n  (your admin may need to allow the app first)",
+		sendStep:       "Send this code to the AgentDuty bot in Teams:",
+		connectedMsg:   "Connected! You'll now receive notifications in Teams.",
+	},
+}
+
+func chatConnected(app chatApp) (bool, error) {
+	data, err := gqlClient.Do(`query { `+app.connectedQuery+` }`, nil)
+	if err != nil {
+		return false, err
+	}
+	var result map[string]bool
+	if err := json.Unmarshal(data, &result); err != nil {
+		return false, fmt.Errorf("parse response: %w", err)
+	}
+	return result[app.connectedQuery], nil
+}
 
-	if checkResult.SlackConnected {
-		fmt.Println("Your Slack account is already connected.")
+func connectChat(app chatApp) error {
+	// Check if already connected
+	connected, err := chatConnected(app)
+	if err != nil {
+		return fmt.Errorf("check connection: %w", err)
+	}
+	if connected {
+		fmt.Printf("Your %s account is already connected.\n", app.name)
 		return nil
 	}
 
 	// Generate link code
-	genQuery := `mutation { generateSlackLinkCode }`
-	data, err = gqlClient.Do(genQuery, nil)
+	data, err := gqlClient.Do(`mutation { `+app.generateCode+` }`, nil)
 	if err != nil {
 		return fmt.Errorf("generate link code: %w", err)
 	}
 
-	var genResult struct {
-		GenerateSlackLinkCode string `json:"generateSlackLinkCode"`
-	}
+	var genResult map[string]string
 	if err := json.Unmarshal(data, &genResult); err != nil {
 		return fmt.Errorf("parse response: %w", err)
 	}
-
-	code := genResult.GenerateSlackLinkCode
+	code := genResult[app.generateCode]
 
 	// Get user ID for the install URL
 	meQuery := `query { me { id } }`
@@ -83,10 +239,9 @@ func connectSlack() error {
 	}
 
 	baseURL := "https://www.agentduty.dev"
-	installURL := fmt.Sprintf("%s/auth/slack/install?user_id=%s", baseURL, userId)
+	installURL := fmt.Sprintf("%s%s?user_id=%s", baseURL, app.installPath, userId)
 
-	fmt.Println("Step 1: Install the AgentDuty Slack app in your workspace")
-	fmt.Println("  (skip if already installed)")
+	fmt.Printf("Step 1: %s\n", app.installStep)
 	fmt.Println()
 	fmt.Printf("  %s\n", installURL)
 	fmt.Println()
@@ -94,7 +249,7 @@ func connectSlack() error {
 	// Try to open browser
 	openBrowser(installURL)
 
-	fmt.Println("Step 2: DM this code to the AgentDuty bot in Slack:")
+	fmt.Printf("Step 2: %s\n", app.sendStep)
 	fmt.Println()
 	fmt.Printf("  %s\n", code)
 	fmt.Println()
@@ -109,24 +264,13 @@ func connectSlack() error {
 	for {
 		select {
 		case <-deadline:
-			fmt.Fprintln(os.Stderr, "Link code expired. Run 'agentduty connect slack' again.")
+			fmt.Fprintf(os.Stderr, "Link code expired. Run 'agentduty connect %s' again.\n", app.service)
 			os.Exit(1)
 		case <-ticker.C:
-			data, err := gqlClient.Do(checkQuery, nil)
-			if err != nil {
-				continue
-			}
-			var result struct {
-				SlackConnected bool `json:"slackConnected"`
-			}
-			if err := json.Unmarshal(data, &result); err != nil {
-				continue
-			}
-			if result.SlackConnected {
-				fmt.Println("Connected! You'll now receive notifications via Slack DM.")
+			if connected, err := chatConnected(app); err == nil && connected {
+				fmt.Println(app.connectedMsg)
 				return nil
 			}
 		}
 	}
 }
-
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var disconnectCmd = &cobra.Command{
+	Use:       "disconnect <service>",
+	Short:     "Stop delivering notifications to a service",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"slack", "discord", "teams", "sms", "email", "webhook"},
+	RunE:      runDisconnect,
+}
+
+func init() {
+	rootCmd.AddCommand(disconnectCmd)
+}
+
+func runDisconnect(cmd *cobra.Command, args []string) error {
+	service := args[0]
+
+	query := `mutation DisconnectChannel($channel: String!) {
+		disconnectChannel(channel: $channel)
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"channel": service})
+	if err != nil {
+		return fmt.Errorf("disconnect %s: %w", service, err)
+	}
+
+	var result struct {
+		DisconnectChannel bool `json:"disconnectChannel"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if !result.DisconnectChannel {
+		return fmt.Errorf("disconnect %s: the server did not disconnect it", service)
+	}
+
+	d := output.Disconnected{Channel: service, Disconnected: true}
+	if jsonFlag {
+		output.PrintJSON(d)
+	} else {
+		output.PrintDisconnected(d)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const holdFields = `
+	doNotDisturb {
+		reason
+		until
+		breakthroughPriority
+	}`
+
+var dndCmd = &cobra.Command{
+	Use:   "dnd",
+	Short: "Hold notifications for a while (do not disturb)",
+	Long: `Hold pushes (Slack, SMS) while you're heads-down. Held notifications stay in
+the feed and are delivered when do-not-disturb ends. Notifications at or above
+the breakthrough priority (P5 by default) always get through.`,
+	RunE: runDNDStatus,
+}
+
+var dndOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Turn on do-not-disturb",
+	RunE:  runDNDOn,
+}
+
+var dndOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Turn off do-not-disturb",
+	RunE:  runDNDOff,
+}
+
+func init() {
+	dndOnCmd.Flags().Duration("for", time.Hour, "How long to hold notifications")
+	dndOnCmd.Flags().Int("breakthrough", 0, "Lowest priority that still gets through (1-5, default unchanged)")
+
+	dndCmd.AddCommand(dndOnCmd)
+	dndCmd.AddCommand(dndOffCmd)
+	rootCmd.AddCommand(dndCmd)
+}
+
+// fetchHold returns the current DND or quiet-hours hold, or nil.
+func fetchHold() (*output.DoNotDisturb, error) {
+	query := `query { me {` + holdFields + `} }`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get do-not-disturb: %w", err)
+	}
+
+	var result struct {
+		Me *struct {
+			DoNotDisturb *output.DoNotDisturb `json:"doNotDisturb"`
+		} `json:"me"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	if result.Me == nil {
+		return nil, fmt.Errorf("not logged in (run: agentduty login)")
+	}
+	return result.Me.DoNotDisturb, nil
+}
+
+func printHold(hold *output.DoNotDisturb) {
+	if jsonFlag {
+		output.PrintJSON(map[string]any{"doNotDisturb": hold})
+		return
+	}
+	if hold == nil {
+		fmt.Println("Notifications are being delivered (no do-not-disturb or quiet hours active).")
+		return
+	}
+	fmt.Println(output.DescribeDND(*hold))
+}
+
+func setBreakthrough(priority int) error {
+	if priority < 1 || priority > 5 {
+		return fmt.Errorf("--breakthrough must be between 1 and 5")
+	}
+	_, err := gqlClient.Do(`mutation SetBreakthrough($priority: Int!) {
+		updateSettings(breakthroughPriority: $priority) { id }
+	}`, map[string]any{"priority": priority})
+	if err != nil {
+		return fmt.Errorf("set breakthrough priority: %w", err)
+	}
+	return nil
+}
+
+func runDNDStatus(cmd *cobra.Command, args []string) error {
+	hold, err := fetchHold()
+	if err != nil {
+		return err
+	}
+	printHold(hold)
+	return nil
+}
+
+func runDNDOn(cmd *cobra.Command, args []string) error {
+	duration, _ := cmd.Flags().GetDuration("for")
+	breakthrough, _ := cmd.Flags().GetInt("breakthrough")
+
+	if duration < time.Minute {
+		return fmt.Errorf("--for must be at least 1m")
+	}
+
+	if cmd.Flags().Changed("breakthrough") {
+		if err := setBreakthrough(breakthrough); err != nil {
+			return err
+		}
+	}
+
+	return setDND(int(duration.Minutes()))
+}
+
+func runDNDOff(cmd *cobra.Command, args []string) error {
+	return setDND(0)
+}
+
+func setDND(minutes int) error {
+	query := `mutation SetDoNotDisturb($minutes: Int!) {
+		setDoNotDisturb(minutes: $minutes) {` + holdFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"minutes": minutes})
+	if err != nil {
+		return fmt.Errorf("set do-not-disturb: %w", err)
+	}
+
+	var result struct {
+		SetDoNotDisturb struct {
+			DoNotDisturb *output.DoNotDisturb `json:"doNotDisturb"`
+		} `json:"setDoNotDisturb"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	printHold(result.SetDoNotDisturb.DoNotDisturb)
+	return nil
+}
@@ -0,0 +1,456 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sestinj/agentduty/cli/internal/escalation"
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const escalationPolicyFields = `
+	id
+	name
+	isDefault
+	priorities
+	steps {
+		stepOrder
+		channel
+		delaySeconds
+	}`
+
+var escalationCmd = &cobra.Command{
+	Use:     "escalation",
+	Aliases: []string{"policy", "policies"},
+	Short:   "Manage escalation policies",
+	Long: `Manage escalation policies: which channels a notification goes to, in
+what order, and how long to wait between them.
+
+Steps are given inline as channel[:delay] (e.g. --step slack --step sms:5m)
+or in a YAML file:
+
+  name: urgent
+  default: false
+  steps:
+    - channel: slack
+    - channel: sms
+      delay: 5m
+
+The first step always fires immediately; each later step fires its delay
+after the previous one, until the notification is answered.`,
+}
+
+var escalationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List escalation policies",
+	RunE:  runEscalationList,
+}
+
+var escalationShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a policy and its timeline",
+	Long:  "Show a policy by name, or with --priority the policy a notification of that priority would use.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runEscalationShow,
+}
+
+var escalationCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create an escalation policy",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runEscalationCreate,
+}
+
+var escalationEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Rename a policy or replace its steps",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEscalationEdit,
+}
+
+var escalationDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an escalation policy",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEscalationDelete,
+}
+
+var escalationSetDefaultCmd = &cobra.Command{
+	Use:   "set-default <name>",
+	Short: "Use a policy for priorities without a route",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEscalationSetDefault,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{escalationCreateCmd, escalationEditCmd} {
+		c.Flags().StringArray("step", nil, "Step as channel[:delay], repeatable (e.g. slack, sms:5m)")
+		c.Flags().StringP("file", "f", "", "Read the policy from a YAML file (- for stdin)")
+		c.Flags().Bool("dry-run", false, "Print the resulting timeline without saving")
+	}
+	escalationCreateCmd.Flags().Bool("default", false, "Make this the default policy")
+	escalationEditCmd.Flags().String("rename", "", "New name for the policy")
+	escalationShowCmd.Flags().IntP("priority", "p", 0, "Show the policy used for this priority (1-5)")
+
+	escalationCmd.AddCommand(escalationListCmd)
+	escalationCmd.AddCommand(escalationShowCmd)
+	escalationCmd.AddCommand(escalationCreateCmd)
+	escalationCmd.AddCommand(escalationEditCmd)
+	escalationCmd.AddCommand(escalationDeleteCmd)
+	escalationCmd.AddCommand(escalationSetDefaultCmd)
+	rootCmd.AddCommand(escalationCmd)
+}
+
+// readSteps collects steps from --step flags or --file. The file may also
+// carry a name and default flag, returned alongside.
+func readSteps(cmd *cobra.Command) ([]escalation.Step, *escalation.File, error) {
+	inline, _ := cmd.Flags().GetStringArray("step")
+	file, _ := cmd.Flags().GetString("file")
+
+	if len(inline) > 0 && file != "" {
+		return nil, nil, fmt.Errorf("--step and --file are mutually exclusive")
+	}
+
+	if file != "" {
+		f, err := escalation.LoadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f.Steps, &f, nil
+	}
+
+	steps := make([]escalation.Step, 0, len(inline))
+	for _, s := range inline {
+		step, err := escalation.ParseStep(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil, nil
+}
+
+func stepVariables(steps []escalation.Step) []map[string]any {
+	vars := make([]map[string]any, len(steps))
+	for i, s := range steps {
+		vars[i] = map[string]any{
+			"channel":      s.Channel,
+			"delaySeconds": int(s.Delay.Seconds()),
+		}
+	}
+	return vars
+}
+
+func outputSteps(steps []escalation.Step) []output.EscalationStep {
+	out := make([]output.EscalationStep, len(steps))
+	for i, s := range steps {
+		out[i] = output.EscalationStep{
+			StepOrder:    i,
+			Channel:      s.Channel,
+			DelaySeconds: int(s.Delay.Seconds()),
+		}
+	}
+	return out
+}
+
+func fetchEscalationPolicies() ([]output.EscalationPolicy, error) {
+	query := `query { escalationPolicies {` + escalationPolicyFields + `} }`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list escalation policies: %w", err)
+	}
+
+	var result struct {
+		EscalationPolicies []output.EscalationPolicy `json:"escalationPolicies"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return result.EscalationPolicies, nil
+}
+
+func fetchEscalationPolicy(name string) (*output.EscalationPolicy, error) {
+	query := `query EscalationPolicy($id: String!) {
+		escalationPolicy(id: $id) {` + escalationPolicyFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"id": name})
+	if err != nil {
+		return nil, fmt.Errorf("get escalation policy: %w", err)
+	}
+
+	var result struct {
+		EscalationPolicy *output.EscalationPolicy `json:"escalationPolicy"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	if result.EscalationPolicy == nil {
+		return nil, fmt.Errorf("escalation policy not found: %s", name)
+	}
+	return result.EscalationPolicy, nil
+}
+
+// policyForPriority returns the policy a notification of the given priority
+// would escalate through: its route, else the default, else nil.
+func policyForPriority(priority int) (*output.EscalationPolicy, error) {
+	policies, err := fetchEscalationPolicies()
+	if err != nil {
+		return nil, err
+	}
+	var fallback *output.EscalationPolicy
+	for i, p := range policies {
+		for _, routed := range p.Priorities {
+			if routed == priority {
+				return &policies[i], nil
+			}
+		}
+		if p.IsDefault {
+			fallback = &policies[i]
+		}
+	}
+	return fallback, nil
+}
+
+func runEscalationList(cmd *cobra.Command, args []string) error {
+	policies, err := fetchEscalationPolicies()
+	if err != nil {
+		return err
+	}
+
+	if jsonFlag {
+		output.PrintJSON(policies)
+	} else {
+		output.PrintEscalationPolicies(policies)
+	}
+	return nil
+}
+
+func runEscalationShow(cmd *cobra.Command, args []string) error {
+	priority, _ := cmd.Flags().GetInt("priority")
+
+	var policy *output.EscalationPolicy
+	var err error
+	switch {
+	case len(args) == 1 && priority != 0:
+		return fmt.Errorf("pass a policy name or --priority, not both")
+	case len(args) == 1:
+		policy, err = fetchEscalationPolicy(args[0])
+	case priority != 0:
+		if priority < 1 || priority > 5 {
+			return fmt.Errorf("--priority must be between 1 and 5")
+		}
+		policy, err = policyForPriority(priority)
+		if err == nil && policy == nil {
+			if jsonFlag {
+				output.PrintJSON(nil)
+			} else {
+				fmt.Printf("P%d has no route and there is no default policy: delivered once via Slack (SMS if Slack isn't connected).\n", priority)
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("pass a policy name or --priority")
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonFlag {
+		output.PrintJSON(policy)
+	} else {
+		output.PrintEscalationPolicy(*policy)
+	}
+	return nil
+}
+
+func runEscalationCreate(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	isDefault, _ := cmd.Flags().GetBool("default")
+
+	steps, file, err := readSteps(cmd)
+	if err != nil {
+		return err
+	}
+
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
+	}
+	if file != nil {
+		if name == "" {
+			name = file.Name
+		}
+		if !cmd.Flags().Changed("default") {
+			isDefault = file.Default
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("policy name is required (argument or name: in --file)")
+	}
+	if len(steps) == 0 {
+		return fmt.Errorf("at least one step is required (--step or --file)")
+	}
+
+	if dryRun {
+		fmt.Printf("Would create policy %s:\n\n", name)
+		output.PrintEscalationTimeline(outputSteps(steps))
+		return nil
+	}
+
+	query := `mutation CreateEscalationPolicy($name: String!, $steps: [EscalationStepInput!]!, $isDefault: Boolean) {
+		createEscalationPolicy(name: $name, steps: $steps, isDefault: $isDefault) {` + escalationPolicyFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{
+		"name":      name,
+		"steps":     stepVariables(steps),
+		"isDefault": isDefault,
+	})
+	if err != nil {
+		return fmt.Errorf("create escalation policy: %w", err)
+	}
+
+	var result struct {
+		CreateEscalationPolicy output.EscalationPolicy `json:"createEscalationPolicy"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.CreateEscalationPolicy)
+	} else {
+		fmt.Printf("Created policy %s\n\n", name)
+		output.PrintEscalationPolicy(result.CreateEscalationPolicy)
+	}
+	return nil
+}
+
+func runEscalationEdit(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	rename, _ := cmd.Flags().GetString("rename")
+
+	steps, _, err := readSteps(cmd)
+	if err != nil {
+		return err
+	}
+	if rename == "" && len(steps) == 0 {
+		return fmt.Errorf("nothing to change: pass --rename, --step or --file")
+	}
+
+	if dryRun {
+		current, err := fetchEscalationPolicy(name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Current timeline for %s:\n\n", current.Name)
+		output.PrintEscalationTimeline(current.Steps)
+		if len(steps) > 0 {
+			fmt.Printf("\nAfter edit:\n\n")
+			output.PrintEscalationTimeline(outputSteps(steps))
+		}
+		if rename != "" {
+			fmt.Printf("\nWould rename %s → %s\n", current.Name, rename)
+		}
+		return nil
+	}
+
+	variables := map[string]any{"id": name}
+	if rename != "" {
+		variables["name"] = rename
+	}
+	if len(steps) > 0 {
+		variables["steps"] = stepVariables(steps)
+	}
+
+	query := `mutation UpdateEscalationPolicy($id: String!, $name: String, $steps: [EscalationStepInput!]) {
+		updateEscalationPolicy(id: $id, name: $name, steps: $steps) {` + escalationPolicyFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, variables)
+	if err != nil {
+		return fmt.Errorf("update escalation policy: %w", err)
+	}
+
+	var result struct {
+		UpdateEscalationPolicy *output.EscalationPolicy `json:"updateEscalationPolicy"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if result.UpdateEscalationPolicy == nil {
+		return fmt.Errorf("escalation policy not found: %s", name)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.UpdateEscalationPolicy)
+	} else {
+		fmt.Printf("Updated policy %s\n\n", result.UpdateEscalationPolicy.Name)
+		output.PrintEscalationPolicy(*result.UpdateEscalationPolicy)
+	}
+	return nil
+}
+
+func runEscalationDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	const query = `mutation DeleteEscalationPolicy($id: String!) {
+		deleteEscalationPolicy(id: $id)
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"id": name})
+	if err != nil {
+		return fmt.Errorf("delete escalation policy: %w", err)
+	}
+
+	var result struct {
+		DeleteEscalationPolicy bool `json:"deleteEscalationPolicy"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if !result.DeleteEscalationPolicy {
+		return fmt.Errorf("escalation policy not found: %s", name)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(map[string]any{"deleted": name})
+	} else {
+		fmt.Printf("Deleted policy %s\n", name)
+	}
+	return nil
+}
+
+func runEscalationSetDefault(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	query := `mutation SetDefaultEscalationPolicy($id: String!) {
+		setDefaultEscalationPolicy(id: $id) {` + escalationPolicyFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"id": name})
+	if err != nil {
+		return fmt.Errorf("set default escalation policy: %w", err)
+	}
+
+	var result struct {
+		SetDefaultEscalationPolicy *output.EscalationPolicy `json:"setDefaultEscalationPolicy"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if result.SetDefaultEscalationPolicy == nil {
+		return fmt.Errorf("escalation policy not found: %s", name)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.SetDefaultEscalationPolicy)
+	} else {
+		fmt.Printf("Default policy is now %s\n", result.SetDefaultEscalationPolicy.Name)
+	}
+	return nil
+}
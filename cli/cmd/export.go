@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sestinj/agentduty/cli/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print your account configuration as an agentduty.yaml manifest",
+	RunE:  runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringP("out", "o", "", "Write to this file instead of stdout")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	out, _ := cmd.Flags().GetString("out")
+
+	state, err := fetchManifestState()
+	if err != nil {
+		return err
+	}
+
+	data, err := manifest.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("render manifest: %w", err)
+	}
+
+	if out == "" {
+		os.Stdout.Write(data)
+		return nil
+	}
+	if err := os.WriteFile(out, data, 0644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", out)
+	return nil
+}
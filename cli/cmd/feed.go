@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/sestinj/agentduty/cli/internal/tui"
@@ -15,11 +16,26 @@ var feedCmd = &cobra.Command{
 }
 
 func init() {
+	feedCmd.Flags().Bool("plain", false, "Line-by-line prompt without colors or screen control, for screen readers and dumb terminals (default when TERM=dumb)")
 	rootCmd.AddCommand(feedCmd)
 }
 
 func runFeed(cmd *cobra.Command, args []string) error {
-	m := tui.NewModel(gqlClient)
+	plain, _ := cmd.Flags().GetBool("plain")
+	if !cmd.Flags().Changed("plain") {
+		plain = os.Getenv("TERM") == "dumb"
+	}
+	if plain {
+		if err := tui.RunPlain(gqlClient, os.Stdin, os.Stdout); err != nil {
+			return fmt.Errorf("feed: %w", err)
+		}
+		return nil
+	}
+
+	m, err := tui.NewModel(gqlClient, cfg.Feed, cfg.TUI)
+	if err != nil {
+		return fmt.Errorf("feed: %w", err)
+	}
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("feed: %w", err)
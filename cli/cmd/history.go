@@ -3,8 +3,14 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/sestinj/agentduty/cli/internal/transcript"
 	"github.com/spf13/cobra"
 )
 
@@ -14,10 +20,29 @@ var historyCmd = &cobra.Command{
 	RunE:  runHistory,
 }
 
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export session transcripts as Markdown, JSON Lines or HTML",
+	Long: `Export every notification, option, response (with channel and responder),
+reaction and timestamp in a session.
+
+  agentduty history export --format md --out run.md
+  agentduty history export --all-sessions --since 7d --format html --out week.html`,
+	RunE: runHistoryExport,
+}
+
 func init() {
 	historyCmd.Flags().StringP("session", "s", "", "Session key (default: auto-generated from workspace)")
 	historyCmd.Flags().StringP("workspace", "w", "", "Workspace path (default $PWD)")
 
+	historyExportCmd.Flags().StringP("session", "s", "", "Session key (default: auto-generated from workspace)")
+	historyExportCmd.Flags().StringP("workspace", "w", "", "Workspace path (default $PWD)")
+	historyExportCmd.Flags().StringP("format", "f", "md", "Output format: "+strings.Join(transcript.Formats, ", "))
+	historyExportCmd.Flags().StringP("out", "o", "", "Write to this file (default stdout)")
+	historyExportCmd.Flags().Bool("all-sessions", false, "Export every session, and every reply thread outside one, with activity since --since")
+	historyExportCmd.Flags().String("since", "7d", "With --all-sessions: a duration (e.g. 48h, 7d) or an RFC 3339 time")
+
+	historyCmd.AddCommand(historyExportCmd)
 	rootCmd.AddCommand(historyCmd)
 }
 
@@ -28,20 +53,46 @@ const sessionHistoryQuery = `query SessionHistory($sessionKey: String!) {
 		notifications {
 			id
 			shortCode
+			parentId
 			message
 			options
 			status
 			createdAt
+			editedAt
+			retractReason
 			responses {
 				text
 				selectedOption
 				channel
+				auto
 				createdAt
 			}
 		}
 	}
 }`
 
+const exportNotificationFields = `
+	id
+	shortCode
+	parentId
+	message
+	priority
+	tags
+	options
+	status
+	createdAt
+	editedAt
+	retractReason
+	responses {
+		text
+		selectedOption
+		channel
+		auto
+		responder
+		reactions { emoji createdAt }
+		createdAt
+	}`
+
 func runHistory(cmd *cobra.Command, args []string) error {
 	session, _ := cmd.Flags().GetString("session")
 	workspace, _ := cmd.Flags().GetString("workspace")
@@ -81,3 +132,158 @@ func runHistory(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// exportPageSize is how many sessions each sessionHistories page asks for.
+const exportPageSize = 100
+
+// fetchExportHistories returns every session, and every reply thread outside
+// a session, with activity since start, oldest first. Sessions come a page at
+// a time, newest first, until a short page.
+func fetchExportHistories(start time.Time) ([]output.SessionHistory, error) {
+	since := start.Format(time.RFC3339)
+	const sessionsQuery = `query SessionHistories($since: String!, $before: String, $limit: Int) {
+		sessionHistories(since: $since, before: $before, limit: $limit) {
+			sessionId
+			sessionKey
+			workspace
+			createdAt
+			notifications {` + exportNotificationFields + `}
+		}
+	}`
+
+	var histories []output.SessionHistory
+	vars := map[string]any{"since": since, "limit": exportPageSize}
+	for {
+		data, err := gqlClient.Do(sessionsQuery, vars)
+		if err != nil {
+			return nil, fmt.Errorf("query sessions: %w", err)
+		}
+		var result struct {
+			SessionHistories []output.SessionHistory `json:"sessionHistories"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("parse response: %w", err)
+		}
+		histories = append(histories, result.SessionHistories...)
+		if len(result.SessionHistories) < exportPageSize {
+			break
+		}
+		vars["before"] = result.SessionHistories[len(result.SessionHistories)-1].SessionID
+	}
+
+	const threadsQuery = `query ThreadHistories($since: String!) {
+		threadHistories(since: $since) {
+			sessionId
+			sessionKey
+			workspace
+			createdAt
+			notifications {` + exportNotificationFields + `}
+		}
+	}`
+	data, err := gqlClient.Do(threadsQuery, map[string]any{"since": since})
+	if err != nil {
+		return nil, fmt.Errorf("query threads: %w", err)
+	}
+	var result struct {
+		ThreadHistories []output.SessionHistory `json:"threadHistories"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	histories = append(histories, result.ThreadHistories...)
+
+	sortHistories(histories)
+	return histories, nil
+}
+
+// sortHistories orders histories oldest first.
+func sortHistories(histories []output.SessionHistory) {
+	slices.SortStableFunc(histories, func(a, b output.SessionHistory) int {
+		switch {
+		case a.CreatedAt == nil || b.CreatedAt == nil:
+			return 0
+		case a.CreatedAt.Before(*b.CreatedAt):
+			return -1
+		case b.CreatedAt.Before(*a.CreatedAt):
+			return 1
+		}
+		return 0
+	})
+}
+
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	session, _ := cmd.Flags().GetString("session")
+	workspace, _ := cmd.Flags().GetString("workspace")
+	format, _ := cmd.Flags().GetString("format")
+	out, _ := cmd.Flags().GetString("out")
+	allSessions, _ := cmd.Flags().GetBool("all-sessions")
+	since, _ := cmd.Flags().GetString("since")
+
+	if !slices.Contains(transcript.Formats, format) {
+		return fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(transcript.Formats, ", "))
+	}
+
+	var sessions []output.SessionHistory
+	if allSessions {
+		if session != "" || workspace != "" {
+			return fmt.Errorf("--all-sessions cannot be combined with --session or --workspace")
+		}
+		start, err := parseSince(since, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if sessions, err = fetchExportHistories(start); err != nil {
+			return err
+		}
+	} else {
+		if workspace == "" {
+			workspace = resolveWorkspace()
+		}
+		if session == "" {
+			session = generateSession(workspace)
+		}
+
+		query := `query SessionHistory($sessionKey: String!) {
+			sessionHistory(sessionKey: $sessionKey) {
+				sessionId
+				sessionKey
+				workspace
+				createdAt
+				notifications {` + exportNotificationFields + `}
+			}
+		}`
+		data, err := gqlClient.Do(query, map[string]any{"sessionKey": session})
+		if err != nil {
+			return fmt.Errorf("query session history: %w", err)
+		}
+		var result struct {
+			SessionHistory *output.SessionHistory `json:"sessionHistory"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		if result.SessionHistory == nil {
+			return fmt.Errorf("no session %s found", session)
+		}
+		sessions = []output.SessionHistory{*result.SessionHistory}
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" && out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := transcript.Write(w, format, sessions); err != nil {
+		return fmt.Errorf("write transcript: %w", err)
+	}
+	if out != "" && out != "-" {
+		fmt.Fprintf(os.Stderr, "Exported %d session(s) to %s.\n", len(sessions), out)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+func TestFetchExportHistories_PagesAndSorts(t *testing.T) {
+	// 150 sessions, newest first, one minute apart, then one reply thread.
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := func(i int) map[string]any {
+		return map[string]any{
+			"sessionId":     fmt.Sprintf("s%03d", i),
+			"createdAt":     base.Add(time.Duration(i) * time.Minute).Format(time.RFC3339),
+			"notifications": []any{},
+		}
+	}
+	var befores []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var data map[string]any
+		if strings.Contains(req.Query, "threadHistories") {
+			data = map[string]any{"threadHistories": []any{map[string]any{
+				"sessionId": "t1", "sessionKey": "AAA",
+				"createdAt":     base.Add(30 * time.Second).Format(time.RFC3339),
+				"notifications": []any{},
+			}}}
+		} else {
+			befores = append(befores, req.Variables["before"])
+			top := 149
+			if b, ok := req.Variables["before"].(string); ok {
+				fmt.Sscanf(b, "s%d", &top)
+				top--
+			}
+			var page []any
+			for i := top; i >= 0 && len(page) < exportPageSize; i-- {
+				page = append(page, session(i))
+			}
+			data = map[string]any{"sessionHistories": page}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	t.Setenv("AGENTDUTY_API_KEY", "")
+	prev := gqlClient
+	gqlClient = client.New(server.URL, &config.Config{})
+	defer func() { gqlClient = prev }()
+
+	got, err := fetchExportHistories(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 151 {
+		t.Fatalf("expected every session and the thread, got %d", len(got))
+	}
+	if len(befores) != 2 || befores[0] != nil || befores[1] != "s050" {
+		t.Errorf("expected a second page before s050, got %v", befores)
+	}
+	if got[0].SessionID != "s000" || got[1].SessionID != "t1" || got[150].SessionID != "s149" {
+		t.Errorf("expected oldest first with the thread in place, got %s, %s … %s", got[0].SessionID, got[1].SessionID, got[150].SessionID)
+	}
+}
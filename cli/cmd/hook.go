@@ -120,8 +120,16 @@ func runHookSessionStart(cmd *cobra.Command, args []string) error {
 - Send a message: agentduty notify -m "your message"
 - Send with options: agentduty notify -m "question?" -o "Yes" -o "No"
 - Wait for response: agentduty notify -m "question?" --wait
+- Set a deadline: agentduty notify -m "question?" -o "Go" -o "Skip" --expires-in 20m --default-option "Skip" (without a default, --wait prints that it expired)
 - Poll for response: agentduty poll <ID> --wait --timeout 30m
+- Follow up on a question: agentduty notify -m "more detail" --reply-to <shortCode>
+- Wait only on one thread: agentduty poll <ID> --wait --for <shortCode>
 - Acknowledge a message: agentduty react <shortCode>
+- Report progress (edits one status line, no reply expected): agentduty progress --key build -m "step 3/7: running tests" --percent 42
+- Identical notifications within 10m collapse into one with a counter; pass --dedup-key to group differently worded repeats, or --no-dedup to always send
+- If notify exits with code 75 you hit the per-session rate limit: stop sending and wait for the retry time it prints
+- Edit a sent question: agentduty update <shortCode> -m "new text" [-o ...]
+- Withdraw a question you resolved yourself: agentduty retract <shortCode> --reason "tests pass now"
 - View history: agentduty history
 
 IMPORTANT: When having a conversation through AgentDuty, always maintain a background poll so you can receive replies. After sending a notification, immediately start a background poll. Never let a poll lapse without starting a new one — losing contact means the user has to rescue you manually.
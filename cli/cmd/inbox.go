@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Overview of open questions across all agent sessions",
+	Long: `Show unanswered notifications grouped by workspace and session, with each
+agent's last message, how long its oldest question has waited, and whether
+an agentduty poll is running to receive your reply.`,
+	RunE: runInbox,
+}
+
+func init() {
+	inboxCmd.Flags().Bool("watch", false, "Refresh until interrupted")
+	inboxCmd.Flags().Duration("interval", 5*time.Second, "Refresh interval with --watch")
+
+	rootCmd.AddCommand(inboxCmd)
+}
+
+const inboxQuery = `query Inbox {
+	inbox {
+		sessionKey
+		workspace
+		polling
+		pendingCount
+		oldestPendingAt
+		lastMessage { id shortCode status priority message createdAt }
+		notifications { id shortCode status priority message createdAt }
+	}
+}`
+
+func runInbox(cmd *cobra.Command, args []string) error {
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
+
+	if !watch {
+		sessions, err := fetchInbox()
+		if err != nil {
+			return err
+		}
+		printInbox(sessions)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for {
+		sessions, err := fetchInbox()
+		if jsonFlag {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			} else {
+				// One compact document per refresh, for piping to jq.
+				line, _ := json.Marshal(sessions)
+				fmt.Println(string(line))
+			}
+		} else {
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("agentduty inbox — %s (every %s, Ctrl-C to stop)\n\n", time.Now().Format("15:04:05"), interval)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			} else {
+				output.PrintInbox(sessions)
+			}
+		}
+
+		if !sleepCtx(ctx, interval) {
+			return nil
+		}
+	}
+}
+
+func printInbox(sessions []output.InboxSession) {
+	if jsonFlag {
+		output.PrintJSON(sessions)
+	} else {
+		output.PrintInbox(sessions)
+	}
+}
+
+func fetchInbox() ([]output.InboxSession, error) {
+	data, err := gqlClient.Do(inboxQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("query inbox: %w", err)
+	}
+
+	var result struct {
+		Inbox []output.InboxSession `json:"inbox"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return result.Inbox, nil
+}
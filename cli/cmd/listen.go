@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/config"
+	"github.com/sestinj/agentduty/cli/internal/listen"
+	"github.com/spf13/cobra"
+)
+
+var listenCmd = &cobra.Command{
+	Use:   "listen --exec <command>",
+	Short: "Run a command whenever someone responds",
+	Long: `Listen for responses to your notifications and run a command for each one.
+
+The command runs through sh -c with the response as JSON on stdin and these
+environment variables set:
+
+  AGENTDUTY_SHORT_CODE, AGENTDUTY_NOTIFICATION_ID, AGENTDUTY_RESPONSE_ID,
+  AGENTDUTY_RESPONSE_TEXT, AGENTDUTY_SELECTED_OPTION, AGENTDUTY_CHANNEL,
+  AGENTDUTY_PRIORITY, AGENTDUTY_SESSION, AGENTDUTY_WORKSPACE, AGENTDUTY_AUTO
+
+Responses arrive over a streaming connection, falling back to polling when
+streaming is unavailable. Commands that still fail after --retries are
+appended to the dead-letter log as JSON lines.
+
+  agentduty listen --exec ./on-response.sh
+  agentduty listen --this-session --exec 'jq -r .text >> replies.txt'`,
+	RunE: runListen,
+}
+
+func init() {
+	listenCmd.Flags().String("exec", "", "Command to run for each response (required)")
+	listenCmd.Flags().StringP("session", "s", "", "Only responses in this session (default: all sessions)")
+	listenCmd.Flags().Bool("this-session", false, "Only responses in this workspace's session")
+	listenCmd.Flags().String("since", "", "Also replay responses since a duration ago (e.g. 1h) or an RFC 3339 time")
+	listenCmd.Flags().Int("concurrency", 4, "Maximum commands running at once")
+	listenCmd.Flags().Duration("timeout", 5*time.Minute, "Kill a command that runs longer than this (0 for no limit)")
+	listenCmd.Flags().Int("retries", 2, "Retry a failed command this many times before dead-lettering it")
+	listenCmd.Flags().String("dead-letter", filepath.Join(config.ConfigDir(), "listen-dead-letter.jsonl"), "Append failed responses to this file")
+	listenCmd.Flags().Bool("poll", false, "Poll instead of streaming")
+	listenCmd.Flags().Duration("interval", 3*time.Second, "Polling interval")
+	listenCmd.MarkFlagRequired("exec")
+
+	rootCmd.AddCommand(listenCmd)
+}
+
+const responseEventsQuery = `query ResponseEvents($after: String, $afterId: String, $sessionKey: String) {
+	responseEvents(after: $after, afterId: $afterId, sessionKey: $sessionKey) {
+		id
+		notificationId
+		shortCode
+		message
+		priority
+		tags
+		sessionKey
+		workspace
+		channel
+		text
+		selectedOption
+		auto
+		createdAt
+	}
+}`
+
+const responseStreamPath = "/api/responses/stream"
+
+// isoMillis matches the server's timestamps (JavaScript toISOString).
+const isoMillis = "2006-01-02T15:04:05.000Z"
+
+func runListen(cmd *cobra.Command, args []string) error {
+	command, _ := cmd.Flags().GetString("exec")
+	session, _ := cmd.Flags().GetString("session")
+	thisSession, _ := cmd.Flags().GetBool("this-session")
+	since, _ := cmd.Flags().GetString("since")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	retries, _ := cmd.Flags().GetInt("retries")
+	deadLetter, _ := cmd.Flags().GetString("dead-letter")
+	pollOnly, _ := cmd.Flags().GetBool("poll")
+	interval, _ := cmd.Flags().GetDuration("interval")
+
+	if thisSession {
+		if session != "" {
+			return fmt.Errorf("--session and --this-session are mutually exclusive")
+		}
+		session = generateSession(resolveWorkspace())
+	}
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	start := time.Now().UTC()
+	if since != "" {
+		var err error
+		if start, err = parseSince(since, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	l := &listener{
+		session: session,
+		start:   start.Format(isoMillis),
+		runner: &listen.Runner{
+			Command:     command,
+			Concurrency: concurrency,
+			Timeout:     timeout,
+			Retries:     retries,
+			RetryDelay:  2 * time.Second,
+			DeadLetter:  deadLetter,
+		},
+	}
+
+	scope := "all sessions"
+	if session != "" {
+		scope = "session " + session
+	}
+	fmt.Fprintf(os.Stderr, "Listening for responses in %s (Ctrl-C to stop).\n", scope)
+
+	err := l.listen(ctx, pollOnly, interval)
+	l.runner.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// parseSince accepts a duration before now (including days, e.g. 7d) or an
+// absolute RFC 3339 time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n).UTC(), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: want a duration like 1h or an RFC 3339 time", s)
+}
+
+type listener struct {
+	session string
+	start   string // only responses created after this, until one is seen
+	lastID  string // the newest response seen; the server resumes after it
+	runner  *listen.Runner
+}
+
+func (l *listener) listen(ctx context.Context, pollOnly bool, interval time.Duration) error {
+	backoff := time.Second
+	for !pollOnly && ctx.Err() == nil {
+		err := l.stream(ctx)
+		if err == nil {
+			// The server closes streams periodically; resume after lastID.
+			backoff = time.Second
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var statusErr *client.StatusError
+		if errors.As(err, &statusErr) {
+			switch statusErr.StatusCode {
+			case http.StatusUnauthorized:
+				return fmt.Errorf("listen: %w (run 'agentduty login')", err)
+			case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+				fmt.Fprintln(os.Stderr, "Streaming is unavailable; polling instead.")
+				pollOnly = true
+				continue
+			}
+		}
+
+		// Transient failure: catch up by polling, then reconnect.
+		fmt.Fprintf(os.Stderr, "Stream interrupted (%v); reconnecting in %s.\n", err, backoff)
+		if err := l.poll(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Poll failed: %v\n", err)
+		}
+		if !sleepCtx(ctx, backoff) {
+			return nil
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+
+	for ctx.Err() == nil {
+		if err := l.poll(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Poll failed: %v\n", err)
+		}
+		if !sleepCtx(ctx, interval) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// stream reads the server's response stream until it closes.
+func (l *listener) stream(ctx context.Context) error {
+	params := url.Values{"after": {l.start}}
+	if l.session != "" {
+		params.Set("session", l.session)
+	}
+
+	resp, err := gqlClient.OpenStream(ctx, responseStreamPath, params, l.lastID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return listen.ReadSSE(resp.Body, func(event, id string, data []byte) error {
+		if event != "response" {
+			return nil
+		}
+		var ev listen.Event
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return fmt.Errorf("parse event: %w", err)
+		}
+		return l.handle(ctx, ev)
+	})
+}
+
+// poll fetches every response after the last one seen.
+func (l *listener) poll(ctx context.Context) error {
+	for ctx.Err() == nil {
+		vars := map[string]any{"after": l.start}
+		if l.lastID != "" {
+			vars["afterId"] = l.lastID
+		}
+		if l.session != "" {
+			vars["sessionKey"] = l.session
+		}
+		data, err := gqlClient.Do(responseEventsQuery, vars)
+		if err != nil {
+			return fmt.Errorf("query responses: %w", err)
+		}
+
+		var result struct {
+			ResponseEvents []listen.Event `json:"responseEvents"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+
+		before := l.lastID
+		for _, ev := range result.ResponseEvents {
+			if err := l.handle(ctx, ev); err != nil {
+				return err
+			}
+		}
+		// A full page may have more behind it.
+		if len(result.ResponseEvents) == 0 || l.lastID == before {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *listener) handle(ctx context.Context, ev listen.Event) error {
+	l.lastID = ev.ID
+	return l.runner.Dispatch(ctx, ev)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
@@ -10,10 +10,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/sestinj/agentduty/cli/internal/config"
 	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/sestinj/agentduty/cli/internal/ratelimit"
 	"github.com/spf13/cobra"
 )
 
@@ -34,10 +37,29 @@ func init() {
 	notifyCmd.Flags().Bool("wait", false, "Wait for response")
 	notifyCmd.Flags().Duration("timeout", 30*time.Minute, "Timeout when waiting")
 	notifyCmd.Flags().Bool("stdin", false, "Read message from stdin")
+	notifyCmd.Flags().Duration("expires-in", 0, "Expire the notification after this long (server-side deadline)")
+	notifyCmd.Flags().String("default-option", "", "Response recorded automatically when the notification expires")
+	notifyCmd.Flags().String("reply-to", "", "Short code of the notification this follows up on")
+	notifyCmd.Flags().String("dedup-key", "", "Collapse repeats with this key into one notification (default: hash of the content)")
+	notifyCmd.Flags().Duration("dedup-window", 10*time.Minute, "How long a dedup key keeps collapsing repeats")
+	notifyCmd.Flags().Bool("no-dedup", false, "Always send a new notification, even for identical content")
+	notifyCmd.Flags().String("surface", "", "Chat app for this session's thread: slack, discord or teams (default: first connected)")
 
 	rootCmd.AddCommand(notifyCmd)
 }
 
+// checkDefaultOption rejects a --default-option that isn't one of -o, since
+// the server could never record it as a choice.
+func checkDefaultOption(def string, options []string) error {
+	if def == "" || slices.Contains(options, def) {
+		return nil
+	}
+	if len(options) == 0 {
+		return fmt.Errorf("--default-option %q needs matching -o options", def)
+	}
+	return fmt.Errorf("--default-option %q is not one of the options (%s)", def, strings.Join(options, ", "))
+}
+
 func runNotify(cmd *cobra.Command, args []string) error {
 	message, _ := cmd.Flags().GetString("message")
 	priority, _ := cmd.Flags().GetInt("priority")
@@ -49,6 +71,12 @@ func runNotify(cmd *cobra.Command, args []string) error {
 	wait, _ := cmd.Flags().GetBool("wait")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
 	readStdin, _ := cmd.Flags().GetBool("stdin")
+	expiresIn, _ := cmd.Flags().GetDuration("expires-in")
+	defaultOption, _ := cmd.Flags().GetString("default-option")
+	replyTo, _ := cmd.Flags().GetString("reply-to")
+	dedupKey, _ := cmd.Flags().GetString("dedup-key")
+	dedupWindow, _ := cmd.Flags().GetDuration("dedup-window")
+	noDedup, _ := cmd.Flags().GetBool("no-dedup")
 
 	if readStdin {
 		scanner := bufio.NewScanner(os.Stdin)
@@ -63,6 +91,22 @@ func runNotify(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("message is required (use -m or --stdin)")
 	}
 
+	if defaultOption != "" && expiresIn <= 0 {
+		return fmt.Errorf("--default-option requires --expires-in")
+	}
+	if err := checkDefaultOption(defaultOption, options); err != nil {
+		return err
+	}
+	if expiresIn < 0 {
+		return fmt.Errorf("--expires-in must be positive")
+	}
+	if dedupWindow <= 0 {
+		return fmt.Errorf("--dedup-window must be positive")
+	}
+	if noDedup && dedupKey != "" {
+		return fmt.Errorf("--no-dedup and --dedup-key are mutually exclusive")
+	}
+
 	if workspace == "" {
 		workspace = resolveWorkspace()
 	}
@@ -71,6 +115,19 @@ func runNotify(cmd *cobra.Command, args []string) error {
 		session = generateSession(workspace)
 	}
 
+	limiter := ratelimit.Limiter{
+		Dir:    filepath.Join(config.ConfigDir(), "ratelimit"),
+		Limit:  cfg.RateLimit.PerSession,
+		Window: cfg.RateLimit.Window,
+	}
+	if err := limiter.Check(session, time.Now()); err != nil {
+		return err
+	}
+
+	if dedupKey == "" && !noDedup {
+		dedupKey = contentDedupKey(message, options, priority)
+	}
+
 	// Build context map from key:value pairs.
 	contextMap := map[string]string{}
 	for _, pair := range contextPairs {
@@ -102,6 +159,22 @@ func runNotify(cmd *cobra.Command, args []string) error {
 	if len(tags) > 0 {
 		variables["tags"] = tags
 	}
+	if expiresIn > 0 {
+		variables["expiresInSeconds"] = int(expiresIn.Seconds())
+	}
+	if defaultOption != "" {
+		variables["defaultOption"] = defaultOption
+	}
+	if replyTo != "" {
+		variables["replyTo"] = replyTo
+	}
+	if dedupKey != "" {
+		variables["dedupKey"] = dedupKey
+		variables["dedupWindowSeconds"] = int(dedupWindow.Seconds())
+	}
+	if surface, _ := cmd.Flags().GetString("surface"); surface != "" {
+		variables["surface"] = surface
+	}
 
 	const query = `mutation CreateNotification(
 		$message: String!,
@@ -110,7 +183,13 @@ func runNotify(cmd *cobra.Command, args []string) error {
 		$context: String,
 		$tags: [String!],
 		$sessionKey: String,
-		$workspace: String
+		$workspace: String,
+		$expiresInSeconds: Int,
+		$defaultOption: String,
+		$replyTo: String,
+		$dedupKey: String,
+		$dedupWindowSeconds: Int,
+		$surface: String
 	) {
 		createNotification(
 			message: $message,
@@ -119,12 +198,22 @@ func runNotify(cmd *cobra.Command, args []string) error {
 			context: $context,
 			tags: $tags,
 			sessionKey: $sessionKey,
-			workspace: $workspace
+			workspace: $workspace,
+			expiresInSeconds: $expiresInSeconds,
+			defaultOption: $defaultOption,
+			replyTo: $replyTo,
+			dedupKey: $dedupKey,
+			dedupWindowSeconds: $dedupWindowSeconds,
+			surface: $surface
 		) {
 			id
 			shortCode
+			parentId
 			status
 			priority
+			expiresAt
+			defaultOption
+			repeatCount
 		}
 	}`
 
@@ -132,6 +221,9 @@ func runNotify(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create notification: %w", err)
 	}
+	if err := limiter.Record(session, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 
 	var result struct {
 		CreateNotification output.Notification `json:"createNotification"`
@@ -152,8 +244,30 @@ func runNotify(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// --wait: poll until response or timeout.
-	return pollForResponse(n.ID, timeout, jsonFlag)
+	// With a deadline the server guarantees an answer (possibly the
+	// default), so wait at least until then unless --timeout was explicit.
+	if expiresIn > 0 && !cmd.Flags().Changed("timeout") && timeout < expiresIn+time.Minute {
+		timeout = expiresIn + time.Minute
+	}
+
+	// --wait: poll until response or timeout. A follow-up waits on its own
+	// thread so answers to unrelated questions don't wake it.
+	forCode := ""
+	if replyTo != "" {
+		forCode = n.ShortCode
+	}
+	return pollForResponse(n.ID, timeout, jsonFlag, forCode)
+}
+
+// contentDedupKey identifies a notification by what it asks, so an agent
+// stuck in a loop re-sending the same question collapses into one.
+func contentDedupKey(message string, options []string, priority int) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%s", priority, message)
+	for _, o := range options {
+		fmt.Fprintf(h, "\x00%s", o)
+	}
+	return fmt.Sprintf("auto:%x", h.Sum(nil)[:8])
 }
 
 func generateSession(workspace string) string {
@@ -0,0 +1,24 @@
+package cmd
+
+import "testing"
+
+func TestCheckDefaultOption(t *testing.T) {
+	options := []string{"Go", "Skip"}
+	for _, def := range []string{"", "Go", "Skip"} {
+		if err := checkDefaultOption(def, options); err != nil {
+			t.Errorf("checkDefaultOption(%q): %v", def, err)
+		}
+	}
+	for _, tt := range []struct {
+		def     string
+		options []string
+	}{
+		{"skip", options},
+		{"Later", options},
+		{"Skip", nil},
+	} {
+		if err := checkDefaultOption(tt.def, tt.options); err == nil {
+			t.Errorf("checkDefaultOption(%q, %v): expected an error", tt.def, tt.options)
+		}
+	}
+}
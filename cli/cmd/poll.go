@@ -24,6 +24,7 @@ var pollCmd = &cobra.Command{
 func init() {
 	pollCmd.Flags().Bool("wait", false, "Wait for response")
 	pollCmd.Flags().Duration("timeout", 30*time.Minute, "Timeout when waiting")
+	pollCmd.Flags().String("for", "", "Only wait for responses in this notification's reply thread, in whichever session it lives")
 
 	rootCmd.AddCommand(pollCmd)
 }
@@ -37,10 +38,15 @@ const getNotificationQuery = `query GetNotification($id: String!) {
 		message
 		options
 		createdAt
+		expiresAt
+		defaultOption
+		editedAt
+		retractReason
 		responses {
 			text
 			selectedOption
 			channel
+			auto
 			createdAt
 		}
 	}
@@ -53,15 +59,19 @@ const getSessionHistoryQuery = `query SessionHistory($sessionKey: String!) {
 		notifications {
 			id
 			shortCode
+			parentId
 			status
 			priority
 			message
 			options
 			createdAt
+			editedAt
+			retractReason
 			responses {
 				text
 				selectedOption
 				channel
+				auto
 				createdAt
 			}
 		}
@@ -72,12 +82,13 @@ func runPoll(cmd *cobra.Command, args []string) error {
 	id := args[0]
 	wait, _ := cmd.Flags().GetBool("wait")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
+	forCode, _ := cmd.Flags().GetString("for")
 
 	if !wait {
 		return queryAndPrint(id)
 	}
 
-	return pollForResponse(id, timeout, jsonFlag)
+	return pollForResponse(id, timeout, jsonFlag, forCode)
 }
 
 func queryAndPrint(id string) error {
@@ -109,6 +120,40 @@ func fetchNotification(id string) (output.Notification, error) {
 	return result.Notification, nil
 }
 
+const pollTargetQuery = `query PollTarget($id: String!) {
+	notification(id: $id) {
+		shortCode
+		sessionKey
+	}
+}`
+
+// resolvePollTarget looks up the notification a thread-scoped poll waits on,
+// returning its canonical short code and the session its thread lives in.
+func resolvePollTarget(code string) (shortCode, sessionKey string, err error) {
+	data, err := gqlClient.Do(pollTargetQuery, map[string]any{"id": code})
+	if err != nil {
+		return "", "", fmt.Errorf("query notification: %w", err)
+	}
+
+	var result struct {
+		Notification *struct {
+			ShortCode  string  `json:"shortCode"`
+			SessionKey *string `json:"sessionKey"`
+		} `json:"notification"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", "", fmt.Errorf("parse response: %w", err)
+	}
+	n := result.Notification
+	if n == nil {
+		return "", "", fmt.Errorf("no notification %s", code)
+	}
+	if n.SessionKey == nil {
+		return "", "", fmt.Errorf("%s isn't part of a session; wait on it with: agentduty poll %s --wait", n.ShortCode, n.ShortCode)
+	}
+	return n.ShortCode, *n.SessionKey, nil
+}
+
 func fetchSessionHistory(sessionKey string) (*output.SessionHistory, error) {
 	data, err := gqlClient.Do(getSessionHistoryQuery, map[string]any{"sessionKey": sessionKey})
 	if err != nil {
@@ -124,10 +169,26 @@ func fetchSessionHistory(sessionKey string) (*output.SessionHistory, error) {
 	return result.SessionHistory, nil
 }
 
+// scopeNotifications narrows a session to the reply thread rooted at forCode.
+// An empty forCode means the whole session.
+func scopeNotifications(history *output.SessionHistory, forCode string) []output.Notification {
+	if forCode == "" {
+		return history.Notifications
+	}
+	ids := output.Subtree(history.Notifications, forCode)
+	var scoped []output.Notification
+	for _, n := range history.Notifications {
+		if ids[n.ID] {
+			scoped = append(scoped, n)
+		}
+	}
+	return scoped
+}
+
 // latestResponseTime returns the most recent response timestamp in the session.
-func latestResponseTime(history *output.SessionHistory) string {
+func latestResponseTime(notifications []output.Notification) string {
 	latest := ""
-	for _, n := range history.Notifications {
+	for _, n := range notifications {
 		for _, r := range n.Responses {
 			if r.CreatedAt > latest {
 				latest = r.CreatedAt
@@ -137,10 +198,48 @@ func latestResponseTime(history *output.SessionHistory) string {
 	return latest
 }
 
+// threadResolved reports whether nothing in the thread still awaits an answer.
+func threadResolved(notifications []output.Notification) bool {
+	for _, n := range notifications {
+		if n.Status == "pending" || n.Status == "delivered" {
+			return false
+		}
+	}
+	return len(notifications) > 0
+}
+
+// closedUnanswered returns the notification id (an ID or short code) when it
+// has ended without an answer: expired with no default option, retracted or
+// archived. Waiting on it any longer is pointless.
+func closedUnanswered(notifications []output.Notification, id string) *output.Notification {
+	for i, n := range notifications {
+		if n.ID != id && !strings.EqualFold(n.ShortCode, id) {
+			continue
+		}
+		if n.FirstResponse() != nil {
+			return nil
+		}
+		switch n.Status {
+		case "expired", "retracted", "archived":
+			return &notifications[i]
+		}
+		return nil
+	}
+	return nil
+}
+
+func printClosed(n output.Notification, asJSON bool) {
+	if asJSON {
+		output.PrintJSON(n)
+		return
+	}
+	fmt.Printf("%s was %s without a response.\n", n.ShortCode, n.Status)
+}
+
 // collectResponsesAfter returns responses with CreatedAt after the given watermark.
-func collectResponsesAfter(history *output.SessionHistory, watermark string) []output.ResponseWithContext {
+func collectResponsesAfter(notifications []output.Notification, watermark string) []output.ResponseWithContext {
 	var result []output.ResponseWithContext
-	for _, n := range history.Notifications {
+	for _, n := range notifications {
 		for i, r := range n.Responses {
 			if r.CreatedAt > watermark {
 				result = append(result, output.ResponseWithContext{
@@ -155,23 +254,34 @@ func collectResponsesAfter(history *output.SessionHistory, watermark string) []o
 }
 
 // watermarkPath returns the path to the watermark file for this session.
-func watermarkPath() string {
+// Thread-scoped polls (--for) keep their own watermark so they never
+// swallow responses that a session-wide poll has yet to report.
+func watermarkPath(forCode string) string {
 	workspace := resolveWorkspace()
 	dir := filepath.Join(workspace, ".claude")
 	_ = os.MkdirAll(dir, 0755)
+	if forCode != "" {
+		return filepath.Join(dir, "agentduty-poll-watermark-"+forCode)
+	}
 	return filepath.Join(dir, "agentduty-poll-watermark")
 }
 
-func readWatermark() string {
-	data, err := os.ReadFile(watermarkPath())
+func readWatermark(forCode string) string {
+	data, err := os.ReadFile(watermarkPath(forCode))
 	if err != nil {
 		return ""
 	}
 	return strings.TrimSpace(string(data))
 }
 
-func writeWatermark(ts string) {
-	_ = os.WriteFile(watermarkPath(), []byte(ts), 0644)
+func writeWatermark(forCode, ts string) {
+	_ = os.WriteFile(watermarkPath(forCode), []byte(ts), 0644)
+}
+
+// removeWatermark deletes a thread-scoped watermark once its thread is
+// resolved, so finished threads don't leave files behind.
+func removeWatermark(forCode string) {
+	_ = os.Remove(watermarkPath(forCode))
 }
 
 // pollPidPath returns the path to the PID file for the current session.
@@ -218,14 +328,48 @@ func IsPollRunning() bool {
 	return err == nil
 }
 
-func pollForResponse(id string, timeout time.Duration, asJSON bool) error {
-	// Write PID file so the stop hook knows a poll is running.
-	writePollPid()
-	defer removePollPid()
+// pollHeartbeatInterval is how often a running poll tells the server it is
+// alive, so `agentduty inbox` can show which agents can hear a reply.
+const pollHeartbeatInterval = time.Minute
+
+// reportPolling tells the server whether a poll is running for the session.
+// Best effort: a failed heartbeat never interrupts the poll.
+func reportPolling(sessionKey, workspace string, active bool) {
+	query := `mutation ReportPolling($sessionKey: String!, $workspace: String, $active: Boolean!) {
+		reportPolling(sessionKey: $sessionKey, workspace: $workspace, active: $active)
+	}`
+	_, _ = gqlClient.Do(query, map[string]any{
+		"sessionKey": sessionKey,
+		"workspace":  workspace,
+		"active":     active,
+	})
+}
 
+func pollForResponse(id string, timeout time.Duration, asJSON bool, forCode string) error {
 	workspace := resolveWorkspace()
 	sessionKey := generateSession(workspace)
 
+	// A thread lives in its root's session, which needn't be this one.
+	if forCode != "" {
+		var err error
+		if forCode, sessionKey, err = resolvePollTarget(forCode); err != nil {
+			return err
+		}
+	}
+
+	// Write PID file so the stop hook knows a poll is running, and tell the
+	// server so the inbox can show it.
+	writePollPid()
+	reportPolling(sessionKey, workspace, true)
+	lastHeartbeat := time.Now()
+
+	// os.Exit skips deferred calls, so clean up explicitly.
+	exit := func(code int) {
+		removePollPid()
+		reportPolling(sessionKey, workspace, false)
+		os.Exit(code)
+	}
+
 	backoff := []time.Duration{
 		500 * time.Millisecond,
 		1 * time.Second,
@@ -238,14 +382,14 @@ func pollForResponse(id string, timeout time.Duration, asJSON bool) error {
 	attempt := 0
 
 	// Use a persisted watermark so we never re-report old responses.
-	watermark := readWatermark()
+	watermark := readWatermark(forCode)
 	if watermark == "" {
 		// No watermark yet — set it to now so we only see future responses.
 		history, err := fetchSessionHistory(sessionKey)
 		if err == nil && history != nil {
-			watermark = latestResponseTime(history)
+			watermark = latestResponseTime(scopeNotifications(history, forCode))
 			if watermark != "" {
-				writeWatermark(watermark)
+				writeWatermark(forCode, watermark)
 			}
 		}
 	}
@@ -258,7 +402,7 @@ func pollForResponse(id string, timeout time.Duration, asJSON bool) error {
 			n, nerr := fetchNotification(id)
 			if nerr != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", nerr)
-				os.Exit(2)
+				exit(2)
 			}
 			if n.FirstResponse() != nil {
 				if asJSON {
@@ -266,10 +410,14 @@ func pollForResponse(id string, timeout time.Duration, asJSON bool) error {
 				} else {
 					output.PrintNotification(n)
 				}
-				os.Exit(0)
+				exit(0)
+			}
+			if closed := closedUnanswered([]output.Notification{n}, n.ID); closed != nil {
+				printClosed(*closed, asJSON)
+				exit(0)
 			}
 		} else if history != nil {
-			newResponses := collectResponsesAfter(history, watermark)
+			newResponses := collectResponsesAfter(scopeNotifications(history, forCode), watermark)
 			if len(newResponses) > 0 {
 				for _, r := range newResponses {
 					if asJSON {
@@ -282,9 +430,25 @@ func pollForResponse(id string, timeout time.Duration, asJSON bool) error {
 						watermark = r.Response.CreatedAt
 					}
 				}
-				writeWatermark(watermark)
-				os.Exit(0)
+				if forCode != "" && threadResolved(scopeNotifications(history, forCode)) {
+					removeWatermark(forCode)
+				} else {
+					writeWatermark(forCode, watermark)
+				}
+				exit(0)
 			}
+			if closed := closedUnanswered(history.Notifications, id); closed != nil {
+				printClosed(*closed, asJSON)
+				if forCode != "" && threadResolved(scopeNotifications(history, forCode)) {
+					removeWatermark(forCode)
+				}
+				exit(0)
+			}
+		}
+
+		if time.Since(lastHeartbeat) >= pollHeartbeatInterval {
+			go reportPolling(sessionKey, workspace, true)
+			lastHeartbeat = time.Now()
 		}
 
 		delay := backoff[len(backoff)-1]
@@ -296,7 +460,7 @@ func pollForResponse(id string, timeout time.Duration, asJSON bool) error {
 		select {
 		case <-deadline:
 			fmt.Fprintln(os.Stderr, "Timeout waiting for response.")
-			os.Exit(1)
+			exit(1)
 		case <-time.After(delay):
 			// continue polling
 		}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+)
+
+func TestThreadResolved(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		want     bool
+	}{
+		{nil, false},
+		{[]string{"responded"}, true},
+		{[]string{"responded", "archived", "expired", "retracted"}, true},
+		{[]string{"responded", "delivered"}, false},
+		{[]string{"pending"}, false},
+	}
+	for _, tt := range tests {
+		var ns []output.Notification
+		for _, s := range tt.statuses {
+			ns = append(ns, output.Notification{Status: s})
+		}
+		if got := threadResolved(ns); got != tt.want {
+			t.Errorf("threadResolved(%v) = %v, want %v", tt.statuses, got, tt.want)
+		}
+	}
+}
+
+func TestClosedUnanswered(t *testing.T) {
+	ns := []output.Notification{
+		{ID: "n1", ShortCode: "AAA", Status: "expired"},
+		{ID: "n2", ShortCode: "BBB", Status: "expired", Responses: []output.Response{{SelectedOption: "Skip"}}},
+		{ID: "n3", ShortCode: "CCC", Status: "delivered"},
+		{ID: "n4", ShortCode: "DDD", Status: "retracted"},
+	}
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"n1", true},
+		{"aaa", true},
+		{"BBB", false}, // expired with a default answer
+		{"CCC", false},
+		{"n4", true},
+		{"ZZZ", false},
+	}
+	for _, tt := range tests {
+		if got := closedUnanswered(ns, tt.id) != nil; got != tt.want {
+			t.Errorf("closedUnanswered(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var progressCmd = &cobra.Command{
+	Use:   "progress",
+	Short: "Report progress on a single live status line",
+	Long: `Report progress without sending a new notification each time.
+
+Each --key holds one status line per session. Repeated calls with the same
+key edit that line (and its Slack message) in place. Progress lines never
+count as pending questions.`,
+	RunE: runProgress,
+}
+
+func init() {
+	progressCmd.Flags().StringP("key", "k", "", "Status line key, e.g. build (required)")
+	progressCmd.MarkFlagRequired("key")
+	progressCmd.Flags().StringP("message", "m", "", "Status text (required)")
+	progressCmd.MarkFlagRequired("message")
+	progressCmd.Flags().Int("percent", -1, "Completion percentage (0-100)")
+	progressCmd.Flags().Bool("done", false, "Mark this status line as finished")
+	progressCmd.Flags().StringP("session", "s", "", "Session ID (auto-generated if empty)")
+	progressCmd.Flags().StringP("workspace", "w", "", "Workspace path (default $PWD)")
+
+	rootCmd.AddCommand(progressCmd)
+}
+
+func runProgress(cmd *cobra.Command, args []string) error {
+	key, _ := cmd.Flags().GetString("key")
+	message, _ := cmd.Flags().GetString("message")
+	percent, _ := cmd.Flags().GetInt("percent")
+	done, _ := cmd.Flags().GetBool("done")
+	session, _ := cmd.Flags().GetString("session")
+	workspace, _ := cmd.Flags().GetString("workspace")
+
+	if cmd.Flags().Changed("percent") && (percent < 0 || percent > 100) {
+		return fmt.Errorf("--percent must be between 0 and 100")
+	}
+
+	if workspace == "" {
+		workspace = resolveWorkspace()
+	}
+
+	if session == "" {
+		session = generateSession(workspace)
+	}
+
+	variables := map[string]any{
+		"sessionKey": session,
+		"workspace":  workspace,
+		"key":        key,
+		"message":    message,
+		"done":       done,
+	}
+	if cmd.Flags().Changed("percent") {
+		variables["percent"] = percent
+	}
+
+	const query = `mutation UpsertProgress(
+		$sessionKey: String!,
+		$workspace: String,
+		$key: String!,
+		$message: String!,
+		$percent: Int,
+		$done: Boolean
+	) {
+		upsertProgress(
+			sessionKey: $sessionKey,
+			workspace: $workspace,
+			key: $key,
+			message: $message,
+			percent: $percent,
+			done: $done
+		) {
+			key
+			message
+			percent
+			completedAt
+			updatedAt
+		}
+	}`
+
+	data, err := gqlClient.Do(query, variables)
+	if err != nil {
+		return fmt.Errorf("update progress: %w", err)
+	}
+
+	var result struct {
+		UpsertProgress output.Progress `json:"upsertProgress"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.UpsertProgress)
+	} else {
+		output.PrintProgress(result.UpsertProgress)
+	}
+	return nil
+}
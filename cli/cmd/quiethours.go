@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var quietHoursCmd = &cobra.Command{
+	Use:   "quiet-hours",
+	Short: "Hold notifications every night",
+	RunE:  runQuietHoursShow,
+}
+
+var quietHoursSetCmd = &cobra.Command{
+	Use:   "set <HH:MM-HH:MM>",
+	Short: "Set daily quiet hours, e.g. 22:00-07:00",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runQuietHoursSet,
+}
+
+var quietHoursOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Remove quiet hours",
+	RunE:  runQuietHoursOff,
+}
+
+func init() {
+	quietHoursSetCmd.Flags().String("tz", "", "IANA timezone, e.g. America/Los_Angeles (default unchanged)")
+	quietHoursSetCmd.Flags().Int("breakthrough", 0, "Lowest priority that still gets through (1-5, default unchanged)")
+
+	quietHoursCmd.AddCommand(quietHoursSetCmd)
+	quietHoursCmd.AddCommand(quietHoursOffCmd)
+	rootCmd.AddCommand(quietHoursCmd)
+}
+
+var quietRangeRe = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+
+// parseQuietRange splits "22:00-07:00" into its start and end.
+func parseQuietRange(s string) (string, string, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok || !quietRangeRe.MatchString(start) || !quietRangeRe.MatchString(end) {
+		return "", "", fmt.Errorf("quiet hours must look like 22:00-07:00, got %q", s)
+	}
+	if start == end {
+		return "", "", fmt.Errorf("quiet hours start and end must differ")
+	}
+	return start, end, nil
+}
+
+type quietHoursSettings struct {
+	Timezone        *string              `json:"timezone"`
+	QuietHoursStart *string              `json:"quietHoursStart"`
+	QuietHoursEnd   *string              `json:"quietHoursEnd"`
+	DoNotDisturb    *output.DoNotDisturb `json:"doNotDisturb"`
+	Preferences     struct {
+		BreakthroughPriority int `json:"breakthroughPriority"`
+	} `json:"preferences"`
+}
+
+const quietHoursFields = `
+	timezone
+	quietHoursStart
+	quietHoursEnd
+	preferences {
+		breakthroughPriority
+	}` + holdFields
+
+func printQuietHours(s quietHoursSettings) {
+	if jsonFlag {
+		output.PrintJSON(s)
+		return
+	}
+	tz := "UTC"
+	if s.Timezone != nil && *s.Timezone != "" {
+		tz = *s.Timezone
+	}
+	if s.QuietHoursStart == nil || s.QuietHoursEnd == nil {
+		fmt.Printf("Quiet hours: off (timezone %s)\n", tz)
+	} else {
+		fmt.Printf("Quiet hours: %s-%s %s (P%d breaks through)\n",
+			clockMinutes(*s.QuietHoursStart), clockMinutes(*s.QuietHoursEnd), tz, s.Preferences.BreakthroughPriority)
+	}
+	if s.DoNotDisturb != nil {
+		fmt.Println(output.DescribeDND(*s.DoNotDisturb))
+	}
+}
+
+func updateQuietHours(vars map[string]any) error {
+	query := `mutation UpdateQuietHours(
+		$timezone: String,
+		$quietHoursStart: String,
+		$quietHoursEnd: String,
+		$clearQuietHours: Boolean
+	) {
+		updateSettings(
+			timezone: $timezone,
+			quietHoursStart: $quietHoursStart,
+			quietHoursEnd: $quietHoursEnd,
+			clearQuietHours: $clearQuietHours
+		) {` + quietHoursFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, vars)
+	if err != nil {
+		return fmt.Errorf("update quiet hours: %w", err)
+	}
+
+	var result struct {
+		UpdateSettings quietHoursSettings `json:"updateSettings"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	printQuietHours(result.UpdateSettings)
+	return nil
+}
+
+func runQuietHoursShow(cmd *cobra.Command, args []string) error {
+	query := `query { me {` + quietHoursFields + `} }`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return fmt.Errorf("get quiet hours: %w", err)
+	}
+
+	var result struct {
+		Me *quietHoursSettings `json:"me"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if result.Me == nil {
+		return fmt.Errorf("not logged in (run: agentduty login)")
+	}
+	printQuietHours(*result.Me)
+	return nil
+}
+
+func runQuietHoursSet(cmd *cobra.Command, args []string) error {
+	tz, _ := cmd.Flags().GetString("tz")
+	breakthrough, _ := cmd.Flags().GetInt("breakthrough")
+
+	start, end, err := parseQuietRange(args[0])
+	if err != nil {
+		return err
+	}
+	if tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("unknown timezone %q", tz)
+		}
+	}
+
+	if cmd.Flags().Changed("breakthrough") {
+		if err := setBreakthrough(breakthrough); err != nil {
+			return err
+		}
+	}
+
+	vars := map[string]any{
+		"quietHoursStart": start,
+		"quietHoursEnd":   end,
+	}
+	if tz != "" {
+		vars["timezone"] = tz
+	}
+	return updateQuietHours(vars)
+}
+
+func runQuietHoursOff(cmd *cobra.Command, args []string) error {
+	return updateQuietHours(map[string]any{"clearQuietHours": true})
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var retractCmd = &cobra.Command{
+	Use:   "retract <shortCode>",
+	Short: "Withdraw a notification the agent no longer needs answered",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRetract,
+}
+
+func init() {
+	retractCmd.Flags().String("reason", "", "Why the question no longer needs an answer")
+	rootCmd.AddCommand(retractCmd)
+}
+
+func runRetract(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	reason, _ := cmd.Flags().GetString("reason")
+
+	const query = `mutation RetractNotification($id: String!, $reason: String) {
+		retractNotification(id: $id, reason: $reason) {
+			id
+			shortCode
+			status
+			priority
+			message
+			retractReason
+		}
+	}`
+
+	variables := map[string]any{"id": id}
+	if reason != "" {
+		variables["reason"] = reason
+	}
+
+	data, err := gqlClient.Do(query, variables)
+	if err != nil {
+		return fmt.Errorf("retract: %w", err)
+	}
+
+	var result struct {
+		RetractNotification *output.Notification `json:"retractNotification"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if result.RetractNotification == nil {
+		return fmt.Errorf("notification not found: %s", id)
+	}
+
+	n := *result.RetractNotification
+	if jsonFlag {
+		output.PrintJSON(n)
+	} else {
+		fmt.Printf("Retracted: %s (P%d) %s\n", n.ShortCode, n.Priority, truncateMsg(n.Message, 50))
+	}
+	return nil
+}
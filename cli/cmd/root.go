@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/sestinj/agentduty/cli/internal/client"
 	"github.com/sestinj/agentduty/cli/internal/config"
+	"github.com/sestinj/agentduty/cli/internal/ratelimit"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -41,8 +43,16 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// exitRateLimited is returned when the local per-session rate limit blocks
+// a send (EX_TEMPFAIL), so agents can tell "back off" apart from failures.
+const exitRateLimited = 75
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exceeded *ratelimit.ExceededError
+		if errors.As(err, &exceeded) {
+			os.Exit(exitRateLimited)
+		}
 		os.Exit(1)
 	}
 }
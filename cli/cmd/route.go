@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var routeCmd = &cobra.Command{
+	Use:     "route",
+	Aliases: []string{"routes"},
+	Short:   "Choose which escalation policy each priority uses",
+}
+
+var routeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the policy used for each priority",
+	RunE:  runRouteList,
+}
+
+var routeSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Route a priority to an escalation policy",
+	RunE:  runRouteSet,
+}
+
+var routeClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove a priority's route so it uses the default policy",
+	RunE:  runRouteClear,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{routeSetCmd, routeClearCmd} {
+		c.Flags().IntP("priority", "p", 0, "Priority level (1-5) (required)")
+		c.MarkFlagRequired("priority")
+	}
+	routeSetCmd.Flags().String("policy", "", "Escalation policy name (required)")
+	routeSetCmd.MarkFlagRequired("policy")
+	routeSetCmd.Flags().Bool("dry-run", false, "Print the timeline this priority would get without saving")
+
+	routeCmd.AddCommand(routeListCmd)
+	routeCmd.AddCommand(routeSetCmd)
+	routeCmd.AddCommand(routeClearCmd)
+	rootCmd.AddCommand(routeCmd)
+}
+
+func runRouteList(cmd *cobra.Command, args []string) error {
+	query := `query {
+		priorityRoutes {
+			priority
+			policy {` + escalationPolicyFields + `}
+		}
+	}`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return fmt.Errorf("list routes: %w", err)
+	}
+
+	var result struct {
+		PriorityRoutes []output.PriorityRoute `json:"priorityRoutes"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.PriorityRoutes)
+		return nil
+	}
+
+	policies, err := fetchEscalationPolicies()
+	if err != nil {
+		return err
+	}
+	var defaultPolicy *output.EscalationPolicy
+	for i, p := range policies {
+		if p.IsDefault {
+			defaultPolicy = &policies[i]
+		}
+	}
+	output.PrintPriorityRoutes(result.PriorityRoutes, defaultPolicy)
+	return nil
+}
+
+func runRouteSet(cmd *cobra.Command, args []string) error {
+	priority, _ := cmd.Flags().GetInt("priority")
+	policyName, _ := cmd.Flags().GetString("policy")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if priority < 1 || priority > 5 {
+		return fmt.Errorf("--priority must be between 1 and 5")
+	}
+
+	if dryRun {
+		policy, err := fetchEscalationPolicy(policyName)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("P%d notifications would escalate via %s:\n\n", priority, policy.Name)
+		output.PrintEscalationTimeline(policy.Steps)
+		return nil
+	}
+
+	query := `mutation SetPriorityRoute($priority: Int!, $policy: String) {
+		setPriorityRoute(priority: $priority, policy: $policy) {
+			priority
+			policy {` + escalationPolicyFields + `}
+		}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{
+		"priority": priority,
+		"policy":   policyName,
+	})
+	if err != nil {
+		return fmt.Errorf("set route: %w", err)
+	}
+
+	var result struct {
+		SetPriorityRoute *output.PriorityRoute `json:"setPriorityRoute"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.SetPriorityRoute)
+	} else {
+		fmt.Printf("P%d now escalates via %s\n", priority, policyName)
+	}
+	return nil
+}
+
+func runRouteClear(cmd *cobra.Command, args []string) error {
+	priority, _ := cmd.Flags().GetInt("priority")
+	if priority < 1 || priority > 5 {
+		return fmt.Errorf("--priority must be between 1 and 5")
+	}
+
+	const query = `mutation ClearPriorityRoute($priority: Int!) {
+		setPriorityRoute(priority: $priority) {
+			priority
+		}
+	}`
+
+	if _, err := gqlClient.Do(query, map[string]any{"priority": priority}); err != nil {
+		return fmt.Errorf("clear route: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(map[string]any{"priority": priority, "policy": nil})
+	} else {
+		fmt.Printf("P%d now uses the default policy\n", priority)
+	}
+	return nil
+}
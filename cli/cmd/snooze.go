@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/sestinj/agentduty/cli/internal/snooze"
+	"github.com/spf13/cobra"
+)
+
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze <id> <when>",
+	Short: "Hide a notification from the feed for a while",
+	Long: `Snooze a notification until a time. It leaves the feed and comes back when
+the snooze ends.
+
+<when> is a duration (45m, 2h, 1h30m, 3d, "an hour"), a clock time
+("until 14:30", 9am; past times mean tomorrow) or a day, optionally with a
+time (tomorrow, tonight, eod, mon, "next week", "fri 10am"). Days alone mean
+09:00.
+
+  agentduty snooze ABC 45m
+  agentduty snooze ABC until 14:30
+  agentduty snooze ABC tomorrow`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runSnooze,
+}
+
+var snoozeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snoozed notifications",
+	Args:  cobra.NoArgs,
+	RunE:  runSnoozeList,
+}
+
+var snoozeCancelCmd = &cobra.Command{
+	Use:   "cancel <id>",
+	Short: "Un-snooze a notification so it's back in the feed now",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSnoozeCancel,
+}
+
+func init() {
+	snoozeCmd.AddCommand(snoozeListCmd)
+	snoozeCmd.AddCommand(snoozeCancelCmd)
+	rootCmd.AddCommand(snoozeCmd)
+}
+
+const snoozeFields = `
+	id
+	shortCode
+	status
+	priority
+	message
+	createdAt
+	snoozedUntil`
+
+func runSnooze(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	until, err := snooze.Parse(strings.Join(args[1:], " "), time.Now())
+	if err != nil {
+		return err
+	}
+
+	query := `mutation SnoozeNotification($id: String!, $until: String!) {
+		snoozeNotification(id: $id, until: $until) {` + snoozeFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"id": id, "until": until.UTC().Format(time.RFC3339)})
+	if err != nil {
+		return fmt.Errorf("snooze: %w", err)
+	}
+
+	var result struct {
+		SnoozeNotification *output.Notification `json:"snoozeNotification"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if result.SnoozeNotification == nil {
+		return fmt.Errorf("notification not found: %s", id)
+	}
+
+	n := *result.SnoozeNotification
+	if jsonFlag {
+		output.PrintJSON(n)
+	} else {
+		fmt.Printf("Snoozed %s until %s: %s\n", n.ShortCode, snooze.Describe(until, time.Now()), truncateMsg(n.Message, 50))
+	}
+	return nil
+}
+
+func runSnoozeList(cmd *cobra.Command, args []string) error {
+	query := `query SnoozedNotifications {
+		snoozedNotifications {` + snoozeFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return fmt.Errorf("list snoozed: %w", err)
+	}
+
+	var result struct {
+		SnoozedNotifications []output.Notification `json:"snoozedNotifications"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.SnoozedNotifications)
+	} else {
+		output.PrintSnoozed(result.SnoozedNotifications)
+	}
+	return nil
+}
+
+func runSnoozeCancel(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	query := `mutation UnsnoozeNotification($id: String!) {
+		unsnoozeNotification(id: $id) {` + snoozeFields + `}
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"id": id})
+	if err != nil {
+		return fmt.Errorf("unsnooze: %w", err)
+	}
+
+	var result struct {
+		UnsnoozeNotification *output.Notification `json:"unsnoozeNotification"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if result.UnsnoozeNotification == nil {
+		return fmt.Errorf("notification not found: %s", id)
+	}
+
+	n := *result.UnsnoozeNotification
+	if jsonFlag {
+		output.PrintJSON(n)
+	} else {
+		fmt.Printf("Back in the feed: %s (P%d) %s\n", n.ShortCode, n.Priority, truncateMsg(n.Message, 50))
+	}
+	return nil
+}
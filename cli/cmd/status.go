@@ -3,6 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sestinj/agentduty/cli/internal/output"
 	"github.com/spf13/cobra"
@@ -10,42 +15,195 @@ import (
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
-	Short: "List active notifications",
-	RunE:  runStatus,
+	Short: "List and search notifications",
+	Long: `List your notifications, newest first. Filters combine:
+
+  agentduty status --status pending,delivered --priority '>=4'
+  agentduty status --tag deploy --since 2h --search migration
+  agentduty status --workspace . --sort priority --limit 20
+
+--priority takes N, >=N, <=N, >N, <N or a range like 3-5. Every --tag must
+be present. When more results exist, the next page's --cursor is printed
+to stderr; --json still prints just the array of notifications.`,
+	RunE: runStatus,
 }
 
 func init() {
+	statusCmd.Flags().StringSlice("status", nil, "Only these statuses (pending, delivered, responded, expired, archived, retracted)")
+	statusCmd.Flags().String("priority", "", "Priority filter, e.g. 5, >=4, <=2 or 3-5")
+	statusCmd.Flags().StringSlice("tag", nil, "Only notifications with all of these tags")
+	statusCmd.Flags().StringP("session", "s", "", "Only this session")
+	statusCmd.Flags().StringP("workspace", "w", "", "Only sessions in this workspace path")
+	statusCmd.Flags().String("since", "", "Only notifications created since a duration ago (e.g. 2h) or an RFC 3339 time")
+	statusCmd.Flags().String("search", "", "Case-insensitive text to find in the message")
+	statusCmd.Flags().String("sort", "newest", "Sort by newest, oldest or priority")
+	statusCmd.Flags().Int("limit", 50, "Maximum notifications to show")
+	statusCmd.Flags().String("cursor", "", "Continue from a previous page")
+
 	rootCmd.AddCommand(statusCmd)
 }
 
-func runStatus(cmd *cobra.Command, args []string) error {
-	const query = `query ListNotifications($status: String) {
-		notifications(status: $status) {
+const notificationPageQuery = `query NotificationPage(
+	$statuses: [String!], $minPriority: Int, $maxPriority: Int, $tags: [String!],
+	$sessionKey: String, $workspace: String, $since: String, $search: String,
+	$sort: String, $limit: Int, $cursor: String
+) {
+	notificationPage(
+		statuses: $statuses, minPriority: $minPriority, maxPriority: $maxPriority, tags: $tags,
+		sessionKey: $sessionKey, workspace: $workspace, since: $since, search: $search,
+		sort: $sort, limit: $limit, cursor: $cursor
+	) {
+		notifications {
 			id
 			shortCode
 			status
 			priority
 			message
+			tags
 			createdAt
 		}
-	}`
+		nextCursor
+	}
+	me {` + holdFields + `}
+}`
 
-	data, err := gqlClient.Do(query, map[string]any{})
+func runStatus(cmd *cobra.Command, args []string) error {
+	statuses, _ := cmd.Flags().GetStringSlice("status")
+	priority, _ := cmd.Flags().GetString("priority")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	session, _ := cmd.Flags().GetString("session")
+	workspace, _ := cmd.Flags().GetString("workspace")
+	since, _ := cmd.Flags().GetString("since")
+	search, _ := cmd.Flags().GetString("search")
+	sort, _ := cmd.Flags().GetString("sort")
+	limit, _ := cmd.Flags().GetInt("limit")
+	cursor, _ := cmd.Flags().GetString("cursor")
+
+	vars := map[string]any{"sort": sort, "limit": limit}
+	if len(statuses) > 0 {
+		vars["statuses"] = statuses
+	}
+	if priority != "" {
+		minP, maxP, err := parsePriorityFilter(priority)
+		if err != nil {
+			return err
+		}
+		if minP > 1 {
+			vars["minPriority"] = minP
+		}
+		if maxP < 5 {
+			vars["maxPriority"] = maxP
+		}
+	}
+	if len(tags) > 0 {
+		vars["tags"] = tags
+	}
+	if session != "" {
+		vars["sessionKey"] = session
+	}
+	if workspace != "" {
+		abs, err := filepath.Abs(workspace)
+		if err != nil {
+			return fmt.Errorf("resolve workspace: %w", err)
+		}
+		vars["workspace"] = abs
+	}
+	if since != "" {
+		t, err := parseSince(since, time.Now())
+		if err != nil {
+			return err
+		}
+		vars["since"] = t.Format(time.RFC3339)
+	}
+	if search != "" {
+		vars["search"] = search
+	}
+	if cursor != "" {
+		vars["cursor"] = cursor
+	}
+
+	data, err := gqlClient.Do(notificationPageQuery, vars)
 	if err != nil {
 		return fmt.Errorf("query notifications: %w", err)
 	}
 
 	var result struct {
-		Notifications []output.Notification `json:"notifications"`
+		NotificationPage struct {
+			Notifications []output.Notification `json:"notifications"`
+			NextCursor    *string               `json:"nextCursor"`
+		} `json:"notificationPage"`
+		Me *struct {
+			DoNotDisturb *output.DoNotDisturb `json:"doNotDisturb"`
+		} `json:"me"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
 		return fmt.Errorf("parse response: %w", err)
 	}
 
+	page := result.NotificationPage
+	var hold *output.DoNotDisturb
+	if result.Me != nil {
+		hold = result.Me.DoNotDisturb
+	}
+	// Say why nothing is arriving before listing what's waiting. With --json
+	// the list stays a bare array, so the hold and cursor go to stderr.
+	if hold != nil {
+		line := fmt.Sprintf("%s — pushes below P%d are held.", output.DescribeDND(*hold), hold.BreakthroughPriority)
+		if jsonFlag {
+			fmt.Fprintln(os.Stderr, line)
+		} else {
+			fmt.Printf("%s\n\n", line)
+		}
+	}
 	if jsonFlag {
-		output.PrintJSON(result.Notifications)
+		output.PrintJSON(page.Notifications)
 	} else {
-		output.PrintNotifications(result.Notifications)
+		output.PrintNotifications(page.Notifications)
+	}
+	if page.NextCursor != nil {
+		fmt.Fprintf(os.Stderr, "More results: --cursor %s\n", *page.NextCursor)
 	}
 	return nil
 }
+
+// parsePriorityFilter turns "5", ">=4", "<=2", ">3", "<3" or "3-5" into an
+// inclusive priority range.
+func parsePriorityFilter(s string) (minP, maxP int, err error) {
+	s = strings.ReplaceAll(s, " ", "")
+	num := func(v string) (int, error) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 5 {
+			return 0, fmt.Errorf("invalid --priority %q: priorities are 1-5", s)
+		}
+		return n, nil
+	}
+
+	minP, maxP = 1, 5
+	switch {
+	case strings.HasPrefix(s, ">="):
+		minP, err = num(s[2:])
+	case strings.HasPrefix(s, "<="):
+		maxP, err = num(s[2:])
+	case strings.HasPrefix(s, ">"):
+		minP, err = num(s[1:])
+		minP++
+	case strings.HasPrefix(s, "<"):
+		maxP, err = num(s[1:])
+		maxP--
+	case strings.Contains(s, "-"):
+		lo, hi, _ := strings.Cut(s, "-")
+		if minP, err = num(lo); err == nil {
+			maxP, err = num(hi)
+		}
+	default:
+		minP, err = num(s)
+		maxP = minP
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if minP > maxP {
+		return 0, 0, fmt.Errorf("invalid --priority %q: matches nothing", s)
+	}
+	return minP, maxP, nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+func TestParsePriorityFilter(t *testing.T) {
+	tests := []struct {
+		in         string
+		minP, maxP int
+	}{
+		{"5", 5, 5},
+		{">=4", 4, 5},
+		{"<=2", 1, 2},
+		{">3", 4, 5},
+		{"<3", 1, 2},
+		{"3-5", 3, 5},
+		{" 2 - 4 ", 2, 4},
+	}
+	for _, tt := range tests {
+		minP, maxP, err := parsePriorityFilter(tt.in)
+		if err != nil || minP != tt.minP || maxP != tt.maxP {
+			t.Errorf("parsePriorityFilter(%q) = %d, %d, %v; want %d, %d", tt.in, minP, maxP, err, tt.minP, tt.maxP)
+		}
+	}
+}
+
+func TestParsePriorityFilter_Invalid(t *testing.T) {
+	for _, in := range []string{"", "0", "6", ">=9", "high", "5-3", ">5", "<1", "2-"} {
+		if _, _, err := parsePriorityFilter(in); err == nil {
+			t.Errorf("parsePriorityFilter(%q): expected an error", in)
+		}
+	}
+}
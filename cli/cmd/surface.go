@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var surfaceCmd = &cobra.Command{
+	Use:   "surface <slack|discord|teams>",
+	Short: "Choose which chat app a session's thread goes to",
+	Long: `Route a session's notifications to Slack, Discord or Teams. Without a
+choice, sessions use the first connected app (Slack, then Discord, then Teams).
+The chosen app must be connected (see: agentduty channels).`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"slack", "discord", "teams"},
+	RunE:      runSurface,
+}
+
+func init() {
+	surfaceCmd.Flags().StringP("session", "s", "", "Session ID (default: this workspace's session)")
+	surfaceCmd.Flags().StringP("workspace", "w", "", "Workspace path (default $PWD)")
+	rootCmd.AddCommand(surfaceCmd)
+}
+
+func runSurface(cmd *cobra.Command, args []string) error {
+	surface := args[0]
+	session, _ := cmd.Flags().GetString("session")
+	workspace, _ := cmd.Flags().GetString("workspace")
+
+	if workspace == "" {
+		workspace = resolveWorkspace()
+	}
+	if session == "" {
+		session = generateSession(workspace)
+	}
+
+	query := `mutation SetSessionSurface($sessionKey: String!, $surface: String!, $workspace: String) {
+		setSessionSurface(sessionKey: $sessionKey, surface: $surface, workspace: $workspace)
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{
+		"sessionKey": session,
+		"surface":    surface,
+		"workspace":  workspace,
+	})
+	if err != nil {
+		return fmt.Errorf("set surface: %w", err)
+	}
+
+	var result struct {
+		SetSessionSurface string `json:"setSessionSurface"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	fmt.Printf("Session %s now posts to %s.\n", session, result.SetSessionSurface)
+	return nil
+}
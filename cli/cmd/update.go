@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update <shortCode>",
+	Short: "Edit the message or options of a sent notification",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUpdate,
+}
+
+func init() {
+	addUpdateFlags(updateCmd)
+	rootCmd.AddCommand(updateCmd)
+}
+
+func addUpdateFlags(c *cobra.Command) {
+	c.Flags().StringP("message", "m", "", "New notification message")
+	c.Flags().StringArrayP("options", "o", nil, "New response options (replaces existing)")
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	message, _ := cmd.Flags().GetString("message")
+	options, _ := cmd.Flags().GetStringArray("options")
+
+	if message == "" && !cmd.Flags().Changed("options") {
+		return fmt.Errorf("nothing to update (use -m and/or -o)")
+	}
+
+	const query = `mutation UpdateNotification($id: String!, $message: String, $options: [String!]) {
+		updateNotification(id: $id, message: $message, options: $options) {
+			id
+			shortCode
+			status
+			priority
+			message
+			options
+			editedAt
+		}
+	}`
+
+	variables := map[string]any{"id": id}
+	if message != "" {
+		variables["message"] = message
+	}
+	if cmd.Flags().Changed("options") {
+		variables["options"] = options
+	}
+
+	data, err := gqlClient.Do(query, variables)
+	if err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	var result struct {
+		UpdateNotification *output.Notification `json:"updateNotification"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if result.UpdateNotification == nil {
+		return fmt.Errorf("notification not found: %s", id)
+	}
+
+	n := *result.UpdateNotification
+	if jsonFlag {
+		output.PrintJSON(n)
+	} else {
+		fmt.Printf("Updated: %s (P%d) %s\n", n.ShortCode, n.Priority, truncateMsg(n.Message, 50))
+	}
+	return nil
+}
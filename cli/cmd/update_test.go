@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// fakeUpdateAPI answers every GraphQL request with reply and records the
+// variables of the last one.
+func fakeUpdateAPI(t *testing.T, reply string) *map[string]any {
+	t.Helper()
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		got = req.Variables
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AGENTDUTY_API_KEY", "")
+	prev := gqlClient
+	gqlClient = client.New(server.URL, &config.Config{})
+	t.Cleanup(func() { gqlClient = prev })
+	return &got
+}
+
+func runUpdateWith(t *testing.T, args ...string) error {
+	t.Helper()
+	c := &cobra.Command{RunE: runUpdate, Args: cobra.ExactArgs(1)}
+	addUpdateFlags(c)
+	c.SetArgs(args)
+	c.SilenceUsage = true
+	c.SilenceErrors = true
+	return c.Execute()
+}
+
+const updatedReply = `{"data":{"updateNotification":{"id":"n1","shortCode":"ABC","status":"delivered","priority":3,"message":"new"}}}`
+
+func TestUpdate_NothingToUpdate(t *testing.T) {
+	got := fakeUpdateAPI(t, updatedReply)
+	err := runUpdateWith(t, "ABC")
+	if err == nil || !strings.Contains(err.Error(), "nothing to update") {
+		t.Fatalf("expected a nothing-to-update error, got %v", err)
+	}
+	if *got != nil {
+		t.Errorf("expected no request, sent %v", *got)
+	}
+}
+
+func TestUpdate_SendsOnlyWhatChanged(t *testing.T) {
+	got := fakeUpdateAPI(t, updatedReply)
+	if err := runUpdateWith(t, "ABC", "-m", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if (*got)["id"] != "ABC" || (*got)["message"] != "new" {
+		t.Errorf("unexpected variables %v", *got)
+	}
+	if _, ok := (*got)["options"]; ok {
+		t.Errorf("options must not be sent unless -o is given, got %v", *got)
+	}
+
+	if err := runUpdateWith(t, "ABC", "-o", "yes", "-o", "no"); err != nil {
+		t.Fatal(err)
+	}
+	opts, _ := (*got)["options"].([]any)
+	if len(opts) != 2 || opts[0] != "yes" || opts[1] != "no" {
+		t.Errorf("expected the new options, got %v", *got)
+	}
+	if _, ok := (*got)["message"]; ok {
+		t.Errorf("message must not be sent unless -m is given, got %v", *got)
+	}
+}
+
+func TestUpdate_Errors(t *testing.T) {
+	fakeUpdateAPI(t, `{"data":{"updateNotification":null}}`)
+	if err := runUpdateWith(t, "ZZZ", "-m", "x"); err == nil || !strings.Contains(err.Error(), "notification not found: ZZZ") {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	fakeUpdateAPI(t, `{"errors":[{"message":"Cannot update a notification that has a response"}]}`)
+	if err := runUpdateWith(t, "ABC", "-m", "x"); err == nil || !strings.Contains(err.Error(), "has a response") {
+		t.Errorf("expected the server's refusal, got %v", err)
+	}
+}
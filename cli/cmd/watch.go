@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/config"
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/sestinj/agentduty/cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Raise desktop and terminal alerts for new notifications",
+	Long: `Poll the feed and alert when a new notification arrives: a desktop
+notification through org.freedesktop.Notifications (Linux, via gdbus), and
+optionally a terminal bell or an OSC 9/777 escape sequence that the terminal
+turns into a system notification.
+
+Notifications below --min-priority never alert. During --quiet-hours only
+--breakthrough priority and above do. While do-not-disturb or the account's
+quiet hours hold pushes, only the account's breakthrough priority and above
+alert (see 'agentduty dnd'), unless --ignore-dnd is set.
+
+  agentduty watch --min-priority 3 --quiet-hours 22:00-07:00
+  agentduty watch --no-desktop --bell --osc 777
+  agentduty watch --detach    # keep watching in the background
+  agentduty watch stop`,
+	RunE: runWatch,
+}
+
+var watchStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop a background watch",
+	RunE:  runWatchStop,
+}
+
+func init() {
+	watchCmd.Flags().Duration("interval", 5*time.Second, "Polling interval")
+	watchCmd.Flags().Int("min-priority", 1, "Lowest priority that alerts (1-5)")
+	watchCmd.Flags().String("quiet-hours", "", "Local hours with only breakthrough alerts, e.g. 22:00-07:00")
+	watchCmd.Flags().Int("breakthrough", 5, "Lowest priority that alerts during --quiet-hours")
+	watchCmd.Flags().Bool("ignore-dnd", false, "Alert even while do-not-disturb or the account's quiet hours hold pushes")
+	watchCmd.Flags().Bool("no-desktop", false, "Don't raise desktop notifications")
+	watchCmd.Flags().Duration("expire", 0, "How long desktop notifications stay up (default: the desktop's choice)")
+	watchCmd.Flags().Bool("bell", false, "Ring the terminal bell")
+	watchCmd.Flags().String("osc", "", "Also send a terminal notification escape sequence: 9 or 777")
+	watchCmd.Flags().Bool("all", false, "Also alert for notifications already pending at start")
+	watchCmd.Flags().Bool("detach", false, "Run in the background, logging to ~/.agentduty/watch.log (not on Windows)")
+
+	watchCmd.AddCommand(watchStopCmd)
+	rootCmd.AddCommand(watchCmd)
+}
+
+const watchQuery = `query Watch {
+	activeFeed {
+		id
+		shortCode
+		message
+		priority
+		options
+		workspace
+		createdAt
+	}
+	me {` + holdFields + `
+	}
+}`
+
+// watchChildEnv marks the background process started by --detach.
+const watchChildEnv = "AGENTDUTY_WATCH_CHILD"
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	minPriority, _ := cmd.Flags().GetInt("min-priority")
+	quietHours, _ := cmd.Flags().GetString("quiet-hours")
+	breakthrough, _ := cmd.Flags().GetInt("breakthrough")
+	ignoreDND, _ := cmd.Flags().GetBool("ignore-dnd")
+	noDesktop, _ := cmd.Flags().GetBool("no-desktop")
+	expire, _ := cmd.Flags().GetDuration("expire")
+	bell, _ := cmd.Flags().GetBool("bell")
+	osc, _ := cmd.Flags().GetString("osc")
+	all, _ := cmd.Flags().GetBool("all")
+	detach, _ := cmd.Flags().GetBool("detach")
+
+	if minPriority < 1 || minPriority > 5 {
+		return fmt.Errorf("--min-priority must be between 1 and 5")
+	}
+	if breakthrough < 1 || breakthrough > 5 {
+		return fmt.Errorf("--breakthrough must be between 1 and 5")
+	}
+	if osc != "" && osc != "9" && osc != "777" {
+		return fmt.Errorf("--osc must be 9 or 777, got %q", osc)
+	}
+	policy := watch.Policy{MinPriority: minPriority, Breakthrough: breakthrough, IgnoreHold: ignoreDND}
+	if quietHours != "" {
+		start, end, err := parseQuietRange(quietHours)
+		if err != nil {
+			return err
+		}
+		policy.Quiet = &watch.QuietHours{Start: start, End: end}
+	}
+
+	child := os.Getenv(watchChildEnv) != ""
+	if detach && !child && (bell || osc != "") {
+		return fmt.Errorf("--bell and --osc need a terminal; drop --detach to use them")
+	}
+	if pid, ok := runningWatchPid(); ok {
+		return fmt.Errorf("watch is already running (pid %d); stop it with `agentduty watch stop`", pid)
+	}
+
+	var alerters []watch.Alerter
+	if !noDesktop {
+		if err := desktopAvailable(); err != nil {
+			fmt.Fprintf(os.Stderr, "Desktop notifications unavailable: %v\n", err)
+		} else {
+			alerters = append(alerters, watch.Desktop{Bus: watch.GDBus{}, Expire: expire})
+		}
+	}
+	if bell || osc != "" {
+		alerters = append(alerters, watch.Terminal{W: os.Stdout, Bell: bell, OSC: osc})
+	}
+	if len(alerters) == 0 {
+		return fmt.Errorf("nothing to alert with; use --bell or --osc")
+	}
+
+	if detach && !child {
+		return detachWatch()
+	}
+
+	writeWatchPid()
+	defer removeWatchPid()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	w := &watch.Watcher{Alerters: alerters, Policy: policy, AlertExisting: all}
+	fmt.Fprintf(os.Stderr, "Watching for new notifications every %s (Ctrl-C to stop).\n", interval)
+
+	for {
+		items, hold, err := fetchWatchFeed()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Error: %v\n", time.Now().Format("15:04:05"), err)
+		} else {
+			alerted, err := w.Observe(ctx, items, hold, time.Now())
+			for _, it := range alerted {
+				printWatchAlert(it)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s Error: %v\n", time.Now().Format("15:04:05"), err)
+			}
+		}
+
+		if !sleepCtx(ctx, interval) {
+			return nil
+		}
+	}
+}
+
+func fetchWatchFeed() ([]watch.Item, *output.DoNotDisturb, error) {
+	data, err := gqlClient.Do(watchQuery, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query feed: %w", err)
+	}
+
+	var result struct {
+		ActiveFeed []watch.Item `json:"activeFeed"`
+		Me         *struct {
+			DoNotDisturb *output.DoNotDisturb `json:"doNotDisturb"`
+		} `json:"me"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, nil, fmt.Errorf("parse response: %w", err)
+	}
+	var hold *output.DoNotDisturb
+	if result.Me != nil {
+		hold = result.Me.DoNotDisturb
+	}
+	return result.ActiveFeed, hold, nil
+}
+
+// printWatchAlert logs one alert, as a JSON line with --json.
+func printWatchAlert(it watch.Item) {
+	if jsonFlag {
+		line, _ := json.Marshal(it)
+		fmt.Println(string(line))
+		return
+	}
+	msg, _, _ := strings.Cut(it.Message, "\n")
+	fmt.Printf("%s %s  %s\n", time.Now().Format("15:04:05"), it.Title(), msg)
+}
+
+// desktopAvailable reports why desktop notifications can't be raised here,
+// if they can't.
+func desktopAvailable() error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("only supported on Linux; use --bell or --osc")
+	}
+	if _, err := exec.LookPath("gdbus"); err != nil {
+		return fmt.Errorf("gdbus not found (install GLib's tools, e.g. libglib2.0-bin)")
+	}
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" && os.Getenv("XDG_RUNTIME_DIR") == "" {
+		return fmt.Errorf("no D-Bus session bus")
+	}
+	return nil
+}
+
+// detachWatch restarts this command in a new session with its output going
+// to the watch log, and returns once it has started.
+func detachWatch() error {
+	attr, err := detachAttr()
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("find executable: %w", err)
+	}
+	logPath := filepath.Join(config.ConfigDir(), "watch.log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("open watch log: %w", err)
+	}
+	defer logFile.Close()
+
+	c := exec.Command(exe, os.Args[1:]...)
+	c.Env = append(os.Environ(), watchChildEnv+"=1")
+	c.Stdout = logFile
+	c.Stderr = logFile
+	c.SysProcAttr = attr
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("start watch: %w", err)
+	}
+	fmt.Printf("Watching in the background (pid %d), logging to %s.\nStop with: agentduty watch stop\n", c.Process.Pid, logPath)
+	return c.Process.Release()
+}
+
+func runWatchStop(cmd *cobra.Command, args []string) error {
+	pid, ok := runningWatchPid()
+	if !ok {
+		fmt.Println("No watch is running.")
+		return nil
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("find watch process: %w", err)
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		return fmt.Errorf("stop watch: %w", err)
+	}
+	fmt.Printf("Stopped watch (pid %d).\n", pid)
+	return nil
+}
+
+func watchPidPath() string {
+	return filepath.Join(config.ConfigDir(), "watch.pid")
+}
+
+func writeWatchPid() {
+	_ = os.MkdirAll(config.ConfigDir(), 0700)
+	_ = os.WriteFile(watchPidPath(), []byte(strconv.Itoa(os.Getpid())), 0644)
+}
+
+func removeWatchPid() {
+	_ = os.Remove(watchPidPath())
+}
+
+// runningWatchPid returns the pid of a live watch process, if there is one.
+func runningWatchPid() (int, bool) {
+	data, err := os.ReadFile(watchPidPath())
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid == os.Getpid() {
+		return 0, false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return 0, false
+	}
+	// Signal 0 checks if process exists without sending a signal.
+	if proc.Signal(syscall.Signal(0)) != nil {
+		return 0, false
+	}
+	return pid, true
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package cmd
+
+import "syscall"
+
+// detachAttr starts the background watch in a session of its own, so it
+// outlives the terminal that ran 'agentduty watch --detach'.
+func detachAttr() (*syscall.SysProcAttr, error) {
+	return &syscall.SysProcAttr{Setsid: true}, nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package cmd
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// detachAttr refuses on Windows: 'agentduty watch stop' finds and stops the
+// background watch with Unix signals, which Windows doesn't deliver.
+func detachAttr() (*syscall.SysProcAttr, error) {
+	return nil, fmt.Errorf("--detach is not supported on Windows; run 'agentduty watch' in its own terminal")
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+	"github.com/sestinj/agentduty/cli/pkg/webhook"
+	"github.com/spf13/cobra"
+)
+
+const webhookSubscriptionFields = `
+	id
+	url
+	events
+	lastDeliveryAt
+	lastStatus
+	lastError
+	createdAt`
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Send notification lifecycle events to your own endpoints",
+	Long: `Subscribe URLs to notification lifecycle events. Each request is a JSON
+POST signed with HMAC-SHA256 in the AgentDuty-Signature header
+("t=<unix seconds>,v1=<hex>" over "<t>.<body>").
+
+Events: notification.created, notification.updated, notification.retracted,
+notification.archived, notification.expired, response.created (or * for all).
+
+Go services can verify requests with github.com/sestinj/agentduty/cli/pkg/webhook;
+anything else can shell out to 'agentduty webhook verify'.`,
+}
+
+var webhookAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Subscribe a URL to events",
+	RunE:  runWebhookAdd,
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhook subscriptions",
+	RunE:  runWebhookList,
+}
+
+var webhookRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Delete a webhook subscription",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWebhookRemove,
+}
+
+var webhookTestCmd = &cobra.Command{
+	Use:   "test <id>",
+	Short: "Send a signed ping to a subscription",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWebhookTest,
+}
+
+var webhookVerifyCmd = &cobra.Command{
+	Use:   "verify [body-file]",
+	Short: "Check a webhook signature (body from a file or stdin)",
+	Long: `Verify that a request body was signed by AgentDuty. Exits non-zero if the
+signature does not match or is older than --tolerance.
+
+  agentduty webhook verify --signature "$HTTP_AGENTDUTY_SIGNATURE" < body.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWebhookVerify,
+}
+
+func init() {
+	webhookAddCmd.Flags().String("url", "", "Endpoint URL (required)")
+	webhookAddCmd.Flags().StringSlice("events", nil, "Events to send, comma-separated (required; * for all)")
+	webhookAddCmd.Flags().String("secret", "", "Signing secret (default: generated and shown once)")
+	webhookAddCmd.MarkFlagRequired("url")
+	webhookAddCmd.MarkFlagRequired("events")
+
+	webhookVerifyCmd.Flags().String("secret", "", "Signing secret (default $AGENTDUTY_WEBHOOK_SECRET)")
+	webhookVerifyCmd.Flags().String("signature", "", "Value of the AgentDuty-Signature header (required)")
+	webhookVerifyCmd.Flags().Duration("tolerance", webhook.DefaultTolerance, "Reject signatures older than this (0 to skip)")
+	webhookVerifyCmd.MarkFlagRequired("signature")
+
+	webhookCmd.AddCommand(webhookAddCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookRemoveCmd)
+	webhookCmd.AddCommand(webhookTestCmd)
+	webhookCmd.AddCommand(webhookVerifyCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+func runWebhookAdd(cmd *cobra.Command, args []string) error {
+	url, _ := cmd.Flags().GetString("url")
+	events, _ := cmd.Flags().GetStringSlice("events")
+	secret, _ := cmd.Flags().GetString("secret")
+
+	vars := map[string]any{"url": url, "events": events}
+	if secret != "" {
+		vars["secret"] = secret
+	}
+
+	query := `mutation CreateWebhookSubscription($url: String!, $events: [String!]!, $secret: String) {
+		createWebhookSubscription(url: $url, events: $events, secret: $secret) {` + webhookSubscriptionFields + `
+			secret
+		}
+	}`
+
+	data, err := gqlClient.Do(query, vars)
+	if err != nil {
+		return fmt.Errorf("add webhook: %w", err)
+	}
+
+	var result struct {
+		CreateWebhookSubscription output.WebhookSubscription `json:"createWebhookSubscription"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	sub := result.CreateWebhookSubscription
+	if secret != "" {
+		// Don't echo back a secret the user already has.
+		sub.Secret = ""
+	}
+	if jsonFlag {
+		output.PrintJSON(sub)
+	} else {
+		output.PrintWebhookCreated(sub)
+	}
+	return nil
+}
+
+func runWebhookList(cmd *cobra.Command, args []string) error {
+	query := `query { webhookSubscriptions {` + webhookSubscriptionFields + `} }`
+
+	data, err := gqlClient.Do(query, nil)
+	if err != nil {
+		return fmt.Errorf("list webhooks: %w", err)
+	}
+
+	var result struct {
+		WebhookSubscriptions []output.WebhookSubscription `json:"webhookSubscriptions"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	if jsonFlag {
+		output.PrintJSON(result.WebhookSubscriptions)
+	} else {
+		output.PrintWebhookSubscriptions(result.WebhookSubscriptions)
+	}
+	return nil
+}
+
+func runWebhookRemove(cmd *cobra.Command, args []string) error {
+	query := `mutation DeleteWebhookSubscription($id: String!) {
+		deleteWebhookSubscription(id: $id)
+	}`
+
+	if _, err := gqlClient.Do(query, map[string]any{"id": args[0]}); err != nil {
+		return fmt.Errorf("remove webhook: %w", err)
+	}
+
+	fmt.Printf("Removed webhook %s.\n", args[0])
+	return nil
+}
+
+func runWebhookTest(cmd *cobra.Command, args []string) error {
+	query := `mutation TestWebhookSubscription($id: String!) {
+		testWebhookSubscription(id: $id) { ok status error }
+	}`
+
+	data, err := gqlClient.Do(query, map[string]any{"id": args[0]})
+	if err != nil {
+		return fmt.Errorf("test webhook: %w", err)
+	}
+
+	var result struct {
+		TestWebhookSubscription output.WebhookTestResult `json:"testWebhookSubscription"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	r := result.TestWebhookSubscription
+	if jsonFlag {
+		output.PrintJSON(r)
+	} else {
+		output.PrintWebhookTestResult(r)
+	}
+	if !r.OK {
+		return fmt.Errorf("webhook test failed")
+	}
+	return nil
+}
+
+func runWebhookVerify(cmd *cobra.Command, args []string) error {
+	secret, _ := cmd.Flags().GetString("secret")
+	signature, _ := cmd.Flags().GetString("signature")
+	tolerance, _ := cmd.Flags().GetDuration("tolerance")
+
+	if secret == "" {
+		secret = os.Getenv("AGENTDUTY_WEBHOOK_SECRET")
+	}
+	if secret == "" {
+		return fmt.Errorf("--secret or AGENTDUTY_WEBHOOK_SECRET is required")
+	}
+
+	var in io.Reader = os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("open body: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	body, err := io.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+
+	if err := webhook.Verify(secret, strings.TrimSpace(signature), body, tolerance); err != nil {
+		return err
+	}
+
+	var event webhook.Event
+	if json.Unmarshal(body, &event) == nil && event.Event != "" {
+		fmt.Printf("Signature valid: %s (sent %s).\n", event.Event, event.SentAt.Local().Format(time.RFC3339))
+	} else {
+		fmt.Println("Signature valid.")
+	}
+	return nil
+}
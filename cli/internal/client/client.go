@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -103,6 +104,65 @@ func (c *Client) doRequest(query string, variables map[string]any) (json.RawMess
 	return gqlResp.Data, nil
 }
 
+// OpenStream opens a server-sent events stream at path on the API host
+// (e.g. "/api/responses/stream"). The caller closes the response body.
+func (c *Client) OpenStream(ctx context.Context, path string, params url.Values, lastEventID string) (*http.Response, error) {
+	resp, err := c.openStream(ctx, path, params, lastEventID)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && c.cfg.RefreshToken != "" {
+		resp.Body.Close()
+		if refreshErr := c.refreshToken(); refreshErr == nil {
+			resp, err = c.openStream(ctx, path, params, lastEventID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	return resp, nil
+}
+
+func (c *Client) openStream(ctx context.Context, path string, params url.Values, lastEventID string) (*http.Response, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse api url: %w", err)
+	}
+	u.Path = path
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	return resp, nil
+}
+
+// StatusError is returned by OpenStream when the server answers with a
+// non-200 status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("http %d: %s", e.StatusCode, e.Body)
+}
+
 func isAuthError(err error) bool {
 	msg := err.Error()
 	return strings.Contains(msg, "Unauthorized") || strings.Contains(msg, "Unexpected error")
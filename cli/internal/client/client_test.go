@@ -1,9 +1,12 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 
@@ -230,3 +233,49 @@ func TestIsAuthError(t *testing.T) {
 type testError struct{ msg string }
 
 func (e *testError) Error() string { return e.msg }
+
+func TestOpenStream_UsesAPIHost(t *testing.T) {
+	var gotPath, gotQuery, gotLastID, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		gotLastID = r.Header.Get("Last-Event-ID")
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "text/event-stream")
+	}))
+	defer server.Close()
+
+	os.Unsetenv("AGENTDUTY_API_KEY")
+	c := New(server.URL+"/api/graphql", &config.Config{AccessToken: "my-token"})
+
+	resp, err := c.OpenStream(context.Background(), "/api/responses/stream", url.Values{"session": {"s1"}}, "2025-01-01T00:00:00.000Z")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotPath != "/api/responses/stream" || gotQuery != "session=s1" {
+		t.Errorf("requested %s?%s", gotPath, gotQuery)
+	}
+	if gotLastID != "2025-01-01T00:00:00.000Z" {
+		t.Errorf("Last-Event-ID = %q", gotLastID)
+	}
+	if gotAuth != "Bearer my-token" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+}
+
+func TestOpenStream_ReturnsStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c := New(server.URL+"/api/graphql", &config.Config{})
+	_, err := c.OpenStream(context.Background(), "/api/responses/stream", nil, "")
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 StatusError, got %v", err)
+	}
+}
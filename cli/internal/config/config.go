@@ -1,16 +1,64 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
 )
 
 type Config struct {
 	APIUrl       string `mapstructure:"api_url" yaml:"api_url"`
 	AccessToken  string `mapstructure:"access_token" yaml:"access_token"`
 	RefreshToken string `mapstructure:"refresh_token" yaml:"refresh_token"`
+
+	RateLimit RateLimit `mapstructure:"rate_limit" yaml:"rate_limit"`
+
+	Feed FeedFilter `mapstructure:"feed" yaml:"feed"`
+	TUI  TUI        `mapstructure:"tui" yaml:"tui"`
+}
+
+// TUI configures the look and keys of the interactive feed. Keys maps an
+// action name (e.g. "archive") to the keys that trigger it, replacing that
+// action's keys from the Keymap preset.
+type TUI struct {
+	Keymap string              `mapstructure:"keymap" yaml:"keymap"` // "default", "vim" or "emacs"
+	Theme  string              `mapstructure:"theme" yaml:"theme"`   // "dark", "light" or "high-contrast"
+	Keys   map[string][]string `mapstructure:"keys" yaml:"keys"`
+
+	// ReplyLimit caps a reply's length in characters (default 500, -1 for
+	// no limit).
+	ReplyLimit int              `mapstructure:"reply_limit" yaml:"reply_limit"`
+	Canned     []CannedResponse `mapstructure:"canned" yaml:"canned"`
+}
+
+// CannedResponse is a stock reply the feed can insert into the reply box.
+// Text may use {shortCode}, {workspace} and {priority}.
+type CannedResponse struct {
+	Name string `mapstructure:"name" yaml:"name"`
+	Text string `mapstructure:"text" yaml:"text"`
+}
+
+// FeedFilter is the search, filter and grouping state of the feed TUI,
+// kept between runs. Zero values mean "show everything, ungrouped".
+type FeedFilter struct {
+	Search      string   `mapstructure:"search" yaml:"search"`
+	MinPriority int      `mapstructure:"min_priority" yaml:"min_priority"`
+	Tags        []string `mapstructure:"tags" yaml:"tags"`
+	Workspace   string   `mapstructure:"workspace" yaml:"workspace"`
+	GroupBy     string   `mapstructure:"group_by" yaml:"group_by"` // "", "workspace" or "session"
+}
+
+// RateLimit caps how many notifications one agent session may send within
+// a sliding window. PerSession <= 0 disables the limit.
+type RateLimit struct {
+	PerSession int           `mapstructure:"per_session" yaml:"per_session"`
+	Window     time.Duration `mapstructure:"window" yaml:"window"`
 }
 
 func ConfigDir() string {
@@ -33,6 +81,8 @@ func Load() (*Config, error) {
 	viper.AddConfigPath(dir)
 
 	viper.SetDefault("api_url", "https://www.agentduty.dev/api/graphql")
+	viper.SetDefault("rate_limit.per_session", 20)
+	viper.SetDefault("rate_limit.window", "10m")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -47,15 +97,69 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
+// Save writes the API URL and tokens, leaving the rest of the file as it is.
 func Save(cfg *Config) error {
+	return updateFile(map[string]any{
+		"api_url":       cfg.APIUrl,
+		"access_token":  cfg.AccessToken,
+		"refresh_token": cfg.RefreshToken,
+	})
+}
+
+// SaveFeedFilter writes the feed's filter state without touching the rest
+// of the config.
+func SaveFeedFilter(f FeedFilter) error {
+	return updateFile(map[string]any{"feed": f})
+}
+
+// writeMu serialises config writes: the feed saves its filter from a
+// background command while the client may be saving refreshed tokens.
+var writeMu sync.Mutex
+
+// updateFile sets the top-level keys in set and replaces the config file
+// atomically, so a crash or a concurrent save never leaves it half-written.
+// It works on the file rather than viper, whose maps aren't safe to change
+// from more than one goroutine.
+func updateFile(set map[string]any) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
 	dir := ConfigDir()
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return err
 	}
+	path := ConfigPath()
 
-	viper.Set("api_url", cfg.APIUrl)
-	viper.Set("access_token", cfg.AccessToken)
-	viper.Set("refresh_token", cfg.RefreshToken)
+	doc := map[string]any{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	for k, v := range set {
+		doc[k] = v
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
 
-	return viper.WriteConfigAs(ConfigPath())
+	tmp, err := os.CreateTemp(dir, ".config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -111,3 +113,132 @@ func TestLoad_CreatesConfigDir(t *testing.T) {
 		t.Fatal("config path is not a directory")
 	}
 }
+
+func TestLoad_RateLimit(t *testing.T) {
+	resetViper()
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.RateLimit.PerSession != 20 || cfg.RateLimit.Window != 10*time.Minute {
+		t.Errorf("unexpected default rate limit: %+v", cfg.RateLimit)
+	}
+
+	resetViper()
+	configFile := filepath.Join(tmpDir, ".agentduty", "config.yaml")
+	os.WriteFile(configFile, []byte("rate_limit:\n  per_session: 5\n  window: 1h\n"), 0600)
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.RateLimit.PerSession != 5 || cfg.RateLimit.Window != time.Hour {
+		t.Errorf("expected configured rate limit, got %+v", cfg.RateLimit)
+	}
+}
+
+func TestSaveFeedFilter_RoundTrip(t *testing.T) {
+	resetViper()
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := FeedFilter{Search: "deploy", MinPriority: 4, Tags: []string{"infra"}, Workspace: "/repo", GroupBy: "session"}
+	if err := SaveFeedFilter(want); err != nil {
+		t.Fatalf("SaveFeedFilter failed: %v", err)
+	}
+
+	resetViper()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	got := cfg.Feed
+	if got.Search != want.Search || got.MinPriority != want.MinPriority || got.Workspace != want.Workspace ||
+		got.GroupBy != want.GroupBy || len(got.Tags) != 1 || got.Tags[0] != "infra" {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestLoad_TUI(t *testing.T) {
+	resetViper()
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	os.MkdirAll(filepath.Join(tmpDir, ".agentduty"), 0700)
+	configFile := filepath.Join(tmpDir, ".agentduty", "config.yaml")
+	os.WriteFile(configFile, []byte("tui:\n  keymap: vim\n  theme: light\n  keys:\n    archive: x\n    snooze: [Z, ctrl+z]\n  reply_limit: 2000\n  canned:\n    - name: lgtm\n      text: Go ahead with {shortCode}.\n"), 0600)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.TUI.Keymap != "vim" || cfg.TUI.Theme != "light" {
+		t.Errorf("unexpected tui config: %+v", cfg.TUI)
+	}
+	if got := cfg.TUI.Keys["archive"]; len(got) != 1 || got[0] != "x" {
+		t.Errorf("expected a single key to load as a list, got %v", got)
+	}
+	if got := cfg.TUI.Keys["snooze"]; len(got) != 2 || got[1] != "ctrl+z" {
+		t.Errorf("expected key list, got %v", got)
+	}
+	if cfg.TUI.ReplyLimit != 2000 {
+		t.Errorf("expected reply_limit 2000, got %d", cfg.TUI.ReplyLimit)
+	}
+	if len(cfg.TUI.Canned) != 1 || cfg.TUI.Canned[0].Name != "lgtm" || cfg.TUI.Canned[0].Text != "Go ahead with {shortCode}." {
+		t.Errorf("unexpected canned responses %+v", cfg.TUI.Canned)
+	}
+}
+
+func TestSave_KeepsOtherSettings(t *testing.T) {
+	resetViper()
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	os.MkdirAll(filepath.Join(tmpDir, ".agentduty"), 0700)
+	configFile := filepath.Join(tmpDir, ".agentduty", "config.yaml")
+	os.WriteFile(configFile, []byte("tui:\n  keymap: vim\n"), 0600)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := Save(&Config{APIUrl: "https://x.example/api/graphql", AccessToken: "tok", RefreshToken: "ref"}); err != nil {
+				t.Errorf("Save failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := SaveFeedFilter(FeedFilter{MinPriority: 4}); err != nil {
+				t.Errorf("SaveFeedFilter failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AccessToken != "tok" || cfg.RefreshToken != "ref" || cfg.Feed.MinPriority != 4 || cfg.TUI.Keymap != "vim" {
+		t.Errorf("expected every setting kept, got %+v", cfg)
+	}
+	entries, _ := os.ReadDir(filepath.Join(tmpDir, ".agentduty"))
+	if len(entries) != 1 {
+		t.Errorf("expected only config.yaml, got %d entries", len(entries))
+	}
+}
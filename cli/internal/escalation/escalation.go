@@ -0,0 +1,142 @@
+// Package escalation parses escalation step definitions given on the command
+// line or in YAML, and previews when each step would fire.
+package escalation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Channels are the delivery channels a step may use.
+var Channels = []string{"slack", "sms", "web", "email", "webhook", "discord", "teams"}
+
+// Step is one escalation step: deliver on Channel, Delay after the previous
+// step. The server delivers the first step immediately and ignores its delay.
+type Step struct {
+	Channel string        `yaml:"channel"`
+	Delay   time.Duration `yaml:"delay,omitempty"`
+}
+
+// File is the YAML form accepted by `escalation create -f`.
+type File struct {
+	Name    string `yaml:"name"`
+	Default bool   `yaml:"default"`
+	Steps   []Step `yaml:"steps"`
+}
+
+// MarshalYAML writes the delay the way people type it ("5m", not "5m0s").
+func (s Step) MarshalYAML() (any, error) {
+	type step struct {
+		Channel string `yaml:"channel"`
+		Delay   string `yaml:"delay,omitempty"`
+	}
+	out := step{Channel: s.Channel}
+	if s.Delay > 0 {
+		d := s.Delay.String()
+		if strings.HasSuffix(d, "m0s") {
+			d = strings.TrimSuffix(d, "0s")
+		}
+		if strings.HasSuffix(d, "h0m") {
+			d = strings.TrimSuffix(d, "0m")
+		}
+		out.Delay = d
+	}
+	return out, nil
+}
+
+// ParseStep parses an inline step of the form "channel" or "channel:delay",
+// e.g. "slack" or "sms:5m".
+func ParseStep(s string) (Step, error) {
+	channel, delay, hasDelay := strings.Cut(strings.TrimSpace(s), ":")
+	step := Step{Channel: strings.ToLower(channel)}
+	if hasDelay {
+		d, err := time.ParseDuration(delay)
+		if err != nil {
+			return Step{}, fmt.Errorf("step %q: invalid delay: %w", s, err)
+		}
+		step.Delay = d
+	}
+	if err := step.Validate(); err != nil {
+		return Step{}, fmt.Errorf("step %q: %w", s, err)
+	}
+	return step, nil
+}
+
+// Validate checks the channel name and that the delay is whole, non-negative
+// seconds.
+func (s Step) Validate() error {
+	known := false
+	for _, c := range Channels {
+		if s.Channel == c {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown channel %q (expected %s)", s.Channel, strings.Join(Channels, ", "))
+	}
+	if s.Delay < 0 {
+		return fmt.Errorf("delay must not be negative")
+	}
+	if s.Delay%time.Second != 0 {
+		return fmt.Errorf("delay must be whole seconds")
+	}
+	return nil
+}
+
+// ParseFile reads a policy definition from YAML.
+func ParseFile(data []byte) (File, error) {
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return File{}, fmt.Errorf("parse policy: %w", err)
+	}
+	if len(f.Steps) == 0 {
+		return File{}, fmt.Errorf("parse policy: no steps defined")
+	}
+	for i, s := range f.Steps {
+		if err := s.Validate(); err != nil {
+			return File{}, fmt.Errorf("parse policy: step %d: %w", i+1, err)
+		}
+	}
+	return f, nil
+}
+
+// LoadFile reads and parses a policy file; "-" reads stdin.
+func LoadFile(path string) (File, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("read policy: %w", err)
+	}
+	return ParseFile(data)
+}
+
+// TimelineEntry is when a step fires, relative to the notification.
+type TimelineEntry struct {
+	At      time.Duration
+	Channel string
+}
+
+// Timeline lays the steps out the way the server runs them: the first step
+// immediately, each later one its delay after the previous.
+func Timeline(steps []Step) []TimelineEntry {
+	entries := make([]TimelineEntry, len(steps))
+	var at time.Duration
+	for i, s := range steps {
+		if i > 0 {
+			at += s.Delay
+		}
+		entries[i] = TimelineEntry{At: at, Channel: s.Channel}
+	}
+	return entries
+}
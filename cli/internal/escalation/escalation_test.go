@@ -0,0 +1,113 @@
+package escalation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func TestParseStep(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Step
+		wantErr string
+	}{
+		{in: "slack", want: Step{Channel: "slack"}},
+		{in: "SMS:5m", want: Step{Channel: "sms", Delay: 5 * time.Minute}},
+		{in: "web:90s", want: Step{Channel: "web", Delay: 90 * time.Second}},
+		{in: "pager:1m", wantErr: "unknown channel"},
+		{in: "sms:soon", wantErr: "invalid delay"},
+		{in: "sms:-1m", wantErr: "must not be negative"},
+		{in: "sms:1500ms", wantErr: "whole seconds"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStep(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseStep(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseStep(%q) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseStep(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	f, err := ParseFile([]byte(`
+name: urgent
+default: true
+steps:
+  - channel: slack
+  - channel: sms
+    delay: 5m
+`))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if f.Name != "urgent" || !f.Default {
+		t.Errorf("unexpected header: %+v", f)
+	}
+	want := []Step{{Channel: "slack"}, {Channel: "sms", Delay: 5 * time.Minute}}
+	if len(f.Steps) != len(want) || f.Steps[0] != want[0] || f.Steps[1] != want[1] {
+		t.Errorf("steps = %+v, want %+v", f.Steps, want)
+	}
+
+	if _, err := ParseFile([]byte("name: empty\n")); err == nil {
+		t.Error("expected error for policy without steps")
+	}
+	if _, err := ParseFile([]byte("steps:\n  - channel: fax\n")); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
+
+func TestTimeline_IgnoresFirstDelay(t *testing.T) {
+	got := Timeline([]Step{
+		{Channel: "slack", Delay: time.Hour},
+		{Channel: "sms", Delay: 5 * time.Minute},
+		{Channel: "web", Delay: 10 * time.Minute},
+	})
+
+	want := []TimelineEntry{
+		{At: 0, Channel: "slack"},
+		{At: 5 * time.Minute, Channel: "sms"},
+		{At: 15 * time.Minute, Channel: "web"},
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStep_MarshalYAML(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                          "",
+		5 * time.Minute:            "5m",
+		90 * time.Second:           "1m30s",
+		time.Hour:                  "1h",
+		time.Hour + 30*time.Minute: "1h30m",
+	}
+	for d, want := range tests {
+		v, err := Step{Channel: "sms", Delay: d}.MarshalYAML()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := yaml.Marshal(v)
+		got := ""
+		if _, after, ok := strings.Cut(string(data), "delay: "); ok {
+			got = strings.TrimSpace(after)
+		}
+		if got != want {
+			t.Errorf("delay %s marshalled as %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package listen runs a command for every response to the user's
+// notifications, as delivered by the server's response stream or by polling.
+package listen
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Event is one response together with the notification it answers. It is
+// written as JSON to the command's stdin.
+type Event struct {
+	ID             string   `json:"id"`
+	NotificationID string   `json:"notificationId"`
+	ShortCode      string   `json:"shortCode"`
+	Message        string   `json:"message"`
+	Priority       int      `json:"priority"`
+	Tags           []string `json:"tags"`
+	SessionKey     string   `json:"sessionKey,omitempty"`
+	Workspace      string   `json:"workspace,omitempty"`
+	Channel        string   `json:"channel"`
+	Text           string   `json:"text,omitempty"`
+	SelectedOption string   `json:"selectedOption,omitempty"`
+	Auto           bool     `json:"auto"`
+	CreatedAt      string   `json:"createdAt"`
+}
+
+// Env returns the AGENTDUTY_* variables describing the event.
+func (e Event) Env() []string {
+	auto := "0"
+	if e.Auto {
+		auto = "1"
+	}
+	return []string{
+		"AGENTDUTY_RESPONSE_ID=" + e.ID,
+		"AGENTDUTY_NOTIFICATION_ID=" + e.NotificationID,
+		"AGENTDUTY_SHORT_CODE=" + e.ShortCode,
+		"AGENTDUTY_PRIORITY=" + strconv.Itoa(e.Priority),
+		"AGENTDUTY_SESSION=" + e.SessionKey,
+		"AGENTDUTY_WORKSPACE=" + e.Workspace,
+		"AGENTDUTY_CHANNEL=" + e.Channel,
+		"AGENTDUTY_RESPONSE_TEXT=" + e.Text,
+		"AGENTDUTY_SELECTED_OPTION=" + e.SelectedOption,
+		"AGENTDUTY_AUTO=" + auto,
+	}
+}
+
+// ReadSSE parses a server-sent events stream, calling fn for each event
+// with its type, id and data. It returns when r is exhausted or fn fails.
+func ReadSSE(r io.Reader, fn func(event, id string, data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event, id string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data.Len() > 0 {
+				if event == "" {
+					event = "message"
+				}
+				if err := fn(event, id, []byte(strings.TrimSuffix(data.String(), "\n"))); err != nil {
+					return err
+				}
+			}
+			event, id = "", ""
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment / heartbeat
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "id":
+			id = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		}
+	}
+	return scanner.Err()
+}
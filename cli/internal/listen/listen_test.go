@@ -0,0 +1,170 @@
+package listen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSSE(t *testing.T) {
+	stream := "retry: 2000\n\n" +
+		": ping\n\n" +
+		"id: 2025-01-01T00:00:01.000Z\nevent: response\ndata: {\"id\":\"r1\"}\n\n" +
+		"data: line one\ndata: line two\n\n"
+
+	type got struct{ event, id, data string }
+	var events []got
+	err := ReadSSE(strings.NewReader(stream), func(event, id string, data []byte) error {
+		events = append(events, got{event, id, string(data)})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []got{
+		{"response", "2025-01-01T00:00:01.000Z", `{"id":"r1"}`},
+		{"message", "", "line one\nline two"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func testEvent() Event {
+	return Event{
+		ID:             "r1",
+		NotificationID: "n1",
+		ShortCode:      "ABC",
+		Message:        "Deploy?",
+		Priority:       4,
+		SessionKey:     "sess-1",
+		Channel:        "slack",
+		Text:           "ship it",
+		CreatedAt:      "2025-01-01T00:00:01.000Z",
+	}
+}
+
+func TestRunner_PassesEventOnStdinAndEnv(t *testing.T) {
+	var out bytes.Buffer
+	r := &Runner{
+		Command: `printf '%s|%s|' "$AGENTDUTY_SHORT_CODE" "$AGENTDUTY_RESPONSE_TEXT"; cat`,
+		Stdout:  &out,
+		Stderr:  &bytes.Buffer{},
+	}
+
+	if err := r.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	r.Wait()
+
+	prefix, body, _ := strings.Cut(out.String(), "|ship it|")
+	if prefix != "ABC" {
+		t.Errorf("output = %q, want env vars first", out.String())
+	}
+	var ev Event
+	if err := json.Unmarshal([]byte(body), &ev); err != nil {
+		t.Fatalf("stdin was not the event JSON: %q", body)
+	}
+	if ev.ID != "r1" || ev.ShortCode != "ABC" || ev.Priority != 4 {
+		t.Errorf("stdin event = %+v", ev)
+	}
+}
+
+func TestRunner_DeadLettersAfterRetries(t *testing.T) {
+	dir := t.TempDir()
+	count := filepath.Join(dir, "count")
+	r := &Runner{
+		Command:    `echo x >> ` + count + `; echo boom >&2; exit 3`,
+		Retries:    2,
+		DeadLetter: filepath.Join(dir, "dead.jsonl"),
+		Stdout:     &bytes.Buffer{},
+		Stderr:     &bytes.Buffer{},
+	}
+
+	if err := r.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	r.Wait()
+
+	runs, _ := os.ReadFile(count)
+	if n := strings.Count(string(runs), "x"); n != 3 {
+		t.Errorf("ran %d times, want 3", n)
+	}
+
+	f, err := os.Open(r.DeadLetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("dead-letter log is empty")
+	}
+	var dl DeadLetter
+	if err := json.Unmarshal(scanner.Bytes(), &dl); err != nil {
+		t.Fatal(err)
+	}
+	if dl.Attempts != 3 || dl.Event.ID != "r1" || strings.TrimSpace(dl.Stderr) != "boom" {
+		t.Errorf("dead letter = %+v", dl)
+	}
+	if !strings.Contains(dl.Error, "exit status 3") {
+		t.Errorf("error = %q", dl.Error)
+	}
+}
+
+func TestRunner_TimesOut(t *testing.T) {
+	dir := t.TempDir()
+	r := &Runner{
+		Command:    "sleep 5",
+		Timeout:    50 * time.Millisecond,
+		DeadLetter: filepath.Join(dir, "dead.jsonl"),
+		Stderr:     &bytes.Buffer{},
+	}
+
+	start := time.Now()
+	r.Dispatch(context.Background(), testEvent())
+	r.Wait()
+	if time.Since(start) > 3*time.Second {
+		t.Fatalf("timeout not enforced (took %s)", time.Since(start))
+	}
+
+	data, _ := os.ReadFile(r.DeadLetter)
+	if !strings.Contains(string(data), "timed out after 50ms") {
+		t.Errorf("dead letter = %s", data)
+	}
+}
+
+func TestRunner_LimitsConcurrency(t *testing.T) {
+	dir := t.TempDir()
+	r := &Runner{
+		// Each run holds a lock directory; a second concurrent run would fail.
+		Command:     `mkdir ` + filepath.Join(dir, "lock") + ` && sleep 0.05 && rmdir ` + filepath.Join(dir, "lock"),
+		Concurrency: 1,
+		DeadLetter:  filepath.Join(dir, "dead.jsonl"),
+		Stderr:      &bytes.Buffer{},
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := r.Dispatch(context.Background(), testEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.Wait()
+
+	if _, err := os.Stat(r.DeadLetter); err == nil {
+		data, _ := os.ReadFile(r.DeadLetter)
+		t.Errorf("runs overlapped: %s", data)
+	}
+}
@@ -0,0 +1,193 @@
+package listen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Runner executes Command once per event, at most Concurrency at a time.
+// Events whose command still fails after Retries extra attempts are
+// appended to DeadLetter as JSON lines.
+type Runner struct {
+	Command     string
+	Concurrency int
+	Timeout     time.Duration
+	Retries     int
+	RetryDelay  time.Duration
+	DeadLetter  string
+
+	// Stdout and Stderr receive the command's output (default os.Stdout/Stderr).
+	Stdout io.Writer
+	Stderr io.Writer
+
+	once sync.Once
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex // serializes dead-letter writes and command output
+}
+
+// DeadLetter is one line in the dead-letter log.
+type DeadLetter struct {
+	FailedAt time.Time `json:"failedAt"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	Stderr   string    `json:"stderr,omitempty"`
+	Event    Event     `json:"event"`
+}
+
+// Dispatch runs the command for ev in the background, blocking while
+// Concurrency runs are already in flight. It returns ctx.Err() if ctx is
+// cancelled while waiting for a slot.
+func (r *Runner) Dispatch(ctx context.Context, ev Event) error {
+	r.once.Do(func() {
+		n := r.Concurrency
+		if n < 1 {
+			n = 1
+		}
+		r.sem = make(chan struct{}, n)
+	})
+
+	select {
+	case r.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer func() { <-r.sem; r.wg.Done() }()
+		r.handle(ctx, ev)
+	}()
+	return nil
+}
+
+// Wait blocks until every dispatched run has finished.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) handle(ctx context.Context, ev Event) {
+	var err error
+	var stderr string
+	attempts := 0
+	for attempts <= r.Retries {
+		if attempts > 0 {
+			select {
+			case <-time.After(r.RetryDelay * time.Duration(attempts)):
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		attempts++
+		stderr, err = r.run(ctx, ev)
+		if err == nil {
+			return
+		}
+	}
+
+	fmt.Fprintf(r.stderr(), "agentduty listen: %s (response %s) failed after %d attempt(s): %v\n",
+		ev.ShortCode, ev.ID, attempts, err)
+	if dlErr := r.deadLetter(DeadLetter{
+		FailedAt: time.Now().UTC(),
+		Attempts: attempts,
+		Error:    err.Error(),
+		Stderr:   stderr,
+		Event:    ev,
+	}); dlErr != nil {
+		fmt.Fprintf(r.stderr(), "agentduty listen: write dead letter: %v\n", dlErr)
+	}
+}
+
+// run executes the command once, returning the tail of its stderr on failure.
+func (r *Runner) run(ctx context.Context, ev Event) (string, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return "", fmt.Errorf("marshal event: %w", err)
+	}
+
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", r.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), ev.Env()...)
+	cmd.WaitDelay = time.Second
+
+	runErr := cmd.Run()
+
+	r.mu.Lock()
+	r.stdout().Write(stdout.Bytes())
+	r.stderr().Write(stderr.Bytes())
+	r.mu.Unlock()
+
+	if runErr == nil {
+		return "", nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		runErr = fmt.Errorf("timed out after %s", r.Timeout)
+	}
+	return tail(stderr.String(), 2048), runErr
+}
+
+func (r *Runner) deadLetter(dl DeadLetter) error {
+	if r.DeadLetter == "" {
+		return nil
+	}
+	line, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(r.DeadLetter), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.DeadLetter, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func (r *Runner) stdout() io.Writer {
+	if r.Stdout != nil {
+		return r.Stdout
+	}
+	return os.Stdout
+}
+
+func (r *Runner) stderr() io.Writer {
+	if r.Stderr != nil {
+		return r.Stderr
+	}
+	return os.Stderr
+}
+
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
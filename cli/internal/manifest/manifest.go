@@ -0,0 +1,372 @@
+// Package manifest describes AgentDuty account configuration as a YAML file
+// and computes the changes needed to make the server match it.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sestinj/agentduty/cli/internal/escalation"
+	"go.yaml.in/yaml/v3"
+)
+
+// Manifest is the agentduty.yaml format shared by `apply` and `export`.
+// Omitted sections are left alone on apply; a present section is authoritative,
+// so policies or routes missing from it are removed.
+type Manifest struct {
+	Settings           *Settings      `yaml:"settings,omitempty"`
+	EscalationPolicies []Policy       `yaml:"escalationPolicies,omitempty"`
+	Routes             map[int]string `yaml:"routes,omitempty"`
+
+	// Set when the corresponding key appears in the file, even if empty,
+	// so "routes: {}" clears every route.
+	managesPolicies bool
+	managesRoutes   bool
+}
+
+// Settings keys left out of the file are not changed; "quietHours: null"
+// turns quiet hours off.
+type Settings struct {
+	Timezone    string       `yaml:"timezone,omitempty"`
+	QuietHours  *QuietHours  `yaml:"quietHours"`
+	Preferences *Preferences `yaml:"preferences,omitempty"`
+
+	managesQuietHours bool
+}
+
+type QuietHours struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+type Preferences struct {
+	SMSMinPriority       int `yaml:"smsMinPriority,omitempty"`
+	BreakthroughPriority int `yaml:"breakthroughPriority,omitempty"`
+}
+
+type Policy struct {
+	Name    string            `yaml:"name"`
+	Default bool              `yaml:"default,omitempty"`
+	Steps   []escalation.Step `yaml:"steps"`
+}
+
+// FromState builds a manifest that manages every section, as exported from
+// the server.
+func FromState(settings *Settings, policies []Policy, routes map[int]string) *Manifest {
+	if settings != nil {
+		settings.managesQuietHours = true
+	}
+	return &Manifest{
+		Settings:           settings,
+		EscalationPolicies: policies,
+		Routes:             routes,
+		managesPolicies:    true,
+		managesRoutes:      true,
+	}
+}
+
+var hhmm = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+
+// Parse reads and validates a manifest.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	var keys map[string]any
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	_, m.managesPolicies = keys["escalationPolicies"]
+	_, m.managesRoutes = keys["routes"]
+	if settings, ok := keys["settings"].(map[string]any); ok && m.Settings != nil {
+		_, m.Settings.managesQuietHours = settings["quietHours"]
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &m, nil
+}
+
+func (m *Manifest) validate() error {
+	names := map[string]bool{}
+	defaults := 0
+	for i, p := range m.EscalationPolicies {
+		if p.Name == "" {
+			return fmt.Errorf("escalationPolicies[%d]: name is required", i)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("escalation policy %q defined twice", p.Name)
+		}
+		names[p.Name] = true
+		if p.Default {
+			defaults++
+		}
+		if len(p.Steps) == 0 {
+			return fmt.Errorf("escalation policy %q: no steps defined", p.Name)
+		}
+		for j, s := range p.Steps {
+			if err := s.Validate(); err != nil {
+				return fmt.Errorf("escalation policy %q: step %d: %w", p.Name, j+1, err)
+			}
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("only one escalation policy may be the default")
+	}
+
+	for priority, name := range m.Routes {
+		if priority < 1 || priority > 5 {
+			return fmt.Errorf("routes: priority %d must be between 1 and 5", priority)
+		}
+		if m.managesPolicies && !names[name] {
+			return fmt.Errorf("routes: P%d refers to undefined policy %q", priority, name)
+		}
+	}
+
+	if s := m.Settings; s != nil {
+		if q := s.QuietHours; q != nil {
+			if !hhmm.MatchString(q.Start) || !hhmm.MatchString(q.End) {
+				return fmt.Errorf("settings.quietHours: start and end must be HH:MM")
+			}
+		}
+		if p := s.Preferences; p != nil {
+			if p.SMSMinPriority != 0 && (p.SMSMinPriority < 1 || p.SMSMinPriority > 5) {
+				return fmt.Errorf("settings.preferences.smsMinPriority must be between 1 and 5")
+			}
+			if p.BreakthroughPriority != 0 && (p.BreakthroughPriority < 1 || p.BreakthroughPriority > 5) {
+				return fmt.Errorf("settings.preferences.breakthroughPriority must be between 1 and 5")
+			}
+		}
+	}
+	return nil
+}
+
+// Marshal renders a manifest as YAML.
+func Marshal(m *Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Action is what a Change does to a resource.
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Change is one step of a plan.
+type Change struct {
+	Action   Action
+	Resource string // "policy", "route", "settings"
+	Name     string
+	Detail   string
+
+	Policy   *Policy   // create/update policy
+	Priority int       // route changes
+	Settings *Settings // settings update
+}
+
+func (c Change) String() string {
+	sign := map[Action]string{Create: "+", Update: "~", Delete: "-"}[c.Action]
+	s := fmt.Sprintf("%s %s %s", sign, c.Resource, c.Name)
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// Diff returns the changes that turn current into desired, in an order that
+// is safe to apply: policies are created before routes point at them and
+// deleted only after routes stop pointing at them.
+func Diff(current, desired *Manifest) []Change {
+	var creates, updates, routes, deletes []Change
+
+	if desired.Settings != nil {
+		if c, ok := diffSettings(current.Settings, desired.Settings); ok {
+			updates = append(updates, c)
+		}
+	}
+
+	if desired.managesPolicies {
+		existing := map[string]Policy{}
+		for _, p := range current.EscalationPolicies {
+			existing[p.Name] = p
+		}
+		wanted := map[string]bool{}
+		for i := range desired.EscalationPolicies {
+			p := &desired.EscalationPolicies[i]
+			wanted[p.Name] = true
+			cur, ok := existing[p.Name]
+			switch {
+			case !ok:
+				creates = append(creates, Change{Action: Create, Resource: "policy", Name: p.Name, Detail: stepSummary(p.Steps) + defaultNote(p.Default), Policy: p})
+			case !sameSteps(cur.Steps, p.Steps) || p.Default != cur.Default:
+				var details []string
+				if !sameSteps(cur.Steps, p.Steps) {
+					details = append(details, stepSummary(cur.Steps)+" → "+stepSummary(p.Steps))
+				}
+				switch {
+				case p.Default && !cur.Default:
+					details = append(details, "make default")
+				case !p.Default && cur.Default:
+					details = append(details, "no longer default")
+				}
+				updates = append(updates, Change{Action: Update, Resource: "policy", Name: p.Name, Detail: strings.Join(details, "; "), Policy: p})
+			}
+		}
+		for _, p := range current.EscalationPolicies {
+			if !wanted[p.Name] {
+				deletes = append(deletes, Change{Action: Delete, Resource: "policy", Name: p.Name})
+			}
+		}
+	}
+
+	if desired.managesRoutes {
+		for _, priority := range sortedPriorities(current.Routes, desired.Routes) {
+			cur, had := current.Routes[priority]
+			want, has := desired.Routes[priority]
+			name := fmt.Sprintf("P%d", priority)
+			switch {
+			case has && !had:
+				routes = append(routes, Change{Action: Create, Resource: "route", Name: name, Detail: "→ " + want, Priority: priority, Policy: &Policy{Name: want}})
+			case has && cur != want:
+				routes = append(routes, Change{Action: Update, Resource: "route", Name: name, Detail: cur + " → " + want, Priority: priority, Policy: &Policy{Name: want}})
+			case !has && had:
+				routes = append(routes, Change{Action: Delete, Resource: "route", Name: name, Detail: "was " + cur, Priority: priority})
+			}
+		}
+	}
+
+	plan := append(creates, updates...)
+	plan = append(plan, routes...)
+	return append(plan, deletes...)
+}
+
+func diffSettings(current, desired *Settings) (Change, bool) {
+	if current == nil {
+		current = &Settings{}
+	}
+	var details []string
+	change := &Settings{}
+
+	if desired.Timezone != "" && desired.Timezone != current.Timezone {
+		details = append(details, fmt.Sprintf("timezone %s → %s", orNone(current.Timezone), desired.Timezone))
+		change.Timezone = desired.Timezone
+	}
+
+	if desired.managesQuietHours && !sameQuietHours(current.QuietHours, desired.QuietHours) {
+		details = append(details, fmt.Sprintf("quiet hours %s → %s", quietText(current.QuietHours), quietText(desired.QuietHours)))
+		if desired.QuietHours == nil {
+			change.QuietHours = &QuietHours{}
+		} else {
+			change.QuietHours = desired.QuietHours
+		}
+	}
+
+	if p := desired.Preferences; p != nil {
+		cur := Preferences{}
+		if current.Preferences != nil {
+			cur = *current.Preferences
+		}
+		prefs := Preferences{}
+		if p.SMSMinPriority != 0 && p.SMSMinPriority != cur.SMSMinPriority {
+			details = append(details, fmt.Sprintf("smsMinPriority %d → %d", cur.SMSMinPriority, p.SMSMinPriority))
+			prefs.SMSMinPriority = p.SMSMinPriority
+		}
+		if p.BreakthroughPriority != 0 && p.BreakthroughPriority != cur.BreakthroughPriority {
+			details = append(details, fmt.Sprintf("breakthroughPriority %d → %d", cur.BreakthroughPriority, p.BreakthroughPriority))
+			prefs.BreakthroughPriority = p.BreakthroughPriority
+		}
+		if prefs != (Preferences{}) {
+			change.Preferences = &prefs
+		}
+	}
+
+	if len(details) == 0 {
+		return Change{}, false
+	}
+	return Change{Action: Update, Resource: "settings", Name: "account", Detail: strings.Join(details, "; "), Settings: change}, true
+}
+
+func sameQuietHours(a, b *QuietHours) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func quietText(q *QuietHours) string {
+	if q == nil {
+		return "off"
+	}
+	return q.Start + "-" + q.End
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func defaultNote(isDefault bool) string {
+	if isDefault {
+		return " (default)"
+	}
+	return ""
+}
+
+func sameSteps(a, b []escalation.Step) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func stepSummary(steps []escalation.Step) string {
+	parts := make([]string, len(steps))
+	for i, s := range steps {
+		parts[i] = s.Channel
+		if s.Delay > 0 {
+			parts[i] += ":" + s.Delay.String()
+		}
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+func sortedPriorities(a, b map[int]string) []int {
+	seen := map[int]bool{}
+	for p := range a {
+		seen[p] = true
+	}
+	for p := range b {
+		seen[p] = true
+	}
+	out := make([]int, 0, len(seen))
+	for p := range seen {
+		out = append(out, p)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	return out
+}
@@ -0,0 +1,163 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/escalation"
+)
+
+const sample = `
+settings:
+  timezone: America/Los_Angeles
+  quietHours:
+    start: "22:00"
+    end: "07:00"
+  preferences:
+    smsMinPriority: 4
+escalationPolicies:
+  - name: urgent
+    default: true
+    steps:
+      - channel: slack
+      - channel: sms
+        delay: 5m
+  - name: quiet
+    steps:
+      - channel: slack
+routes:
+  5: urgent
+  4: urgent
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if m.Settings.Timezone != "America/Los_Angeles" || m.Settings.QuietHours.Start != "22:00" {
+		t.Errorf("unexpected settings: %+v", m.Settings)
+	}
+	if len(m.EscalationPolicies) != 2 || m.EscalationPolicies[0].Steps[1].Delay != 5*time.Minute {
+		t.Errorf("unexpected policies: %+v", m.EscalationPolicies)
+	}
+	if m.Routes[5] != "urgent" || !m.managesRoutes || !m.managesPolicies {
+		t.Errorf("unexpected routes: %+v", m.Routes)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"undefined policy": "escalationPolicies:\n  - name: a\n    steps: [{channel: slack}]\nroutes:\n  5: b\n",
+		"bad priority":     "routes:\n  7: a\n",
+		"two defaults":     "escalationPolicies:\n  - {name: a, default: true, steps: [{channel: slack}]}\n  - {name: b, default: true, steps: [{channel: slack}]}\n",
+		"bad quiet hours":  "settings:\n  quietHours: {start: \"10pm\", end: \"07:00\"}\n",
+		"duplicate policy": "escalationPolicies:\n  - {name: a, steps: [{channel: slack}]}\n  - {name: a, steps: [{channel: sms}]}\n",
+		"policy w/o steps": "escalationPolicies:\n  - {name: a}\n",
+		"bad step channel": "escalationPolicies:\n  - {name: a, steps: [{channel: fax}]}\n",
+	}
+	for name, doc := range tests {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	desired, _ := Parse([]byte(sample))
+	current := FromState(desired.Settings, desired.EscalationPolicies, desired.Routes)
+
+	if plan := Diff(current, desired); len(plan) != 0 {
+		t.Errorf("expected empty plan, got %v", plan)
+	}
+}
+
+func TestDiff_OrdersChangesSafely(t *testing.T) {
+	desired, _ := Parse([]byte(sample))
+	current := FromState(
+		&Settings{Timezone: "UTC"},
+		[]Policy{
+			{Name: "urgent", Steps: []escalation.Step{{Channel: "slack"}}},
+			{Name: "legacy", Steps: []escalation.Step{{Channel: "sms"}}},
+		},
+		map[int]string{5: "legacy", 3: "legacy"},
+	)
+
+	var got []string
+	for _, c := range Diff(current, desired) {
+		got = append(got, string(c.Action)+" "+c.Resource+" "+c.Name)
+	}
+	want := []string{
+		"create policy quiet",
+		"update settings account",
+		"update policy urgent",
+		"update route P5",
+		"create route P4",
+		"delete route P3",
+		"delete policy legacy",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("plan =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiff_OmittedSectionsAreUnmanaged(t *testing.T) {
+	desired, err := Parse([]byte("settings:\n  timezone: UTC\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := FromState(
+		&Settings{Timezone: "UTC", QuietHours: &QuietHours{Start: "22:00", End: "07:00"}},
+		[]Policy{{Name: "keep", Steps: []escalation.Step{{Channel: "slack"}}}},
+		map[int]string{5: "keep"},
+	)
+
+	if plan := Diff(current, desired); len(plan) != 0 {
+		t.Errorf("expected no changes for unmanaged sections, got %v", plan)
+	}
+}
+
+func TestDiff_ClearsQuietHours(t *testing.T) {
+	desired, _ := Parse([]byte("settings:\n  quietHours: null\n"))
+	current := FromState(&Settings{QuietHours: &QuietHours{Start: "22:00", End: "07:00"}}, nil, nil)
+
+	plan := Diff(current, desired)
+	if len(plan) != 1 || plan[0].Settings.QuietHours == nil || plan[0].Settings.QuietHours.Start != "" {
+		t.Fatalf("expected a quiet hours clear, got %+v", plan)
+	}
+	if !strings.Contains(plan[0].String(), "22:00-07:00 → off") {
+		t.Errorf("unexpected detail: %s", plan[0])
+	}
+}
+
+func TestDiff_DefaultBothWays(t *testing.T) {
+	desired, _ := Parse([]byte(sample))
+	current := FromState(desired.Settings, []Policy{
+		{Name: "urgent", Steps: desired.EscalationPolicies[0].Steps},
+		{Name: "quiet", Default: true, Steps: desired.EscalationPolicies[1].Steps},
+	}, desired.Routes)
+
+	var got []string
+	for _, c := range Diff(current, desired) {
+		got = append(got, c.String())
+	}
+	if len(got) != 2 || !strings.Contains(got[0], "make default") || !strings.Contains(got[1], "no longer default") {
+		t.Errorf("expected urgent made default and quiet unset, got %q", got)
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	m, _ := Parse([]byte(sample))
+	data, err := Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("re-parse exported manifest: %v\n%s", err, data)
+	}
+	if plan := Diff(FromState(again.Settings, again.EscalationPolicies, again.Routes), m); len(plan) != 0 {
+		t.Errorf("round trip changed the manifest: %v\n%s", plan, data)
+	}
+}
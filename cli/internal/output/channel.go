@@ -0,0 +1,50 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+type Channel struct {
+	Channel    string `json:"channel"`
+	Address    string `json:"address"`
+	Verified   bool   `json:"verified"`
+	VerifiedAt string `json:"verifiedAt,omitempty"`
+}
+
+func PrintChannels(channels []Channel) {
+	if len(channels) == 0 {
+		fmt.Println("No channels connected. Connect one with: agentduty connect slack|discord|teams|sms|email|webhook")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANNEL\tADDRESS\tSTATUS")
+	for _, c := range channels {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Channel, c.Address, channelStatus(c))
+	}
+	w.Flush()
+}
+
+func channelStatus(c Channel) string {
+	if c.Verified {
+		return "verified"
+	}
+	switch c.Channel {
+	case "webhook":
+		return "unverified (ping failed)"
+	default:
+		return "unverified (run: agentduty connect " + c.Channel + " --code <code>)"
+	}
+}
+
+// Disconnected is the result of `agentduty disconnect`.
+type Disconnected struct {
+	Channel      string `json:"channel"`
+	Disconnected bool   `json:"disconnected"`
+}
+
+func PrintDisconnected(d Disconnected) {
+	fmt.Printf("Disconnected %s.\n", d.Channel)
+}
@@ -0,0 +1,22 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChannelStatus(t *testing.T) {
+	tests := []struct {
+		channel Channel
+		want    string
+	}{
+		{Channel{Channel: "slack", Verified: true}, "verified"},
+		{Channel{Channel: "webhook"}, "ping failed"},
+		{Channel{Channel: "sms"}, "agentduty connect sms --code"},
+	}
+	for _, tt := range tests {
+		if got := channelStatus(tt.channel); !strings.Contains(got, tt.want) {
+			t.Errorf("channelStatus(%+v) = %q, want it to contain %q", tt.channel, got, tt.want)
+		}
+	}
+}
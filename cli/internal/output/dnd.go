@@ -0,0 +1,31 @@
+package output
+
+import (
+	"fmt"
+	"time"
+)
+
+// DoNotDisturb is an active hold on pushes, from DND or quiet hours.
+type DoNotDisturb struct {
+	Reason               string    `json:"reason"`
+	Until                time.Time `json:"until"`
+	BreakthroughPriority int       `json:"breakthroughPriority"`
+}
+
+// DescribeDND explains why pushes are held and what still gets through,
+// e.g. "Do not disturb until 14:30 (P5 breaks through)".
+func DescribeDND(d DoNotDisturb) string {
+	label := "Do not disturb"
+	if d.Reason == "quiet-hours" {
+		label = "Quiet hours"
+	}
+	until := d.Until.Local().Format("15:04")
+	if time.Until(d.Until) > 24*time.Hour {
+		until = d.Until.Local().Format("Jan 2 15:04")
+	}
+	through := fmt.Sprintf("P%d", d.BreakthroughPriority)
+	if d.BreakthroughPriority < 5 {
+		through += "+"
+	}
+	return fmt.Sprintf("%s until %s (%s breaks through)", label, until, through)
+}
@@ -0,0 +1,20 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescribeDND(t *testing.T) {
+	until := time.Now().Add(2 * time.Hour)
+	got := DescribeDND(DoNotDisturb{Reason: "dnd", Until: until, BreakthroughPriority: 5})
+	want := "Do not disturb until " + until.Local().Format("15:04") + " (P5 breaks through)"
+	if got != want {
+		t.Errorf("DescribeDND = %q, want %q", got, want)
+	}
+
+	got = DescribeDND(DoNotDisturb{Reason: "quiet-hours", Until: until, BreakthroughPriority: 4})
+	if got != "Quiet hours until "+until.Local().Format("15:04")+" (P4+ breaks through)" {
+		t.Errorf("DescribeDND = %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type EscalationStep struct {
+	StepOrder    int    `json:"stepOrder"`
+	Channel      string `json:"channel"`
+	DelaySeconds int    `json:"delaySeconds"`
+}
+
+type EscalationPolicy struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	IsDefault  bool             `json:"isDefault"`
+	Steps      []EscalationStep `json:"steps"`
+	Priorities []int            `json:"priorities,omitempty"`
+}
+
+type PriorityRoute struct {
+	Priority int               `json:"priority"`
+	Policy   *EscalationPolicy `json:"policy"`
+}
+
+// StepSummary renders steps compactly, e.g. "slack → sms +5m".
+func StepSummary(steps []EscalationStep) string {
+	parts := make([]string, len(steps))
+	for i, s := range steps {
+		parts[i] = s.Channel
+		if i > 0 && s.DelaySeconds > 0 {
+			parts[i] += " +" + formatDelay(time.Duration(s.DelaySeconds)*time.Second)
+		}
+	}
+	return strings.Join(parts, " → ")
+}
+
+func priorityList(ps []int) string {
+	if len(ps) == 0 {
+		return "-"
+	}
+	labels := make([]string, len(ps))
+	for i, p := range ps {
+		labels[i] = fmt.Sprintf("P%d", p)
+	}
+	return strings.Join(labels, ",")
+}
+
+func PrintEscalationPolicies(policies []EscalationPolicy) {
+	if len(policies) == 0 {
+		fmt.Println("No escalation policies. Create one with: agentduty escalation create <name> --step slack --step sms:5m")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDEFAULT\tROUTES\tSTEPS")
+	for _, p := range policies {
+		def := ""
+		if p.IsDefault {
+			def = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, def, priorityList(p.Priorities), StepSummary(p.Steps))
+	}
+	w.Flush()
+}
+
+func PrintEscalationPolicy(p EscalationPolicy) {
+	fmt.Printf("Policy:   %s\n", p.Name)
+	if p.IsDefault {
+		fmt.Println("Default:  yes (used for priorities without a route)")
+	}
+	fmt.Printf("Routes:   %s\n", priorityList(p.Priorities))
+	fmt.Println()
+	PrintEscalationTimeline(p.Steps)
+}
+
+// PrintEscalationTimeline shows when each step fires after a notification
+// is sent. The first step always fires immediately, as on the server.
+func PrintEscalationTimeline(steps []EscalationStep) {
+	var at time.Duration
+	for i, s := range steps {
+		if i > 0 {
+			at += time.Duration(s.DelaySeconds) * time.Second
+		}
+		fmt.Printf("  T+%-7s step %d  %s\n", formatDelay(at), i+1, s.Channel)
+	}
+	if len(steps) > 1 {
+		fmt.Println("  (stops as soon as the notification is answered, expires, or is retracted)")
+	}
+}
+
+func PrintPriorityRoutes(routes []PriorityRoute, defaultPolicy *EscalationPolicy) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PRIORITY\tPOLICY\tSTEPS")
+	byPriority := map[int]*EscalationPolicy{}
+	for _, r := range routes {
+		byPriority[r.Priority] = r.Policy
+	}
+	for p := 5; p >= 1; p-- {
+		policy, routed := byPriority[p]
+		switch {
+		case routed && policy != nil:
+			fmt.Fprintf(w, "P%d\t%s\t%s\n", p, policy.Name, StepSummary(policy.Steps))
+		case defaultPolicy != nil:
+			fmt.Fprintf(w, "P%d\t%s (default)\t%s\n", p, defaultPolicy.Name, StepSummary(defaultPolicy.Steps))
+		default:
+			fmt.Fprintf(w, "P%d\t-\tslack (no policy)\n", p)
+		}
+	}
+	w.Flush()
+}
+
+// formatDelay renders a delay compactly: 0, 45s, 5m, 1h30m.
+func formatDelay(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
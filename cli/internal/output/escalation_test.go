@@ -0,0 +1,34 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStepSummary(t *testing.T) {
+	steps := []EscalationStep{
+		{StepOrder: 0, Channel: "slack", DelaySeconds: 60},
+		{StepOrder: 1, Channel: "sms", DelaySeconds: 300},
+		{StepOrder: 2, Channel: "web", DelaySeconds: 0},
+	}
+	if got, want := StepSummary(steps), "slack → sms +5m → web"; got != want {
+		t.Errorf("StepSummary = %q, want %q", got, want)
+	}
+}
+
+func TestFormatDelay(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                           "0",
+		45 * time.Second:            "45s",
+		5 * time.Minute:             "5m",
+		90 * time.Second:            "1m30s",
+		time.Hour:                   "1h",
+		time.Hour + 30*time.Minute:  "1h30m",
+		2*time.Hour + 5*time.Second: "2h0m5s",
+	}
+	for d, want := range tests {
+		if got := formatDelay(d); got != want {
+			t.Errorf("formatDelay(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
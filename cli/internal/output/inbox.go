@@ -0,0 +1,100 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// InboxSession is one agent session's open questions.
+type InboxSession struct {
+	SessionKey      string         `json:"sessionKey,omitempty"`
+	Workspace       string         `json:"workspace,omitempty"`
+	Polling         bool           `json:"polling"`
+	PendingCount    int            `json:"pendingCount"`
+	OldestPendingAt *time.Time     `json:"oldestPendingAt,omitempty"`
+	LastMessage     *Notification  `json:"lastMessage,omitempty"`
+	Notifications   []Notification `json:"notifications"`
+}
+
+func PrintInbox(sessions []InboxSession) {
+	WriteInbox(os.Stdout, sessions, time.Now())
+}
+
+// WriteInbox renders sessions grouped under workspace headings. The server
+// already orders sessions by workspace, then by oldest question.
+func WriteInbox(w io.Writer, sessions []InboxSession, now time.Time) {
+	if len(sessions) == 0 {
+		fmt.Fprintln(w, "Inbox zero: no agent is waiting on you.")
+		return
+	}
+
+	pending := 0
+	for _, s := range sessions {
+		pending += s.PendingCount
+	}
+	fmt.Fprintf(w, "%d open question(s) across %d session(s)\n", pending, len(sessions))
+
+	workspace := "\x00"
+	for _, s := range sessions {
+		if s.Workspace != workspace {
+			workspace = s.Workspace
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, workspaceHeading(s))
+		}
+		fmt.Fprintf(w, "  %s\n", sessionSummary(s, now))
+		if s.LastMessage != nil {
+			fmt.Fprintf(w, "    last: %s %s\n", s.LastMessage.ShortCode, ellipsize(s.LastMessage.Message, 60))
+		}
+		for _, n := range s.Notifications {
+			fmt.Fprintf(w, "    P%d %s %s (%s)\n", n.Priority, n.ShortCode, ellipsize(n.Message, 56), formatAge(now.Sub(n.CreatedAt)))
+		}
+	}
+}
+
+func workspaceHeading(s InboxSession) string {
+	switch {
+	case s.Workspace != "":
+		return s.Workspace
+	case s.SessionKey != "":
+		return "(no workspace)"
+	default:
+		return "(no session)"
+	}
+}
+
+func sessionSummary(s InboxSession, now time.Time) string {
+	parts := []string{}
+	if s.SessionKey != "" {
+		parts = append(parts, "session "+s.SessionKey)
+	} else {
+		parts = append(parts, "ad-hoc")
+	}
+	if s.PendingCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", s.PendingCount))
+		if s.OldestPendingAt != nil {
+			parts = append(parts, "oldest "+formatAge(now.Sub(*s.OldestPendingAt)))
+		}
+	} else {
+		parts = append(parts, "nothing pending")
+	}
+	if s.SessionKey != "" {
+		if s.Polling {
+			parts = append(parts, "polling")
+		} else {
+			parts = append(parts, "not polling")
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// ellipsize shortens s to n runes on one line, marking the cut.
+func ellipsize(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-3]) + "..."
+}
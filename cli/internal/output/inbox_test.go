@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteInbox_GroupsByWorkspace(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	oldest := now.Add(-90 * time.Minute)
+	sessions := []InboxSession{
+		{
+			SessionKey:      "a1b2",
+			Workspace:       "/repo/api",
+			Polling:         true,
+			PendingCount:    1,
+			OldestPendingAt: &oldest,
+			LastMessage:     &Notification{ShortCode: "K3X", Message: "Run the migration?"},
+			Notifications: []Notification{
+				{ShortCode: "K3X", Priority: 4, Message: "Run the migration?", CreatedAt: oldest},
+			},
+		},
+		{SessionKey: "c3d4", Workspace: "/repo/api"},
+		{
+			PendingCount:    1,
+			OldestPendingAt: &oldest,
+			Notifications: []Notification{
+				{ShortCode: "Q7Z", Priority: 2, Message: "FYI\nbuild is green", CreatedAt: oldest},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	WriteInbox(&buf, sessions, now)
+
+	want := `2 open question(s) across 3 session(s)
+
+/repo/api
+  session a1b2 · 1 pending · oldest 1h · polling
+    last: K3X Run the migration?
+    P4 K3X Run the migration? (1h)
+  session c3d4 · nothing pending · not polling
+
+(no session)
+  ad-hoc · 1 pending · oldest 1h
+    P2 Q7Z FYI build is green (1h)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteInbox output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteInbox_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	WriteInbox(&buf, nil, time.Now())
+	if got := buf.String(); got != "Inbox zero: no agent is waiting on you.\n" {
+		t.Errorf("got %q", got)
+	}
+}
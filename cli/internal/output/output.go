@@ -7,19 +7,32 @@ import (
 	"strings"
 	"text/tabwriter"
 	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/snooze"
 )
 
 type Notification struct {
 	ID        string     `json:"id"`
 	ShortCode string     `json:"shortCode"`
+	ParentID  string     `json:"parentId,omitempty"`
 	Status    string     `json:"status"`
 	Priority  int        `json:"priority"`
 	Message   string     `json:"message"`
+	Tags      []string   `json:"tags,omitempty"`
 	Options   []string   `json:"options,omitempty"`
 	Channels  []string   `json:"channels,omitempty"`
 	CreatedAt time.Time  `json:"createdAt"`
 	Responses []Response `json:"responses,omitempty"`
 	Response  *Response  `json:"response,omitempty"`
+
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	DefaultOption string     `json:"defaultOption,omitempty"`
+	RetractReason string     `json:"retractReason,omitempty"`
+	EditedAt      *time.Time `json:"editedAt,omitempty"`
+	SnoozedUntil  *time.Time `json:"snoozedUntil,omitempty"`
+	// RepeatCount is above 1 when later identical sends were collapsed
+	// into this notification instead of paging again.
+	RepeatCount int `json:"repeatCount,omitempty"`
 }
 
 func (n *Notification) FirstResponse() *Response {
@@ -37,6 +50,17 @@ type Response struct {
 	SelectedOption string `json:"selectedOption,omitempty"`
 	Channel        string `json:"channel"`
 	CreatedAt      string `json:"createdAt"`
+	// Auto is set when the server recorded the notification's default
+	// option because nobody answered before the deadline.
+	Auto      bool       `json:"auto,omitempty"`
+	Responder string     `json:"responder,omitempty"`
+	Reactions []Reaction `json:"reactions,omitempty"`
+}
+
+// Reaction is an emoji the agent added to a response.
+type Reaction struct {
+	Emoji     string `json:"emoji"`
+	CreatedAt string `json:"createdAt"`
 }
 
 type ResponseWithContext struct {
@@ -52,19 +76,47 @@ func PrintJSON(v any) {
 }
 
 func PrintNotificationCreated(n Notification) {
-	fmt.Printf("Notification sent: %s\n", n.ShortCode)
+	if n.RepeatCount > 1 {
+		fmt.Printf("Duplicate of open notification %s (sent %d times, not re-delivered)\n", n.ShortCode, n.RepeatCount)
+	} else {
+		fmt.Printf("Notification sent: %s\n", n.ShortCode)
+	}
 	fmt.Printf("Priority: %d | Status: %s\n", n.Priority, n.Status)
+	if n.ExpiresAt != nil {
+		fmt.Printf("Expires:  %s\n", describeDeadline(n))
+	}
 	fmt.Printf("Poll: agentduty poll %s\n", n.ShortCode)
 }
 
+// describeDeadline renders when a notification expires and what happens then.
+func describeDeadline(n Notification) string {
+	s := fmt.Sprintf("%s (in %s)", n.ExpiresAt.Local().Format("15:04"), formatAge(time.Until(*n.ExpiresAt)))
+	if n.DefaultOption != "" {
+		s += fmt.Sprintf(", defaults to %q", n.DefaultOption)
+	}
+	return s
+}
+
 func PrintNotification(n Notification) {
 	fmt.Printf("ID:       %s\n", n.ShortCode)
 	fmt.Printf("Status:   %s\n", n.Status)
 	fmt.Printf("Priority: %d\n", n.Priority)
 	fmt.Printf("Message:  %s\n", n.Message)
+	if n.RepeatCount > 1 {
+		fmt.Printf("Repeats:  ×%d\n", n.RepeatCount)
+	}
+	if n.EditedAt != nil {
+		fmt.Printf("Edited:   %s ago\n", formatAge(time.Since(*n.EditedAt)))
+	}
+	if n.Status == "retracted" {
+		fmt.Printf("Retracted: %s\n", retractedText(n))
+	}
 	if len(n.Options) > 0 {
 		fmt.Printf("Options:  %s\n", strings.Join(n.Options, ", "))
 	}
+	if n.ExpiresAt != nil && n.FirstResponse() == nil {
+		fmt.Printf("Expires:  %s\n", describeDeadline(n))
+	}
 	if r := n.FirstResponse(); r != nil {
 		fmt.Println()
 		fmt.Printf("Response: %s\n", r.Text)
@@ -72,12 +124,15 @@ func PrintNotification(n Notification) {
 			fmt.Printf("Selected: %s\n", r.SelectedOption)
 		}
 		fmt.Printf("Channel:  %s\n", r.Channel)
+		if r.Auto {
+			fmt.Println("Auto:     default applied (no reply before deadline)")
+		}
 	}
 }
 
 func PrintNotifications(notifications []Notification) {
 	if len(notifications) == 0 {
-		fmt.Println("No active notifications.")
+		fmt.Println("No matching notifications.")
 		return
 	}
 
@@ -94,9 +149,52 @@ func PrintNotifications(notifications []Notification) {
 	w.Flush()
 }
 
+// PrintSnoozed lists snoozed notifications, soonest to return first.
+func PrintSnoozed(notifications []Notification) {
+	if len(notifications) == 0 {
+		fmt.Println("Nothing is snoozed.")
+		return
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPRIORITY\tMESSAGE\tUNTIL")
+	for _, n := range notifications {
+		until := ""
+		if n.SnoozedUntil != nil {
+			until = snooze.Describe(*n.SnoozedUntil, now)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", n.ShortCode, n.Priority, truncate(n.Message, 50), until)
+	}
+	w.Flush()
+}
+
+// Progress is a live status line an agent keeps updating in place.
+type Progress struct {
+	Key         string  `json:"key"`
+	Message     string  `json:"message"`
+	Percent     *int    `json:"percent,omitempty"`
+	CompletedAt *string `json:"completedAt,omitempty"`
+	UpdatedAt   string  `json:"updatedAt"`
+}
+
+func PrintProgress(p Progress) {
+	state := "updated"
+	if p.CompletedAt != nil {
+		state = "done"
+	}
+	if p.Percent != nil {
+		fmt.Printf("Progress [%s] %d%% %s (%s)\n", p.Key, *p.Percent, p.Message, state)
+	} else {
+		fmt.Printf("Progress [%s] %s (%s)\n", p.Key, p.Message, state)
+	}
+}
+
 type SessionHistory struct {
 	SessionID     string         `json:"sessionId"`
+	SessionKey    string         `json:"sessionKey,omitempty"`
 	Workspace     string         `json:"workspace,omitempty"`
+	CreatedAt     *time.Time     `json:"createdAt,omitempty"`
 	Notifications []Notification `json:"notifications"`
 }
 
@@ -113,30 +211,52 @@ func PrintSessionHistory(h SessionHistory) {
 		return
 	}
 
-	for _, n := range h.Notifications {
+	for _, e := range Thread(h.Notifications) {
+		n := e.Notification
+		indent := strings.Repeat("    ", e.Depth)
+		marker := ""
+		if e.Depth > 0 {
+			marker = "↳ "
+		}
 		optStr := ""
 		if len(n.Options) > 0 {
 			optStr = " (" + strings.Join(n.Options, ", ") + ")"
 		}
-		fmt.Printf("[%s] %s%s\n", n.ShortCode, n.Message, optStr)
+		edited := ""
+		if n.EditedAt != nil {
+			edited = " (edited)"
+		}
+		fmt.Printf("%s%s[%s] %s%s%s\n", indent, marker, n.ShortCode, n.Message, optStr, edited)
 
 		if len(n.Responses) > 0 {
 			for i, r := range n.Responses {
 				age := formatAge(timeSince(r.CreatedAt))
 				idx := i + 1 // 1-based for react -r flag
-				if r.SelectedOption != "" {
-					fmt.Printf("  %d. Selected: %s (%s, %s ago)\n", idx, r.SelectedOption, r.Channel, age)
+				if r.Auto {
+					fmt.Printf("%s  %d. Defaulted to: %s (expired, %s ago)\n", indent, idx, r.SelectedOption, age)
+				} else if r.SelectedOption != "" {
+					fmt.Printf("%s  %d. Selected: %s (%s, %s ago)\n", indent, idx, r.SelectedOption, r.Channel, age)
 				} else if r.Text != "" {
-					fmt.Printf("  %d. %s (%s, %s ago)\n", idx, r.Text, r.Channel, age)
+					fmt.Printf("%s  %d. %s (%s, %s ago)\n", indent, idx, r.Text, r.Channel, age)
 				}
 			}
+		} else if n.Status == "retracted" {
+			fmt.Printf("%s  (retracted: %s)\n", indent, retractedText(n))
 		} else {
-			fmt.Println("  (awaiting response)")
+			fmt.Printf("%s  (awaiting response)\n", indent)
 		}
 		fmt.Println()
 	}
 }
 
+// retractedText explains a retraction, falling back when no reason was given.
+func retractedText(n Notification) string {
+	if n.RetractReason != "" {
+		return n.RetractReason
+	}
+	return "resolved by the agent"
+}
+
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -161,6 +281,10 @@ func PrintResponse(r Response) {
 }
 
 func PrintResponseWithContext(r ResponseWithContext) {
+	if r.Response.Auto {
+		fmt.Printf("[%s] Defaulted to: %s (no reply before deadline)\n", r.ShortCode, r.Response.SelectedOption)
+		return
+	}
 	if r.Response.SelectedOption != "" {
 		fmt.Printf("[%s] Selected: %s\n", r.ShortCode, r.Response.SelectedOption)
 	} else {
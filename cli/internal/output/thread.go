@@ -0,0 +1,77 @@
+package output
+
+// ThreadEntry is a notification positioned within its session's reply tree.
+type ThreadEntry struct {
+	Notification
+	Depth int // 0 for top-level questions
+}
+
+// Thread orders notifications depth-first so each follow-up appears directly
+// under the notification it replies to. Siblings keep their original
+// (chronological) order. A notification whose parent is not in the list is
+// treated as a root.
+func Thread(notifications []Notification) []ThreadEntry {
+	known := make(map[string]bool, len(notifications))
+	for _, n := range notifications {
+		known[n.ID] = true
+	}
+
+	children := make(map[string][]Notification)
+	var roots []Notification
+	for _, n := range notifications {
+		if n.ParentID != "" && known[n.ParentID] && n.ParentID != n.ID {
+			children[n.ParentID] = append(children[n.ParentID], n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+
+	result := make([]ThreadEntry, 0, len(notifications))
+	visited := make(map[string]bool, len(notifications))
+	var walk func(n Notification, depth int)
+	walk = func(n Notification, depth int) {
+		if visited[n.ID] {
+			return
+		}
+		visited[n.ID] = true
+		result = append(result, ThreadEntry{Notification: n, Depth: depth})
+		for _, c := range children[n.ID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, r := range roots {
+		walk(r, 0)
+	}
+	return result
+}
+
+// Subtree returns the IDs of the notification with the given short code (or
+// ID) and all of its descendants. It returns nil if no notification matches.
+func Subtree(notifications []Notification, code string) map[string]bool {
+	var root string
+	children := make(map[string][]string)
+	for _, n := range notifications {
+		if n.ShortCode == code || n.ID == code {
+			root = n.ID
+		}
+		if n.ParentID != "" {
+			children[n.ParentID] = append(children[n.ParentID], n.ID)
+		}
+	}
+	if root == "" {
+		return nil
+	}
+
+	ids := map[string]bool{}
+	queue := []string{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if ids[id] {
+			continue
+		}
+		ids[id] = true
+		queue = append(queue, children[id]...)
+	}
+	return ids
+}
@@ -0,0 +1,68 @@
+package output
+
+import "testing"
+
+func threadFixture() []Notification {
+	return []Notification{
+		{ID: "1", ShortCode: "AAA"},
+		{ID: "2", ShortCode: "BBB"},
+		{ID: "3", ShortCode: "CCC", ParentID: "1"},
+		{ID: "4", ShortCode: "DDD", ParentID: "3"},
+		{ID: "5", ShortCode: "EEE", ParentID: "1"},
+		{ID: "6", ShortCode: "FFF", ParentID: "missing"},
+	}
+}
+
+func TestThread_DepthFirstOrder(t *testing.T) {
+	got := Thread(threadFixture())
+
+	want := []struct {
+		code  string
+		depth int
+	}{
+		{"AAA", 0},
+		{"CCC", 1},
+		{"DDD", 2},
+		{"EEE", 1},
+		{"BBB", 0},
+		{"FFF", 0}, // orphan becomes a root
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].ShortCode != w.code || got[i].Depth != w.depth {
+			t.Errorf("entry %d = %s@%d, want %s@%d", i, got[i].ShortCode, got[i].Depth, w.code, w.depth)
+		}
+	}
+}
+
+func TestThread_SelfParentIsRoot(t *testing.T) {
+	got := Thread([]Notification{{ID: "1", ShortCode: "AAA", ParentID: "1"}})
+	if len(got) != 1 || got[0].Depth != 0 {
+		t.Fatalf("expected a single root, got %+v", got)
+	}
+}
+
+func TestSubtree(t *testing.T) {
+	ids := Subtree(threadFixture(), "AAA")
+	for _, id := range []string{"1", "3", "4", "5"} {
+		if !ids[id] {
+			t.Errorf("expected %s in subtree", id)
+		}
+	}
+	for _, id := range []string{"2", "6"} {
+		if ids[id] {
+			t.Errorf("did not expect %s in subtree", id)
+		}
+	}
+
+	if got := Subtree(threadFixture(), "CCC"); len(got) != 2 || !got["4"] {
+		t.Errorf("expected CCC subtree {3,4}, got %v", got)
+	}
+
+	if Subtree(threadFixture(), "ZZZ") != nil {
+		t.Error("expected nil for unknown short code")
+	}
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type WebhookSubscription struct {
+	ID             string   `json:"id"`
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`
+	Secret         string   `json:"secret,omitempty"`
+	LastDeliveryAt string   `json:"lastDeliveryAt,omitempty"`
+	LastStatus     *int     `json:"lastStatus,omitempty"`
+	LastError      string   `json:"lastError,omitempty"`
+	CreatedAt      string   `json:"createdAt"`
+}
+
+type WebhookTestResult struct {
+	OK     bool   `json:"ok"`
+	Status *int   `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func PrintWebhookSubscriptions(subs []WebhookSubscription) {
+	if len(subs) == 0 {
+		fmt.Println("No webhook subscriptions. Add one with: agentduty webhook add --url <url> --events notification.created")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tURL\tEVENTS\tLAST DELIVERY")
+	for _, s := range subs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.ID, s.URL, strings.Join(s.Events, ","), lastDelivery(s))
+	}
+	w.Flush()
+}
+
+func lastDelivery(s WebhookSubscription) string {
+	switch {
+	case s.LastDeliveryAt == "":
+		return "never"
+	case s.LastError != "":
+		return "failed: " + s.LastError
+	case s.LastStatus != nil:
+		return fmt.Sprintf("%d", *s.LastStatus)
+	default:
+		return "ok"
+	}
+}
+
+func PrintWebhookCreated(s WebhookSubscription) {
+	fmt.Printf("Subscribed %s to %s (id %s).\n", s.URL, strings.Join(s.Events, ", "), s.ID)
+	if s.Secret != "" {
+		fmt.Println()
+		fmt.Println("Signing secret (shown once; store it with your receiver):")
+		fmt.Printf("  %s\n", s.Secret)
+	}
+}
+
+func PrintWebhookTestResult(r WebhookTestResult) {
+	fmt.Println(webhookTestLine(r))
+}
+
+func webhookTestLine(r WebhookTestResult) string {
+	switch {
+	case r.OK && r.Status != nil:
+		return fmt.Sprintf("Ping delivered (HTTP %d).", *r.Status)
+	case r.OK:
+		return "Ping delivered."
+	default:
+		return "Ping failed: " + r.Error
+	}
+}
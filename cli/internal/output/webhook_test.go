@@ -0,0 +1,20 @@
+package output
+
+import "testing"
+
+func TestWebhookTestLine(t *testing.T) {
+	status := 204
+	tests := []struct {
+		result WebhookTestResult
+		want   string
+	}{
+		{WebhookTestResult{OK: true, Status: &status}, "Ping delivered (HTTP 204)."},
+		{WebhookTestResult{OK: true}, "Ping delivered."},
+		{WebhookTestResult{Error: "connection refused"}, "Ping failed: connection refused"},
+	}
+	for _, tt := range tests {
+		if got := webhookTestLine(tt.result); got != tt.want {
+			t.Errorf("webhookTestLine(%+v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
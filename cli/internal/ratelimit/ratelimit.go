@@ -0,0 +1,110 @@
+// Package ratelimit enforces the per-session notification limit locally so a
+// looping agent is told to back off before it reaches the server.
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExceededError is returned when a session has used up its window.
+type ExceededError struct {
+	Session    string
+	Limit      int
+	Window     time.Duration
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded: %d notifications per %s in session %s; retry in %s",
+		e.Limit, e.Window, e.Session, e.RetryAfter.Round(time.Second))
+}
+
+// Limiter records send times per session in small files under Dir.
+type Limiter struct {
+	Dir    string
+	Limit  int
+	Window time.Duration
+}
+
+// Check returns *ExceededError if session already sent Limit notifications
+// within the window ending at now. It records nothing; call Record once the
+// send has gone through, so failed sends don't use up the limit.
+func (l Limiter) Check(session string, now time.Time) error {
+	if l.Limit <= 0 || l.Window <= 0 {
+		return nil
+	}
+
+	sent := l.recent(l.path(session), now)
+	if len(sent) >= l.Limit {
+		return &ExceededError{
+			Session:    session,
+			Limit:      l.Limit,
+			Window:     l.Window,
+			RetryAfter: sent[0].Add(l.Window).Sub(now),
+		}
+	}
+	return nil
+}
+
+// Record notes a send for session at now. The state file is replaced
+// atomically, so a concurrent reader never sees it half written.
+func (l Limiter) Record(session string, now time.Time) error {
+	if l.Limit <= 0 || l.Window <= 0 {
+		return nil
+	}
+
+	path := l.path(session)
+	sent := append(l.recent(path, now), now)
+	if err := os.MkdirAll(l.Dir, 0700); err != nil {
+		return fmt.Errorf("rate limit state: %w", err)
+	}
+	data, _ := json.Marshal(sent)
+
+	tmp, err := os.CreateTemp(l.Dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("rate limit state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("rate limit state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("rate limit state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rate limit state: %w", err)
+	}
+	return nil
+}
+
+// recent returns the session's send times still inside the window, oldest
+// first. Missing or corrupt state counts as no sends.
+func (l Limiter) recent(path string, now time.Time) []time.Time {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var all []time.Time
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil
+	}
+	cutoff := now.Add(-l.Window)
+	var kept []time.Time
+	for _, t := range all {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+func (l Limiter) path(session string) string {
+	h := sha256.Sum256([]byte(session))
+	return filepath.Join(l.Dir, fmt.Sprintf("%x.json", h[:8]))
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// send checks and then records, the way a successful notify does.
+func send(l Limiter, session string, now time.Time) error {
+	if err := l.Check(session, now); err != nil {
+		return err
+	}
+	return l.Record(session, now)
+}
+
+func TestLimiter_BlocksAfterLimit(t *testing.T) {
+	l := Limiter{Dir: t.TempDir(), Limit: 2, Window: 10 * time.Minute}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := send(l, "s1", now); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	if err := send(l, "s1", now.Add(time.Minute)); err != nil {
+		t.Fatalf("second send: %v", err)
+	}
+
+	err := send(l, "s1", now.Add(2*time.Minute))
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("expected ExceededError, got %v", err)
+	}
+	if exceeded.RetryAfter != 8*time.Minute {
+		t.Errorf("RetryAfter = %s, want 8m", exceeded.RetryAfter)
+	}
+}
+
+func TestLimiter_WindowSlides(t *testing.T) {
+	l := Limiter{Dir: t.TempDir(), Limit: 1, Window: 10 * time.Minute}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := send(l, "s1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := send(l, "s1", now.Add(11*time.Minute)); err != nil {
+		t.Errorf("send after window: %v", err)
+	}
+}
+
+func TestLimiter_SessionsAreIndependent(t *testing.T) {
+	l := Limiter{Dir: t.TempDir(), Limit: 1, Window: time.Hour}
+	now := time.Now()
+
+	if err := send(l, "s1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := send(l, "s2", now); err != nil {
+		t.Errorf("other session should not be limited: %v", err)
+	}
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	l := Limiter{Dir: t.TempDir(), Limit: 0, Window: time.Hour}
+	for i := 0; i < 5; i++ {
+		if err := send(l, "s1", time.Now()); err != nil {
+			t.Fatalf("disabled limiter returned %v", err)
+		}
+	}
+}
+
+func TestLimiter_CheckAloneRecordsNothing(t *testing.T) {
+	l := Limiter{Dir: t.TempDir(), Limit: 1, Window: time.Hour}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := l.Check("s1", now); err != nil {
+			t.Fatalf("unrecorded (failed) sends must not count: %v", err)
+		}
+	}
+	if err := l.Record("s1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Check("s1", now); err == nil {
+		t.Error("expected the recorded send to count")
+	}
+}
+
+func TestLimiter_RecordLeavesNoTempFiles(t *testing.T) {
+	l := Limiter{Dir: t.TempDir(), Limit: 5, Window: time.Hour}
+	for i := 0; i < 3; i++ {
+		if err := l.Record("s1", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the state file, got %d entries", len(entries))
+	}
+}
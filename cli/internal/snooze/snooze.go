@@ -0,0 +1,259 @@
+// Package snooze turns what people type when snoozing ("45m", "until 14:30",
+// "tomorrow", "mon 10am") into the time the snooze ends.
+package snooze
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Times used when only a day is given.
+const (
+	morningHour = 9  // "tomorrow", "monday", "next week"
+	tonightHour = 20 // "tonight"
+	eodHour     = 17 // "eod", "end of day"
+)
+
+// Parse resolves s relative to now, in now's location. It accepts:
+//
+//	durations   45m, 2h, 1h30m, 1.5h, 3d, 1w, 90 (minutes), "2 hours",
+//	            "an hour", "half an hour", optionally after "in" or "for"
+//	clock times 14:30, 9am, 2:15pm, noon, midnight, optionally after
+//	            "until" or "at"; a time already past today means tomorrow
+//	days        today, tonight, tomorrow, eod, next week, monday (or mon,
+//	            next monday), each optionally followed by a time; a day
+//	            whose time has already passed is an error
+func Parse(s string, now time.Time) (time.Time, error) {
+	in := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	if in == "" {
+		return time.Time{}, fmt.Errorf("say how long to snooze, e.g. 45m, 14:30 or tomorrow")
+	}
+
+	clockOnly := false
+	for _, p := range []string{"until ", "till ", "til ", "at "} {
+		if rest, ok := strings.CutPrefix(in, p); ok {
+			in, clockOnly = rest, true
+			break
+		}
+	}
+	if !clockOnly {
+		for _, p := range []string{"in ", "for "} {
+			if rest, ok := strings.CutPrefix(in, p); ok {
+				in = rest
+				break
+			}
+		}
+		if d, ok := parseDuration(in); ok {
+			if d <= 0 {
+				return time.Time{}, fmt.Errorf("snooze must be longer than zero")
+			}
+			return now.Add(d), nil
+		}
+	}
+
+	day, rest, hour, ok := parseDay(in, now)
+	rest = strings.TrimPrefix(strings.TrimPrefix(rest, "at "), "@ ")
+	min := 0
+	if !ok || rest != "" {
+		var clockOK bool
+		if hour, min, clockOK = parseClock(rest); !clockOK {
+			return time.Time{}, fmt.Errorf("can't tell when %q is; try 45m, 2h, 14:30, 9am, tomorrow or mon 10:00", s)
+		}
+	}
+	if ok {
+		// "eod" at 21:00 names a time that has gone, as does "today 9am".
+		t := atClock(day, hour, min)
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("%s has already passed", Describe(t, now))
+		}
+		return t, nil
+	}
+	t := atClock(now, hour, min)
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Describe formats the end of a snooze for display: "14:30" today,
+// "Tue 09:00" within the week, "Jan 2 09:00" after that.
+func Describe(t, now time.Time) string {
+	t = t.In(now.Location())
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.Date()
+	switch {
+	case y1 == y2 && m1 == m2 && d1 == d2:
+		return t.Format("15:04")
+	case t.After(now) && t.Sub(now) < 6*24*time.Hour:
+		return t.Format("Mon 15:04")
+	default:
+		return t.Format("Jan 2 15:04")
+	}
+}
+
+var durationPart = regexp.MustCompile(`^(\d+(?:\.\d+)?) ?([a-z]+)(?: and | |,? |$)`)
+
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// parseDuration reads "45m", "1h30m", "2 hours and 15 minutes", "an hour"
+// or a bare number of minutes.
+func parseDuration(s string) (time.Duration, bool) {
+	switch s {
+	case "half an hour", "half hour":
+		return 30 * time.Minute, true
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return time.Duration(n) * time.Minute, true
+	}
+	for _, a := range []string{"an ", "a "} {
+		if rest, ok := strings.CutPrefix(s, a); ok {
+			s = "1 " + rest
+			break
+		}
+	}
+
+	// Split glued units ("1h30m") so each number is followed by its unit.
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && r >= '0' && r <= '9' && s[i-1] >= 'a' && s[i-1] <= 'z' {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	s = b.String()
+
+	var total time.Duration
+	for s != "" {
+		m := durationPart.FindStringSubmatch(s)
+		if m == nil {
+			return 0, false
+		}
+		unit, ok := durationUnits[m[2]]
+		if !ok {
+			return 0, false
+		}
+		n, _ := strconv.ParseFloat(m[1], 64)
+		total += time.Duration(n * float64(unit))
+		s = s[len(m[0]):]
+	}
+	return total, true
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseDay reads a leading day word, returning that day, the text after it
+// and the hour to use when no time follows.
+func parseDay(s string, now time.Time) (time.Time, string, int, bool) {
+	for _, w := range []string{"end of day", "next week"} {
+		if rest, ok := strings.CutPrefix(s, w); ok && (rest == "" || rest[0] == ' ') {
+			if w == "end of day" {
+				return now, strings.TrimSpace(rest), eodHour, true
+			}
+			return nextWeekday(now, time.Monday), strings.TrimSpace(rest), morningHour, true
+		}
+	}
+
+	word, rest, _ := strings.Cut(s, " ")
+	next := false
+	if word == "next" {
+		next = true
+		word, rest, _ = strings.Cut(rest, " ")
+	}
+	switch word {
+	case "today":
+		if !next {
+			return now, rest, morningHour, true
+		}
+	case "tonight":
+		if !next {
+			return now, rest, tonightHour, true
+		}
+	case "eod":
+		if !next {
+			return now, rest, eodHour, true
+		}
+	case "tomorrow", "tmrw", "tmr":
+		if !next {
+			return now.AddDate(0, 0, 1), rest, morningHour, true
+		}
+	}
+	if wd, ok := weekdays[word]; ok {
+		return nextWeekday(now, wd), rest, morningHour, true
+	}
+	return time.Time{}, s, 0, false
+}
+
+// nextWeekday is the next wd strictly after today.
+func nextWeekday(now time.Time, wd time.Weekday) time.Time {
+	days := (int(wd) - int(now.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return now.AddDate(0, 0, days)
+}
+
+var clockRe = regexp.MustCompile(`^(\d{1,2})(?:[:.](\d{2}))? ?(am|pm|a|p)?$`)
+
+// parseClock reads "14:30", "9am", "2:15 pm", "noon" or "midnight".
+func parseClock(s string) (int, int, bool) {
+	switch s {
+	case "noon", "midday":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	min := 0
+	if m[2] != "" {
+		min, _ = strconv.Atoi(m[2])
+	}
+	if min > 59 {
+		return 0, 0, false
+	}
+	switch m[3] {
+	case "am", "a":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm", "p":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, false
+		}
+	}
+	return hour, min, true
+}
+
+func atClock(day time.Time, hour, min int) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, hour, min, 0, 0, day.Location())
+}
@@ -0,0 +1,114 @@
+package snooze
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// now is Wednesday 2026-03-04 13:00 UTC.
+var now = time.Date(2026, 3, 4, 13, 0, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"45m", "2026-03-04 13:45"},
+		{"2h", "2026-03-04 15:00"},
+		{"1h30m", "2026-03-04 14:30"},
+		{"1.5h", "2026-03-04 14:30"},
+		{"90", "2026-03-04 14:30"},
+		{"in 2 hours", "2026-03-04 15:00"},
+		{"for 1 hour and 15 minutes", "2026-03-04 14:15"},
+		{"an hour", "2026-03-04 14:00"},
+		{"half an hour", "2026-03-04 13:30"},
+		{"3d", "2026-03-07 13:00"},
+		{"1w", "2026-03-11 13:00"},
+		{"until 14:30", "2026-03-04 14:30"},
+		{"14:30", "2026-03-04 14:30"},
+		{"until 9", "2026-03-05 09:00"},
+		{"at 2:15pm", "2026-03-04 14:15"},
+		{"9am", "2026-03-05 09:00"},
+		{"12am", "2026-03-05 00:00"},
+		{"noon", "2026-03-05 12:00"},
+		{"Tomorrow", "2026-03-05 09:00"},
+		{"tomorrow 14:00", "2026-03-05 14:00"},
+		{"tomorrow at 2pm", "2026-03-05 14:00"},
+		{"tonight", "2026-03-04 20:00"},
+		{"eod", "2026-03-04 17:00"},
+		{"end of day", "2026-03-04 17:00"},
+		{"next week", "2026-03-09 09:00"},
+		{"mon", "2026-03-09 09:00"},
+		{"next friday 10am", "2026-03-06 10:00"},
+		{"wednesday", "2026-03-11 09:00"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if s := got.Format("2006-01-02 15:04"); s != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, s, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "say how long"},
+		{"soonish", "can't tell when"},
+		{"25:00", "can't tell when"},
+		{"13pm", "can't tell when"},
+		{"0m", "longer than zero"},
+		{"today 9am", "already passed"},
+		{"5 parsecs", "can't tell when"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in, now)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestParse_Evening(t *testing.T) {
+	evening := time.Date(2026, 3, 4, 21, 0, 0, 0, time.UTC)
+	for _, in := range []string{"today", "tonight", "eod", "end of day", "today 17:00"} {
+		_, err := Parse(in, evening)
+		if err == nil || !strings.Contains(err.Error(), "already passed") {
+			t.Errorf("Parse(%q) at 21:00 error = %v, want already passed", in, err)
+		}
+	}
+
+	for in, want := range map[string]string{
+		"tomorrow": "2026-03-05 09:00",
+		"20:00":    "2026-03-05 20:00",
+		"today 22": "2026-03-04 22:00",
+	} {
+		got, err := Parse(in, evening)
+		if err != nil || got.Format("2006-01-02 15:04") != want {
+			t.Errorf("Parse(%q) at 21:00 = %v, %v; want %s", in, got, err, want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(time.Hour), "14:00"},
+		{now.Add(24 * time.Hour), "Thu 13:00"},
+		{now.Add(10 * 24 * time.Hour), "Mar 14 13:00"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.t, now); got != tt.want {
+			t.Errorf("Describe(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
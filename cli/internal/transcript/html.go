@@ -0,0 +1,72 @@
+package transcript
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+)
+
+var htmlTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"thread":       output.Thread,
+	"sessionTitle": sessionTitle,
+	"formatTime":   formatTime,
+	"timestamp":    formatTimestamp,
+	"responder":    responder,
+	"responseText": responseText,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>AgentDuty transcript</title>
+<style>
+body { font: 15px/1.5 system-ui, sans-serif; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+section { margin-bottom: 2.5rem; }
+article { border-left: 3px solid #d0d7de; padding: .25rem 0 .25rem 1rem; margin: 1rem 0; }
+article.reply { margin-left: 2rem; }
+.code { font-family: ui-monospace, monospace; font-weight: 600; }
+.meta { color: #656d76; font-size: 13px; }
+.message { white-space: pre-wrap; margin: .25rem 0; }
+.tag, .option { display: inline-block; border: 1px solid #d0d7de; border-radius: 1rem; padding: 0 .5rem; font-size: 12px; margin-right: .25rem; }
+ol { padding-left: 1.25rem; }
+</style>
+</head>
+<body>
+<h1>AgentDuty transcript</h1>
+{{- range .}}
+<section>
+<h2>{{sessionTitle .}}</h2>
+<p class="meta">{{with .CreatedAt}}Started {{formatTime .}} · {{end}}{{len .Notifications}} notification(s)</p>
+{{- range $e := thread .Notifications}}{{with $e.Notification}}
+<article{{if $e.Depth}} class="reply"{{end}} id="{{.ShortCode}}">
+<p class="meta"><span class="code">[{{.ShortCode}}]</span> P{{.Priority}} · {{.Status}} · {{formatTime .CreatedAt}}{{with .EditedAt}} · edited {{formatTime .}}{{end}}</p>
+<p class="message">{{.Message}}</p>
+{{- if .Tags}}
+<p>{{range .Tags}}<span class="tag">{{.}}</span>{{end}}</p>
+{{- end}}
+{{- if .Options}}
+<p>{{range .Options}}<span class="option">{{.}}</span>{{end}}</p>
+{{- end}}
+{{- if and (eq .Status "retracted") .RetractReason}}
+<p class="meta">Retracted: {{.RetractReason}}</p>
+{{- end}}
+{{- if .Responses}}
+<ol>
+{{- range .Responses}}
+<li><span class="meta">{{responder .}} via {{.Channel}} · {{timestamp .CreatedAt}}</span><br>{{responseText .}}
+{{- range .Reactions}} <span class="meta">:{{.Emoji}}:</span>{{end}}</li>
+{{- end}}
+</ol>
+{{- end}}
+</article>
+{{- end}}{{end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML writes a standalone page with inline styles.
+func HTML(w io.Writer, sessions []output.SessionHistory) error {
+	return htmlTemplate.Execute(w, sessions)
+}
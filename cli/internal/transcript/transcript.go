@@ -0,0 +1,174 @@
+// Package transcript renders session histories for sharing and
+// post-mortems: Markdown, JSON Lines and a standalone HTML page.
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{"md", "jsonl", "html"}
+
+// Write renders sessions to w in the given format.
+func Write(w io.Writer, format string, sessions []output.SessionHistory) error {
+	switch format {
+	case "md":
+		return Markdown(w, sessions)
+	case "jsonl":
+		return JSONL(w, sessions)
+	case "html":
+		return HTML(w, sessions)
+	default:
+		return fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Record is one JSON Lines record: a notification with its session.
+type Record struct {
+	SessionKey string `json:"sessionKey,omitempty"`
+	Workspace  string `json:"workspace,omitempty"`
+	output.Notification
+}
+
+// JSONL writes one notification per line, in thread order.
+func JSONL(w io.Writer, sessions []output.SessionHistory) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, s := range sessions {
+		for _, e := range output.Thread(s.Notifications) {
+			if err := enc.Encode(Record{SessionKey: s.SessionKey, Workspace: s.Workspace, Notification: e.Notification}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Markdown writes a heading per session and per notification, with options,
+// responses and reactions as lists. Replies are nested under their parent.
+func Markdown(w io.Writer, sessions []output.SessionHistory) error {
+	var b strings.Builder
+	b.WriteString("# AgentDuty transcript\n")
+
+	for _, s := range sessions {
+		fmt.Fprintf(&b, "\n## %s\n\n", sessionTitle(s))
+		if s.CreatedAt != nil {
+			fmt.Fprintf(&b, "Started %s · ", formatTime(*s.CreatedAt))
+		}
+		fmt.Fprintf(&b, "%d notification(s)\n", len(s.Notifications))
+
+		for _, e := range output.Thread(s.Notifications) {
+			n := e.Notification
+			fmt.Fprintf(&b, "\n### [%s] %s\n\n", n.ShortCode, firstLine(n.Message))
+			if e.Depth > 0 {
+				fmt.Fprintf(&b, "_Follow-up to [%s]_\n\n", parentCode(s.Notifications, n.ParentID))
+			}
+			fmt.Fprintf(&b, "- Sent: %s\n", formatTime(n.CreatedAt))
+			fmt.Fprintf(&b, "- Priority: P%d · Status: %s\n", n.Priority, n.Status)
+			if len(n.Tags) > 0 {
+				fmt.Fprintf(&b, "- Tags: %s\n", strings.Join(n.Tags, ", "))
+			}
+			if len(n.Options) > 0 {
+				fmt.Fprintf(&b, "- Options: %s\n", strings.Join(n.Options, " / "))
+			}
+			if n.EditedAt != nil {
+				fmt.Fprintf(&b, "- Edited: %s\n", formatTime(*n.EditedAt))
+			}
+			if n.Status == "retracted" && n.RetractReason != "" {
+				fmt.Fprintf(&b, "- Retracted: %s\n", n.RetractReason)
+			}
+			if strings.Contains(n.Message, "\n") {
+				b.WriteString("\n")
+				for _, line := range strings.Split(n.Message, "\n") {
+					fmt.Fprintf(&b, "> %s\n", line)
+				}
+			}
+
+			if len(n.Responses) > 0 {
+				b.WriteString("\n**Responses**\n\n")
+				for i, r := range n.Responses {
+					fmt.Fprintf(&b, "%d. %s — %s\n", i+1, responseMeta(r), responseText(r))
+					for _, re := range r.Reactions {
+						fmt.Fprintf(&b, "   - reacted :%s: at %s\n", re.Emoji, formatTimestamp(re.CreatedAt))
+					}
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sessionTitle(s output.SessionHistory) string {
+	title := "Session " + s.SessionKey
+	if s.SessionKey == "" {
+		title = "Session " + s.SessionID
+	}
+	if s.Workspace != "" {
+		title += " — " + s.Workspace
+	}
+	return title
+}
+
+func parentCode(notifications []output.Notification, parentID string) string {
+	for _, n := range notifications {
+		if n.ID == parentID {
+			return n.ShortCode
+		}
+	}
+	return parentID
+}
+
+func responseMeta(r output.Response) string {
+	return fmt.Sprintf("**%s** via %s at %s", responder(r), r.Channel, formatTimestamp(r.CreatedAt))
+}
+
+func responder(r output.Response) string {
+	switch {
+	case r.Auto:
+		return "default (no reply before deadline)"
+	case r.Responder != "":
+		return r.Responder
+	default:
+		return "someone"
+	}
+}
+
+func responseText(r output.Response) string {
+	switch {
+	case r.SelectedOption != "" && r.Text != "":
+		return fmt.Sprintf("selected %q: %s", r.SelectedOption, r.Text)
+	case r.SelectedOption != "":
+		return fmt.Sprintf("selected %q", r.SelectedOption)
+	default:
+		return r.Text
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+const timeLayout = "2006-01-02 15:04:05 UTC"
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// formatTimestamp formats an RFC 3339 string from the API, passing through
+// anything it can't parse.
+func formatTimestamp(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return formatTime(t)
+}
@@ -0,0 +1,113 @@
+package transcript
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func fixture() []output.SessionHistory {
+	started := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	edited := started.Add(2 * time.Minute)
+	return []output.SessionHistory{
+		{
+			SessionID:  "0d7a1e52-5a0c-4d5f-9b1e-2f3c4d5e6f70",
+			SessionKey: "a1b2c3d4",
+			Workspace:  "/home/dev/api",
+			CreatedAt:  &started,
+			Notifications: []output.Notification{
+				{
+					ID:        "n1",
+					ShortCode: "K3X",
+					Status:    "responded",
+					Priority:  4,
+					Message:   "Run the <prod> migration now?\nIt locks the users table for ~30s.",
+					Tags:      []string{"deploy", "db"},
+					Options:   []string{"Run it", "Wait"},
+					CreatedAt: started,
+					EditedAt:  &edited,
+					Responses: []output.Response{
+						{
+							SelectedOption: "Wait",
+							Text:           "after 6pm & not before",
+							Channel:        "slack",
+							Responder:      "Dana",
+							CreatedAt:      "2025-03-04T09:05:00.000Z",
+							Reactions: []output.Reaction{
+								{Emoji: "thumbsup", CreatedAt: "2025-03-04T09:05:30.000Z"},
+							},
+						},
+					},
+				},
+				{
+					ID:        "n2",
+					ShortCode: "M2Q",
+					ParentID:  "n1",
+					Status:    "expired",
+					Priority:  3,
+					Message:   "Scheduled for 18:00. Proceed then?",
+					Options:   []string{"Yes", "No"},
+					CreatedAt: started.Add(10 * time.Minute),
+					Responses: []output.Response{
+						{SelectedOption: "Yes", Channel: "web", Auto: true, CreatedAt: "2025-03-04T09:40:00.000Z"},
+					},
+				},
+				{
+					ID:            "n3",
+					ShortCode:     "Z9P",
+					Status:        "retracted",
+					Priority:      2,
+					Message:       "Should I bump the linter?",
+					RetractReason: "decided on my own",
+					CreatedAt:     started.Add(20 * time.Minute),
+				},
+			},
+		},
+		{
+			SessionID:  "9f8e7d6c-0000-4000-8000-000000000000",
+			SessionKey: "ffee0011",
+			CreatedAt:  &started,
+			Notifications: []output.Notification{
+				{ID: "n4", ShortCode: "B7T", Status: "delivered", Priority: 3, Message: "FYI: docs rebuilt", CreatedAt: started.Add(time.Hour)},
+			},
+		},
+	}
+}
+
+func TestWrite_Golden(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, format, fixture()); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "transcript."+format+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test ./internal/transcript -update)", err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("%s output differs from %s:\n%s", format, golden, got)
+			}
+		})
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "pdf", nil); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
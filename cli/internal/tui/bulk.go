@@ -0,0 +1,372 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/client"
+)
+
+// Multi-select: space marks the focused notification and `V` starts a range
+// that follows the cursor until `V` is pressed again. Reply, option, snooze
+// and archive then act on the whole selection, and `T` tags it.
+//
+// Bulk actions hide their items straight away but wait undoWindow before
+// calling the server, showing an undo toast meanwhile. Undo puts the items
+// back; otherwise (or on quit) the pending action is sent. Archiving several
+// notifications, or everything with `A`, asks for confirmation first.
+
+const undoWindow = 5 * time.Second
+
+// pendingBulk is a bulk action waiting out its undo window.
+type pendingBulk struct {
+	seq  int
+	verb string
+	ids  []string
+	run  tea.Cmd
+}
+
+// confirmPrompt is a yes/no question guarding a destructive bulk action.
+type confirmPrompt struct {
+	question string
+	ids      []string
+}
+
+type bulkCommitMsg struct {
+	seq int
+}
+
+type bulkDoneMsg struct {
+	verb   string
+	failed []string
+	err    error
+}
+
+type taggedMsg struct {
+	count int
+	tags  []string
+	err   error
+}
+
+func newTagInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Tags: "
+	ti.Placeholder = "comma-separated"
+	ti.CharLimit = 100
+	return ti
+}
+
+// selection is every marked notification plus the active range, in list
+// order. It is empty when nothing is marked.
+func (m Model) selection() []feedNotification {
+	visible := m.visibleItems()
+	lo, hi := m.visualRange(visible)
+	var out []feedNotification
+	for i, n := range visible {
+		if m.marked[n.ID] || (i >= lo && i <= hi) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// visualRange is the index span of the `V` range in visible, or (-1, -2)
+// when no range is active.
+func (m Model) visualRange(visible []feedNotification) (int, int) {
+	if m.anchor == "" {
+		return -1, -2
+	}
+	for i, n := range visible {
+		if n.ID == m.anchor {
+			return min(i, m.cursor), max(i, m.cursor)
+		}
+	}
+	return -1, -2
+}
+
+func (m Model) isSelected(visible []feedNotification, i int) bool {
+	if i < 0 || i >= len(visible) {
+		return false
+	}
+	lo, hi := m.visualRange(visible)
+	return m.marked[visible[i].ID] || (i >= lo && i <= hi)
+}
+
+func idsOf(items []feedNotification) []string {
+	out := make([]string, len(items))
+	for i, n := range items {
+		out[i] = n.ID
+	}
+	return out
+}
+
+// handleSelectKey handles marking and the bulk versions of browsing keys.
+// ok is false when msg should fall through to the single-item handling.
+func (m Model) handleSelectKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Mark):
+		visible := m.visibleItems()
+		if m.cursor >= len(visible) {
+			return m, nil, true
+		}
+		id := visible[m.cursor].ID
+		if m.marked[id] {
+			delete(m.marked, id)
+		} else {
+			m.marked[id] = true
+		}
+		if m.cursor < len(visible)-1 {
+			m.cursor++
+		}
+		m.status = m.selectionStatus()
+		return m, nil, true
+
+	case key.Matches(msg, m.keys.MarkRange):
+		visible := m.visibleItems()
+		if m.anchor != "" {
+			for _, n := range m.selection() {
+				m.marked[n.ID] = true
+			}
+			m.anchor = ""
+			m.status = m.selectionStatus()
+		} else if m.cursor < len(visible) {
+			m.anchor = visible[m.cursor].ID
+			m.status = "Range: move to extend · " + m.keys.MarkRange.Help().Key + " to mark · " + m.keys.Back.Help().Key + " to cancel"
+		}
+		return m, nil, true
+
+	case key.Matches(msg, m.keys.Undo):
+		return m.undo(), nil, true
+
+	case key.Matches(msg, m.keys.AddTag):
+		if len(m.targets()) == 0 {
+			return m, nil, true
+		}
+		m.state = stateTagInput
+		m.tagInput.Reset()
+		m.status = fmt.Sprintf("Tag %s · Enter to apply · Esc to cancel", m.describeTargets())
+		return m, m.tagInput.Focus(), true
+
+	case key.Matches(msg, m.keys.ArchiveAll):
+		// Only what is on screen now: items the filter hides, or that
+		// arrive before the undo window closes, are left alone.
+		shown := idsOf(m.visibleItems())
+		if len(shown) == 0 {
+			return m, nil, true
+		}
+		m.confirm = &confirmPrompt{
+			question: fmt.Sprintf("Archive all %d shown notifications?", len(shown)),
+			ids:      shown,
+		}
+		m.state = stateConfirm
+		return m, nil, true
+	}
+
+	sel := m.selection()
+	if len(sel) == 0 {
+		return m, nil, false
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.marked = make(map[string]bool)
+		m.anchor = ""
+		m.status = "Selection cleared"
+		return m, nil, true
+	case key.Matches(msg, m.keys.Archive):
+		m.confirm = &confirmPrompt{
+			question: fmt.Sprintf("Archive %d notifications?", len(sel)),
+			ids:      idsOf(sel),
+		}
+		m.state = stateConfirm
+		return m, nil, true
+	}
+
+	if s := msg.String(); len(s) == 1 && s[0] >= '1' && s[0] <= '9' {
+		// Options are answered by label, taken from the focused item (or the
+		// first selected one), so differently ordered options still match.
+		idx := int(s[0]-'0') - 1
+		src := &sel[0]
+		if m.isSelected(m.visibleItems(), m.cursor) {
+			src = m.focusedItem()
+		}
+		if idx >= len(src.Options) {
+			return m, nil, true
+		}
+		opt := src.Options[idx]
+		var matching []string
+		for _, n := range sel {
+			for _, o := range n.Options {
+				if o == opt {
+					matching = append(matching, n.ID)
+					break
+				}
+			}
+		}
+		verb := fmt.Sprintf("Answered %q on", opt)
+		c := m.client
+		return m.beginBulk(verb, matching, eachID(verb, matching, func(id string) error {
+			return submitResponse(c, id, nil, &opt)
+		}))
+	}
+	return m, nil, false
+}
+
+// targets is what an action applies to: the selection, or else the focused
+// notification.
+func (m Model) targets() []feedNotification {
+	if sel := m.selection(); len(sel) > 0 {
+		return sel
+	}
+	if n := m.focusedItem(); n != nil {
+		return []feedNotification{*n}
+	}
+	return nil
+}
+
+func (m Model) describeTargets() string {
+	t := m.targets()
+	if len(t) == 1 {
+		return t[0].ShortCode
+	}
+	return fmt.Sprintf("%d notifications", len(t))
+}
+
+func (m Model) selectionStatus() string {
+	n := len(m.selection())
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d selected", n)
+}
+
+// beginBulk hides ids and schedules run once the undo window passes. Any
+// bulk action already waiting is sent now.
+func (m Model) beginBulk(verb string, ids []string, run tea.Cmd) (Model, tea.Cmd, bool) {
+	if len(ids) == 0 {
+		m.status = "Nothing to " + strings.ToLower(strings.Fields(verb)[0])
+		return m, nil, true
+	}
+	var flush tea.Cmd
+	if m.pending != nil {
+		flush = m.pending.run
+	}
+
+	m.bulkSeq++
+	m.pending = &pendingBulk{seq: m.bulkSeq, verb: verb, ids: ids, run: run}
+	for _, id := range ids {
+		m.hidden[id] = true
+	}
+	m.marked = make(map[string]bool)
+	m.anchor = ""
+	if m.cursor >= len(m.visibleItems()) {
+		m.cursor = max(0, len(m.visibleItems())-1)
+	}
+	m.status = fmt.Sprintf("%s %d · %s to undo", verb, len(ids), m.keys.Undo.Help().Key)
+
+	seq := m.bulkSeq
+	commit := tea.Tick(undoWindow, func(time.Time) tea.Msg { return bulkCommitMsg{seq: seq} })
+	return m, tea.Batch(flush, commit), true
+}
+
+// eachID runs op for every id in turn, collecting the ones that fail.
+func eachID(verb string, ids []string, op func(id string) error) tea.Cmd {
+	return func() tea.Msg {
+		var failed []string
+		var errs []error
+		for _, id := range ids {
+			if err := op(id); err != nil {
+				failed = append(failed, id)
+				errs = append(errs, err)
+			}
+		}
+		return bulkDoneMsg{verb: verb, failed: failed, err: errors.Join(errs...)}
+	}
+}
+
+// undo cancels the pending bulk action and shows its items again.
+func (m Model) undo() Model {
+	if m.pending == nil {
+		m.status = "Nothing to undo"
+		return m
+	}
+	for _, id := range m.pending.ids {
+		delete(m.hidden, id)
+	}
+	m.status = fmt.Sprintf("Undone: %s %d", strings.ToLower(m.pending.verb), len(m.pending.ids))
+	m.pending = nil
+	return m
+}
+
+func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.confirm = nil
+		m.state = stateBrowsing
+		cl := m.client
+		next, cmd, _ := m.beginBulk("Archived", c.ids, eachID("Archived", c.ids, func(id string) error {
+			return archiveNotificationReq(cl, id)
+		}))
+		return next, cmd
+	case "n", "N", "esc", "ctrl+g":
+		m.confirm = nil
+		m.state = stateBrowsing
+		m.status = "Cancelled"
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) handleTagKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		m.tagInput.Blur()
+		m.state = stateBrowsing
+		m.status = ""
+		return m, nil
+	case "enter":
+		tags := parseTags(m.tagInput.Value())
+		m.tagInput.Blur()
+		m.state = stateBrowsing
+		if len(tags) == 0 {
+			m.status = ""
+			return m, nil
+		}
+		targets := idsOf(m.targets())
+		m.marked = make(map[string]bool)
+		m.anchor = ""
+		m.status = fmt.Sprintf("Tagging %d...", len(targets))
+		return m, tagCmd(m.client, targets, tags)
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// parseTags splits "a, b c" into ["a", "b", "c"], dropping a leading '#'.
+func parseTags(s string) []string {
+	var tags []string
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if t := strings.TrimPrefix(f, "#"); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+func tagCmd(c *client.Client, ids []string, tags []string) tea.Cmd {
+	return func() tea.Msg {
+		err := tagNotifications(c, ids, tags)
+		return taggedMsg{count: len(ids), tags: tags, err: err}
+	}
+}
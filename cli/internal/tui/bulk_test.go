@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+func bulkModel(t *testing.T) Model {
+	m := newTestModel(t, config.FeedFilter{})
+	m.width, m.height = 100, 40
+	m.items = filterItems()
+	return m
+}
+
+func press(t *testing.T, m Model, keys ...string) Model {
+	t.Helper()
+	for _, k := range keys {
+		next, _ := m.Update(keyPress(k))
+		m = next.(Model)
+	}
+	return m
+}
+
+func selectedIDs(m Model) string {
+	var out []string
+	for _, n := range m.selection() {
+		out = append(out, n.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestMark_SpaceAndRange(t *testing.T) {
+	m := bulkModel(t)
+
+	m = press(t, m, " ", " ")
+	if got := selectedIDs(m); got != "a,b" {
+		t.Fatalf("expected space to mark and advance, got %s", got)
+	}
+	m = press(t, m, "k", " ", " ")
+	if got := selectedIDs(m); got != "a,c" {
+		t.Fatalf("expected space to toggle a mark off, got %s", got)
+	}
+
+	m = press(t, m, "esc")
+	if got := selectedIDs(m); got != "" {
+		t.Fatalf("expected back to clear the selection, got %s", got)
+	}
+
+	m = press(t, m, "k", "k", "V", "j", "j")
+	if got := selectedIDs(m); got != "b,c,d" {
+		t.Fatalf("expected the range to follow the cursor, got %s", got)
+	}
+	m = press(t, m, "V", "k", "k", "k")
+	if got := selectedIDs(m); got != "b,c,d" || m.anchor != "" {
+		t.Fatalf("expected V to fix the range as marks, got %s", got)
+	}
+	if !strings.Contains(m.View(), "3 selected") {
+		t.Errorf("expected header to show the selection, got:\n%s", m.View())
+	}
+}
+
+func TestBulkArchive_ConfirmAndUndo(t *testing.T) {
+	m := bulkModel(t)
+	m = press(t, m, " ", " ", "a")
+	if m.state != stateConfirm || !strings.Contains(m.View(), "Archive 2 notifications?") {
+		t.Fatalf("expected a confirmation, got state=%v:\n%s", m.state, m.View())
+	}
+
+	next, cmd := m.Update(keyPress("y"))
+	m = next.(Model)
+	if cmd == nil || m.pending == nil || !m.hidden["a"] || !m.hidden["b"] {
+		t.Fatalf("expected archive to hide both and wait out the undo window")
+	}
+	if len(m.marked) != 0 || !strings.Contains(m.status, "u to undo") {
+		t.Errorf("expected marks cleared and an undo toast, got %q", m.status)
+	}
+
+	m = press(t, m, "u")
+	if m.pending != nil || m.hidden["a"] || m.hidden["b"] {
+		t.Fatal("expected undo to cancel the action and show the items again")
+	}
+	if got := ids(m.visibleItems()); got != "a,b,c,d" {
+		t.Errorf("expected all items back, got %s", got)
+	}
+}
+
+func TestBulkArchive_Cancel(t *testing.T) {
+	m := bulkModel(t)
+	m = press(t, m, "A")
+	if m.state != stateConfirm || !strings.Contains(m.confirm.question, "all 4") {
+		t.Fatalf("expected A to ask first, got state=%v", m.state)
+	}
+	m = press(t, m, "n")
+	if m.state != stateBrowsing || len(m.hidden) != 0 || m.pending != nil {
+		t.Errorf("expected n to cancel without hiding anything")
+	}
+}
+
+func TestBulkArchive_AllOnlyShown(t *testing.T) {
+	m := newTestModel(t, config.FeedFilter{Search: "deploy"})
+	m.width, m.height = 100, 40
+	m.items = filterItems()
+
+	m = press(t, m, "A")
+	if m.state != stateConfirm || !strings.Contains(m.confirm.question, "all 2") {
+		t.Fatalf("expected A to count only the filtered items, got %+v", m.confirm)
+	}
+	m = press(t, m, "y")
+	m.items = append(m.items, feedNotification{ID: "e", Message: "Another deploy?"})
+
+	if m.pending == nil || !reflect.DeepEqual(m.pending.ids, []string{"a", "c"}) {
+		t.Fatalf("expected exactly the counted items to be archived, got %+v", m.pending)
+	}
+	if m.hidden["b"] || m.hidden["d"] || m.hidden["e"] {
+		t.Error("expected filtered-out and newly arrived items to be left alone")
+	}
+}
+
+func TestBulkCommit(t *testing.T) {
+	m := bulkModel(t)
+	ran := false
+	m, _, _ = m.beginBulk("Archived", []string{"a"}, func() tea.Msg {
+		ran = true
+		return bulkDoneMsg{verb: "Archived"}
+	})
+
+	// A commit from an earlier, undone action is ignored.
+	next, cmd := m.Update(bulkCommitMsg{seq: m.pending.seq - 1})
+	m = next.(Model)
+	if cmd != nil || m.pending == nil {
+		t.Fatal("expected stale commit to be ignored")
+	}
+
+	next, cmd = m.Update(bulkCommitMsg{seq: m.pending.seq})
+	m = next.(Model)
+	if cmd == nil || m.pending != nil {
+		t.Fatal("expected commit to hand back the pending action")
+	}
+	cmd()
+	if !ran {
+		t.Error("expected the pending action to run")
+	}
+}
+
+func TestBulkDone_RestoresFailures(t *testing.T) {
+	m := bulkModel(t)
+	m.hidden = map[string]bool{"a": true, "b": true}
+	next, _ := m.Update(bulkDoneMsg{verb: "Snoozed", failed: []string{"b"}, err: errors.New("boom")})
+	m = next.(Model)
+	if !m.hidden["a"] || m.hidden["b"] {
+		t.Errorf("expected only the failed item back, got %v", m.hidden)
+	}
+	if !strings.Contains(m.status, "boom") {
+		t.Errorf("expected the error in the status, got %q", m.status)
+	}
+}
+
+func TestEachID(t *testing.T) {
+	var seen []string
+	msg := eachID("Archived", []string{"a", "b", "c"}, func(id string) error {
+		seen = append(seen, id)
+		if id == "b" {
+			return errors.New("nope")
+		}
+		return nil
+	})().(bulkDoneMsg)
+	if len(seen) != 3 || !reflect.DeepEqual(msg.failed, []string{"b"}) || msg.err == nil {
+		t.Errorf("unexpected result %+v after %v", msg, seen)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got := parseTags(" later, #deploy  infra,,")
+	if !reflect.DeepEqual(got, []string{"later", "deploy", "infra"}) {
+		t.Errorf("unexpected tags %v", got)
+	}
+}
@@ -0,0 +1,351 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+// Composing replies: whatever is in the reply box when it is closed with Esc
+// is kept as a draft for that notification, in drafts.json next to the
+// config, and comes back the next time the box opens. Canned responses from
+// `tui.canned` are inserted with ctrl+t, and ctrl+o hands the reply to
+// $VISUAL/$EDITOR for longer answers.
+//
+//	tui:
+//	  reply_limit: 2000
+//	  canned:
+//	    - name: lgtm
+//	      text: "Looks good, go ahead with {shortCode}."
+//
+// {shortCode}, {workspace} and {priority} are filled in from the
+// notification being answered (for each one, when replying to a selection).
+
+// defaultReplyLimit is the reply box's character limit when
+// `tui.reply_limit` isn't set.
+const defaultReplyLimit = 500
+
+// draftMaxAge is how long an untouched draft is kept.
+const draftMaxAge = 30 * 24 * time.Hour
+
+type draft struct {
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type draftsLoadedMsg struct {
+	drafts map[string]draft
+	err    error
+}
+
+type draftsSavedMsg struct {
+	err error
+}
+
+type editorDoneMsg struct {
+	text string
+	err  error
+}
+
+func draftsPath() string {
+	return filepath.Join(config.ConfigDir(), "drafts.json")
+}
+
+// replyLimit turns `tui.reply_limit` into a textarea CharLimit, where 0
+// means unlimited. A negative setting turns the limit off.
+func replyLimit(n int) int {
+	switch {
+	case n == 0:
+		return defaultReplyLimit
+	case n < 0:
+		return 0
+	}
+	return n
+}
+
+// openReply focuses the reply box for id ("" for a selection), restoring
+// any draft saved for it.
+func (m *Model) openReply(id string) tea.Cmd {
+	m.replyTo = id
+	m.textarea.Reset()
+	if d, ok := m.drafts[id]; ok && id != "" {
+		m.textarea.SetValue(d.Text)
+	}
+	return m.textarea.Focus()
+}
+
+// replyHelp is the status line shown while the reply box is open.
+func (m Model) replyHelp(target string) string {
+	s := "Enter to send · Alt+Enter for newline"
+	if len(m.canned) > 0 {
+		s += " · " + m.keys.Canned.Help().Key + " canned"
+	}
+	s += " · " + m.keys.Editor.Help().Key + " editor · Esc to keep as draft"
+	if target != "" {
+		s = "Reply to " + target + " · " + s
+	}
+	if _, ok := m.drafts[m.replyTo]; ok && m.replyTo != "" {
+		s = "Draft restored · " + s
+	}
+	return s
+}
+
+// stashDraft closes the reply box, keeping what was typed as the draft for
+// the notification it was answering.
+func (m *Model) stashDraft() tea.Cmd {
+	text := strings.TrimSpace(m.textarea.Value())
+	id := m.replyTo
+	m.textarea.Blur()
+	m.textarea.Reset()
+	m.replyTo = ""
+	if id == "" {
+		return nil
+	}
+	if text == "" {
+		if _, ok := m.drafts[id]; !ok {
+			return nil
+		}
+		delete(m.drafts, id)
+	} else {
+		m.drafts[id] = draft{Text: text, UpdatedAt: time.Now()}
+		m.status = "Draft saved"
+	}
+	return saveDraftsCmd(m.draftsPath, m.drafts)
+}
+
+// clearDraft forgets the draft for ids once their reply has been sent.
+func (m *Model) clearDraft(ids ...string) tea.Cmd {
+	m.replyTo = ""
+	changed := false
+	for _, id := range ids {
+		if _, ok := m.drafts[id]; ok {
+			delete(m.drafts, id)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return saveDraftsCmd(m.draftsPath, m.drafts)
+}
+
+// handleComposeKey handles the canned-response and editor keys inside either
+// reply box. ok is false when msg is for the textarea.
+func (m Model) handleComposeKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Canned):
+		if len(m.canned) == 0 {
+			m.status = "No canned responses; add them under tui.canned in " + config.ConfigPath()
+			return m, nil, true
+		}
+		m.cannedReturn = m.state
+		m.cannedCursor = 0
+		m.state = stateCanned
+		return m, nil, true
+	case key.Matches(msg, m.keys.Editor):
+		return m, m.openEditor(), true
+	}
+	return m, nil, false
+}
+
+func (m Model) handleCannedKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := msg.String()
+	pick := -1
+	switch {
+	case s == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back) || s == "esc":
+		m.state = m.cannedReturn
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		m.cannedCursor = max(0, m.cannedCursor-1)
+		return m, nil
+	case key.Matches(msg, m.keys.Down):
+		m.cannedCursor = min(len(m.canned)-1, m.cannedCursor+1)
+		return m, nil
+	case s == "enter":
+		pick = m.cannedCursor
+	case len(s) == 1 && s[0] >= '1' && s[0] <= '9':
+		pick = int(s[0] - '1')
+	}
+	if pick < 0 || pick >= len(m.canned) {
+		return m, nil
+	}
+	text := m.canned[pick].Text
+	if n := m.findNotification(m.replyTo); n != nil {
+		text = expandCanned(text, *n)
+	}
+	m.state = m.cannedReturn
+	m.textarea.InsertString(text)
+	return m, nil
+}
+
+func (m Model) viewCanned() string {
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("Canned responses") + "\n\n")
+	n := m.findNotification(m.replyTo)
+	width := min(max(m.width-12, 20), 72)
+	for i, c := range m.canned {
+		marker := "  "
+		if i == m.cannedCursor {
+			marker = threadTargetStyle.Render("▶ ")
+		}
+		label := c.Name
+		if i < 9 {
+			label = fmt.Sprintf("[%d] %s", i+1, label)
+		}
+		text := c.Text
+		if n != nil {
+			text = expandCanned(text, *n)
+		}
+		b.WriteString(marker + label + "\n")
+		b.WriteString(metaStyle.Render(indent(truncateText(text, width), "    ")) + "\n")
+	}
+	b.WriteString("\n" + metaStyle.Render("Enter or 1-9 to insert · Esc to go back"))
+	box := detailPanelStyle.Render(b.String())
+	return lipgloss.Place(m.width, max(m.height-1, lipgloss.Height(box)), lipgloss.Center, lipgloss.Center, box)
+}
+
+// expandCanned fills in the placeholders a canned response may use.
+func expandCanned(text string, n feedNotification) string {
+	return strings.NewReplacer(
+		"{shortCode}", n.ShortCode,
+		"{workspace}", deref(n.Workspace),
+		"{priority}", strconv.Itoa(n.Priority),
+	).Replace(text)
+}
+
+// findNotification looks id up in the feed, then in the open thread.
+func (m Model) findNotification(id string) *feedNotification {
+	if id == "" {
+		return nil
+	}
+	for _, n := range m.items {
+		if n.ID == id {
+			return &n
+		}
+	}
+	if m.thread != nil {
+		for _, n := range m.thread.Notifications {
+			if n.ID == id {
+				return &n
+			}
+		}
+	}
+	return nil
+}
+
+// openEditor suspends the TUI and edits the reply in $VISUAL or $EDITOR
+// (vi if neither is set).
+func (m Model) openEditor() tea.Cmd {
+	f, err := os.CreateTemp("", "agentduty-reply-*.md")
+	if err != nil {
+		return func() tea.Msg { return editorDoneMsg{err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(m.textarea.Value())
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return editorDoneMsg{err: err} }
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// The editor setting may carry flags, e.g. "code --wait".
+	args := append(strings.Fields(editor), path)
+	c := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return editorDoneMsg{err: fmt.Errorf("%s: %w", args[0], err)}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return editorDoneMsg{err: err}
+		}
+		return editorDoneMsg{text: strings.TrimRight(string(data), "\n")}
+	})
+}
+
+// applyEditorText puts the edited reply back in the box, noting when it
+// had to be cut to the reply limit.
+func (m *Model) applyEditorText(text string) {
+	m.textarea.SetValue(text)
+	if limit := m.textarea.CharLimit; limit > 0 && len([]rune(text)) > limit {
+		m.status = fmt.Sprintf("Trimmed to the %d-character reply limit", limit)
+		return
+	}
+	m.status = "Edited · Enter to send · Esc to keep as draft"
+}
+
+func loadDraftsCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		drafts, err := loadDrafts(path, time.Now())
+		return draftsLoadedMsg{drafts: drafts, err: err}
+	}
+}
+
+// loadDrafts reads the drafts file, dropping drafts older than draftMaxAge.
+// A missing file is no drafts.
+func loadDrafts(path string, now time.Time) (map[string]draft, error) {
+	drafts := map[string]draft{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return drafts, nil
+	}
+	if err != nil {
+		return drafts, err
+	}
+	if err := json.Unmarshal(data, &drafts); err != nil {
+		return map[string]draft{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for id, d := range drafts {
+		if now.Sub(d.UpdatedAt) > draftMaxAge {
+			delete(drafts, id)
+		}
+	}
+	return drafts, nil
+}
+
+// saveDraftsCmd writes a snapshot of drafts, replacing the file atomically.
+func saveDraftsCmd(path string, drafts map[string]draft) tea.Cmd {
+	snapshot := make(map[string]draft, len(drafts))
+	for id, d := range drafts {
+		snapshot[id] = d
+	}
+	return func() tea.Msg {
+		return draftsSavedMsg{err: saveDrafts(path, snapshot)}
+	}
+}
+
+func saveDrafts(path string, drafts map[string]draft) error {
+	data, err := json.MarshalIndent(drafts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+// composeModel is bulkModel with drafts saved in a temporary directory.
+func composeModel(t *testing.T) Model {
+	t.Helper()
+	m := bulkModel(t)
+	m.draftsPath = filepath.Join(t.TempDir(), "drafts.json")
+	return m
+}
+
+func typeText(t *testing.T, m Model, s string) Model {
+	t.Helper()
+	for _, r := range s {
+		m = press(t, m, string(r))
+	}
+	return m
+}
+
+func TestDrafts_SurviveEsc(t *testing.T) {
+	m := composeModel(t)
+	m = press(t, m, "r")
+	m = typeText(t, m, "half done")
+
+	next, cmd := m.Update(keyPress("esc"))
+	m = next.(Model)
+	if cmd == nil || m.state != stateBrowsing || m.drafts["a"].Text != "half done" {
+		t.Fatalf("expected esc to keep a draft, got %+v", m.drafts)
+	}
+	if msg := cmd().(draftsSavedMsg); msg.err != nil {
+		t.Fatalf("save drafts: %v", msg.err)
+	}
+	if !strings.Contains(m.View(), "✎ draft") {
+		t.Errorf("expected the draft to show in the detail panel")
+	}
+
+	m = press(t, m, "r")
+	if m.textarea.Value() != "half done" || !strings.HasPrefix(m.status, "Draft restored") {
+		t.Fatalf("expected the draft back, got %q (%q)", m.textarea.Value(), m.status)
+	}
+
+	next, _ = m.Update(keyPress("enter"))
+	m = next.(Model)
+	if _, ok := m.drafts["a"]; ok {
+		t.Error("expected sending to clear the draft")
+	}
+
+	// Drafts written to disk come back after a restart.
+	loaded, err := loadDrafts(m.draftsPath, time.Now())
+	if err != nil || loaded["a"].Text != "half done" {
+		t.Errorf("expected the saved draft on disk, got %+v, %v", loaded, err)
+	}
+}
+
+func TestDrafts_EmptyReplyDropsDraft(t *testing.T) {
+	m := composeModel(t)
+	m.drafts["a"] = draft{Text: "old", UpdatedAt: time.Now()}
+	m = press(t, m, "r")
+	m.textarea.Reset()
+	m = press(t, m, "esc")
+	if _, ok := m.drafts["a"]; ok {
+		t.Error("expected clearing the box to drop the draft")
+	}
+}
+
+func TestLoadDrafts_PrunesOld(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drafts.json")
+	now := time.Now()
+	err := saveDrafts(path, map[string]draft{
+		"new": {Text: "keep", UpdatedAt: now.Add(-time.Hour)},
+		"old": {Text: "stale", UpdatedAt: now.Add(-draftMaxAge - time.Hour)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadDrafts(path, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got["new"].Text != "keep" {
+		t.Errorf("unexpected drafts %+v", got)
+	}
+
+	if got, err := loadDrafts(filepath.Join(t.TempDir(), "missing.json"), now); err != nil || len(got) != 0 {
+		t.Errorf("expected no drafts for a missing file, got %+v, %v", got, err)
+	}
+}
+
+func TestCanned_InsertsWithPlaceholders(t *testing.T) {
+	m := composeModel(t)
+	m.canned = []config.CannedResponse{
+		{Name: "lgtm", Text: "Ship it"},
+		{Name: "where", Text: "{shortCode} (P{priority}) in {workspace}: go ahead."},
+	}
+	m = press(t, m, "r", "ctrl+t")
+	if m.state != stateCanned || !strings.Contains(m.View(), "AAA (P5) in /api: go ahead.") {
+		t.Fatalf("expected the canned picker with expanded previews, got state=%v:\n%s", m.state, m.View())
+	}
+
+	m = press(t, m, "2")
+	if m.state != stateTextInput || m.textarea.Value() != "AAA (P5) in /api: go ahead." {
+		t.Errorf("expected the expanded response in the reply box, got state=%v %q", m.state, m.textarea.Value())
+	}
+
+	m = press(t, m, "ctrl+t", "j", "esc")
+	if m.state != stateTextInput {
+		t.Errorf("expected esc to return to the reply box, got %v", m.state)
+	}
+}
+
+func TestCanned_NoneConfigured(t *testing.T) {
+	m := composeModel(t)
+	m = press(t, m, "r", "ctrl+t")
+	if m.state != stateTextInput || !strings.Contains(m.status, "tui.canned") {
+		t.Errorf("expected a hint about tui.canned, got state=%v %q", m.state, m.status)
+	}
+}
+
+func TestReplyLimit(t *testing.T) {
+	for _, tt := range []struct{ in, want int }{{0, defaultReplyLimit}, {2000, 2000}, {-1, 0}} {
+		if got := replyLimit(tt.in); got != tt.want {
+			t.Errorf("replyLimit(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	m := composeModel(t)
+	m.textarea.CharLimit = replyLimit(5)
+	m = press(t, m, "r")
+	m.applyEditorText("from the editor")
+	if m.textarea.Value() != "from " || !strings.Contains(m.status, "5-character") {
+		t.Errorf("expected the editor text trimmed to the limit, got %q (%q)", m.textarea.Value(), m.status)
+	}
+}
+
+func TestReply_GoesToWhereTheBoxWasOpened(t *testing.T) {
+	m := composeModel(t)
+	m = press(t, m, "r")
+	m = typeText(t, m, "ship it")
+
+	// A refresh moves the cursor while the reply box is open.
+	m.cursor = 1
+
+	next, cmd := m.Update(keyPress("enter"))
+	m = next.(Model)
+	if cmd == nil || !m.hidden["a"] || m.hidden["b"] {
+		t.Errorf("expected the reply sent to AAA, got hidden=%v", m.hidden)
+	}
+}
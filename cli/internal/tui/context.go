@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The detail panel shows what an agent attached with `notify -c key:value`
+// (repo, branch, PR link...) as a table, and `o` opens a URL from it in the
+// browser, asking which one when there are several.
+
+// contextEntry is one key/value pair from a notification's context.
+type contextEntry struct {
+	Key   string
+	Value string
+}
+
+// feedContext is a notification's context, sorted by key. The server sends
+// it as a JSON-encoded object inside a string.
+type feedContext []contextEntry
+
+func (c *feedContext) UnmarshalJSON(data []byte) error {
+	var raw *string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = nil
+	if raw == nil || *raw == "" {
+		return nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(*raw), &obj); err != nil {
+		// Not an object: show it whole rather than failing the feed.
+		*c = feedContext{{Key: "context", Value: *raw}}
+		return nil
+	}
+	for k, v := range obj {
+		s, ok := v.(string)
+		if !ok {
+			b, _ := json.Marshal(v)
+			s = string(b)
+		}
+		*c = append(*c, contextEntry{Key: k, Value: s})
+	}
+	sort.Slice(*c, func(i, j int) bool { return (*c)[i].Key < (*c)[j].Key })
+	return nil
+}
+
+// urls is every context value that is a web link, in key order.
+func (c feedContext) urls() []contextEntry {
+	var out []contextEntry
+	for _, e := range c {
+		if isWebURL(e.Value) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+type openedMsg struct {
+	url string
+	err error
+}
+
+// openURL starts the platform's URL handler. Tests replace it.
+var openURL = func(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Start()
+}
+
+func openURLCmd(u string) tea.Cmd {
+	return func() tea.Msg {
+		return openedMsg{url: u, err: openURL(u)}
+	}
+}
+
+// openContextURL opens the focused notification's link, or asks which one
+// when it has several.
+func (m Model) openContextURL() (Model, tea.Cmd) {
+	n := m.focusedItem()
+	if n == nil {
+		return m, nil
+	}
+	urls := n.Context.urls()
+	switch len(urls) {
+	case 0:
+		m.status = fmt.Sprintf("%s has no links in its context", n.ShortCode)
+		return m, nil
+	case 1:
+		m.status = "Opening " + urls[0].Value
+		return m, openURLCmd(urls[0].Value)
+	}
+	m.state = stateOpenPicker
+	m.status = "Open: " + openPickerText(urls) + "  Esc cancel"
+	return m, nil
+}
+
+func openPickerText(urls []contextEntry) string {
+	var parts []string
+	for i, e := range urls {
+		if i == 9 {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("[%d] %s", i+1, e.Key))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func (m Model) handleOpenPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := msg.String()
+	switch {
+	case s == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.state = stateBrowsing
+		m.status = ""
+		return m, nil
+	}
+	n := m.focusedItem()
+	if n == nil || len(s) != 1 || s[0] < '1' || s[0] > '9' {
+		return m, nil
+	}
+	urls := n.Context.urls()
+	idx := int(s[0] - '1')
+	if idx >= len(urls) {
+		return m, nil
+	}
+	m.state = stateBrowsing
+	m.status = "Opening " + urls[idx].Value
+	return m, openURLCmd(urls[idx].Value)
+}
+
+// renderContext lays the context out as a two-column table. Links are
+// marked with the digit that opens them.
+func renderContext(c feedContext, width int) []string {
+	keyWidth := 0
+	for _, e := range c {
+		keyWidth = max(keyWidth, len(e.Key))
+	}
+	keyWidth = min(keyWidth, 16)
+
+	var lines []string
+	link := 0
+	for _, e := range c {
+		k := e.Key
+		if len(k) > keyWidth {
+			k = k[:keyWidth-1] + "…"
+		}
+		val := e.Value
+		suffix := ""
+		if isWebURL(val) {
+			link++
+			suffix = metaStyle.Render(fmt.Sprintf(" ↗%d", link))
+		}
+		val = truncateText(val, max(width-keyWidth-6, 10))
+		if suffix != "" {
+			val = linkStyle.Render(val)
+		}
+		lines = append(lines, "  "+metaStyle.Render(fmt.Sprintf("%-*s", keyWidth, k))+"  "+val+suffix)
+	}
+	return lines
+}
+
+// originLine says where a notification came from: its workspace and
+// session, and the escalation step when a policy is paging.
+func originLine(n feedNotification) string {
+	var parts []string
+	if ws := deref(n.Workspace); ws != "" {
+		parts = append(parts, "Workspace "+ws)
+	}
+	if sess := deref(n.SessionKey); sess != "" {
+		parts = append(parts, "Session "+sess)
+	} else if sess := deref(n.SessionID); sess != "" {
+		parts = append(parts, "Session "+sess)
+	}
+	if n.PolicyID != nil {
+		parts = append(parts, fmt.Sprintf("Escalation step %d", n.EscalationStep+1))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderTags draws tags as chips.
+func renderTags(tags []string) string {
+	chips := make([]string, len(tags))
+	for i, t := range tags {
+		chips[i] = tagChipStyle.Render("#" + t)
+	}
+	return strings.Join(chips, " ")
+}
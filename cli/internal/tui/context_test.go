@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFeedContext_Unmarshal(t *testing.T) {
+	var n feedNotification
+	data := `{"id":"a","context":"{\"repo\":\"acme/api\",\"pr\":\"https://github.com/acme/api/pull/7\",\"attempt\":3,\"ci\":{\"ok\":false}}"}`
+	if err := json.Unmarshal([]byte(data), &n); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := feedContext{
+		{"attempt", "3"},
+		{"ci", `{"ok":false}`},
+		{"pr", "https://github.com/acme/api/pull/7"},
+		{"repo", "acme/api"},
+	}
+	if len(n.Context) != len(want) {
+		t.Fatalf("got %+v", n.Context)
+	}
+	for i := range want {
+		if n.Context[i] != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, n.Context[i], want[i])
+		}
+	}
+	if urls := n.Context.urls(); len(urls) != 1 || urls[0].Key != "pr" {
+		t.Errorf("expected one link, got %+v", urls)
+	}
+
+	for _, raw := range []string{`{"context":null}`, `{}`, `{"context":""}`} {
+		var n feedNotification
+		if err := json.Unmarshal([]byte(raw), &n); err != nil || n.Context != nil {
+			t.Errorf("%s: expected no context, got %+v (%v)", raw, n.Context, err)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"context":"not json"}`), &n); err != nil || len(n.Context) != 1 || n.Context[0].Value != "not json" {
+		t.Errorf("expected a non-object context kept whole, got %+v (%v)", n.Context, err)
+	}
+}
+
+func TestDetailPanel_Context(t *testing.T) {
+	m := bulkModel(t)
+	policy, session := "pol", "claude-42"
+	m.items[0].Context = feedContext{{"branch", "main"}, {"pr", "https://example.com/pr/1"}}
+	m.items[0].Tags = []string{"deploy"}
+	m.items[0].SessionKey = &session
+	m.items[0].PolicyID = &policy
+	m.items[0].EscalationStep = 1
+
+	view := m.View()
+	for _, want := range []string{"Context", "branch", "main", "https://example.com/pr/1", "#deploy", "Workspace /api", "Session claude-42", "Escalation step 2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the detail panel:\n%s", want, view)
+		}
+	}
+}
+
+func TestOpenContextURL(t *testing.T) {
+	var opened []string
+	orig := openURL
+	openURL = func(u string) error { opened = append(opened, u); return nil }
+	defer func() { openURL = orig }()
+
+	m := bulkModel(t)
+	m = press(t, m, "o")
+	if !strings.Contains(m.status, "no links") {
+		t.Errorf("expected a note when there is nothing to open, got %q", m.status)
+	}
+
+	m.items[0].Context = feedContext{{"pr", "https://example.com/pr/1"}}
+	next, cmd := m.Update(keyPress("o"))
+	m = next.(Model)
+	if cmd == nil || m.state != stateBrowsing {
+		t.Fatal("expected a single link to open straight away")
+	}
+	cmd()
+
+	m.items[0].Context = feedContext{{"ci", "https://ci.example.com/1"}, {"doc", "notes"}, {"pr", "https://example.com/pr/1"}}
+	m = press(t, m, "o")
+	if m.state != stateOpenPicker || !strings.Contains(m.status, "[2] pr") {
+		t.Fatalf("expected a picker, got state=%v status=%q", m.state, m.status)
+	}
+	m = press(t, m, "7")
+	if m.state != stateOpenPicker {
+		t.Error("expected an out-of-range digit to be ignored")
+	}
+	next, cmd = m.Update(keyPress("2"))
+	m = next.(Model)
+	if cmd == nil || m.state != stateBrowsing {
+		t.Fatal("expected the digit to open the link")
+	}
+	cmd()
+
+	if len(opened) != 2 || opened[0] != "https://example.com/pr/1" || opened[1] != "https://example.com/pr/1" {
+		t.Errorf("got %v", opened)
+	}
+}
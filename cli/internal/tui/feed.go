@@ -5,11 +5,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/config"
+	"github.com/sestinj/agentduty/cli/internal/output"
 )
 
 type state int
@@ -18,6 +23,15 @@ const (
 	stateBrowsing state = iota
 	stateTextInput
 	stateSnoozePicker
+	stateThread
+	stateThreadInput
+	stateSearch
+	stateConfirm
+	stateTagInput
+	stateSnoozeInput
+	stateSnoozed
+	stateCanned
+	stateOpenPicker
 )
 
 // Layout constants
@@ -30,8 +44,10 @@ const (
 // Messages
 
 type feedRefreshedMsg struct {
-	items []feedNotification
-	err   error
+	items    []feedNotification
+	progress []feedProgress
+	hold     *output.DoNotDisturb
+	err      error
 }
 
 type respondedMsg struct {
@@ -46,57 +62,12 @@ type archivedMsg struct {
 	id  string
 	err error
 }
-type archivedAllMsg struct {
-	count int
-	err   error
-}
-
-// Styles
-
-var (
-	accentBlue = lipgloss.Color("#3b82f6")
-	dimColor   = lipgloss.Color("#6b7280")
-	errorColor = lipgloss.Color("#ef4444")
-
-	focusedBorder = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(accentBlue).
-			Padding(0, 1)
-
-	normalBorder = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#374151")).
-			Padding(0, 1)
-
-	skippedBorder = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#374151")).
-			Padding(0, 1).
-			Foreground(dimColor)
-
-	priorityStyles = map[int]lipgloss.Style{
-		5: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ef4444")), // red
-		4: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#f97316")), // orange
-		3: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#eab308")), // yellow
-		2: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#3b82f6")), // blue
-		1: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#9ca3af")), // gray
-	}
-
-	metaStyle   = lipgloss.NewStyle().Foreground(dimColor)
-	footerStyle = lipgloss.NewStyle().Foreground(dimColor)
-	errorStyle  = lipgloss.NewStyle().Foreground(errorColor)
-
-	detailPanelStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(accentBlue).
-				Padding(1, 2)
-
-	detailHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(accentBlue)
-)
 
 type Model struct {
 	client   *client.Client
 	items    []feedNotification
+	progress []feedProgress
+	hold     *output.DoNotDisturb
 	cursor   int
 	state    state
 	textarea textarea.Model
@@ -106,12 +77,65 @@ type Model struct {
 	height   int
 	err      error
 	status   string
+
+	// Keybindings and help from the tui config section.
+	keys     keyMap
+	help     help.Model
+	showHelp bool
+
+	// Multi-select, confirmations and the undo window for bulk actions.
+	marked   map[string]bool
+	anchor   string // ID where a `V` range starts, "" when none
+	confirm  *confirmPrompt
+	pending  *pendingBulk
+	bulkSeq  int
+	tagInput textinput.Model
+
+	// Search, filters and grouping, saved to config when they change. One
+	// save runs at a time; a change made meanwhile is saved when it finishes.
+	filter       filterState
+	search       textinput.Model
+	filterSaving bool
+	filterDirty  bool
+
+	// Custom snooze times and the snoozed view.
+	snoozeInput   textinput.Model
+	snoozed       []feedNotification
+	snoozedCursor int
+
+	// Reply drafts (kept per notification ID) and canned responses.
+	drafts       map[string]draft
+	draftsPath   string
+	replyTo      string // notification the reply box answers, "" for a selection
+	canned       []config.CannedResponse
+	cannedCursor int
+	cannedReturn state
+
+	// Thread mode: the conversation opened with `t` and its viewport.
+	thread   *feedThread
+	threadID string
+	viewport viewport.Model
 }
 
-func NewModel(c *client.Client) Model {
+// NewModel creates the feed, starting from the filter state saved in config
+// and the keymap and theme from its tui section.
+func NewModel(c *client.Client, filter config.FeedFilter, ui config.TUI) (Model, error) {
+	keys, err := newKeyMap(ui)
+	if err != nil {
+		return Model{}, err
+	}
+	th, err := lookupTheme(ui.Theme)
+	if err != nil {
+		return Model{}, err
+	}
+	applyTheme(th)
+
+	h := help.New()
+	h.Styles = helpStyles
+
 	ta := textarea.New()
 	ta.Placeholder = "Type your response... (shift+enter for newline)"
-	ta.CharLimit = 500
+	ta.CharLimit = replyLimit(ui.ReplyLimit)
 	ta.ShowLineNumbers = false
 	ta.SetHeight(3)
 	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
@@ -125,12 +149,25 @@ func NewModel(c *client.Client) Model {
 		textarea: ta,
 		skipped:  make(map[string]bool),
 		hidden:   make(map[string]bool),
-	}
+		filter:   filterState{filter},
+		search:   newSearchInput(),
+		keys:     keys,
+		help:     h,
+		marked:   make(map[string]bool),
+		tagInput: newTagInput(),
+
+		snoozeInput: newSnoozeInput(),
+
+		drafts:     make(map[string]draft),
+		draftsPath: draftsPath(),
+		canned:     ui.Canned,
+	}, nil
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		fetchFeed(m.client),
+		loadDraftsCmd(m.draftsPath),
 		tea.WindowSize(),
 	)
 }
@@ -140,6 +177,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.state == stateThread || m.state == stateThreadInput {
+			m.resizeThread()
+		}
+		return m, nil
+
+	case threadLoadedMsg:
+		if msg.id != m.threadID || (m.state != stateThread && m.state != stateThreadInput) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.thread = msg.thread
+		m.status = ""
+		m.resizeThread()
+		m.viewport.GotoBottom()
 		return m, nil
 
 	case feedRefreshedMsg:
@@ -147,6 +201,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 		} else {
 			m.items = msg.items
+			m.progress = msg.progress
+			m.hold = msg.hold
 			m.err = nil
 			// Reconcile hidden: remove IDs no longer in server response
 			serverIDs := make(map[string]bool, len(msg.items))
@@ -172,6 +228,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.thread != nil {
+			// Show the reply in the thread as soon as the server has it.
+			return m, tea.Batch(fetchFeed(m.client), fetchThreadCmd(m.client, m.threadID))
+		}
 		return m, fetchFeed(m.client)
 
 	case snoozedMsg:
@@ -184,6 +244,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, fetchFeed(m.client)
 
+	case snoozedLoadedMsg:
+		if m.state != stateSnoozed {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		m.snoozed = msg.items
+		m.snoozedCursor = max(0, min(len(m.snoozed)-1, m.snoozedCursor))
+		m.status = ""
+		return m, nil
+
+	case unsnoozedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.status = fmt.Sprintf("%s is back in the feed", msg.shortCode)
+		}
+		if m.state == stateSnoozed {
+			return m, tea.Batch(fetchFeed(m.client), fetchSnoozedCmd(m.client))
+		}
+		return m, fetchFeed(m.client)
+
 	case archivedMsg:
 		if msg.err != nil {
 			m.status = fmt.Sprintf("Error: %v", msg.err)
@@ -194,11 +278,74 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, fetchFeed(m.client)
 
-	case archivedAllMsg:
+	case bulkCommitMsg:
+		if m.pending == nil || m.pending.seq != msg.seq {
+			return m, nil // undone, or already sent
+		}
+		run := m.pending.run
+		m.pending = nil
+		m.status = ""
+		return m, run
+
+	case bulkDoneMsg:
+		if msg.err != nil {
+			for _, id := range msg.failed {
+				delete(m.hidden, id)
+			}
+			m.status = fmt.Sprintf("Error: %s %d of them failed: %v", strings.ToLower(msg.verb), len(msg.failed), msg.err)
+		}
+		return m, fetchFeed(m.client)
+
+	case taggedMsg:
 		if msg.err != nil {
 			m.status = fmt.Sprintf("Error: %v", msg.err)
-			// Clear hidden since we're rolling back
-			m.hidden = make(map[string]bool)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Tagged %d with %s", msg.count, "#"+strings.Join(msg.tags, " #"))
+		return m, fetchFeed(m.client)
+
+	case draftsLoadedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Couldn't load drafts: %v", msg.err)
+		}
+		for id, d := range msg.drafts {
+			if _, ok := m.drafts[id]; !ok {
+				m.drafts[id] = d
+			}
+		}
+		return m, nil
+
+	case draftsSavedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Couldn't save drafts: %v", msg.err)
+		}
+		return m, nil
+
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Editor: %v", msg.err)
+			return m, nil
+		}
+		m.applyEditorText(msg.text)
+		if m.state == stateThreadInput {
+			m.resizeThread()
+		}
+		return m, nil
+
+	case openedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Couldn't open %s: %v", msg.url, msg.err)
+		}
+		return m, nil
+
+	case filterSavedMsg:
+		m.filterSaving = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Couldn't save filters: %v", msg.err)
+		}
+		if m.filterDirty {
+			cmd := m.saveFilter()
+			return m, cmd
 		}
 		return m, nil
 
@@ -209,25 +356,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleKey(msg)
 	}
 
-	if m.state == stateTextInput {
+	if m.state == stateTextInput || m.state == stateThreadInput {
 		var cmd tea.Cmd
 		m.textarea, cmd = m.textarea.Update(msg)
 		return m, cmd
 	}
+	if m.state == stateSearch {
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		return m, cmd
+	}
+	if m.state == stateTagInput {
+		var cmd tea.Cmd
+		m.tagInput, cmd = m.tagInput.Update(msg)
+		return m, cmd
+	}
+	if m.state == stateSnoozeInput {
+		var cmd tea.Cmd
+		m.snoozeInput, cmd = m.snoozeInput.Update(msg)
+		return m, cmd
+	}
 
 	return m, nil
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.showHelp {
+		// Any key dismisses the help overlay.
+		m.showHelp = false
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
 	switch m.state {
+	case stateThread, stateThreadInput:
+		return m.handleThreadKey(msg)
+
+	case stateSearch:
+		return m.handleSearchKey(msg)
+
+	case stateConfirm:
+		return m.handleConfirmKey(msg)
+
+	case stateTagInput:
+		return m.handleTagKey(msg)
+
+	case stateCanned:
+		return m.handleCannedKey(msg)
+
+	case stateOpenPicker:
+		return m.handleOpenPickerKey(msg)
+
 	case stateTextInput:
+		if next, cmd, ok := m.handleComposeKey(msg); ok {
+			return next, cmd
+		}
 		switch msg.String() {
 		case "esc":
 			m.state = stateBrowsing
-			m.textarea.Blur()
-			m.textarea.Reset()
 			m.status = ""
-			return m, nil
+			cmd := m.stashDraft()
+			return m, cmd
+		case "ctrl+c":
+			cmd := m.stashDraft()
+			return m, tea.Sequence(cmd, tea.Quit)
 		case "enter":
 			text := strings.TrimSpace(m.textarea.Value())
 			if text == "" {
@@ -236,15 +430,31 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.textarea.Reset()
 			m.textarea.Blur()
 			m.state = stateBrowsing
-			n := m.focusedItem()
-			if n == nil {
-				return m, nil
+			// The reply goes where the box was opened, even if a refresh has
+			// moved the cursor since.
+			if id := m.replyTo; id != "" {
+				m.hidden[id] = true
+				if m.cursor >= len(m.visibleItems()) {
+					m.cursor = max(0, len(m.visibleItems())-1)
+				}
+				clear := m.clearDraft(id)
+				return m, tea.Batch(submitResponseCmd(m.client, id, &text, nil), clear)
 			}
-			m.hidden[n.ID] = true
-			if m.cursor >= len(m.visibleItems()) {
-				m.cursor = max(0, len(m.visibleItems())-1)
+			if sel := m.selection(); len(sel) > 0 {
+				// Canned placeholders are filled in per notification.
+				byID := make(map[string]feedNotification, len(sel))
+				for _, n := range sel {
+					byID[n.ID] = n
+				}
+				c, targets := m.client, idsOf(sel)
+				next, cmd, _ := m.beginBulk("Replied to", targets, eachID("Replied to", targets, func(id string) error {
+					reply := expandCanned(text, byID[id])
+					return submitResponse(c, id, &reply, nil)
+				}))
+				clear := next.clearDraft(targets...)
+				return next, tea.Batch(cmd, clear)
 			}
-			return m, submitResponseCmd(m.client, n.ID, &text, nil)
+			return m, nil
 		case "alt+enter":
 			// Insert a newline
 			m.textarea.InsertString("\n")
@@ -256,44 +466,55 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case stateSnoozePicker:
-		durations := []int{5, 15, 60, 240}
-		switch msg.String() {
-		case "1", "2", "3", "4":
-			idx := int(msg.String()[0]-'0') - 1
-			n := m.focusedItem()
-			if n == nil {
-				m.state = stateBrowsing
-				return m, nil
-			}
-			m.state = stateBrowsing
-			m.hidden[n.ID] = true
-			if m.cursor >= len(m.visibleItems()) {
-				m.cursor = max(0, len(m.visibleItems())-1)
-			}
-			return m, snoozeCmd(m.client, n.ID, durations[idx])
-		case "esc":
-			m.state = stateBrowsing
-			m.status = ""
-			return m, nil
-		}
-		return m, nil
+		return m.handleSnoozePickerKey(msg)
+
+	case stateSnoozeInput:
+		return m.handleSnoozeInputKey(msg)
+
+	case stateSnoozed:
+		return m.handleSnoozedKey(msg)
 
 	default: // stateBrowsing
+		if next, cmd, ok := m.handleSelectKey(msg); ok {
+			return next, cmd
+		}
+		if next, cmd, ok := m.handleFilterKey(msg); ok {
+			return next, cmd
+		}
 		visible := m.visibleItems()
-		switch msg.String() {
-		case "q", "ctrl+c":
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			if m.pending != nil {
+				// Send whatever is waiting out its undo window first.
+				return m, tea.Sequence(m.pending.run, tea.Quit)
+			}
 			return m, tea.Quit
-		case "up", "k":
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
 			return m, nil
-		case "down", "j":
+		case key.Matches(msg, m.keys.Down):
 			if m.cursor < len(visible)-1 {
 				m.cursor++
 			}
 			return m, nil
-		case "enter":
+		case key.Matches(msg, m.keys.PageUp):
+			m.cursor = max(0, m.cursor-10)
+			return m, nil
+		case key.Matches(msg, m.keys.PageDown):
+			m.cursor = max(0, min(len(visible)-1, m.cursor+10))
+			return m, nil
+		case key.Matches(msg, m.keys.Top):
+			m.cursor = 0
+			return m, nil
+		case key.Matches(msg, m.keys.Bottom):
+			m.cursor = max(0, len(visible)-1)
+			return m, nil
+		case key.Matches(msg, m.keys.Reply):
 			m.state = stateTextInput
 			// Set textarea width based on layout mode
 			if m.width >= minSplitWidth {
@@ -302,10 +523,14 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				m.textarea.SetWidth(min(m.width-4, 80) - 4)
 			}
-			m.textarea.Focus()
-			m.status = "Enter to send · Alt+Enter for newline · Esc to cancel"
-			return m, m.textarea.Focus()
-		case "s":
+			id := ""
+			if n := m.focusedItem(); n != nil && len(m.selection()) == 0 {
+				id = n.ID
+			}
+			cmd := m.openReply(id)
+			m.status = m.replyHelp("")
+			return m, cmd
+		case key.Matches(msg, m.keys.Skip):
 			n := m.focusedItem()
 			if n != nil {
 				m.skipped[n.ID] = true
@@ -324,14 +549,15 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
-		case "z":
-			n := m.focusedItem()
-			if n != nil {
-				m.state = stateSnoozePicker
-				m.status = "Snooze: [1] 5m  [2] 15m  [3] 1h  [4] 4h  Esc cancel"
-			}
-			return m, nil
-		case "a":
+		case key.Matches(msg, m.keys.Snooze):
+			return m.openSnoozePicker(), nil
+		case key.Matches(msg, m.keys.Snoozed):
+			return m.openSnoozed()
+		case key.Matches(msg, m.keys.Thread):
+			return m.openThread()
+		case key.Matches(msg, m.keys.Open):
+			return m.openContextURL()
+		case key.Matches(msg, m.keys.Archive):
 			n := m.focusedItem()
 			if n != nil {
 				m.hidden[n.ID] = true
@@ -341,14 +567,6 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, archiveCmd(m.client, n.ID)
 			}
 			return m, nil
-		case "A":
-			if len(visible) > 0 {
-				m.items = nil
-				m.hidden = make(map[string]bool)
-				m.cursor = 0
-				return m, archiveAllCmd(m.client)
-			}
-			return m, nil
 		default:
 			// Number keys 1-9 for option selection
 			if len(msg.String()) == 1 && msg.String()[0] >= '1' && msg.String()[0] <= '9' {
@@ -371,7 +589,7 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) visibleItems() []feedNotification {
 	var active, skipped []feedNotification
 	for _, n := range m.items {
-		if m.hidden[n.ID] {
+		if m.hidden[n.ID] || !m.filter.matches(n) {
 			continue
 		}
 		if m.skipped[n.ID] {
@@ -380,7 +598,48 @@ func (m Model) visibleItems() []feedNotification {
 			active = append(active, n)
 		}
 	}
-	return append(active, skipped...)
+	return m.filter.group(append(active, skipped...))
+}
+
+// footer is the one-line key summary for the current bindings.
+func (m Model) footer(keys help.KeyMap) string {
+	h := m.help
+	h.Width = m.width
+	return h.View(keys)
+}
+
+// viewHelp is the `?` overlay listing every active binding.
+func (m Model) viewHelp() string {
+	h := m.help
+	h.Width = m.width
+	h.ShowAll = true
+	var keys help.KeyMap = m.keys
+	switch m.state {
+	case stateThread, stateThreadInput:
+		keys = threadKeys{m.keys}
+	case stateSnoozed:
+		keys = snoozedKeys{m.keys}
+	}
+	title := lipgloss.NewStyle().Bold(true).Render("Keybindings")
+	box := detailPanelStyle.Render(title + "\n\n" + h.View(keys))
+	return box + "\n" + footerStyle.Render("Press any key to close")
+}
+
+// viewConfirm asks before a destructive bulk action.
+func (m Model) viewConfirm() string {
+	body := lipgloss.NewStyle().Bold(true).Render(m.confirm.question) + "\n\n" +
+		"[y] yes   [n] no" + "\n" +
+		metaStyle.Render(fmt.Sprintf("You'll have %s to undo.", undoWindow))
+	box := detailPanelStyle.BorderForeground(errorStyle.GetForeground()).Render(body)
+	return lipgloss.Place(m.width, max(m.height-1, lipgloss.Height(box)), lipgloss.Center, lipgloss.Center, box)
+}
+
+// emptyMessage explains an empty list: nothing pending, or nothing matching.
+func (m Model) emptyMessage() string {
+	if m.filter.active() && len(m.items) > 0 {
+		return "No notifications match the current filters (c to clear)."
+	}
+	return "No pending notifications. Refreshing every 3s..."
 }
 
 func (m Model) focusedItem() *feedNotification {
@@ -396,14 +655,56 @@ func (m Model) View() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
+	if m.showHelp {
+		return m.viewHelp()
+	}
+	if m.state == stateCanned {
+		return m.viewCanned()
+	}
+	if m.state == stateThread || m.state == stateThreadInput {
+		return m.viewThread()
+	}
+	if m.state == stateConfirm {
+		return m.viewConfirm()
+	}
+	if m.state == stateSnoozed {
+		return m.viewSnoozed()
+	}
 
 	// Header
 	title := lipgloss.NewStyle().Bold(true).Render("AgentDuty Feed")
 	count := fmt.Sprintf(" (%d pending)", len(m.visibleItems()))
+	if m.filter.active() {
+		total := 0
+		for _, n := range m.items {
+			if !m.hidden[n.ID] {
+				total++
+			}
+		}
+		count = fmt.Sprintf(" (%d of %d pending)", len(m.visibleItems()), total)
+	}
 	header := title + metaStyle.Render(count)
+	if d := m.filter.describe(); d != "" {
+		header += "  " + filterStyle.Render("⏷ "+d)
+	}
+	if n := len(m.selection()); n > 0 {
+		header += "  " + selectedStyle.Render(fmt.Sprintf("● %d selected", n))
+	}
+	if m.state == stateSearch {
+		header += "\n" + m.search.View()
+	}
+	if m.state == stateTagInput {
+		header += "\n" + m.tagInput.View()
+	}
+	if m.hold != nil {
+		header += "  " + holdStyle.Render("☾ "+output.DescribeDND(*m.hold))
+	}
+	if len(m.progress) > 0 {
+		header += "\n" + m.renderProgress(min(m.width-4, 80))
+	}
 
 	visible := m.visibleItems()
-	footer := footerStyle.Render("↑↓ navigate · 1-9 option · enter reply · a archive · A archive all · s skip · z snooze · q quit")
+	footer := m.footer(m.keys)
 
 	useSplit := m.width >= minSplitWidth
 	if !useSplit {
@@ -417,7 +718,7 @@ func (m Model) viewSingleColumn(header string, visible []feedNotification, foote
 	b.WriteString(header + "\n\n")
 
 	if len(visible) == 0 && m.err == nil {
-		b.WriteString(metaStyle.Render("  No pending notifications. Refreshing every 3s...\n"))
+		b.WriteString(metaStyle.Render("  " + m.emptyMessage() + "\n"))
 	}
 
 	if m.err != nil {
@@ -425,7 +726,7 @@ func (m Model) viewSingleColumn(header string, visible []feedNotification, foote
 	}
 
 	// Calculate available height for cards
-	headerLines := 2
+	headerLines := strings.Count(header, "\n") + 2
 	footerLines := 3
 	availableHeight := m.height - headerLines - footerLines
 
@@ -434,7 +735,10 @@ func (m Model) viewSingleColumn(header string, visible []feedNotification, foote
 	linesUsed := 0
 
 	for i, n := range visible {
-		card := m.renderCard(n, i == m.cursor, cardWidth)
+		card := m.renderCard(n, i == m.cursor, m.isSelected(visible, i), cardWidth)
+		if h := m.groupHeader(visible, i); h != "" {
+			card = h + "\n" + card
+		}
 		cardLines := strings.Count(card, "\n") + 1
 		if linesUsed+cardLines > availableHeight && i > m.cursor {
 			remaining := len(visible) - i
@@ -470,7 +774,7 @@ func (m Model) viewSplitPane(header string, visible []feedNotification, footer s
 	}
 
 	// Available height for the panels (subtract header + footer)
-	headerLines := 2
+	headerLines := strings.Count(header, "\n") + 2
 	footerLines := 2
 	panelHeight := m.height - headerLines - footerLines
 	if panelHeight < 5 {
@@ -480,7 +784,7 @@ func (m Model) viewSplitPane(header string, visible []feedNotification, footer s
 	rightWidth := m.width - listPanelWidth - panelGap
 
 	if len(visible) == 0 && m.err == nil {
-		left := metaStyle.Render("  No pending notifications.\n  Refreshing every 3s...")
+		left := metaStyle.Render("  " + m.emptyMessage())
 		b.WriteString(left + "\n")
 		b.WriteString(footer)
 		return b.String()
@@ -514,13 +818,23 @@ func (m Model) viewSplitPane(header string, visible []feedNotification, footer s
 		leftLines = append(leftLines, metaStyle.Render(fmt.Sprintf("  +%d above", startIdx)))
 	}
 	for i := startIdx; i < endIdx; i++ {
-		card := m.renderCompactCard(visible[i], i == m.cursor, cardInnerWidth)
+		if h := m.groupHeader(visible, i); h != "" {
+			leftLines = append(leftLines, h)
+		} else if i == startIdx && m.filter.GroupBy != groupNone {
+			leftLines = append(leftLines, groupHeaderStyle.Render("▾ "+m.filter.groupKey(visible[i])))
+		}
+		card := m.renderCompactCard(visible[i], i == m.cursor, m.isSelected(visible, i), cardInnerWidth)
 		leftLines = append(leftLines, card)
 	}
 	if endIdx < len(visible) {
 		leftLines = append(leftLines, metaStyle.Render(fmt.Sprintf("  +%d more", len(visible)-endIdx)))
 	}
 	leftContent := strings.Join(leftLines, "\n")
+	// Group headers take lines the card budget didn't account for; drop
+	// whatever overflows rather than pushing the footer off screen.
+	if lines := strings.Split(leftContent, "\n"); len(lines) > panelHeight {
+		leftContent = strings.Join(lines[:panelHeight], "\n")
+	}
 
 	// Build right panel: detail view
 	rightContent := m.renderDetailPanel(panelHeight, rightWidth)
@@ -536,7 +850,33 @@ func (m Model) viewSplitPane(header string, visible []feedNotification, footer s
 	return b.String()
 }
 
-func (m Model) renderCompactCard(n feedNotification, focused bool, width int) string {
+// renderProgress lists the live status lines agents keep updating, one per
+// line, with a bar when a percentage was reported.
+func (m Model) renderProgress(width int) string {
+	var lines []string
+	for _, p := range m.progress {
+		line := "  " + progressKeyStyle.Render(p.Key)
+		if p.Percent != nil {
+			line += " " + renderProgressBar(*p.Percent, progressBarWidth) + metaStyle.Render(fmt.Sprintf(" %3d%%", *p.Percent))
+		}
+		used := lipgloss.Width(line) + 1
+		line += " " + truncateText(p.Message, max(width-used, 10))
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+const progressBarWidth = 20
+
+// renderProgressBar draws a fixed-width bar for percent, clamped to 0-100.
+func renderProgressBar(percent, width int) string {
+	percent = max(0, min(100, percent))
+	filled := percent * width / 100
+	return progressFillStyle.Render(strings.Repeat("█", filled)) +
+		metaStyle.Render(strings.Repeat("░", width-filled))
+}
+
+func (m Model) renderCompactCard(n feedNotification, focused, selected bool, width int) string {
 	pStyle, ok := priorityStyles[n.Priority]
 	if !ok {
 		pStyle = priorityStyles[3]
@@ -547,12 +887,25 @@ func (m Model) renderCompactCard(n feedNotification, focused bool, width int) st
 	scCode := metaStyle.Render(n.ShortCode)
 	// Reserve space for badge + gaps + shortcode
 	msgMaxWidth := width - 4 - len(n.ShortCode) - 1
+	if selected {
+		msgMaxWidth -= 2
+	}
+	_, hasDraft := m.drafts[n.ID]
+	if hasDraft {
+		msgMaxWidth -= 2
+	}
 	if msgMaxWidth < 5 {
 		msgMaxWidth = 5
 	}
 	msgLine := truncateText(n.Message, msgMaxWidth)
 
 	content := badge + "  " + msgLine + " " + scCode
+	if hasDraft {
+		content += metaStyle.Render(" ✎")
+	}
+	if selected {
+		content = selectedStyle.Render("● ") + content
+	}
 
 	isSkipped := m.skipped[n.ID]
 	var style lipgloss.Style
@@ -600,12 +953,40 @@ func (m Model) renderDetailPanel(panelHeight, totalWidth int) string {
 	// Metadata
 	sections = append(sections, "")
 	metaLine := n.Age() + " · " + n.ShortCode
+	if n.RepeatCount > 1 {
+		metaLine += fmt.Sprintf(" · ×%d", n.RepeatCount)
+	}
+	if n.EditedAt != nil {
+		metaLine += " · edited"
+	}
+	if _, ok := m.drafts[n.ID]; ok {
+		metaLine += " · ✎ draft"
+	}
 	if n.SnoozedUntil != nil {
 		if t, err := time.Parse(time.RFC3339, *n.SnoozedUntil); err == nil {
 			metaLine += fmt.Sprintf(" · snoozed until %s", t.Format("15:04"))
 		}
 	}
 	sections = append(sections, metaStyle.Render(metaLine))
+	if cd := n.Countdown(); cd != "" {
+		sections = append(sections, deadlineStyle.Render("⏳ "+cd))
+	}
+	if where := originLine(*n); where != "" {
+		sections = append(sections, metaStyle.Render(truncateText(where, innerWidth)))
+	}
+	if len(n.Tags) > 0 {
+		sections = append(sections, renderTags(n.Tags))
+	}
+
+	if len(n.Context) > 0 {
+		sections = append(sections, "")
+		header := detailHeaderStyle.Render("Context")
+		if len(n.Context.urls()) > 0 && m.keys.Open.Enabled() {
+			header += metaStyle.Render("  " + m.keys.Open.Help().Key + " to open")
+		}
+		sections = append(sections, header)
+		sections = append(sections, renderContext(n.Context, innerWidth)...)
+	}
 
 	// Options
 	if len(n.Options) > 0 {
@@ -616,11 +997,43 @@ func (m Model) renderDetailPanel(panelHeight, totalWidth int) string {
 		}
 	}
 
+	// Responses so far; the full conversation is in thread mode.
+	if len(n.Responses) > 0 {
+		sections = append(sections, "")
+		sections = append(sections, detailHeaderStyle.Render("Responses"))
+		for _, r := range n.Responses {
+			text := ""
+			if r.SelectedOption != nil {
+				text = "→ " + *r.SelectedOption
+			}
+			if r.Text != nil && *r.Text != "" {
+				text = *r.Text
+			}
+			prefix := fmt.Sprintf("  ↳ %s: ", r.Channel)
+			sections = append(sections, metaStyle.Render(prefix)+truncateText(text, max(innerWidth-len(prefix), 10)))
+		}
+		if m.keys.Thread.Enabled() {
+			sections = append(sections, metaStyle.Render("  "+m.keys.Thread.Help().Key+" to view the whole thread"))
+		}
+	}
+
 	// Status line / snooze picker / text input in the detail panel
 	if m.state == stateSnoozePicker {
 		sections = append(sections, "")
 		sections = append(sections, detailHeaderStyle.Render("Snooze"))
-		sections = append(sections, "  [1] 5m  [2] 15m  [3] 1h  [4] 4h  Esc cancel")
+		sections = append(sections, "  "+snoozePresetsText())
+	}
+	if m.state == stateSnoozeInput {
+		sections = append(sections, "")
+		sections = append(sections, detailHeaderStyle.Render("Snooze"))
+		sections = append(sections, m.snoozeInput.View())
+		sections = append(sections, metaStyle.Render(m.status))
+	}
+
+	if m.state == stateOpenPicker {
+		sections = append(sections, "")
+		sections = append(sections, detailHeaderStyle.Render("Open"))
+		sections = append(sections, "  "+openPickerText(n.Context.urls())+"  Esc cancel")
 	}
 
 	if m.state == stateTextInput {
@@ -643,7 +1056,7 @@ func (m Model) renderDetailPanel(panelHeight, totalWidth int) string {
 		maxLines = 1
 	}
 	if len(contentLines) > maxLines {
-		if m.state == stateTextInput || m.state == stateSnoozePicker {
+		if m.state == stateTextInput || m.state == stateSnoozePicker || m.state == stateSnoozeInput || m.state == stateOpenPicker {
 			// Keep the bottom visible (input area)
 			start := len(contentLines) - maxLines
 			contentLines = append([]string{"..."}, contentLines[start+1:]...)
@@ -656,7 +1069,7 @@ func (m Model) renderDetailPanel(panelHeight, totalWidth int) string {
 	return detailPanelStyle.Width(totalWidth - 2).Height(panelHeight - 2).Render(content)
 }
 
-func (m Model) renderCard(n feedNotification, focused bool, width int) string {
+func (m Model) renderCard(n feedNotification, focused, selected bool, width int) string {
 	var lines []string
 
 	// Priority badge + message
@@ -670,6 +1083,9 @@ func (m Model) renderCard(n feedNotification, focused bool, width int) string {
 	msgWidth := width - 8 // account for border + padding + badge
 	msg := wrapText(n.Message, msgWidth)
 	msgLines := strings.Split(msg, "\n")
+	if selected {
+		badge = selectedStyle.Render("● ") + badge
+	}
 	lines = append(lines, badge+"  "+msgLines[0])
 	for _, l := range msgLines[1:] {
 		lines = append(lines, "    "+l)
@@ -677,8 +1093,20 @@ func (m Model) renderCard(n feedNotification, focused bool, width int) string {
 
 	// Metadata line
 	meta := metaStyle.Render(fmt.Sprintf("    %s · %s", n.Age(), n.ShortCode))
+	if _, ok := m.drafts[n.ID]; ok {
+		meta += metaStyle.Render(" · ✎ draft")
+	}
+	if cd := n.Countdown(); cd != "" {
+		meta += " " + deadlineStyle.Render("· ⏳ "+cd)
+	}
+	if focused && len(n.Context.urls()) > 0 && m.keys.Open.Enabled() {
+		meta += metaStyle.Render(fmt.Sprintf(" · ↗ %s to open", m.keys.Open.Help().Key))
+	}
 	lines = append(lines, "")
 	lines = append(lines, meta)
+	if len(n.Tags) > 0 {
+		lines = append(lines, "    "+renderTags(n.Tags))
+	}
 
 	// Options
 	if len(n.Options) > 0 {
@@ -718,8 +1146,8 @@ func scheduleRefresh() tea.Cmd {
 
 func fetchFeed(c *client.Client) tea.Cmd {
 	return func() tea.Msg {
-		items, err := fetchActiveFeed(c)
-		return feedRefreshedMsg{items: items, err: err}
+		snap, err := fetchActiveFeed(c)
+		return feedRefreshedMsg{items: snap.items, progress: snap.progress, hold: snap.hold, err: err}
 	}
 }
 
@@ -730,13 +1158,6 @@ func submitResponseCmd(c *client.Client, id string, text *string, selectedOption
 	}
 }
 
-func snoozeCmd(c *client.Client, id string, minutes int) tea.Cmd {
-	return func() tea.Msg {
-		err := snoozeNotification(c, id, minutes)
-		return snoozedMsg{id: id, err: err}
-	}
-}
-
 func archiveCmd(c *client.Client, id string) tea.Cmd {
 	return func() tea.Msg {
 		err := archiveNotificationReq(c, id)
@@ -744,13 +1165,6 @@ func archiveCmd(c *client.Client, id string) tea.Cmd {
 	}
 }
 
-func archiveAllCmd(c *client.Client) tea.Cmd {
-	return func() tea.Msg {
-		count, err := archiveAllNotificationsReq(c)
-		return archivedAllMsg{count: count, err: err}
-	}
-}
-
 // Helpers
 
 func wrapText(s string, width int) string {
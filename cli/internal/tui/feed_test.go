@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
 )
 
 func TestMinSplitWidth(t *testing.T) {
@@ -220,6 +222,105 @@ func TestFeedNotification_Age(t *testing.T) {
 	}
 }
 
+func TestFeedNotification_Countdown(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		n        feedNotification
+		expected string
+	}{
+		{
+			name:     "no deadline",
+			n:        feedNotification{},
+			expected: "",
+		},
+		{
+			name:     "minutes left with default",
+			n:        feedNotification{ExpiresAt: str(time.Now().Add(12*time.Minute + 30*time.Second).Format(time.RFC3339)), DefaultOption: str("Skip")},
+			expected: "expires in 12m",
+		},
+		{
+			name:     "past deadline",
+			n:        feedNotification{ExpiresAt: str(time.Now().Add(-time.Minute).Format(time.RFC3339))},
+			expected: "expiring",
+		},
+		{
+			name:     "invalid time",
+			n:        feedNotification{ExpiresAt: str("soon")},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.n.Countdown()
+			if !strings.HasPrefix(got, tt.expected) || (tt.expected == "" && got != "") {
+				t.Errorf("Countdown() = %q, want prefix %q", got, tt.expected)
+			}
+			if tt.n.DefaultOption != nil && !strings.HasSuffix(got, "→ Skip") {
+				t.Errorf("Countdown() = %q, want default option suffix", got)
+			}
+		})
+	}
+}
+
+func TestRenderProgressBar(t *testing.T) {
+	tests := []struct {
+		percent int
+		filled  int
+	}{
+		{0, 0},
+		{42, 4},
+		{100, 10},
+		{150, 10},
+		{-5, 0},
+	}
+
+	for _, tt := range tests {
+		got := renderProgressBar(tt.percent, 10)
+		if n := strings.Count(got, "█"); n != tt.filled {
+			t.Errorf("renderProgressBar(%d, 10) filled %d cells, want %d", tt.percent, n, tt.filled)
+		}
+		if n := strings.Count(got, "█") + strings.Count(got, "░"); n != 10 {
+			t.Errorf("renderProgressBar(%d, 10) has %d cells, want 10", tt.percent, n)
+		}
+	}
+}
+
+func TestView_ShowsProgress(t *testing.T) {
+	pct := 42
+	m := Model{
+		progress: []feedProgress{{ID: "p", Key: "build", Message: "running tests", Percent: &pct}},
+		width:    120,
+		height:   30,
+		hidden:   map[string]bool{},
+		skipped:  map[string]bool{},
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "build") || !strings.Contains(view, "42%") {
+		t.Error("view should render the progress line with its percentage")
+	}
+	if !strings.Contains(view, "(0 pending)") {
+		t.Error("progress lines should not count as pending")
+	}
+}
+
+func TestView_ShowsDoNotDisturb(t *testing.T) {
+	m := Model{
+		hold:    &output.DoNotDisturb{Reason: "dnd", Until: time.Now().Add(time.Hour), BreakthroughPriority: 5},
+		width:   120,
+		height:  30,
+		hidden:  map[string]bool{},
+		skipped: map[string]bool{},
+	}
+
+	if view := m.View(); !strings.Contains(view, "Do not disturb until") {
+		t.Error("header should show do-not-disturb state")
+	}
+}
+
 func TestWrapText(t *testing.T) {
 	tests := []struct {
 		input    string
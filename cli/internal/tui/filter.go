@@ -0,0 +1,293 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+// Filtering narrows the feed by incremental search, minimum priority, tag
+// and workspace, and can group what's left under workspace or session
+// headers. The state is saved to the config file whenever it changes, so the
+// next `agentduty feed` opens the same view.
+
+type filterSavedMsg struct {
+	err error
+}
+
+const (
+	groupNone      = ""
+	groupWorkspace = "workspace"
+	groupSession   = "session"
+)
+
+func newSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "search messages"
+	ti.CharLimit = 100
+	return ti
+}
+
+// filterState wraps the persisted filter with the helpers the model needs.
+type filterState struct {
+	config.FeedFilter
+}
+
+// matches reports whether n passes every active filter.
+func (f filterState) matches(n feedNotification) bool {
+	if f.MinPriority > 0 && n.Priority < f.MinPriority {
+		return false
+	}
+	if f.Workspace != "" && deref(n.Workspace) != f.Workspace {
+		return false
+	}
+	for _, want := range f.Tags {
+		found := false
+		for _, tag := range n.Tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q := strings.TrimSpace(f.Search); q != "" {
+		q = strings.ToLower(q)
+		if !strings.Contains(strings.ToLower(n.Message), q) &&
+			!strings.Contains(strings.ToLower(n.ShortCode), q) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f filterState) active() bool {
+	return f.Search != "" || f.MinPriority > 0 || len(f.Tags) > 0 || f.Workspace != ""
+}
+
+// describe summarises the active filters and grouping for the header.
+func (f filterState) describe() string {
+	var parts []string
+	if f.Search != "" {
+		parts = append(parts, fmt.Sprintf("%q", f.Search))
+	}
+	if f.MinPriority > 0 {
+		parts = append(parts, fmt.Sprintf("P≥%d", f.MinPriority))
+	}
+	for _, t := range f.Tags {
+		parts = append(parts, "#"+t)
+	}
+	if f.Workspace != "" {
+		parts = append(parts, f.Workspace)
+	}
+	if f.GroupBy != groupNone {
+		parts = append(parts, "by "+f.GroupBy)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// groupKey is the heading an item is listed under, or "" when ungrouped.
+func (f filterState) groupKey(n feedNotification) string {
+	switch f.GroupBy {
+	case groupWorkspace:
+		if ws := deref(n.Workspace); ws != "" {
+			return ws
+		}
+		return "(no workspace)"
+	case groupSession:
+		key := deref(n.SessionKey)
+		if key == "" {
+			return "(no session)"
+		}
+		if ws := deref(n.Workspace); ws != "" {
+			return key + " — " + ws
+		}
+		return key
+	}
+	return ""
+}
+
+// group orders items so each group is contiguous, groups in order of first
+// appearance. Order within a group is preserved.
+func (f filterState) group(items []feedNotification) []feedNotification {
+	if f.GroupBy == groupNone {
+		return items
+	}
+	first := map[string]int{}
+	for i, n := range items {
+		if _, ok := first[f.groupKey(n)]; !ok {
+			first[f.groupKey(n)] = i
+		}
+	}
+	grouped := append([]feedNotification(nil), items...)
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return first[f.groupKey(grouped[i])] < first[f.groupKey(grouped[j])]
+	})
+	return grouped
+}
+
+// groupHeader returns the header to print before visible[i], or "" when it
+// continues the previous item's group.
+func (m Model) groupHeader(visible []feedNotification, i int) string {
+	key := m.filter.groupKey(visible[i])
+	if key == "" || (i > 0 && m.filter.groupKey(visible[i-1]) == key) {
+		return ""
+	}
+	return groupHeaderStyle.Render("▾ " + key)
+}
+
+// cycle returns the value after current in values, wrapping to "".
+func cycle(values []string, current string) string {
+	for i, v := range values {
+		if v == current {
+			if i+1 < len(values) {
+				return values[i+1]
+			}
+			return ""
+		}
+	}
+	if len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// feedTags and feedWorkspaces list the distinct values present in the feed,
+// sorted, for the filter toggles to cycle through.
+func (m Model) feedTags() []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, n := range m.items {
+		for _, t := range n.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func (m Model) feedWorkspaces() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, n := range m.items {
+		if ws := deref(n.Workspace); ws != "" && !seen[ws] {
+			seen[ws] = true
+			out = append(out, ws)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// handleFilterKey applies a filter toggle in browsing mode. ok is false when
+// msg isn't bound to a filter action.
+func (m Model) handleFilterKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Search):
+		m.state = stateSearch
+		m.search.SetValue(m.filter.Search)
+		m.search.CursorEnd()
+		m.status = "Type to search · Enter to keep · Esc to clear"
+		return m, m.search.Focus(), true
+	case key.Matches(msg, m.keys.Priority):
+		// 0 (all) → P2+ → P3+ → P4+ → P5 → all
+		m.filter.MinPriority = (m.filter.MinPriority + 1) % 6
+		if m.filter.MinPriority == 1 {
+			m.filter.MinPriority = 2
+		}
+	case key.Matches(msg, m.keys.Tag):
+		current := ""
+		if len(m.filter.Tags) > 0 {
+			current = m.filter.Tags[0]
+		}
+		if next := cycle(m.feedTags(), current); next != "" {
+			m.filter.Tags = []string{next}
+		} else {
+			m.filter.Tags = nil
+		}
+	case key.Matches(msg, m.keys.Workspace):
+		m.filter.Workspace = cycle(m.feedWorkspaces(), m.filter.Workspace)
+	case key.Matches(msg, m.keys.Group):
+		m.filter.GroupBy = cycle([]string{groupWorkspace, groupSession}, m.filter.GroupBy)
+	case key.Matches(msg, m.keys.ClearFilters):
+		if !m.filter.active() {
+			return m, nil, true
+		}
+		group := m.filter.GroupBy
+		m.filter = filterState{}
+		m.filter.GroupBy = group
+	default:
+		return m, nil, false
+	}
+	m.cursor = 0
+	if d := m.filter.describe(); d != "" {
+		m.status = "Filter: " + d
+	} else {
+		m.status = "Showing all notifications"
+	}
+	cmd := m.saveFilter()
+	return m, cmd, true
+}
+
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		m.filter.Search = ""
+		m.search.Reset()
+		m.search.Blur()
+		m.state = stateBrowsing
+		m.status = ""
+		m.cursor = 0
+		cmd := m.saveFilter()
+		return m, cmd
+	case "enter":
+		m.search.Blur()
+		m.state = stateBrowsing
+		m.status = ""
+		cmd := m.saveFilter()
+		return m, cmd
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	m.filter.Search = m.search.Value()
+	m.cursor = 0
+	return m, cmd
+}
+
+// saveFilter saves the current filter, or marks it to be saved once the save
+// already running finishes, so saves never overlap or land out of order.
+func (m *Model) saveFilter() tea.Cmd {
+	if m.filterSaving {
+		m.filterDirty = true
+		return nil
+	}
+	m.filterSaving = true
+	m.filterDirty = false
+	return saveFilterCmd(m.filter.FeedFilter)
+}
+
+func saveFilterCmd(f config.FeedFilter) tea.Cmd {
+	return func() tea.Msg {
+		return filterSavedMsg{err: config.SaveFeedFilter(f)}
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+func filterItems() []feedNotification {
+	return []feedNotification{
+		{ID: "a", ShortCode: "AAA", Message: "Deploy to prod?", Priority: 5, Tags: []string{"deploy"}, Workspace: strPtr("/api"), SessionKey: strPtr("s1")},
+		{ID: "b", ShortCode: "BBB", Message: "Which linter config?", Priority: 2, Workspace: strPtr("/web"), SessionKey: strPtr("s2")},
+		{ID: "c", ShortCode: "CCC", Message: "Roll back the deploy?", Priority: 4, Tags: []string{"deploy", "infra"}, Workspace: strPtr("/api"), SessionKey: strPtr("s3")},
+		{ID: "d", ShortCode: "DDD", Message: "Rename the package?", Priority: 3, Workspace: strPtr("/web"), SessionKey: strPtr("s2")},
+	}
+}
+
+func ids(items []feedNotification) string {
+	var out []string
+	for _, n := range items {
+		out = append(out, n.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestVisibleItems_Filters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter config.FeedFilter
+		want   string
+	}{
+		{"none", config.FeedFilter{}, "a,b,c,d"},
+		{"search is case-insensitive", config.FeedFilter{Search: "DEPLOY"}, "a,c"},
+		{"search matches short codes", config.FeedFilter{Search: "ddd"}, "d"},
+		{"min priority", config.FeedFilter{MinPriority: 4}, "a,c"},
+		{"tag", config.FeedFilter{Tags: []string{"infra"}}, "c"},
+		{"workspace", config.FeedFilter{Workspace: "/web"}, "b,d"},
+		{"combined", config.FeedFilter{Workspace: "/api", MinPriority: 5}, "a"},
+		{"group by workspace", config.FeedFilter{GroupBy: "workspace"}, "a,c,b,d"},
+		{"group by session", config.FeedFilter{GroupBy: "session"}, "a,b,d,c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{
+				items:   filterItems(),
+				hidden:  map[string]bool{},
+				skipped: map[string]bool{},
+				filter:  filterState{tt.filter},
+			}
+			if got := ids(m.visibleItems()); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterKeys_CycleAndClear(t *testing.T) {
+	m := newTestModel(t, config.FeedFilter{})
+	m.items = filterItems()
+	m.width, m.height = 100, 40
+
+	press := func(k string) tea.Cmd {
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = next.(Model)
+		return cmd
+	}
+
+	if cmd := press("p"); cmd == nil || m.filter.MinPriority != 2 {
+		t.Fatalf("expected p to filter to P2+ and save, got %d", m.filter.MinPriority)
+	}
+	for m.filter.MinPriority != 0 {
+		press("p")
+	}
+
+	press("#")
+	if len(m.filter.Tags) != 1 || m.filter.Tags[0] != "deploy" {
+		t.Errorf("expected first tag, got %v", m.filter.Tags)
+	}
+	press("#")
+	press("#")
+	if len(m.filter.Tags) != 0 {
+		t.Errorf("expected tag filter to wrap back to none, got %v", m.filter.Tags)
+	}
+
+	press("w")
+	press("g")
+	if m.filter.Workspace != "/api" || m.filter.GroupBy != "workspace" {
+		t.Errorf("unexpected filter %+v", m.filter)
+	}
+	if !strings.Contains(m.View(), "2 of 4 pending") || !strings.Contains(m.View(), "▾ /api") {
+		t.Errorf("expected filtered, grouped view, got:\n%s", m.View())
+	}
+
+	press("c")
+	if m.filter.active() || m.filter.GroupBy != "workspace" {
+		t.Errorf("expected c to clear filters but keep grouping, got %+v", m.filter)
+	}
+}
+
+func TestSearch_Incremental(t *testing.T) {
+	m := newTestModel(t, config.FeedFilter{})
+	m.items = filterItems()
+	m.width, m.height = 100, 40
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = next.(Model)
+	if m.state != stateSearch {
+		t.Fatalf("expected / to start a search, got state=%v", m.state)
+	}
+	for _, r := range "roll" {
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(Model)
+	}
+	if got := ids(m.visibleItems()); got != "c" {
+		t.Errorf("expected search to narrow as you type, got %s", got)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.state != stateBrowsing || m.filter.Search != "roll" {
+		t.Errorf("expected enter to keep the search, got state=%v search=%q", m.state, m.filter.Search)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = next.(Model)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.filter.Search != "" || len(m.visibleItems()) != 4 {
+		t.Errorf("expected esc to clear the search, got %q", m.filter.Search)
+	}
+}
+
+func TestView_FilteredEmpty(t *testing.T) {
+	m := newTestModel(t, config.FeedFilter{Search: "nothing matches this"})
+	m.items = filterItems()
+	m.width, m.height = 100, 40
+	if !strings.Contains(m.View(), "No notifications match") {
+		t.Errorf("expected filtered empty state, got:\n%s", m.View())
+	}
+}
+
+func TestSaveFilter_OneAtATime(t *testing.T) {
+	m := newTestModel(t, config.FeedFilter{})
+	m.items = filterItems()
+
+	next, first := m.Update(keyPress("p"))
+	m = next.(Model)
+	next, second := m.Update(keyPress("p"))
+	m = next.(Model)
+	if first == nil || second != nil || !m.filterDirty {
+		t.Fatalf("expected the second change to wait for the first save, got %v, %v", first != nil, second != nil)
+	}
+
+	next, again := m.Update(filterSavedMsg{})
+	m = next.(Model)
+	if again == nil || m.filterDirty || !m.filterSaving {
+		t.Fatal("expected the waiting change to be saved once the first finished")
+	}
+
+	next, done := m.Update(filterSavedMsg{})
+	m = next.(Model)
+	if done != nil || m.filterSaving {
+		t.Error("expected nothing more to save")
+	}
+}
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+// The replay harness drives a Model the way the bubbletea runtime would:
+// messages and keys go through Update, the commands it returns are run and
+// their messages fed back in, and the API behind the client is a fakeAPI.
+// Timers (the refresh tick, the bulk undo window) are not waited on; tests
+// send the message a timer would have sent when they want it.
+//
+// View() output is compared with testdata/*.golden, without colors. Run
+// `go test ./internal/tui -update` to rewrite the golden files.
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// fakeAPI answers GraphQL requests by operation name and records the calls
+// it sees. fail makes an operation return a GraphQL error, keyed by the
+// operation name or by "Operation id" for a single notification.
+type fakeAPI struct {
+	t         *testing.T
+	mu        sync.Mutex
+	responses map[string]string // operation name -> data JSON
+	fail      map[string]string
+	calls     []fakeCall
+}
+
+type fakeCall struct {
+	op   string
+	vars map[string]any
+}
+
+func (f *fakeAPI) RoundTrip(r *http.Request) (*http.Response, error) {
+	var req struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		f.t.Fatalf("decode request: %v", err)
+	}
+	// "query ActiveFeed {" / "mutation ArchiveNotification($id..."
+	op := strings.Fields(req.Query)[1]
+	op, _, _ = strings.Cut(op, "(")
+	id, _ := req.Variables["id"].(string)
+
+	f.mu.Lock()
+	f.calls = append(f.calls, fakeCall{op: op, vars: req.Variables})
+	data, ok := f.responses[op]
+	msg, failed := f.fail[op]
+	if !failed && id != "" {
+		msg, failed = f.fail[op+" "+id]
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		data = "{}"
+	}
+	body := `{"data":` + data + `}`
+	if failed {
+		b, _ := json.Marshal(msg)
+		body = `{"data":null,"errors":[{"message":` + string(b) + `}]}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}, nil
+}
+
+func (f *fakeAPI) client() *client.Client {
+	c := client.New("http://agentduty.test/graphql", &config.Config{})
+	c.HTTPClient = &http.Client{Transport: f}
+	return c
+}
+
+// mutations lists the non-feed calls, as "Op id".
+func (f *fakeAPI) mutations() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, c := range f.calls {
+		if c.op == "ActiveFeed" {
+			continue
+		}
+		id, _ := c.vars["id"].(string)
+		out = append(out, c.op+" "+id)
+	}
+	return out
+}
+
+type harness struct {
+	t      *testing.T
+	api    *fakeAPI
+	m      Model
+	timers int  // timer commands seen but not run
+	quit   bool // a command asked to quit
+}
+
+// tickFunc identifies the closure tea.Tick returns, so timers can be told
+// apart from commands that finish straight away.
+var tickFunc = reflect.ValueOf(tea.Tick(0, nil)).Pointer()
+
+var cmdType = reflect.TypeOf((tea.Cmd)(nil))
+
+// newHarness starts a feed against api at the given terminal size, with
+// HOME in a temporary directory so saved filters and drafts stay out of the
+// real config.
+func newHarness(t *testing.T, api *fakeAPI, width, height int) *harness {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	m, err := NewModel(api.client(), config.FeedFilter{}, config.TUI{})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	h := &harness{t: t, api: api, m: m}
+	h.send(tea.WindowSizeMsg{Width: width, Height: height})
+	h.run(h.m.Init())
+	return h
+}
+
+// send feeds msg to Update and runs whatever it returns.
+func (h *harness) send(msg tea.Msg) {
+	h.t.Helper()
+	switch msg := msg.(type) {
+	case nil:
+		return
+	case tea.QuitMsg:
+		h.quit = true
+		return
+	case tea.BatchMsg:
+		for _, c := range msg {
+			h.run(c)
+		}
+		return
+	}
+	// tea.Sequence's message is an unexported []tea.Cmd.
+	if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice && v.Type().Elem() == cmdType {
+		for i := 0; i < v.Len(); i++ {
+			h.run(v.Index(i).Interface().(tea.Cmd))
+		}
+		return
+	}
+	next, cmd := h.m.Update(msg)
+	h.m = next.(Model)
+	h.run(cmd)
+}
+
+func (h *harness) run(cmd tea.Cmd) {
+	h.t.Helper()
+	if cmd == nil {
+		return
+	}
+	if reflect.ValueOf(cmd).Pointer() == tickFunc {
+		h.timers++
+		return
+	}
+	h.send(cmd())
+}
+
+// press sends keys in order; see keyPress for the names it understands.
+func (h *harness) press(keys ...string) {
+	h.t.Helper()
+	for _, k := range keys {
+		h.send(keyPress(k))
+	}
+}
+
+func (h *harness) resize(width, height int) {
+	h.send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]")
+
+// view is View() without colors or trailing spaces.
+func (h *harness) view() string {
+	lines := strings.Split(ansiCodes.ReplaceAllString(h.m.View(), ""), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// snapshot compares the current view with testdata/<name>.golden.
+func (h *harness) snapshot(name string) {
+	h.t.Helper()
+	got := h.view()
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			h.t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			h.t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		h.t.Fatalf("%v (run go test ./internal/tui -update)", err)
+	}
+	if got != string(want) {
+		h.t.Errorf("view differs from %s:\n%s", golden, got)
+	}
+}
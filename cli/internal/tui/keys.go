@@ -0,0 +1,327 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+// Keybindings come from a preset (`tui.keymap`: default, vim or emacs) with
+// per-action overrides from `tui.keys`, e.g.
+//
+//	tui:
+//	  keymap: vim
+//	  keys:
+//	    archive: [x, d]
+//
+// Write the space bar as "space". Option digits (1-9), the editing keys
+// inside the reply box (other than canned and editor) and y/n in
+// confirmations are fixed.
+
+// keyActions lists every remappable action with its help text, in the order
+// the help overlay shows them.
+var keyActions = []struct {
+	name string
+	help string
+}{
+	{"up", "up"},
+	{"down", "down"},
+	{"page_up", "page up"},
+	{"page_down", "page down"},
+	{"top", "top"},
+	{"bottom", "bottom"},
+	{"reply", "reply"},
+	{"canned", "canned reply"},
+	{"editor", "reply in $EDITOR"},
+	{"thread", "thread"},
+	{"open", "open link"},
+	{"archive", "archive"},
+	{"archive_all", "archive all"},
+	{"skip", "skip"},
+	{"snooze", "snooze"},
+	{"snoozed", "snoozed list"},
+	{"unsnooze", "un-snooze"},
+	{"search", "search"},
+	{"priority", "priority filter"},
+	{"tag", "tag filter"},
+	{"workspace", "workspace filter"},
+	{"group", "group"},
+	{"clear_filters", "clear filters"},
+	{"mark", "mark"},
+	{"mark_range", "mark range"},
+	{"add_tag", "tag marked"},
+	{"undo", "undo"},
+	{"back", "back"},
+	{"help", "help"},
+	{"quit", "quit"},
+}
+
+var keyPresets = map[string]map[string][]string{
+	"default": {
+		"up":            {"up", "k"},
+		"down":          {"down", "j"},
+		"page_up":       {"pgup"},
+		"page_down":     {"pgdown"},
+		"top":           {"home"},
+		"bottom":        {"end"},
+		"reply":         {"enter", "r"},
+		"canned":        {"ctrl+t"},
+		"editor":        {"ctrl+o"},
+		"thread":        {"t"},
+		"open":          {"o"},
+		"archive":       {"a"},
+		"archive_all":   {"A"},
+		"skip":          {"s"},
+		"snooze":        {"z"},
+		"snoozed":       {"Z"},
+		"unsnooze":      {"u", "enter"},
+		"search":        {"/"},
+		"priority":      {"p"},
+		"tag":           {"#"},
+		"workspace":     {"w"},
+		"group":         {"g"},
+		"clear_filters": {"c"},
+		"mark":          {" "},
+		"mark_range":    {"V"},
+		"add_tag":       {"T"},
+		"undo":          {"u"},
+		"back":          {"esc"},
+		"help":          {"?"},
+		"quit":          {"q", "ctrl+c"},
+	},
+	"vim": {
+		"up":            {"k", "up"},
+		"down":          {"j", "down"},
+		"page_up":       {"ctrl+u", "pgup"},
+		"page_down":     {"ctrl+d", "pgdown"},
+		"top":           {"home"},
+		"bottom":        {"G", "end"},
+		"reply":         {"i", "enter"},
+		"canned":        {"ctrl+t"},
+		"editor":        {"ctrl+o"},
+		"thread":        {"t"},
+		"open":          {"o"},
+		"archive":       {"x", "a"},
+		"archive_all":   {"X", "A"},
+		"skip":          {"s"},
+		"snooze":        {"z"},
+		"snoozed":       {"Z"},
+		"unsnooze":      {"u", "enter"},
+		"search":        {"/"},
+		"priority":      {"p"},
+		"tag":           {"#"},
+		"workspace":     {"w"},
+		"group":         {"g"},
+		"clear_filters": {"c"},
+		"mark":          {" "},
+		"mark_range":    {"V"},
+		"add_tag":       {"T"},
+		"undo":          {"u"},
+		"back":          {"esc", "h"},
+		"help":          {"?"},
+		"quit":          {"q", "ctrl+c"},
+	},
+	"emacs": {
+		"up":            {"ctrl+p", "up"},
+		"down":          {"ctrl+n", "down"},
+		"page_up":       {"alt+v", "pgup"},
+		"page_down":     {"ctrl+v", "pgdown"},
+		"top":           {"alt+<", "home"},
+		"bottom":        {"alt+>", "end"},
+		"reply":         {"enter"},
+		"canned":        {"alt+/"},
+		"editor":        {"alt+e"},
+		"thread":        {"ctrl+t"},
+		"open":          {"alt+o"},
+		"archive":       {"ctrl+k"},
+		"archive_all":   {"alt+k"},
+		"skip":          {"ctrl+f"},
+		"snooze":        {"ctrl+z"},
+		"snoozed":       {"alt+z"},
+		"unsnooze":      {"enter", "u"},
+		"search":        {"ctrl+s"},
+		"priority":      {"alt+p"},
+		"tag":           {"alt+t"},
+		"workspace":     {"alt+w"},
+		"group":         {"alt+g"},
+		"clear_filters": {"alt+c"},
+		"mark":          {"ctrl+@", " "},
+		"mark_range":    {"alt+m"},
+		"add_tag":       {"alt+T"},
+		"undo":          {"ctrl+_", "u"},
+		"back":          {"ctrl+g", "esc"},
+		"help":          {"alt+h", "?"}, // ctrl+h is backspace in many terminals
+		"quit":          {"ctrl+x", "ctrl+c"},
+	},
+}
+
+// keyScreens lists the actions each screen listens for: the feed, the
+// snoozed list and the reply box. Two actions may share a key only when no
+// screen listens for both, like reply and unsnooze on enter. "options" is
+// the fixed 1-9.
+var keyScreens = func() [][]string {
+	feed := []string{"options"}
+	for _, a := range keyActions {
+		switch a.name {
+		case "unsnooze", "canned", "editor":
+		default:
+			feed = append(feed, a.name)
+		}
+	}
+	return [][]string{
+		feed,
+		{"up", "down", "top", "bottom", "unsnooze", "snoozed", "back", "help", "quit"},
+		{"canned", "editor", "back"},
+	}
+}()
+
+// checkKeyConflicts returns an error if a key is bound to two actions that
+// the same screen listens for.
+func checkKeyConflicts(keys map[string][]string) error {
+	for _, screen := range keyScreens {
+		owner := map[string]string{}
+		for _, action := range screen {
+			for _, k := range keys[action] {
+				if other, ok := owner[k]; ok && other != action {
+					return fmt.Errorf("tui key %q is bound to both %s and %s", keyLabel([]string{k}), other, action)
+				}
+				owner[k] = action
+			}
+		}
+	}
+	return nil
+}
+
+// keyMap holds the active bindings, one per action.
+type keyMap struct {
+	Up, Down, PageUp, PageDown, Top, Bottom        key.Binding
+	Reply, Thread, Archive, ArchiveAll, Skip       key.Binding
+	Snooze, Search, Priority, Tag, Workspace       key.Binding
+	Group, ClearFilters, Back, Help, Quit, Options key.Binding
+	Mark, MarkRange, AddTag, Undo                  key.Binding
+	Snoozed, Unsnooze, Canned, Editor, Open        key.Binding
+}
+
+func newKeyMap(cfg config.TUI) (keyMap, error) {
+	name := cfg.Keymap
+	if name == "" {
+		name = "default"
+	}
+	preset, ok := keyPresets[name]
+	if !ok {
+		return keyMap{}, fmt.Errorf("unknown tui keymap %q (want default, vim or emacs)", name)
+	}
+
+	keys := make(map[string][]string, len(preset))
+	for action, ks := range preset {
+		keys[action] = ks
+	}
+	for action, ks := range cfg.Keys {
+		if _, ok := preset[action]; !ok {
+			return keyMap{}, fmt.Errorf("unknown tui key action %q (want one of %s)", action, strings.Join(actionNames(), ", "))
+		}
+		if len(ks) == 0 {
+			return keyMap{}, fmt.Errorf("tui key action %q has no keys", action)
+		}
+		keys[action] = ks
+	}
+	for action, ks := range keys {
+		for i, k := range ks {
+			if k == "space" {
+				ks = append([]string(nil), ks...)
+				ks[i] = " "
+				keys[action] = ks
+			}
+		}
+	}
+
+	keys["options"] = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}
+	if err := checkKeyConflicts(keys); err != nil {
+		return keyMap{}, err
+	}
+
+	b := make(map[string]key.Binding, len(keyActions))
+	for _, a := range keyActions {
+		b[a.name] = key.NewBinding(key.WithKeys(keys[a.name]...), key.WithHelp(keyLabel(keys[a.name]), a.help))
+	}
+	return keyMap{
+		Up: b["up"], Down: b["down"], PageUp: b["page_up"], PageDown: b["page_down"],
+		Top: b["top"], Bottom: b["bottom"],
+		Reply: b["reply"], Thread: b["thread"], Archive: b["archive"], ArchiveAll: b["archive_all"],
+		Skip: b["skip"], Snooze: b["snooze"], Search: b["search"], Priority: b["priority"],
+		Tag: b["tag"], Workspace: b["workspace"], Group: b["group"], ClearFilters: b["clear_filters"],
+		Back: b["back"], Help: b["help"], Quit: b["quit"],
+		Mark: b["mark"], MarkRange: b["mark_range"], AddTag: b["add_tag"], Undo: b["undo"],
+		Snoozed: b["snoozed"], Unsnooze: b["unsnooze"], Canned: b["canned"], Editor: b["editor"],
+		Open:    b["open"],
+		Options: key.NewBinding(key.WithKeys(keys["options"]...), key.WithHelp("1-9", "option")),
+	}, nil
+}
+
+// keyLabel is how keys appear in help; bubbletea reports space as " ".
+func keyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
+
+func actionNames() []string {
+	var names []string
+	for _, a := range keyActions {
+		names = append(names, a.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ShortHelp is the footer line.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Options, k.Reply, k.Thread, k.Archive, k.Snooze, k.Search, k.Help, k.Quit}
+}
+
+// FullHelp is the `?` overlay, one column per area.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
+		{k.Options, k.Reply, k.Canned, k.Editor, k.Thread, k.Open, k.Archive, k.ArchiveAll, k.Skip, k.Snooze, k.Snoozed},
+		{k.Mark, k.MarkRange, k.AddTag, k.Undo},
+		{k.Search, k.Priority, k.Tag, k.Workspace, k.Group, k.ClearFilters},
+		{k.Back, k.Help, k.Quit},
+	}
+}
+
+// threadKeys is the subset of bindings that apply in thread mode.
+type threadKeys struct{ keyMap }
+
+func (k threadKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.PageDown, k.Reply, k.Options, k.Back, k.Help}
+}
+
+func (k threadKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
+		{k.Options, k.Reply, k.Canned, k.Editor, k.Back, k.Help},
+	}
+}
+
+// snoozedKeys is the subset of bindings that apply in the snoozed view.
+type snoozedKeys struct{ keyMap }
+
+func (k snoozedKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Unsnooze, k.Back, k.Help}
+}
+
+func (k snoozedKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom},
+		{k.Unsnooze, k.Back, k.Help},
+	}
+}
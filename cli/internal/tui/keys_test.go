@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+// newTestModel builds a feed with the default keymap and theme.
+func newTestModel(t *testing.T, filter config.FeedFilter) Model {
+	t.Helper()
+	m, err := NewModel(nil, filter, config.TUI{})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	return m
+}
+
+func keyPress(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "ctrl+n":
+		return tea.KeyMsg{Type: tea.KeyCtrlN}
+	case "ctrl+t":
+		return tea.KeyMsg{Type: tea.KeyCtrlT}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestNewKeyMap_Presets(t *testing.T) {
+	tests := []struct {
+		keymap  string
+		press   string
+		binding func(keyMap) key.Binding
+	}{
+		{"", "j", func(k keyMap) key.Binding { return k.Down }},
+		{"default", "a", func(k keyMap) key.Binding { return k.Archive }},
+		{"vim", "x", func(k keyMap) key.Binding { return k.Archive }},
+		{"vim", "i", func(k keyMap) key.Binding { return k.Reply }},
+		{"emacs", "ctrl+n", func(k keyMap) key.Binding { return k.Down }},
+	}
+	for _, tt := range tests {
+		keys, err := newKeyMap(config.TUI{Keymap: tt.keymap})
+		if err != nil {
+			t.Fatalf("%q: %v", tt.keymap, err)
+		}
+		if !key.Matches(keyPress(tt.press), tt.binding(keys)) {
+			t.Errorf("%q: expected %q to be bound", tt.keymap, tt.press)
+		}
+	}
+
+	emacs, _ := newKeyMap(config.TUI{Keymap: "emacs"})
+	if key.Matches(keyPress("j"), emacs.Down) {
+		t.Error("expected emacs preset to leave j unbound")
+	}
+}
+
+func TestNewKeyMap_Overrides(t *testing.T) {
+	keys, err := newKeyMap(config.TUI{Keys: map[string][]string{"archive": {"x", "d"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Matches(keyPress("d"), keys.Archive) || key.Matches(keyPress("a"), keys.Archive) {
+		t.Error("expected override to replace the preset's archive keys")
+	}
+	if keys.Archive.Help().Key != "x/d" {
+		t.Errorf("expected help to show remapped keys, got %q", keys.Archive.Help().Key)
+	}
+
+	if _, err := newKeyMap(config.TUI{Keymap: "nano"}); err == nil {
+		t.Error("expected unknown keymap to fail")
+	}
+	if _, err := newKeyMap(config.TUI{Keys: map[string][]string{"explode": {"x"}}}); err == nil || !strings.Contains(err.Error(), "explode") {
+		t.Errorf("expected unknown action to fail, got %v", err)
+	}
+}
+
+func TestNewKeyMap_Conflicts(t *testing.T) {
+	tests := []struct {
+		keymap string
+		keys   map[string][]string
+		want   string
+	}{
+		{"", map[string][]string{"archive": {"j"}}, `"j" is bound to both down and archive`},
+		{"", map[string][]string{"skip": {"2"}}, `"2" is bound to both options and skip`},
+		{"vim", map[string][]string{"unsnooze": {"k"}}, `"k" is bound to both up and unsnooze`},
+		{"", map[string][]string{"editor": {"esc"}}, `"esc" is bound to both editor and back`},
+	}
+	for _, tt := range tests {
+		_, err := newKeyMap(config.TUI{Keymap: tt.keymap, Keys: tt.keys})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected %q, got %v", tt.keys, tt.want, err)
+		}
+	}
+
+	// Keys shared by actions on different screens are fine.
+	if _, err := newKeyMap(config.TUI{Keys: map[string][]string{"unsnooze": {"a"}}}); err != nil {
+		t.Errorf("expected unsnooze to share a feed key, got %v", err)
+	}
+}
+
+func TestPresets_CoverEveryAction(t *testing.T) {
+	for name, preset := range keyPresets {
+		for _, a := range keyActions {
+			if len(preset[a.name]) == 0 {
+				t.Errorf("preset %s has no keys for %s", name, a.name)
+			}
+		}
+	}
+}
+
+func TestLookupTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	for _, name := range []string{"", "dark", "light", "high-contrast"} {
+		if _, err := lookupTheme(name); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+	if _, err := lookupTheme("neon"); err == nil {
+		t.Error("expected unknown theme to fail")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	th, err := lookupTheme("light")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Accent != noColorTheme.Accent {
+		t.Error("expected NO_COLOR to override the configured theme")
+	}
+}
+
+func TestHelpOverlay(t *testing.T) {
+	m, err := NewModel(nil, config.FeedFilter{}, config.TUI{Keys: map[string][]string{"snooze": {"ctrl+y"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.width, m.height = 120, 40
+
+	next, _ := m.Update(keyPress("?"))
+	m = next.(Model)
+	view := m.View()
+	if !m.showHelp || !strings.Contains(view, "Keybindings") {
+		t.Fatalf("expected ? to open the help overlay, got:\n%s", view)
+	}
+	if !strings.Contains(view, "ctrl+y") || !strings.Contains(view, "snooze") {
+		t.Errorf("expected help to list the remapped snooze key, got:\n%s", view)
+	}
+
+	next, _ = m.Update(keyPress("j"))
+	if next.(Model).showHelp {
+		t.Error("expected any key to close the help overlay")
+	}
+}
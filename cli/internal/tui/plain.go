@@ -0,0 +1,277 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/snooze"
+)
+
+// Plain mode (`feed --plain`) is the feed as a line-at-a-time prompt: no
+// alternate screen, cursor movement, borders or color, so it works on serial
+// consoles, dumb terminals and with screen readers. It uses the same queries
+// and mutations as the full-screen feed.
+
+const plainHelp = `Commands:
+  l                 list pending notifications
+  s CODE            show one in full
+  r CODE text       reply
+  o CODE N          pick option N (or type the option)
+  z CODE when       snooze, e.g. 15m, 14:30, tomorrow
+  a CODE            archive
+  h                 this help
+  q                 quit`
+
+type plainSession struct {
+	client *client.Client
+	out    io.Writer
+	items  []feedNotification
+	now    func() time.Time
+}
+
+// RunPlain runs the plain-mode feed, reading commands from in until it sees
+// "q" or end of input.
+func RunPlain(c *client.Client, in io.Reader, out io.Writer) error {
+	s := &plainSession{client: c, out: out, now: time.Now}
+	if err := s.list(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Type h for help.")
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "q" || line == "quit" || line == "exit" {
+			return nil
+		}
+		if err := s.run(line); err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+		}
+	}
+}
+
+// run carries out one command line.
+func (s *plainSession) run(line string) error {
+	if line == "" {
+		return nil
+	}
+	verb, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	code, arg, _ := strings.Cut(rest, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(verb) {
+	case "l", "list", "ls":
+		return s.list()
+	case "h", "help", "?":
+		fmt.Fprintln(s.out, plainHelp)
+		return nil
+	case "s", "show":
+		n, err := s.lookup(code)
+		if err != nil {
+			return err
+		}
+		s.show(*n)
+		return nil
+	case "r", "reply":
+		n, err := s.lookup(code)
+		if err != nil {
+			return err
+		}
+		if arg == "" {
+			return fmt.Errorf("usage: r CODE text")
+		}
+		if err := submitResponse(s.client, n.ID, &arg, nil); err != nil {
+			return fmt.Errorf("reply: %w", err)
+		}
+		s.forget(n.ID)
+		fmt.Fprintf(s.out, "Replied to %s.\n", n.ShortCode)
+		return nil
+	case "o", "option":
+		n, err := s.lookup(code)
+		if err != nil {
+			return err
+		}
+		opt, err := pickOption(*n, arg)
+		if err != nil {
+			return err
+		}
+		if err := submitResponse(s.client, n.ID, nil, &opt); err != nil {
+			return fmt.Errorf("respond: %w", err)
+		}
+		s.forget(n.ID)
+		fmt.Fprintf(s.out, "Answered %s: %s.\n", n.ShortCode, opt)
+		return nil
+	case "z", "snooze":
+		n, err := s.lookup(code)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		until, err := snooze.Parse(arg, now)
+		if err != nil {
+			return err
+		}
+		if err := snoozeNotification(s.client, n.ID, until); err != nil {
+			return fmt.Errorf("snooze: %w", err)
+		}
+		s.forget(n.ID)
+		fmt.Fprintf(s.out, "Snoozed %s until %s.\n", n.ShortCode, snooze.Describe(until, now))
+		return nil
+	case "a", "archive":
+		n, err := s.lookup(code)
+		if err != nil {
+			return err
+		}
+		if err := archiveNotificationReq(s.client, n.ID); err != nil {
+			return fmt.Errorf("archive: %w", err)
+		}
+		s.forget(n.ID)
+		fmt.Fprintf(s.out, "Archived %s.\n", n.ShortCode)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q; type h for help", verb)
+}
+
+func (s *plainSession) refresh() error {
+	snap, err := fetchActiveFeed(s.client)
+	if err != nil {
+		return fmt.Errorf("fetch feed: %w", err)
+	}
+	s.items = snap.items
+	return nil
+}
+
+func (s *plainSession) list() error {
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	switch len(s.items) {
+	case 0:
+		fmt.Fprintln(s.out, "No pending notifications.")
+		return nil
+	case 1:
+		fmt.Fprintln(s.out, "1 pending notification:")
+	default:
+		fmt.Fprintf(s.out, "%d pending notifications:\n", len(s.items))
+	}
+	for _, n := range s.items {
+		fmt.Fprintf(s.out, "%s P%d, %s%s: %s\n", n.ShortCode, n.Priority, n.Age(), plainWhere(n), oneLine(n.Message))
+		if len(n.Options) > 0 {
+			fmt.Fprintln(s.out, "  Options: "+plainOptions(n.Options))
+		}
+	}
+	return nil
+}
+
+func (s *plainSession) show(n feedNotification) {
+	fmt.Fprintf(s.out, "%s, priority %d, %s, %s\n", n.ShortCode, n.Priority, n.Status, n.Age())
+	fmt.Fprintln(s.out, n.Message)
+	if cd := n.Countdown(); cd != "" {
+		fmt.Fprintln(s.out, "Deadline: "+cd)
+	}
+	if where := originLine(n); where != "" {
+		fmt.Fprintln(s.out, where)
+	}
+	if len(n.Tags) > 0 {
+		fmt.Fprintln(s.out, "Tags: "+strings.Join(n.Tags, ", "))
+	}
+	if len(n.Options) > 0 {
+		fmt.Fprintln(s.out, "Options: "+plainOptions(n.Options))
+	}
+	for _, e := range n.Context {
+		fmt.Fprintf(s.out, "Context %s: %s\n", e.Key, e.Value)
+	}
+	for _, r := range n.Responses {
+		text := deref(r.Text)
+		if text == "" && r.SelectedOption != nil {
+			text = "chose " + *r.SelectedOption
+		}
+		fmt.Fprintf(s.out, "Response via %s: %s\n", r.Channel, text)
+	}
+}
+
+// lookup finds a notification by short code, case-insensitively, fetching
+// the feed again if it isn't in the last listing.
+func (s *plainSession) lookup(code string) (*feedNotification, error) {
+	if code == "" {
+		return nil, fmt.Errorf("which notification? give its short code")
+	}
+	find := func() *feedNotification {
+		for _, n := range s.items {
+			if strings.EqualFold(n.ShortCode, code) {
+				return &n
+			}
+		}
+		return nil
+	}
+	if n := find(); n != nil {
+		return n, nil
+	}
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+	if n := find(); n != nil {
+		return n, nil
+	}
+	return nil, fmt.Errorf("no pending notification %s", strings.ToUpper(code))
+}
+
+// forget drops an answered notification from the last listing.
+func (s *plainSession) forget(id string) {
+	for i, n := range s.items {
+		if n.ID == id {
+			s.items = append(s.items[:i:i], s.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// pickOption resolves "2" or an option's text to the option itself.
+func pickOption(n feedNotification, arg string) (string, error) {
+	if len(n.Options) == 0 {
+		return "", fmt.Errorf("%s has no options; reply with r %s text", n.ShortCode, n.ShortCode)
+	}
+	if i, err := strconv.Atoi(arg); err == nil {
+		if i < 1 || i > len(n.Options) {
+			return "", fmt.Errorf("%s has options 1 to %d", n.ShortCode, len(n.Options))
+		}
+		return n.Options[i-1], nil
+	}
+	for _, o := range n.Options {
+		if arg != "" && strings.EqualFold(o, arg) {
+			return o, nil
+		}
+	}
+	return "", fmt.Errorf("usage: o %s N, where the options are %s", n.ShortCode, plainOptions(n.Options))
+}
+
+func plainOptions(opts []string) string {
+	parts := make([]string, len(opts))
+	for i, o := range opts {
+		parts[i] = fmt.Sprintf("%d %s", i+1, o)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plainWhere(n feedNotification) string {
+	if ws := deref(n.Workspace); ws != "" {
+		return ", " + ws
+	}
+	return ""
+}
+
+// oneLine folds a multi-line message onto one line for the listing.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const plainFeed = `{"activeFeed":[
+	{"id":"n1","shortCode":"ABC","message":"Deploy to prod?","priority":5,"options":["yes","no"],"status":"delivered","createdAt":"2026-01-01T00:00:00Z","workspace":"/api"},
+	{"id":"n2","shortCode":"DEF","message":"Which branch\nshould I use?","priority":3,"status":"delivered","createdAt":"2026-01-01T00:00:00Z","tags":["git"],"context":"{\"pr\":\"https://example.com/pr/1\"}"}
+]}`
+
+func runPlainScript(t *testing.T, api *fakeAPI, script string) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := RunPlain(api.client(), strings.NewReader(script), &out); err != nil {
+		t.Fatalf("RunPlain: %v", err)
+	}
+	return out.String()
+}
+
+func TestPlain_Commands(t *testing.T) {
+	api := &fakeAPI{t: t, responses: map[string]string{"ActiveFeed": plainFeed}}
+	out := runPlainScript(t, api, strings.Join([]string{
+		"s def",
+		"o ABC 2",
+		"r DEF use main",
+		"z abc 15m",
+		"a ABC",
+		"q",
+		"a DEF",
+	}, "\n"))
+
+	for _, want := range []string{
+		"2 pending notifications:",
+		"ABC P5, ",
+		", /api: Deploy to prod?",
+		"  Options: 1 yes, 2 no",
+		": Which branch should I use?",
+		"Tags: git",
+		"Context pr: https://example.com/pr/1",
+		"Answered ABC: no.",
+		"Replied to DEF.",
+		"Snoozed ABC until ",
+		"Archived ABC.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\x1b") {
+		t.Error("plain mode must not write escape sequences")
+	}
+
+	want := []string{"RespondToNotification n1", "RespondToNotification n2", "SnoozeNotification n1", "ArchiveNotification n1"}
+	got := api.mutations()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("mutations: got %v, want %v", got, want)
+	}
+	if v := api.calls[1].vars; v["selectedOption"] != "no" {
+		t.Errorf("expected option 2 to send its text, got %v", v)
+	}
+}
+
+func TestPlain_Errors(t *testing.T) {
+	api := &fakeAPI{t: t, responses: map[string]string{"ActiveFeed": plainFeed}}
+	out := runPlainScript(t, api, strings.Join([]string{
+		"o ABC 3",
+		"o DEF 1",
+		"r ABC",
+		"z ABC whenever",
+		"a XYZ",
+		"frobnicate",
+	}, "\n"))
+
+	for _, want := range []string{
+		"Error: ABC has options 1 to 2",
+		"Error: DEF has no options",
+		"Error: usage: r CODE text",
+		"Error: can't tell when",
+		"Error: no pending notification XYZ",
+		`Error: unknown command "frobnicate"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if m := api.mutations(); len(m) != 0 {
+		t.Errorf("expected no mutations, got %v", m)
+	}
+}
+
+func TestPickOption_ByText(t *testing.T) {
+	n := feedNotification{ShortCode: "ABC", Options: []string{"Yes", "No"}}
+	if got, err := pickOption(n, "no"); err != nil || got != "No" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/output"
 )
 
 const activeFeedQuery = `query ActiveFeed {
@@ -18,11 +19,71 @@ const activeFeedQuery = `query ActiveFeed {
 		status
 		createdAt
 		snoozedUntil
+		expiresAt
+		defaultOption
+		editedAt
+		repeatCount
+		tags
+		sessionId
+		sessionKey
+		workspace
+		parentId
+		context
+		policyId
+		currentEscalationStep
 		responses {
 			text
 			selectedOption
 			channel
+			responder
 			createdAt
+			reactions {
+				emoji
+			}
+		}
+	}
+	activeProgress {
+		id
+		key
+		message
+		percent
+		workspace
+		updatedAt
+	}
+	me {
+		doNotDisturb {
+			reason
+			until
+			breakthroughPriority
+		}
+	}
+}`
+
+const notificationThreadQuery = `query NotificationThread($id: String!) {
+	notificationThread(id: $id) {
+		sessionKey
+		workspace
+		notifications {
+			id
+			shortCode
+			message
+			priority
+			options
+			status
+			createdAt
+			repeatCount
+			sessionId
+			parentId
+			responses {
+				text
+				selectedOption
+				channel
+				responder
+				createdAt
+				reactions {
+					emoji
+				}
+			}
 		}
 	}
 }`
@@ -41,17 +102,36 @@ const archiveMutation = `mutation ArchiveNotification($id: String!) {
 	}
 }`
 
-const archiveAllMutation = `mutation ArchiveAllNotifications {
-	archiveAllNotifications
+const snoozeMutation = `mutation SnoozeNotification($id: String!, $until: String!) {
+	snoozeNotification(id: $id, until: $until) {
+		id
+		snoozedUntil
+	}
 }`
 
-const snoozeMutation = `mutation SnoozeNotification($id: String!, $minutes: Int!) {
-	snoozeNotification(id: $id, minutes: $minutes) {
+const unsnoozeMutation = `mutation UnsnoozeNotification($id: String!) {
+	unsnoozeNotification(id: $id) {
 		id
+	}
+}`
+
+const snoozedNotificationsQuery = `query SnoozedNotifications {
+	snoozedNotifications {
+		id
+		shortCode
+		message
+		priority
 		snoozedUntil
 	}
 }`
 
+const tagNotificationsMutation = `mutation TagNotifications($ids: [String!]!, $tags: [String!]!) {
+	tagNotifications(ids: $ids, tags: $tags) {
+		id
+		tags
+	}
+}`
+
 type feedNotification struct {
 	ID           string   `json:"id"`
 	ShortCode    string   `json:"shortCode"`
@@ -61,6 +141,47 @@ type feedNotification struct {
 	Status       string   `json:"status"`
 	CreatedAt    string   `json:"createdAt"`
 	SnoozedUntil *string  `json:"snoozedUntil"`
+
+	ExpiresAt     *string `json:"expiresAt"`
+	DefaultOption *string `json:"defaultOption"`
+	EditedAt      *string `json:"editedAt"`
+	RepeatCount   int     `json:"repeatCount"`
+
+	Tags       []string       `json:"tags"`
+	SessionID  *string        `json:"sessionId"`
+	SessionKey *string        `json:"sessionKey"`
+	Workspace  *string        `json:"workspace"`
+	ParentID   *string        `json:"parentId"`
+	Responses  []feedResponse `json:"responses"`
+
+	// Context is what the agent attached with --context. PolicyID is set
+	// while an escalation policy is paging, and EscalationStep (0-based) is
+	// the step it has reached.
+	Context        feedContext `json:"context"`
+	PolicyID       *string     `json:"policyId"`
+	EscalationStep int         `json:"currentEscalationStep"`
+}
+
+// feedResponse is one answer to a notification, from whichever channel it
+// arrived on.
+type feedResponse struct {
+	Text           *string        `json:"text"`
+	SelectedOption *string        `json:"selectedOption"`
+	Channel        string         `json:"channel"`
+	Responder      *string        `json:"responder"`
+	CreatedAt      string         `json:"createdAt"`
+	Reactions      []feedReaction `json:"reactions"`
+}
+
+type feedReaction struct {
+	Emoji string `json:"emoji"`
+}
+
+// feedThread is the conversation a notification belongs to, oldest first.
+type feedThread struct {
+	SessionKey    string             `json:"sessionKey"`
+	Workspace     *string            `json:"workspace"`
+	Notifications []feedNotification `json:"notifications"`
 }
 
 func (n feedNotification) Age() string {
@@ -81,19 +202,89 @@ func (n feedNotification) Age() string {
 	}
 }
 
-func fetchActiveFeed(c *client.Client) ([]feedNotification, error) {
+// Countdown describes the time left before the notification's deadline, or
+// "" when it has none.
+func (n feedNotification) Countdown() string {
+	if n.ExpiresAt == nil {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, *n.ExpiresAt)
+	if err != nil {
+		return ""
+	}
+	d := time.Until(t)
+	var s string
+	switch {
+	case d <= 0:
+		s = "expiring"
+	case d < time.Minute:
+		s = fmt.Sprintf("expires in %ds", int(d.Seconds()))
+	case d < time.Hour:
+		s = fmt.Sprintf("expires in %dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		s = fmt.Sprintf("expires in %dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	if n.DefaultOption != nil && *n.DefaultOption != "" {
+		s += " → " + *n.DefaultOption
+	}
+	return s
+}
+
+// feedProgress is a live status line reported with `agentduty progress`.
+type feedProgress struct {
+	ID        string  `json:"id"`
+	Key       string  `json:"key"`
+	Message   string  `json:"message"`
+	Percent   *int    `json:"percent"`
+	Workspace *string `json:"workspace"`
+	UpdatedAt string  `json:"updatedAt"`
+}
+
+// feedSnapshot is everything one feed refresh fetches.
+type feedSnapshot struct {
+	items    []feedNotification
+	progress []feedProgress
+	hold     *output.DoNotDisturb
+}
+
+func fetchActiveFeed(c *client.Client) (feedSnapshot, error) {
 	data, err := c.Do(activeFeedQuery, nil)
 	if err != nil {
-		return nil, err
+		return feedSnapshot{}, err
+	}
+
+	var result struct {
+		ActiveFeed     []feedNotification `json:"activeFeed"`
+		ActiveProgress []feedProgress     `json:"activeProgress"`
+		Me             *struct {
+			DoNotDisturb *output.DoNotDisturb `json:"doNotDisturb"`
+		} `json:"me"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return feedSnapshot{}, err
+	}
+	snap := feedSnapshot{items: result.ActiveFeed, progress: result.ActiveProgress}
+	if result.Me != nil {
+		snap.hold = result.Me.DoNotDisturb
 	}
+	return snap, nil
+}
 
+func fetchNotificationThread(c *client.Client, id string) (*feedThread, error) {
+	data, err := c.Do(notificationThreadQuery, map[string]any{"id": id})
+	if err != nil {
+		return nil, err
+	}
 	var result struct {
-		ActiveFeed []feedNotification `json:"activeFeed"`
+		NotificationThread *feedThread `json:"notificationThread"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, err
 	}
-	return result.ActiveFeed, nil
+	if result.NotificationThread == nil {
+		return nil, fmt.Errorf("notification %s not found", id)
+	}
+	return result.NotificationThread, nil
 }
 
 func submitResponse(c *client.Client, id string, text *string, selectedOption *string) error {
@@ -108,28 +299,40 @@ func submitResponse(c *client.Client, id string, text *string, selectedOption *s
 	return err
 }
 
-func snoozeNotification(c *client.Client, id string, minutes int) error {
-	vars := map[string]any{"id": id, "minutes": minutes}
+func snoozeNotification(c *client.Client, id string, until time.Time) error {
+	vars := map[string]any{"id": id, "until": until.UTC().Format(time.RFC3339)}
 	_, err := c.Do(snoozeMutation, vars)
 	return err
 }
 
-func archiveNotificationReq(c *client.Client, id string) error {
+func unsnoozeNotification(c *client.Client, id string) error {
 	vars := map[string]any{"id": id}
-	_, err := c.Do(archiveMutation, vars)
+	_, err := c.Do(unsnoozeMutation, vars)
 	return err
 }
 
-func archiveAllNotificationsReq(c *client.Client) (int, error) {
-	data, err := c.Do(archiveAllMutation, nil)
+func fetchSnoozed(c *client.Client) ([]feedNotification, error) {
+	data, err := c.Do(snoozedNotificationsQuery, nil)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	var result struct {
-		ArchiveAllNotifications int `json:"archiveAllNotifications"`
+		SnoozedNotifications []feedNotification `json:"snoozedNotifications"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
-		return 0, err
+		return nil, err
 	}
-	return result.ArchiveAllNotifications, nil
+	return result.SnoozedNotifications, nil
+}
+
+func archiveNotificationReq(c *client.Client, id string) error {
+	vars := map[string]any{"id": id}
+	_, err := c.Do(archiveMutation, vars)
+	return err
+}
+
+func tagNotifications(c *client.Client, ids []string, tags []string) error {
+	vars := map[string]any{"ids": ids, "tags": tags}
+	_, err := c.Do(tagNotificationsMutation, vars)
+	return err
 }
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// replayFeed is an activeFeed response with everything created just now, so
+// ages render as "now" in snapshots. Leave out IDs to drop items.
+func replayFeed(drop ...string) string {
+	now := time.Now().UTC().Format(time.RFC3339)
+	items := []struct{ id, json string }{
+		{"n1", `{"id":"n1","shortCode":"ABC","message":"Deploy api to production?","priority":5,"options":["yes","no"],"status":"delivered","createdAt":"%s","workspace":"/srv/api","tags":["deploy"],"sessionKey":"claude-1","context":"{\"pr\":\"https://example.com/pr/7\",\"branch\":\"main\"}"}`},
+		{"n2", `{"id":"n2","shortCode":"DEF","message":"Which migration should run first, the users table or the orders table?","priority":3,"status":"delivered","createdAt":"%s","workspace":"/srv/web"}`},
+		{"n3", `{"id":"n3","shortCode":"GHI","message":"Tests are flaky on CI, retry?","priority":2,"options":["retry","skip"],"status":"pending","createdAt":"%s"}`},
+	}
+	var parts []string
+	for _, it := range items {
+		skip := false
+		for _, d := range drop {
+			skip = skip || d == it.id
+		}
+		if !skip {
+			parts = append(parts, fmt.Sprintf(it.json, now))
+		}
+	}
+	progress := fmt.Sprintf(`{"id":"p1","key":"build","message":"Compiling","percent":40,"workspace":"/srv/api","updatedAt":"%s"}`, now)
+	return `{"activeFeed":[` + strings.Join(parts, ",") + `],"activeProgress":[` + progress + `],"me":{"doNotDisturb":null}}`
+}
+
+func replayAPI(t *testing.T) *fakeAPI {
+	return &fakeAPI{t: t, responses: map[string]string{"ActiveFeed": replayFeed()}, fail: map[string]string{}}
+}
+
+func TestReplay_Layouts(t *testing.T) {
+	for _, size := range []struct {
+		name          string
+		width, height int
+	}{
+		{"split_120", 120, 30},
+		{"split_90", minSplitWidth, 30},
+		{"single_89", minSplitWidth - 1, 30},
+		{"single_50", 50, 24},
+	} {
+		t.Run(size.name, func(t *testing.T) {
+			h := newHarness(t, replayAPI(t), size.width, size.height)
+			h.press("j")
+			h.snapshot("layout_" + size.name)
+		})
+	}
+}
+
+func TestReplay_Resize(t *testing.T) {
+	h := newHarness(t, replayAPI(t), 120, 30)
+	if !strings.Contains(h.view(), "https://example.com/pr/7") {
+		t.Fatalf("expected the split layout's detail panel:\n%s", h.view())
+	}
+	h.resize(60, 30)
+	if strings.Contains(h.view(), "https://example.com/pr/7") {
+		t.Errorf("expected a narrow terminal to drop the detail panel:\n%s", h.view())
+	}
+	h.resize(120, 30)
+	h.snapshot("layout_split_120_resized")
+}
+
+func TestReplay_OptionHidesItem(t *testing.T) {
+	api := replayAPI(t)
+	h := newHarness(t, api, 120, 30)
+
+	// The server still lists ABC on the first refresh after answering.
+	h.press("1")
+	if !h.m.hidden["n1"] || strings.Contains(h.view(), "Deploy api") {
+		t.Fatalf("expected ABC hidden while the server catches up:\n%s", h.view())
+	}
+	if got := api.mutations(); len(got) != 1 || got[0] != "RespondToNotification n1" || api.calls[len(api.calls)-2].vars["selectedOption"] != "yes" {
+		t.Fatalf("expected the option sent, got %v", got)
+	}
+
+	// Once it drops out of the feed it's forgotten.
+	api.responses["ActiveFeed"] = replayFeed("n1")
+	h.send(tickMsg{})
+	if h.m.hidden["n1"] || h.m.cursor != 0 {
+		t.Errorf("expected hidden to be reconciled, got hidden=%v cursor=%d", h.m.hidden, h.m.cursor)
+	}
+	h.snapshot("option_answered")
+}
+
+func TestReplay_ArchiveRollback(t *testing.T) {
+	api := replayAPI(t)
+	api.fail["ArchiveNotification"] = "notification is locked"
+	h := newHarness(t, api, 120, 30)
+
+	h.press("a")
+	if h.m.hidden["n1"] {
+		t.Fatal("expected the failed archive to bring ABC back")
+	}
+	if !strings.Contains(h.m.status, "notification is locked") {
+		t.Errorf("expected the error in the status line, got %q", h.m.status)
+	}
+	h.snapshot("archive_rollback")
+}
+
+func TestReplay_BulkPartialFailure(t *testing.T) {
+	api := replayAPI(t)
+	api.fail["ArchiveNotification n2"] = "already answered"
+	h := newHarness(t, api, 120, 30)
+
+	h.press(" ", " ", "a")
+	if h.m.state != stateConfirm {
+		t.Fatalf("expected a confirmation, got state %v", h.m.state)
+	}
+	h.press("y")
+	if !h.m.hidden["n1"] || !h.m.hidden["n2"] || len(api.mutations()) != 0 {
+		t.Fatalf("expected both hidden and nothing sent during the undo window, got %v", api.mutations())
+	}
+	if h.timers == 0 {
+		t.Fatal("expected an undo timer")
+	}
+
+	// The undo window runs out.
+	api.responses["ActiveFeed"] = replayFeed("n1")
+	h.send(bulkCommitMsg{seq: h.m.pending.seq})
+	if got := strings.Join(api.mutations(), ","); got != "ArchiveNotification n1,ArchiveNotification n2" {
+		t.Errorf("got %s", got)
+	}
+	if h.m.hidden["n2"] || !strings.Contains(h.m.status, "1 of them failed") {
+		t.Errorf("expected DEF rolled back with an error, got hidden=%v status=%q", h.m.hidden, h.m.status)
+	}
+	h.snapshot("bulk_partial_failure")
+}
+
+func TestReplay_Undo(t *testing.T) {
+	api := replayAPI(t)
+	h := newHarness(t, api, 80, 30)
+
+	h.press(" ", "2")
+	if !h.m.hidden["n1"] || h.m.pending == nil {
+		t.Fatalf("expected the bulk answer to wait for the undo window")
+	}
+	h.press("u")
+	seq := h.m.bulkSeq
+	h.send(bulkCommitMsg{seq: seq})
+	if h.m.hidden["n1"] || len(api.mutations()) != 0 {
+		t.Errorf("expected undo to cancel the answer, got hidden=%v sent=%v", h.m.hidden, api.mutations())
+	}
+	h.snapshot("undo_single_80")
+}
+
+func TestReplay_Quit(t *testing.T) {
+	h := newHarness(t, replayAPI(t), 120, 30)
+	h.press("q")
+	if !h.quit {
+		t.Error("expected q to quit")
+	}
+}
@@ -0,0 +1,268 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sestinj/agentduty/cli/internal/client"
+	"github.com/sestinj/agentduty/cli/internal/snooze"
+)
+
+// The snooze picker offers a few presets on the digit keys; `/` (or just
+// starting to type) opens a free-text box that understands "45m", "14:30",
+// "tomorrow", "fri 10am" and so on. The snoozed view (`Z`) lists what is
+// snoozed and brings items back early.
+
+var snoozePresets = []struct {
+	label string
+	in    string // parsed with snooze.Parse
+}{
+	{"5m", "5m"},
+	{"15m", "15m"},
+	{"1h", "1h"},
+	{"4h", "4h"},
+	{"tomorrow", "tomorrow"},
+}
+
+type snoozedLoadedMsg struct {
+	items []feedNotification
+	err   error
+}
+
+type unsnoozedMsg struct {
+	shortCode string
+	err       error
+}
+
+func newSnoozeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "Snooze for/until: "
+	ti.Placeholder = "45m, 14:30, tomorrow, fri 10am"
+	ti.CharLimit = 40
+	return ti
+}
+
+// snoozePresetsText is the picker's one-line menu.
+func snoozePresetsText() string {
+	var parts []string
+	for i, p := range snoozePresets {
+		parts = append(parts, fmt.Sprintf("[%d] %s", i+1, p.label))
+	}
+	return strings.Join(parts, "  ") + "  [/] other  Esc cancel"
+}
+
+func (m Model) openSnoozePicker() Model {
+	if m.focusedItem() == nil {
+		return m
+	}
+	m.state = stateSnoozePicker
+	m.status = "Snooze: " + snoozePresetsText()
+	if sel := m.selection(); len(sel) > 0 {
+		m.status = fmt.Sprintf("Snooze %d: %s", len(sel), snoozePresetsText())
+	}
+	return m
+}
+
+func (m Model) handleSnoozePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := msg.String()
+	if len(s) == 1 && s[0] >= '1' && int(s[0]-'1') < len(snoozePresets) {
+		until, err := snooze.Parse(snoozePresets[s[0]-'1'].in, time.Now())
+		if err != nil {
+			m.state = stateBrowsing
+			m.status = fmt.Sprintf("Error: %v", err)
+			return m, nil
+		}
+		return m.snoozeUntil(until)
+	}
+
+	switch {
+	case s == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.state = stateBrowsing
+		m.status = ""
+		return m, nil
+	case s == "/" || s == "tab":
+		m.state = stateSnoozeInput
+		m.snoozeInput.Reset()
+		return m, m.snoozeInput.Focus()
+	case msg.Type == tea.KeyRunes:
+		// Typing straight away starts a custom time with what was typed.
+		m.state = stateSnoozeInput
+		m.snoozeInput.SetValue(string(msg.Runes))
+		m.snoozeInput.CursorEnd()
+		return m, m.snoozeInput.Focus()
+	}
+	return m, nil
+}
+
+func (m Model) handleSnoozeInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		m.snoozeInput.Blur()
+		m.state = stateBrowsing
+		m.status = ""
+		return m, nil
+	case "enter":
+		until, err := snooze.Parse(m.snoozeInput.Value(), time.Now())
+		if err != nil {
+			m.status = fmt.Sprintf("Error: %v", err)
+			return m, nil
+		}
+		m.snoozeInput.Blur()
+		return m.snoozeUntil(until)
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.snoozeInput, cmd = m.snoozeInput.Update(msg)
+	m.status = m.snoozePreview()
+	return m, cmd
+}
+
+// snoozePreview says when the typed snooze would end, or why it can't be
+// understood yet.
+func (m Model) snoozePreview() string {
+	v := strings.TrimSpace(m.snoozeInput.Value())
+	if v == "" {
+		return "Enter to snooze · Esc to cancel"
+	}
+	now := time.Now()
+	until, err := snooze.Parse(v, now)
+	if err != nil {
+		return "Not a time yet · try 45m, 14:30, tomorrow or fri 10am"
+	}
+	return "Until " + snooze.Describe(until, now) + " · Enter to snooze · Esc to cancel"
+}
+
+// snoozeUntil snoozes the selection, or else the focused notification.
+func (m Model) snoozeUntil(until time.Time) (tea.Model, tea.Cmd) {
+	m.state = stateBrowsing
+	c := m.client
+	if sel := m.selection(); len(sel) > 0 {
+		targets := idsOf(sel)
+		next, cmd, _ := m.beginBulk("Snoozed", targets, eachID("Snoozed", targets, func(id string) error {
+			return snoozeNotification(c, id, until)
+		}))
+		return next, cmd
+	}
+	n := m.focusedItem()
+	if n == nil {
+		return m, nil
+	}
+	m.hidden[n.ID] = true
+	if m.cursor >= len(m.visibleItems()) {
+		m.cursor = max(0, len(m.visibleItems())-1)
+	}
+	m.status = fmt.Sprintf("Snoozed %s until %s", n.ShortCode, snooze.Describe(until, time.Now()))
+	return m, snoozeCmd(c, n.ID, until)
+}
+
+func (m Model) openSnoozed() (Model, tea.Cmd) {
+	m.state = stateSnoozed
+	m.snoozed = nil
+	m.snoozedCursor = 0
+	m.status = "Loading snoozed..."
+	return m, fetchSnoozedCmd(m.client)
+}
+
+func (m Model) handleSnoozedKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back, m.keys.Quit, m.keys.Snoozed):
+		m.state = stateBrowsing
+		m.snoozed = nil
+		m.status = ""
+		return m, nil
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = true
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		m.snoozedCursor = max(0, m.snoozedCursor-1)
+		return m, nil
+	case key.Matches(msg, m.keys.Down):
+		m.snoozedCursor = max(0, min(len(m.snoozed)-1, m.snoozedCursor+1))
+		return m, nil
+	case key.Matches(msg, m.keys.Top):
+		m.snoozedCursor = 0
+		return m, nil
+	case key.Matches(msg, m.keys.Bottom):
+		m.snoozedCursor = max(0, len(m.snoozed)-1)
+		return m, nil
+	case key.Matches(msg, m.keys.Unsnooze):
+		if m.snoozedCursor >= len(m.snoozed) {
+			return m, nil
+		}
+		n := m.snoozed[m.snoozedCursor]
+		m.snoozed = append(m.snoozed[:m.snoozedCursor:m.snoozedCursor], m.snoozed[m.snoozedCursor+1:]...)
+		m.snoozedCursor = max(0, min(len(m.snoozed)-1, m.snoozedCursor))
+		m.status = fmt.Sprintf("Un-snoozing %s...", n.ShortCode)
+		return m, unsnoozeCmd(m.client, n.ID, n.ShortCode)
+	}
+	return m, nil
+}
+
+func (m Model) viewSnoozed() string {
+	var b strings.Builder
+	b.WriteString(threadTitleStyle.Render("Snoozed") + metaStyle.Render(fmt.Sprintf(" (%d)", len(m.snoozed))) + "\n\n")
+
+	now := time.Now()
+	if len(m.snoozed) == 0 && m.status != "Loading snoozed..." {
+		b.WriteString(metaStyle.Render("Nothing is snoozed.") + "\n")
+	}
+	for i, n := range m.snoozed {
+		marker := "  "
+		if i == m.snoozedCursor {
+			marker = threadTargetStyle.Render("▶ ")
+		}
+		pStyle, ok := priorityStyles[n.Priority]
+		if !ok {
+			pStyle = priorityStyles[3]
+		}
+		until := ""
+		if n.SnoozedUntil != nil {
+			if t, err := time.Parse(time.RFC3339, *n.SnoozedUntil); err == nil {
+				until = "until " + snooze.Describe(t, now)
+			}
+		}
+		head := marker + pStyle.Render(fmt.Sprintf("P%d", n.Priority)) + " " +
+			threadTitleStyle.Render(n.ShortCode) + " " + metaStyle.Render(fmt.Sprintf("%-16s", until)) + " "
+		b.WriteString(head + truncateText(n.Message, max(m.width-lipgloss.Width(head), 10)) + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(metaStyle.Render(m.status) + "\n")
+	} else {
+		b.WriteString("\n")
+	}
+	b.WriteString(m.footer(snoozedKeys{m.keys}))
+	return b.String()
+}
+
+func snoozeCmd(c *client.Client, id string, until time.Time) tea.Cmd {
+	return func() tea.Msg {
+		err := snoozeNotification(c, id, until)
+		return snoozedMsg{id: id, err: err}
+	}
+}
+
+func fetchSnoozedCmd(c *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		items, err := fetchSnoozed(c)
+		return snoozedLoadedMsg{items: items, err: err}
+	}
+}
+
+func unsnoozeCmd(c *client.Client, id, shortCode string) tea.Cmd {
+	return func() tea.Msg {
+		err := unsnoozeNotification(c, id)
+		return unsnoozedMsg{shortCode: shortCode, err: err}
+	}
+}
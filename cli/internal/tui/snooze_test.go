@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnoozePicker_Preset(t *testing.T) {
+	m := bulkModel(t)
+	m = press(t, m, "z")
+	if m.state != stateSnoozePicker || !strings.Contains(m.status, "[5] tomorrow") {
+		t.Fatalf("expected the picker, got state=%v status=%q", m.state, m.status)
+	}
+
+	next, cmd := m.Update(keyPress("2"))
+	m = next.(Model)
+	if cmd == nil || m.state != stateBrowsing || !m.hidden["a"] {
+		t.Fatal("expected the preset to snooze the focused item")
+	}
+	if !strings.Contains(m.status, "Snoozed AAA until") {
+		t.Errorf("expected a confirmation, got %q", m.status)
+	}
+}
+
+func TestSnoozePicker_FreeText(t *testing.T) {
+	m := bulkModel(t)
+	m = press(t, m, "z", "t", "o", "m")
+	if m.state != stateSnoozeInput || m.snoozeInput.Value() != "tom" {
+		t.Fatalf("expected typing to start a custom time, got state=%v value=%q", m.state, m.snoozeInput.Value())
+	}
+	if !strings.HasPrefix(m.status, "Not a time yet") {
+		t.Errorf("expected an unparsed preview, got %q", m.status)
+	}
+
+	m = press(t, m, "o", "r", "r", "o", "w")
+	if !strings.HasPrefix(m.status, "Until ") {
+		t.Errorf("expected a preview of the end time, got %q", m.status)
+	}
+
+	next, cmd := m.Update(keyPress("enter"))
+	m = next.(Model)
+	if cmd == nil || m.state != stateBrowsing || !m.hidden["a"] {
+		t.Fatalf("expected enter to snooze, got state=%v status=%q", m.state, m.status)
+	}
+}
+
+func TestSnoozePicker_FreeTextError(t *testing.T) {
+	m := bulkModel(t)
+	m = press(t, m, "z", "/")
+	for _, r := range "soonish" {
+		m = press(t, m, string(r))
+	}
+	m = press(t, m, "enter")
+	if m.state != stateSnoozeInput || !strings.Contains(m.status, "can't tell when") || m.hidden["a"] {
+		t.Errorf("expected to stay in the input with an error, got state=%v status=%q", m.state, m.status)
+	}
+	m = press(t, m, "esc")
+	if m.state != stateBrowsing {
+		t.Errorf("expected esc to cancel")
+	}
+}
+
+func TestSnoozePicker_Bulk(t *testing.T) {
+	m := bulkModel(t)
+	m = press(t, m, " ", " ", "z", "3")
+	if m.pending == nil || !m.hidden["a"] || !m.hidden["b"] || m.hidden["c"] {
+		t.Fatalf("expected both marked items snoozed with undo, got hidden=%v", m.hidden)
+	}
+}
+
+func TestSnoozedView(t *testing.T) {
+	m := bulkModel(t)
+	next, cmd := m.Update(keyPress("Z"))
+	m = next.(Model)
+	if cmd == nil || m.state != stateSnoozed {
+		t.Fatalf("expected Z to open the snoozed view and load it")
+	}
+
+	until := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	items := filterItems()[:2]
+	for i := range items {
+		items[i].SnoozedUntil = &until
+	}
+	next, _ = m.Update(snoozedLoadedMsg{items: items})
+	m = next.(Model)
+	view := m.View()
+	if !strings.Contains(view, "Snoozed (2)") || !strings.Contains(view, "until ") {
+		t.Fatalf("expected the snoozed list, got:\n%s", view)
+	}
+
+	next, _ = m.Update(keyPress("j"))
+	m = next.(Model)
+	next, cmd = m.Update(keyPress("u"))
+	m = next.(Model)
+	if cmd == nil || len(m.snoozed) != 1 || m.snoozed[0].ID != "a" || m.snoozedCursor != 0 {
+		t.Fatalf("expected u to un-snooze the focused item, got %+v cursor=%d", m.snoozed, m.snoozedCursor)
+	}
+
+	next, _ = m.Update(unsnoozedMsg{shortCode: "BBB"})
+	m = next.(Model)
+	if !strings.Contains(m.status, "BBB is back in the feed") {
+		t.Errorf("expected a confirmation, got %q", m.status)
+	}
+
+	m = press(t, m, "esc")
+	if m.state != stateBrowsing || m.snoozed != nil {
+		t.Errorf("expected esc to leave the snoozed view")
+	}
+}
@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is the palette every style in the feed is built from. Set
+// `tui.theme` in config.yaml to pick one; NO_COLOR in the environment
+// overrides it and keeps only bold and borders.
+type theme struct {
+	Accent    lipgloss.TerminalColor
+	Dim       lipgloss.TerminalColor
+	Error     lipgloss.TerminalColor
+	Border    lipgloss.TerminalColor
+	Warning   lipgloss.TerminalColor
+	Highlight lipgloss.TerminalColor
+	Success   lipgloss.TerminalColor
+	Priority  map[int]lipgloss.TerminalColor
+}
+
+var themes = map[string]theme{
+	"dark": {
+		Accent:    lipgloss.Color("#3b82f6"),
+		Dim:       lipgloss.Color("#6b7280"),
+		Error:     lipgloss.Color("#ef4444"),
+		Border:    lipgloss.Color("#374151"),
+		Warning:   lipgloss.Color("#f97316"),
+		Highlight: lipgloss.Color("#a78bfa"),
+		Success:   lipgloss.Color("#22c55e"),
+		Priority: map[int]lipgloss.TerminalColor{
+			5: lipgloss.Color("#ef4444"), // red
+			4: lipgloss.Color("#f97316"), // orange
+			3: lipgloss.Color("#eab308"), // yellow
+			2: lipgloss.Color("#3b82f6"), // blue
+			1: lipgloss.Color("#9ca3af"), // gray
+		},
+	},
+	"light": {
+		Accent:    lipgloss.Color("#1d4ed8"),
+		Dim:       lipgloss.Color("#57534e"),
+		Error:     lipgloss.Color("#b91c1c"),
+		Border:    lipgloss.Color("#d1d5db"),
+		Warning:   lipgloss.Color("#c2410c"),
+		Highlight: lipgloss.Color("#6d28d9"),
+		Success:   lipgloss.Color("#15803d"),
+		Priority: map[int]lipgloss.TerminalColor{
+			5: lipgloss.Color("#b91c1c"),
+			4: lipgloss.Color("#c2410c"),
+			3: lipgloss.Color("#a16207"),
+			2: lipgloss.Color("#1d4ed8"),
+			1: lipgloss.Color("#57534e"),
+		},
+	},
+	// high-contrast sticks to the 16 ANSI colors so it follows the
+	// terminal's own (often accessibility-tuned) palette.
+	"high-contrast": {
+		Accent:    lipgloss.Color("14"),
+		Dim:       lipgloss.Color("15"),
+		Error:     lipgloss.Color("9"),
+		Border:    lipgloss.Color("15"),
+		Warning:   lipgloss.Color("11"),
+		Highlight: lipgloss.Color("13"),
+		Success:   lipgloss.Color("10"),
+		Priority: map[int]lipgloss.TerminalColor{
+			5: lipgloss.Color("9"),
+			4: lipgloss.Color("11"),
+			3: lipgloss.Color("15"),
+			2: lipgloss.Color("14"),
+			1: lipgloss.Color("7"),
+		},
+	},
+}
+
+var noColorTheme = theme{
+	Accent:    lipgloss.NoColor{},
+	Dim:       lipgloss.NoColor{},
+	Error:     lipgloss.NoColor{},
+	Border:    lipgloss.NoColor{},
+	Warning:   lipgloss.NoColor{},
+	Highlight: lipgloss.NoColor{},
+	Success:   lipgloss.NoColor{},
+	Priority:  map[int]lipgloss.TerminalColor{},
+}
+
+// lookupTheme resolves a theme name from config, honouring NO_COLOR
+// (https://no-color.org) over any configured theme.
+func lookupTheme(name string) (theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return noColorTheme, nil
+	}
+	if name == "" {
+		name = "dark"
+	}
+	t, ok := themes[name]
+	if !ok {
+		return theme{}, fmt.Errorf("unknown tui theme %q (want dark, light or high-contrast)", name)
+	}
+	return t, nil
+}
+
+// Styles, rebuilt by applyTheme.
+var (
+	focusedBorder lipgloss.Style
+	normalBorder  lipgloss.Style
+	skippedBorder lipgloss.Style
+
+	priorityStyles map[int]lipgloss.Style
+
+	metaStyle   lipgloss.Style
+	footerStyle lipgloss.Style
+	errorStyle  lipgloss.Style
+
+	deadlineStyle lipgloss.Style
+
+	holdStyle lipgloss.Style
+
+	progressKeyStyle  lipgloss.Style
+	progressFillStyle lipgloss.Style
+
+	detailPanelStyle  lipgloss.Style
+	detailHeaderStyle lipgloss.Style
+
+	threadTitleStyle   lipgloss.Style
+	threadTargetStyle  lipgloss.Style
+	threadReplyStyle   lipgloss.Style
+	threadChannelStyle lipgloss.Style
+
+	groupHeaderStyle lipgloss.Style
+	filterStyle      lipgloss.Style
+	selectedStyle    lipgloss.Style
+
+	tagChipStyle lipgloss.Style
+	linkStyle    lipgloss.Style
+
+	helpStyles help.Styles
+)
+
+func init() {
+	applyTheme(themes["dark"])
+}
+
+func applyTheme(t theme) {
+	focusedBorder = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(0, 1)
+
+	normalBorder = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Padding(0, 1)
+
+	skippedBorder = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Padding(0, 1).
+		Foreground(t.Dim)
+
+	priorityStyles = map[int]lipgloss.Style{}
+	for p := 1; p <= 5; p++ {
+		c, ok := t.Priority[p]
+		if !ok {
+			c = lipgloss.NoColor{}
+		}
+		priorityStyles[p] = lipgloss.NewStyle().Bold(true).Foreground(c)
+	}
+
+	metaStyle = lipgloss.NewStyle().Foreground(t.Dim)
+	footerStyle = lipgloss.NewStyle().Foreground(t.Dim)
+	errorStyle = lipgloss.NewStyle().Foreground(t.Error)
+
+	deadlineStyle = lipgloss.NewStyle().Foreground(t.Warning)
+
+	holdStyle = lipgloss.NewStyle().Foreground(t.Highlight)
+
+	progressKeyStyle = lipgloss.NewStyle().Bold(true)
+	progressFillStyle = lipgloss.NewStyle().Foreground(t.Success)
+
+	detailPanelStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(1, 2)
+
+	detailHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent)
+
+	threadTitleStyle = lipgloss.NewStyle().Bold(true)
+	threadTargetStyle = lipgloss.NewStyle().Foreground(t.Accent)
+	threadReplyStyle = lipgloss.NewStyle().Foreground(t.Success)
+	threadChannelStyle = lipgloss.NewStyle().Foreground(t.Highlight)
+
+	groupHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Highlight)
+	filterStyle = lipgloss.NewStyle().Foreground(t.Accent)
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Highlight)
+
+	tagChipStyle = lipgloss.NewStyle().Background(t.Border).Foreground(t.Highlight).Padding(0, 1)
+	linkStyle = lipgloss.NewStyle().Underline(true).Foreground(t.Accent)
+
+	helpStyles = help.Styles{
+		Ellipsis:       lipgloss.NewStyle().Foreground(t.Dim),
+		ShortKey:       lipgloss.NewStyle().Foreground(t.Accent),
+		ShortDesc:      lipgloss.NewStyle().Foreground(t.Dim),
+		ShortSeparator: lipgloss.NewStyle().Foreground(t.Border),
+		FullKey:        lipgloss.NewStyle().Bold(true).Foreground(t.Accent),
+		FullDesc:       lipgloss.NewStyle(),
+		FullSeparator:  lipgloss.NewStyle().Foreground(t.Border),
+	}
+}
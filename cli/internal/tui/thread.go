@@ -0,0 +1,306 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/client"
+)
+
+// Thread mode shows the whole conversation a notification belongs to: every
+// notification in its session, each response and the channel it came from,
+// and any reactions, in a scrollable viewport. Replies sent from here go to
+// the notification the thread was opened from, or to the latest open question
+// once that one is answered.
+
+type threadLoadedMsg struct {
+	id     string
+	thread *feedThread
+	err    error
+}
+
+// openThread switches to thread mode for the focused notification and starts
+// loading its conversation.
+func (m Model) openThread() (Model, tea.Cmd) {
+	n := m.focusedItem()
+	if n == nil {
+		return m, nil
+	}
+	m.state = stateThread
+	m.threadID = n.ID
+	m.thread = nil
+	m.viewport = viewport.New(0, 0)
+	m.resizeThread()
+	m.status = "Loading thread..."
+	return m, fetchThreadCmd(m.client, n.ID)
+}
+
+func (m Model) closeThread() Model {
+	m.state = stateBrowsing
+	m.thread = nil
+	m.threadID = ""
+	m.status = ""
+	m.textarea.Blur()
+	m.textarea.Reset()
+	return m
+}
+
+// replyTarget is the notification a reply from thread mode answers: the one
+// the thread was opened from while it still waits on a response, otherwise
+// the newest one in the thread that does.
+func (m Model) replyTarget() *feedNotification {
+	if m.thread == nil {
+		return nil
+	}
+	var opened *feedNotification
+	for i := range m.thread.Notifications {
+		if m.thread.Notifications[i].ID == m.threadID {
+			opened = &m.thread.Notifications[i]
+		}
+	}
+	if opened != nil && isOpen(*opened) {
+		return opened
+	}
+	for i := len(m.thread.Notifications) - 1; i >= 0; i-- {
+		if isOpen(m.thread.Notifications[i]) {
+			return &m.thread.Notifications[i]
+		}
+	}
+	return opened
+}
+
+func isOpen(n feedNotification) bool {
+	return n.Status == "pending" || n.Status == "delivered"
+}
+
+// resizeThread fits the viewport between the thread header and whatever sits
+// below it, and re-renders the conversation at the new width.
+func (m *Model) resizeThread() {
+	height := m.height - 5 // header, blank line, status, footer
+	if m.state == stateThreadInput {
+		height -= m.textarea.Height() + 1
+	}
+	m.viewport.Width = max(m.width, 20)
+	m.viewport.Height = max(height, 3)
+	if m.thread != nil {
+		m.viewport.SetContent(m.renderThread(m.viewport.Width))
+	}
+}
+
+func (m Model) handleThreadKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.state == stateThreadInput {
+		if next, cmd, ok := m.handleComposeKey(msg); ok {
+			return next, cmd
+		}
+		switch msg.String() {
+		case "esc":
+			m.state = stateThread
+			m.status = ""
+			cmd := m.stashDraft()
+			m.resizeThread()
+			return m, cmd
+		case "ctrl+c":
+			cmd := m.stashDraft()
+			return m, tea.Sequence(cmd, tea.Quit)
+		case "enter":
+			text := strings.TrimSpace(m.textarea.Value())
+			if text == "" {
+				return m, nil
+			}
+			target := m.replyTarget()
+			if target == nil {
+				return m, nil
+			}
+			m.textarea.Reset()
+			m.textarea.Blur()
+			m.state = stateThread
+			m.status = fmt.Sprintf("Replying to %s...", target.ShortCode)
+			m.resizeThread()
+			clear := m.clearDraft(target.ID)
+			return m, tea.Batch(submitResponseCmd(m.client, target.ID, &text, nil), clear)
+		case "alt+enter":
+			m.textarea.InsertString("\n")
+			return m, nil
+		default:
+			var cmd tea.Cmd
+			m.textarea, cmd = m.textarea.Update(msg)
+			return m, cmd
+		}
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back, m.keys.Quit, m.keys.Thread):
+		return m.closeThread(), nil
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = true
+		return m, nil
+	case key.Matches(msg, m.keys.Reply):
+		target := m.replyTarget()
+		if target == nil {
+			return m, nil
+		}
+		m.state = stateThreadInput
+		m.textarea.SetWidth(max(m.width-4, 20))
+		cmd := m.openReply(target.ID)
+		m.status = m.replyHelp(target.ShortCode)
+		m.resizeThread()
+		m.viewport.GotoBottom()
+		return m, cmd
+	case key.Matches(msg, m.keys.Up):
+		m.viewport.ScrollUp(1)
+		return m, nil
+	case key.Matches(msg, m.keys.Down):
+		m.viewport.ScrollDown(1)
+		return m, nil
+	case key.Matches(msg, m.keys.PageUp):
+		m.viewport.PageUp()
+		return m, nil
+	case key.Matches(msg, m.keys.PageDown):
+		m.viewport.PageDown()
+		return m, nil
+	case key.Matches(msg, m.keys.Top):
+		m.viewport.GotoTop()
+		return m, nil
+	case key.Matches(msg, m.keys.Bottom):
+		m.viewport.GotoBottom()
+		return m, nil
+	}
+
+	if s := msg.String(); len(s) == 1 && s[0] >= '1' && s[0] <= '9' {
+		target := m.replyTarget()
+		idx := int(s[0]-'0') - 1
+		if target != nil && idx < len(target.Options) {
+			opt := target.Options[idx]
+			m.status = fmt.Sprintf("Answering %s with %q...", target.ShortCode, opt)
+			return m, submitResponseCmd(m.client, target.ID, nil, &opt)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewThread() string {
+	var b strings.Builder
+
+	title := threadTitleStyle.Render("Thread")
+	if m.thread != nil {
+		title += metaStyle.Render(" · " + m.thread.SessionKey)
+		if m.thread.Workspace != nil && *m.thread.Workspace != "" {
+			title += metaStyle.Render(" · " + *m.thread.Workspace)
+		}
+		title += metaStyle.Render(fmt.Sprintf(" (%d notifications)", len(m.thread.Notifications)))
+	}
+	b.WriteString(title + "\n\n")
+
+	if m.thread != nil {
+		b.WriteString(m.viewport.View() + "\n")
+	}
+
+	if m.status != "" {
+		b.WriteString(metaStyle.Render(m.status) + "\n")
+	} else {
+		b.WriteString("\n")
+	}
+	if m.state == stateThreadInput {
+		b.WriteString(m.textarea.View() + "\n")
+	}
+	b.WriteString(m.footer(threadKeys{m.keys}))
+	return b.String()
+}
+
+// renderThread lays out the conversation oldest first. The notification the
+// thread was opened from is marked, and the one replies will go to is
+// labelled.
+func (m Model) renderThread(width int) string {
+	if m.thread == nil {
+		return ""
+	}
+	target := m.replyTarget()
+	textWidth := max(width-6, 10)
+
+	var blocks []string
+	for _, n := range m.thread.Notifications {
+		var lines []string
+
+		pStyle, ok := priorityStyles[n.Priority]
+		if !ok {
+			pStyle = priorityStyles[3]
+		}
+		marker := "  "
+		if n.ID == m.threadID {
+			marker = threadTargetStyle.Render("▶ ")
+		}
+		head := marker + pStyle.Render(fmt.Sprintf("P%d", n.Priority)) + " " +
+			threadTitleStyle.Render(n.ShortCode) +
+			metaStyle.Render(fmt.Sprintf(" · %s · %s", threadTime(n.CreatedAt), n.Status))
+		if target != nil && n.ID == target.ID {
+			head += " " + threadTargetStyle.Render("← replies go here")
+		}
+		lines = append(lines, head)
+		lines = append(lines, indent(wrapText(n.Message, textWidth), "    "))
+
+		if len(n.Options) > 0 {
+			var opts []string
+			for i, o := range n.Options {
+				opts = append(opts, fmt.Sprintf("[%d] %s", i+1, o))
+			}
+			lines = append(lines, metaStyle.Render("    "+strings.Join(opts, "  ")))
+		}
+
+		for _, r := range n.Responses {
+			who := "you"
+			if r.Responder != nil && *r.Responder != "" {
+				who = *r.Responder
+			}
+			meta := threadReplyStyle.Render("    ↳ "+who) +
+				metaStyle.Render(" via ") + threadChannelStyle.Render(r.Channel) +
+				metaStyle.Render(" · "+threadTime(r.CreatedAt))
+			lines = append(lines, meta)
+			if r.SelectedOption != nil && *r.SelectedOption != "" {
+				lines = append(lines, "      → "+*r.SelectedOption)
+			}
+			if r.Text != nil && *r.Text != "" {
+				lines = append(lines, indent(wrapText(*r.Text, textWidth-2), "      "))
+			}
+			if len(r.Reactions) > 0 {
+				var emoji []string
+				for _, re := range r.Reactions {
+					emoji = append(emoji, ":"+re.Emoji+":")
+				}
+				lines = append(lines, metaStyle.Render("      "+strings.Join(emoji, " ")))
+			}
+		}
+		if len(n.Responses) == 0 {
+			lines = append(lines, metaStyle.Render("    no responses yet"))
+		}
+
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// threadTime formats a server timestamp for the thread view, falling back
+// to the raw value if it doesn't parse.
+func threadTime(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format("Jan 2 15:04")
+}
+
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
+
+func fetchThreadCmd(c *client.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		thread, err := fetchNotificationThread(c, id)
+		return threadLoadedMsg{id: id, thread: thread, err: err}
+	}
+}
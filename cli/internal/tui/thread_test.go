@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sestinj/agentduty/cli/internal/config"
+)
+
+func strPtr(s string) *string { return &s }
+
+func testThread() *feedThread {
+	return &feedThread{
+		SessionKey: "sess-1",
+		Workspace:  strPtr("/repo"),
+		Notifications: []feedNotification{
+			{
+				ID: "n1", ShortCode: "AAA", Message: "Which database?", Priority: 3,
+				Options: []string{"Postgres", "SQLite"}, Status: "responded",
+				CreatedAt: "2025-01-01T10:00:00.000Z",
+				Responses: []feedResponse{{
+					SelectedOption: strPtr("Postgres"),
+					Channel:        "slack",
+					Responder:      strPtr("Ada"),
+					CreatedAt:      "2025-01-01T10:05:00.000Z",
+					Reactions:      []feedReaction{{Emoji: "white_check_mark"}},
+				}},
+			},
+			{
+				ID: "n2", ShortCode: "BBB", Message: "Run the migration now?", Priority: 4,
+				Status: "pending", CreatedAt: "2025-01-01T11:00:00.000Z",
+			},
+		},
+	}
+}
+
+func threadModel(t *testing.T) Model {
+	m := newTestModel(t, config.FeedFilter{})
+	m.width = 100
+	m.height = 40
+	m.items = []feedNotification{{ID: "n2", ShortCode: "BBB", Message: "Run the migration now?"}}
+	return m
+}
+
+func TestRenderThread_ShowsWholeConversation(t *testing.T) {
+	m := threadModel(t)
+	m.thread = testThread()
+	m.threadID = "n2"
+
+	out := m.renderThread(80)
+	for _, want := range []string{
+		"AAA", "Which database?", "Ada", "via", "slack", "→ Postgres",
+		":white_check_mark:", "BBB", "Run the migration now?", "no responses yet",
+		"replies go here",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected thread to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "AAA") > strings.Index(out, "BBB") {
+		t.Error("expected notifications oldest first")
+	}
+}
+
+func TestReplyTarget(t *testing.T) {
+	m := threadModel(t)
+	m.thread = testThread()
+	m.threadID = "n1"
+
+	if got := m.replyTarget(); got == nil || got.ID != "n2" {
+		t.Errorf("expected replies to go to the open question n2, got %+v", got)
+	}
+
+	m.thread.Notifications[0].Status = "delivered"
+	if got := m.replyTarget(); got == nil || got.ID != "n1" {
+		t.Errorf("expected replies to go to the opened notification while it is open, got %+v", got)
+	}
+
+	m.thread.Notifications[0].Status = "responded"
+	m.thread.Notifications[1].Status = "responded"
+	if got := m.replyTarget(); got == nil || got.ID != "n1" {
+		t.Errorf("expected replies to fall back to the opened notification, got %+v", got)
+	}
+}
+
+func TestThreadMode_OpenLoadAndClose(t *testing.T) {
+	m := threadModel(t)
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = next.(Model)
+	if m.state != stateThread || m.threadID != "n2" || cmd == nil {
+		t.Fatalf("expected t to open the thread for n2, got state=%v id=%q", m.state, m.threadID)
+	}
+
+	next, _ = m.Update(threadLoadedMsg{id: "n2", thread: testThread()})
+	m = next.(Model)
+	view := m.View()
+	if !strings.Contains(view, "sess-1") || !strings.Contains(view, "Which database?") {
+		t.Errorf("expected thread view, got:\n%s", view)
+	}
+
+	// A late load for a thread we've left is ignored.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.state != stateBrowsing || m.thread != nil {
+		t.Fatalf("expected esc to return to the feed, got state=%v", m.state)
+	}
+	next, _ = m.Update(threadLoadedMsg{id: "n2", thread: testThread()})
+	if next.(Model).thread != nil {
+		t.Error("expected stale thread load to be ignored")
+	}
+}
+
+func TestThreadMode_ReplyContinuesThread(t *testing.T) {
+	m := threadModel(t)
+	m.state = stateThread
+	m.threadID = "n1"
+	m.thread = testThread()
+	m.resizeThread()
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(Model)
+	if m.state != stateThreadInput {
+		t.Fatalf("expected r to open the reply box, got state=%v", m.state)
+	}
+	if !strings.Contains(m.status, "BBB") {
+		t.Errorf("expected status to name the reply target, got %q", m.status)
+	}
+
+	m.textarea.SetValue("yes, go ahead")
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.state != stateThread || cmd == nil {
+		t.Errorf("expected enter to send the reply and stay in the thread, got state=%v", m.state)
+	}
+}
@@ -0,0 +1,124 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Caller invokes a method on the D-Bus session bus, passing arguments in
+// GVariant text form.
+type Caller interface {
+	Call(ctx context.Context, dest, path, method string, args ...string) (string, error)
+}
+
+// GDBus is a Caller that runs the gdbus tool shipped with GLib, so agentduty
+// needs no D-Bus library of its own.
+type GDBus struct{}
+
+func (GDBus) Call(ctx context.Context, dest, path, method string, args ...string) (string, error) {
+	argv := append([]string{"call", "--session", "--dest", dest, "--object-path", path, "--method", method}, args...)
+	out, err := exec.CommandContext(ctx, "gdbus", argv...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("gdbus: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("gdbus: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Desktop raises notifications through the freedesktop.org notification
+// service (org.freedesktop.Notifications), which GNOME, KDE, dunst, mako and
+// most other Linux desktops provide.
+type Desktop struct {
+	Bus Caller
+	// Expire is how long a notification stays up; 0 leaves it to the
+	// notification server.
+	Expire time.Duration
+}
+
+const (
+	notificationsDest   = "org.freedesktop.Notifications"
+	notificationsPath   = "/org/freedesktop/Notifications"
+	notificationsNotify = "org.freedesktop.Notifications.Notify"
+)
+
+// maxBodyLen keeps long agent messages from filling the screen.
+const maxBodyLen = 300
+
+func (d Desktop) Alert(ctx context.Context, it Item) error {
+	body := truncate(it.Message, maxBodyLen)
+	if len(it.Options) > 0 {
+		body += "\n" + strings.Join(it.Options, " / ")
+	}
+	expire := int32(-1)
+	if d.Expire > 0 {
+		expire = int32(d.Expire / time.Millisecond)
+	}
+
+	// Notify(app_name, replaces_id, app_icon, summary, body, actions, hints, expire_timeout)
+	_, err := d.Bus.Call(ctx, notificationsDest, notificationsPath, notificationsNotify,
+		gvariantString("agentduty"),
+		"uint32 0",
+		gvariantString("dialog-information"),
+		gvariantString(it.Title()),
+		gvariantString(body),
+		"@as []",
+		fmt.Sprintf("{'urgency': <byte %d>}", urgency(it.Priority)),
+		fmt.Sprintf("int32 %d", expire),
+	)
+	if err != nil {
+		return fmt.Errorf("desktop notification for %s: %w", it.ShortCode, err)
+	}
+	return nil
+}
+
+// urgency maps a priority onto the spec's low (0), normal (1) and critical
+// (2) levels. Critical notifications stay up until dismissed.
+func urgency(priority int) int {
+	switch {
+	case priority >= 5:
+		return 2
+	case priority >= 3:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// gvariantString quotes s as a GVariant string literal.
+func gvariantString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsControl(r):
+			// dropped
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,55 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Terminal alerts through the terminal itself: a bell, and optionally an
+// OSC escape sequence that many terminals turn into a system notification.
+// OSC 9 is understood by iTerm2, Windows Terminal, WezTerm and kitty; OSC
+// 777 by foot, urxvt and VTE-based terminals such as GNOME Terminal.
+type Terminal struct {
+	W    io.Writer
+	Bell bool
+	OSC  string // "9", "777" or "" for none
+}
+
+func (t Terminal) Alert(ctx context.Context, it Item) error {
+	var seq string
+	switch t.OSC {
+	case "9":
+		seq = "\033]9;" + oscText(it.Title()+": "+truncate(it.Message, maxBodyLen)) + "\a"
+	case "777":
+		// Fields are separated by ';', so the title must not contain one.
+		title := strings.ReplaceAll(oscText(it.Title()), ";", ",")
+		seq = "\033]777;notify;" + title + ";" + oscText(truncate(it.Message, maxBodyLen)) + "\a"
+	}
+	if t.Bell {
+		seq += "\a"
+	}
+	if seq == "" {
+		return nil
+	}
+	if _, err := io.WriteString(t.W, seq); err != nil {
+		return fmt.Errorf("terminal alert for %s: %w", it.ShortCode, err)
+	}
+	return nil
+}
+
+// oscText flattens s onto one line and strips control characters, which
+// could otherwise end the escape sequence early or inject another one.
+func oscText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,123 @@
+// Package watch raises local alerts (desktop notifications, a terminal bell,
+// OSC escape sequences) for notifications that newly appear in the feed.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+)
+
+// Item is one pending notification from the feed.
+type Item struct {
+	ID        string   `json:"id"`
+	ShortCode string   `json:"shortCode"`
+	Message   string   `json:"message"`
+	Priority  int      `json:"priority"`
+	Options   []string `json:"options"`
+	Workspace *string  `json:"workspace"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+// Title is the one-line summary alerts lead with, e.g. "P4 ABC · api".
+func (it Item) Title() string {
+	title := fmt.Sprintf("P%d %s", it.Priority, it.ShortCode)
+	if it.Workspace != nil && *it.Workspace != "" {
+		title += " · " + *it.Workspace
+	}
+	return title
+}
+
+// Alerter delivers an alert for one item.
+type Alerter interface {
+	Alert(ctx context.Context, it Item) error
+}
+
+// QuietHours is a daily local window, in "15:04" form, during which only
+// priorities at or above the breakthrough raise alerts. A window whose end
+// is before its start runs past midnight.
+type QuietHours struct {
+	Start string
+	End   string
+}
+
+// Contains reports whether t falls inside the window.
+func (q QuietHours) Contains(t time.Time) bool {
+	now := t.Format("15:04")
+	if q.Start < q.End {
+		return now >= q.Start && now < q.End
+	}
+	return now >= q.Start || now < q.End
+}
+
+// Policy decides which new items are worth an alert.
+type Policy struct {
+	MinPriority  int
+	Quiet        *QuietHours
+	Breakthrough int  // lowest priority alerting during Quiet (default 5)
+	IgnoreHold   bool // alert even while the server holds pushes
+}
+
+// Allows reports whether it should alert at now, given the server's current
+// hold (do-not-disturb or quiet hours), if any.
+func (p Policy) Allows(it Item, hold *output.DoNotDisturb, now time.Time) bool {
+	if it.Priority < p.MinPriority {
+		return false
+	}
+	if p.Quiet != nil && p.Quiet.Contains(now) {
+		through := p.Breakthrough
+		if through == 0 {
+			through = 5
+		}
+		if it.Priority < through {
+			return false
+		}
+	}
+	if hold != nil && !p.IgnoreHold && now.Before(hold.Until) && it.Priority < hold.BreakthroughPriority {
+		return false
+	}
+	return true
+}
+
+// Watcher remembers which items it has seen and alerts for new ones.
+//
+// The first Observe only records what is already pending, unless
+// AlertExisting is set. Items held back by the policy count as seen, so the
+// end of quiet hours doesn't bring a burst of stale alerts. An item that
+// leaves the feed and comes back (a snooze ending) alerts again.
+type Watcher struct {
+	Alerters      []Alerter
+	Policy        Policy
+	AlertExisting bool
+
+	seen map[string]bool
+}
+
+// Observe takes the current feed and alerts for items not seen before. It
+// returns the items it alerted for; err joins every alerter failure.
+func (w *Watcher) Observe(ctx context.Context, items []Item, hold *output.DoNotDisturb, now time.Time) ([]Item, error) {
+	first := w.seen == nil
+	next := make(map[string]bool, len(items))
+	var alerted []Item
+	var errs []error
+	for _, it := range items {
+		next[it.ID] = true
+		if w.seen[it.ID] || (first && !w.AlertExisting) {
+			continue
+		}
+		if !w.Policy.Allows(it, hold, now) {
+			continue
+		}
+		for _, a := range w.Alerters {
+			if err := a.Alert(ctx, it); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		alerted = append(alerted, it)
+	}
+	w.seen = next
+	return alerted, errors.Join(errs...)
+}
@@ -0,0 +1,164 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sestinj/agentduty/cli/internal/output"
+)
+
+// fakeBus records D-Bus calls instead of making them.
+type fakeBus struct {
+	calls [][]string
+	err   error
+}
+
+func (b *fakeBus) Call(ctx context.Context, dest, path, method string, args ...string) (string, error) {
+	b.calls = append(b.calls, append([]string{dest, path, method}, args...))
+	return "(uint32 7,)", b.err
+}
+
+type recorder struct{ codes []string }
+
+func (r *recorder) Alert(ctx context.Context, it Item) error {
+	r.codes = append(r.codes, it.ShortCode)
+	return nil
+}
+
+func item(id string, priority int) Item {
+	return Item{ID: id, ShortCode: strings.ToUpper(id), Message: "msg " + id, Priority: priority}
+}
+
+func at(clock string) time.Time {
+	t, _ := time.ParseInLocation("2006-01-02 15:04", "2026-03-02 "+clock, time.Local)
+	return t
+}
+
+func TestWatcher_AlertsOnlyNewItems(t *testing.T) {
+	rec := &recorder{}
+	w := &Watcher{Alerters: []Alerter{rec}}
+	ctx := context.Background()
+
+	if got, _ := w.Observe(ctx, []Item{item("a", 3)}, nil, at("12:00")); len(got) != 0 {
+		t.Fatalf("expected the first poll to only seed, got %v", got)
+	}
+	w.Observe(ctx, []Item{item("a", 3), item("b", 3)}, nil, at("12:00"))
+	w.Observe(ctx, []Item{item("b", 3)}, nil, at("12:00"))
+	// a was snoozed and is back.
+	w.Observe(ctx, []Item{item("a", 3), item("b", 3)}, nil, at("12:00"))
+
+	if got := strings.Join(rec.codes, ","); got != "B,A" {
+		t.Errorf("alerted %s, want B,A", got)
+	}
+}
+
+func TestWatcher_AlertExisting(t *testing.T) {
+	rec := &recorder{}
+	w := &Watcher{Alerters: []Alerter{rec}, AlertExisting: true}
+	w.Observe(context.Background(), []Item{item("a", 3)}, nil, at("12:00"))
+	if len(rec.codes) != 1 {
+		t.Errorf("expected an alert for the existing item, got %v", rec.codes)
+	}
+}
+
+func TestWatcher_ErrorsDontStopOthers(t *testing.T) {
+	rec := &recorder{}
+	bus := &fakeBus{err: errors.New("no notification daemon")}
+	w := &Watcher{Alerters: []Alerter{Desktop{Bus: bus}, rec}}
+	w.Observe(context.Background(), nil, nil, at("12:00"))
+
+	got, err := w.Observe(context.Background(), []Item{item("a", 3)}, nil, at("12:00"))
+	if err == nil || !strings.Contains(err.Error(), "no notification daemon") {
+		t.Errorf("expected the desktop error, got %v", err)
+	}
+	if len(got) != 1 || len(rec.codes) != 1 {
+		t.Errorf("expected the other alerter to still run, got %v", rec.codes)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	hold := &output.DoNotDisturb{Reason: "dnd", Until: at("13:00"), BreakthroughPriority: 5}
+	quiet := &QuietHours{Start: "22:00", End: "07:00"}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		item   Item
+		hold   *output.DoNotDisturb
+		now    time.Time
+		want   bool
+	}{
+		{"below minimum", Policy{MinPriority: 3}, item("a", 2), nil, at("12:00"), false},
+		{"at minimum", Policy{MinPriority: 3}, item("a", 3), nil, at("12:00"), true},
+		{"quiet hours before midnight", Policy{Quiet: quiet}, item("a", 4), nil, at("23:30"), false},
+		{"quiet hours after midnight", Policy{Quiet: quiet}, item("a", 4), nil, at("06:59"), false},
+		{"quiet hours over", Policy{Quiet: quiet}, item("a", 4), nil, at("07:00"), true},
+		{"quiet hours breakthrough", Policy{Quiet: quiet}, item("a", 5), nil, at("23:30"), true},
+		{"custom breakthrough", Policy{Quiet: quiet, Breakthrough: 4}, item("a", 4), nil, at("23:30"), true},
+		{"server hold", Policy{}, item("a", 4), hold, at("12:00"), false},
+		{"server hold breakthrough", Policy{}, item("a", 5), hold, at("12:00"), true},
+		{"server hold expired", Policy{}, item("a", 4), hold, at("13:30"), true},
+		{"server hold ignored", Policy{IgnoreHold: true}, item("a", 4), hold, at("12:00"), true},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Allows(tt.item, tt.hold, tt.now); got != tt.want {
+			t.Errorf("%s: Allows = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDesktop_Notify(t *testing.T) {
+	bus := &fakeBus{}
+	ws := "api"
+	it := Item{ID: "n1", ShortCode: "ABC", Priority: 5, Workspace: &ws, Message: `Ship "v2"?`, Options: []string{"yes", "no"}}
+	if err := (Desktop{Bus: bus, Expire: 10 * time.Second}).Alert(context.Background(), it); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.calls) != 1 {
+		t.Fatalf("expected one call, got %d", len(bus.calls))
+	}
+	want := []string{
+		"org.freedesktop.Notifications",
+		"/org/freedesktop/Notifications",
+		"org.freedesktop.Notifications.Notify",
+		`"agentduty"`,
+		"uint32 0",
+		`"dialog-information"`,
+		`"P5 ABC · api"`,
+		`"Ship \"v2\"?\nyes / no"`,
+		"@as []",
+		"{'urgency': <byte 2>}",
+		"int32 10000",
+	}
+	if got := strings.Join(bus.calls[0], "|"); got != strings.Join(want, "|") {
+		t.Errorf("call =\n%s\nwant\n%s", got, strings.Join(want, "|"))
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	it := Item{ShortCode: "ABC", Priority: 4, Message: "line one\nline two\033]0;pwned\a"}
+
+	tests := []struct {
+		term Terminal
+		want string
+	}{
+		{Terminal{Bell: true}, "\a"},
+		{Terminal{OSC: "9"}, "\033]9;P4 ABC: line one line two]0;pwned\a"},
+		{Terminal{OSC: "777", Bell: true}, "\033]777;notify;P4 ABC;line one line two]0;pwned\a\a"},
+		{Terminal{}, ""},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		tt.term.W = &buf
+		if err := tt.term.Alert(context.Background(), it); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%+v wrote %q, want %q", tt.term, buf.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package webhook verifies the signatures AgentDuty puts on outbound webhook
+// requests. Services receiving AgentDuty events can import it directly:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//		event, err := webhook.ParseRequest(r, secret, webhook.DefaultTolerance)
+//		if err != nil {
+//			http.Error(w, err.Error(), http.StatusUnauthorized)
+//			return
+//		}
+//		// event.Event is e.g. "response.created"; event.Data holds the payload.
+//	}
+//
+// The AgentDuty-Signature header has the form "t=<unix seconds>,v1=<hex>",
+// where v1 is HMAC-SHA256 over "<t>.<body>" keyed with the subscription's
+// secret. A header may carry several v1 values while a secret is rotated.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "AgentDuty-Signature"
+	EventHeader     = "AgentDuty-Event"
+	DeliveryHeader  = "AgentDuty-Delivery"
+
+	// DefaultTolerance is how old a signed request may be before it is
+	// treated as a replay.
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook: missing signature header")
+	ErrInvalidHeader    = errors.New("webhook: malformed signature header")
+	ErrNoMatch          = errors.New("webhook: signature does not match")
+	ErrTooOld           = errors.New("webhook: timestamp outside tolerance")
+)
+
+// Event is the JSON body of a webhook request.
+type Event struct {
+	ID     string          `json:"id"`
+	Event  string          `json:"event"`
+	SentAt time.Time       `json:"sentAt"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Sign returns the signature header value for body at time t.
+func Sign(secret string, body []byte, t time.Time) string {
+	ts := t.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", ts, hex.EncodeToString(mac(secret, ts, body)))
+}
+
+func mac(secret string, ts int64, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(h, "%d.", ts)
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Verify checks a signature header against body. A tolerance of zero skips
+// the timestamp check.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	return verifyAt(secret, header, body, tolerance, time.Now())
+}
+
+func verifyAt(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	var ts int64
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidHeader
+		}
+		switch key {
+		case "t":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidHeader
+			}
+			ts = n
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				return ErrInvalidHeader
+			}
+			sigs = append(sigs, sig)
+		}
+	}
+	if ts == 0 || len(sigs) == 0 {
+		return ErrInvalidHeader
+	}
+
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(ts, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrTooOld
+		}
+	}
+
+	expected := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrNoMatch
+}
+
+// VerifyRequest verifies r's signature and returns its body. r.Body is
+// replaced so handlers can read it again.
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: read body: %w", err)
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := Verify(secret, r.Header.Get(SignatureHeader), body, tolerance); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// ParseRequest verifies r and decodes its event.
+func ParseRequest(r *http.Request, secret string, tolerance time.Duration) (*Event, error) {
+	body, err := VerifyRequest(r, secret, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("webhook: decode event: %w", err)
+	}
+	return &event, nil
+}
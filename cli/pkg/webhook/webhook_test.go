@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const secret = "whsec_0123456789abcdef"
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("secret", `1700000000.{"event":"ping"}`), as the server signs it.
+	got := Sign("secret", []byte(`{"event":"ping"}`), time.Unix(1700000000, 0))
+	want := "t=1700000000,v1=4d39bd2442f073b6bc62e95d0297ce25475582a17389ab860abdc778fe1d9f77"
+	if got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"response.created"}`)
+	header := Sign(secret, body, now)
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   string
+		now    time.Time
+		want   error
+	}{
+		{name: "valid", secret: secret, header: header, body: string(body), now: now},
+		{name: "tampered body", secret: secret, header: header, body: `{"event":"forged"}`, now: now, want: ErrNoMatch},
+		{name: "wrong secret", secret: "other", header: header, body: string(body), now: now, want: ErrNoMatch},
+		{name: "replayed", secret: secret, header: header, body: string(body), now: now.Add(10 * time.Minute), want: ErrTooOld},
+		{name: "missing", secret: secret, header: "", body: string(body), now: now, want: ErrMissingSignature},
+		{name: "garbage", secret: secret, header: "sha256=abc", body: string(body), now: now, want: ErrInvalidHeader},
+		{name: "no v1", secret: secret, header: "t=1700000000", body: string(body), now: now, want: ErrInvalidHeader},
+		{
+			name:   "rotated secret",
+			secret: secret,
+			header: Sign("old-secret", body, now) + ",v1=" + strings.SplitN(header, "v1=", 2)[1],
+			body:   string(body),
+			now:    now,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyAt(tt.secret, tt.header, []byte(tt.body), DefaultTolerance, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("verifyAt() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequest_LocalReceiver(t *testing.T) {
+	received := make(chan *Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := ParseRequest(r, secret, DefaultTolerance)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	post := func(body, signature string) int {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		req.Header.Set(SignatureHeader, signature)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	body := `{"id":"evt-1","event":"notification.created","sentAt":"2025-01-01T00:00:00Z","data":{"shortCode":"ABC"}}`
+	if code := post(body, Sign(secret, []byte(body), time.Now())); code != http.StatusNoContent {
+		t.Fatalf("signed request: status %d, want 204", code)
+	}
+	event := <-received
+	if event.ID != "evt-1" || event.Event != "notification.created" || string(event.Data) != `{"shortCode":"ABC"}` {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if code := post(body, Sign("wrong", []byte(body), time.Now())); code != http.StatusUnauthorized {
+		t.Errorf("forged request: status %d, want 401", code)
+	}
+}
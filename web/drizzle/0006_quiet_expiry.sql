@@ -0,0 +1,3 @@
+ALTER TABLE "notifications" ADD COLUMN "expires_at" timestamp;--> statement-breakpoint
+ALTER TABLE "notifications" ADD COLUMN "default_option" text;--> statement-breakpoint
+ALTER TABLE "responses" ADD COLUMN "auto" boolean DEFAULT false NOT NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE "notifications" ADD COLUMN "parent_id" uuid;--> statement-breakpoint
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_parent_id_notifications_id_fk" FOREIGN KEY ("parent_id") REFERENCES "public"."notifications"("id") ON DELETE no action ON UPDATE no action;
\ No newline at end of file
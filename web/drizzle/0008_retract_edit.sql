@@ -0,0 +1,3 @@
+ALTER TYPE "public"."notification_status" ADD VALUE 'retracted';--> statement-breakpoint
+ALTER TABLE "notifications" ADD COLUMN "retract_reason" text;--> statement-breakpoint
+ALTER TABLE "notifications" ADD COLUMN "edited_at" timestamp;
\ No newline at end of file
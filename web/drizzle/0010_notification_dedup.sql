@@ -0,0 +1,3 @@
+ALTER TABLE "notifications" ADD COLUMN "dedup_key" text;--> statement-breakpoint
+ALTER TABLE "notifications" ADD COLUMN "repeat_count" integer DEFAULT 1 NOT NULL;--> statement-breakpoint
+ALTER TABLE "notifications" ADD COLUMN "last_repeated_at" timestamp;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "preferences" jsonb;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "dnd_until" timestamp;
\ No newline at end of file
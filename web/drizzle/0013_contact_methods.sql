@@ -0,0 +1,15 @@
+ALTER TYPE "public"."channel" ADD VALUE 'email';--> statement-breakpoint
+ALTER TYPE "public"."channel" ADD VALUE 'webhook';--> statement-breakpoint
+CREATE TABLE "contact_methods" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"user_id" uuid NOT NULL,
+	"channel" "channel" NOT NULL,
+	"address" text NOT NULL,
+	"secret" text,
+	"verification_code_hash" text,
+	"verification_expires_at" timestamp,
+	"verified_at" timestamp,
+	"created_at" timestamp DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "contact_methods" ADD CONSTRAINT "contact_methods_user_id_users_id_fk" FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE no action ON UPDATE no action;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TYPE "public"."channel" ADD VALUE 'discord';--> statement-breakpoint
+ALTER TYPE "public"."channel" ADD VALUE 'teams';--> statement-breakpoint
+ALTER TABLE "agent_sessions" ADD COLUMN "surface" text;--> statement-breakpoint
+ALTER TABLE "agent_sessions" ADD COLUMN "discord_message_id" text;--> statement-breakpoint
+ALTER TABLE "agent_sessions" ADD COLUMN "teams_activity_id" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "discord_user_id" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "discord_dm_channel_id" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "discord_link_code" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "discord_link_code_expires_at" timestamp;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "teams_user_id" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "teams_conversation" jsonb;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "teams_link_code" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "teams_link_code_expires_at" timestamp;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "agent_sessions" ADD COLUMN "poll_heartbeat_at" timestamp;
\ No newline at end of file
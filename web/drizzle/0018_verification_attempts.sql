@@ -0,0 +1 @@
+ALTER TABLE "contact_methods" ADD COLUMN "verification_attempts" integer DEFAULT 0 NOT NULL;
@@ -0,0 +1,2 @@
+DELETE FROM "session_progress" a USING "session_progress" b WHERE a."session_id" = b."session_id" AND a."key" = b."key" AND (a."updated_at", a."id") < (b."updated_at", b."id");--> statement-breakpoint
+ALTER TABLE "session_progress" ADD CONSTRAINT "session_progress_session_id_key_unique" UNIQUE("session_id","key");
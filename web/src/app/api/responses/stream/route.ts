@@ -0,0 +1,75 @@
+import { authenticateRequest } from "@/auth/api-keys";
+import { isResponseId, listResponseEvents } from "@/channels/response-feed";
+
+// Serverless functions are time-boxed; clients reconnect with Last-Event-ID.
+export const maxDuration = 300;
+
+const POLL_INTERVAL_MS = 2_000;
+const HEARTBEAT_MS = 15_000;
+const STREAM_LIFETIME_MS = 280_000;
+
+/**
+ * Server-sent events for new responses, used by `agentduty listen`.
+ * Each event's id is the response's id, so a reconnecting client resumes
+ * right after the last response it saw. `?after=<timestamp>` picks the
+ * starting point for a new client; `?session=<key>` narrows to one session.
+ */
+export async function GET(request: Request) {
+  const auth = await authenticateRequest(request);
+  if (!auth) return new Response("Unauthorized", { status: 401 });
+
+  const url = new URL(request.url);
+  const sessionKey = url.searchParams.get("session") ?? undefined;
+  let afterId = request.headers.get("last-event-id") || undefined;
+  if (afterId && !isResponseId(afterId)) {
+    return new Response(`Invalid Last-Event-ID: ${afterId}`, { status: 400 });
+  }
+  const since = url.searchParams.get("after");
+  const after = since ? new Date(since) : new Date();
+  if (isNaN(after.getTime())) {
+    return new Response(`Invalid timestamp: ${since}`, { status: 400 });
+  }
+
+  const encoder = new TextEncoder();
+  const stream = new ReadableStream({
+    async start(controller) {
+      const started = Date.now();
+      let lastWrite = started;
+      const write = (chunk: string) => {
+        controller.enqueue(encoder.encode(chunk));
+        lastWrite = Date.now();
+      };
+
+      write(`retry: ${POLL_INTERVAL_MS}\n\n`);
+      try {
+        while (!request.signal.aborted && Date.now() - started < STREAM_LIFETIME_MS) {
+          const events = await listResponseEvents(auth.userId, {
+            after,
+            afterId,
+            sessionKey,
+          });
+          for (const event of events) {
+            const createdAt = event.createdAt.toISOString();
+            write(
+              `id: ${event.id}\nevent: response\ndata: ${JSON.stringify({ ...event, createdAt })}\n\n`
+            );
+            afterId = event.id;
+          }
+          if (Date.now() - lastWrite >= HEARTBEAT_MS) write(": ping\n\n");
+          await new Promise((r) => setTimeout(r, POLL_INTERVAL_MS));
+        }
+      } catch (err) {
+        console.error("Response stream failed:", err);
+      }
+      controller.close();
+    },
+  });
+
+  return new Response(stream, {
+    headers: {
+      "Content-Type": "text/event-stream",
+      "Cache-Control": "no-cache, no-transform",
+      Connection: "keep-alive",
+    },
+  });
+}
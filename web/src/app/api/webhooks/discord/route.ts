@@ -0,0 +1,44 @@
+import crypto from "crypto";
+import { NextRequest } from "next/server";
+import { handleDiscordInteraction } from "@/webhooks/discord";
+
+// DER prefix that turns a raw 32-byte Ed25519 key into an SPKI public key.
+const ED25519_SPKI_PREFIX = Buffer.from("302a300506032b6570032100", "hex");
+
+function verifyDiscordSignature(
+  body: string,
+  timestamp: string | null,
+  signature: string | null
+): boolean {
+  const publicKey = process.env.DISCORD_PUBLIC_KEY;
+  if (!publicKey || !timestamp || !signature) return false;
+
+  try {
+    const key = crypto.createPublicKey({
+      key: Buffer.concat([ED25519_SPKI_PREFIX, Buffer.from(publicKey, "hex")]),
+      format: "der",
+      type: "spki",
+    });
+    return crypto.verify(
+      null,
+      Buffer.from(timestamp + body),
+      key,
+      Buffer.from(signature, "hex")
+    );
+  } catch {
+    return false;
+  }
+}
+
+export async function POST(request: NextRequest) {
+  const body = await request.text();
+  const timestamp = request.headers.get("x-signature-timestamp");
+  const signature = request.headers.get("x-signature-ed25519");
+
+  if (!verifyDiscordSignature(body, timestamp, signature)) {
+    return new Response("Invalid signature", { status: 401 });
+  }
+
+  const response = await handleDiscordInteraction(JSON.parse(body));
+  return Response.json(response);
+}
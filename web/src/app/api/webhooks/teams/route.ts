@@ -0,0 +1,52 @@
+import { NextRequest } from "next/server";
+import { createRemoteJWKSet, jwtVerify } from "jose";
+import {
+  handleTeamsActivity,
+  sameServiceUrl,
+  type TeamsActivity,
+} from "@/webhooks/teams";
+
+const BOT_FRAMEWORK_JWKS = createRemoteJWKSet(
+  new URL("https://login.botframework.com/v1/.well-known/keys")
+);
+
+/**
+ * Verify the Bot Framework token and return its serviceUrl claim, or null
+ * when the token is missing or invalid.
+ */
+async function verifyBotFrameworkToken(
+  authorization: string | null
+): Promise<string | null> {
+  const appId = process.env.MICROSOFT_APP_ID;
+  const token = authorization?.match(/^Bearer (.+)$/)?.[1];
+  if (!appId || !token) return null;
+
+  try {
+    const { payload } = await jwtVerify(token, BOT_FRAMEWORK_JWKS, {
+      issuer: "https://api.botframework.com",
+      audience: appId,
+    });
+    return typeof payload.serviceUrl === "string" ? payload.serviceUrl : null;
+  } catch {
+    return null;
+  }
+}
+
+export async function POST(request: NextRequest) {
+  const serviceUrl = await verifyBotFrameworkToken(
+    request.headers.get("authorization")
+  );
+  if (!serviceUrl) {
+    return new Response("Unauthorized", { status: 401 });
+  }
+
+  // Replies go to the activity's serviceUrl, so it must be the one the token
+  // was issued for.
+  const activity: TeamsActivity = await request.json();
+  if (!sameServiceUrl(serviceUrl, activity.serviceUrl)) {
+    return new Response("Unauthorized", { status: 401 });
+  }
+
+  await handleTeamsActivity(activity);
+  return new Response(null, { status: 200 });
+}
@@ -0,0 +1,22 @@
+import { NextResponse } from "next/server";
+
+// Installs the bot for the user (not a server), so it can DM them and they
+// can use its slash commands anywhere.
+const USER_INSTALL = "1";
+
+export async function GET() {
+  const clientId = process.env.DISCORD_APPLICATION_ID;
+  if (!clientId) {
+    return NextResponse.json(
+      { error: "DISCORD_APPLICATION_ID not configured" },
+      { status: 500 }
+    );
+  }
+
+  const discordUrl = new URL("https://discord.com/oauth2/authorize");
+  discordUrl.searchParams.set("client_id", clientId);
+  discordUrl.searchParams.set("integration_type", USER_INSTALL);
+  discordUrl.searchParams.set("scope", "applications.commands");
+
+  return NextResponse.redirect(discordUrl.toString());
+}
@@ -0,0 +1,17 @@
+import { NextResponse } from "next/server";
+
+export async function GET() {
+  const appId = process.env.MICROSOFT_APP_ID;
+  if (!appId) {
+    return NextResponse.json(
+      { error: "MICROSOFT_APP_ID not configured" },
+      { status: 500 }
+    );
+  }
+
+  // Deep link that opens a 1:1 chat with the bot.
+  const teamsUrl = new URL("https://teams.microsoft.com/l/chat/0/0");
+  teamsUrl.searchParams.set("users", `28:${appId}`);
+
+  return NextResponse.redirect(teamsUrl.toString());
+}
@@ -1,6 +1,6 @@
 import { describe, it, expect, vi, beforeEach } from "vitest";
 
-const { mockChain, setupDb, mockSendSlackDM, mockSendSMS } = vi.hoisted(() => {
+const { mockChain, setupDb, mockSendSlackDM, mockSendSMS, mockInngestSend, mockSendToContact, mockSendDiscordDM } = vi.hoisted(() => {
   let dbResults: any[][] = [];
   let dbCallIndex = 0;
 
@@ -26,7 +26,11 @@ const { mockChain, setupDb, mockSendSlackDM, mockSendSMS } = vi.hoisted(() => {
   const mockSendSlackDM = { fn: async (..._args: any[]): Promise<any> => ({ ts: "1234.5678", channel: "D123" }) };
   const mockSendSMS = { fn: async (..._args: any[]): Promise<any> => ({ sid: "SM123" }) };
 
-  return { mockChain: chain, setupDb, mockSendSlackDM, mockSendSMS };
+  const mockInngestSend = { fn: async (..._args: any[]): Promise<any> => undefined };
+  const mockSendToContact = { fn: async (..._args: any[]): Promise<any> => ({}) };
+  const mockSendDiscordDM = { fn: async (..._args: any[]): Promise<any> => ({ messageId: "M1", channelId: "DM1" }) };
+
+  return { mockChain: chain, setupDb, mockSendSlackDM, mockSendSMS, mockInngestSend, mockSendToContact, mockSendDiscordDM };
 });
 
 vi.mock("@/db", () => ({ db: mockChain }));
@@ -39,11 +43,16 @@ vi.mock("@/db/schema", () => {
     deliveries: table("deliveries"),
     users: table("users"),
     agentSessions: table("agentSessions"),
+    contactMethods: table("contactMethods"),
+    DEFAULT_PREFERENCES: { smsMinPriority: 1, breakthroughPriority: 5 },
   };
 });
 
 vi.mock("drizzle-orm", () => ({
   eq: () => {},
+  and: () => {},
+  inArray: () => {},
+  isNotNull: () => {},
 }));
 
 vi.mock("@/channels/slack", () => ({
@@ -54,6 +63,23 @@ vi.mock("@/channels/twilio", () => ({
   sendSMS: (...args: any[]) => mockSendSMS.fn(...args),
 }));
 
+vi.mock("@/channels/discord", () => ({
+  openDiscordDM: () => Promise.resolve("DM1"),
+  sendDiscordDM: (...args: any[]) => mockSendDiscordDM.fn(...args),
+}));
+
+vi.mock("@/channels/teams", () => ({
+  sendTeamsNotification: () => Promise.resolve({ activityId: "A1" }),
+}));
+
+vi.mock("@/channels/contact", () => ({
+  sendToContactMethod: (...args: any[]) => mockSendToContact.fn(...args),
+}));
+
+vi.mock("@/inngest/client", () => ({
+  inngest: { send: (...args: any[]) => mockInngestSend.fn(...args) },
+}));
+
 import { deliverNotification } from "../deliver";
 
 function makeNotification(overrides: Record<string, any> = {}) {
@@ -63,6 +89,7 @@ function makeNotification(overrides: Record<string, any> = {}) {
     userId: "user-1",
     sessionId: null,
     message: "Test message",
+    priority: 3,
     options: ["Yes", "No"],
     ...overrides,
   };
@@ -83,6 +110,9 @@ describe("deliverNotification", () => {
     setupDb();
     mockSendSlackDM.fn = vi.fn().mockResolvedValue({ ts: "1234.5678", channel: "D123" });
     mockSendSMS.fn = vi.fn().mockResolvedValue({ sid: "SM123" });
+    mockInngestSend.fn = vi.fn().mockResolvedValue(undefined);
+    mockSendToContact.fn = vi.fn().mockResolvedValue({});
+    mockSendDiscordDM.fn = vi.fn().mockResolvedValue({ messageId: "M1", channelId: "DM1" });
   });
 
   it("does nothing if notification not found", async () => {
@@ -112,6 +142,7 @@ describe("deliverNotification", () => {
       [makeUser()],
       [],   // insert slack delivery
       [],   // insert sms delivery
+      [],   // contact methods
       [],   // update notification status
     );
 
@@ -190,6 +221,108 @@ describe("deliverNotification", () => {
     );
   });
 
+  it("posts to the session's chosen surface instead of Slack", async () => {
+    const notification = makeNotification({ sessionId: "session-1" });
+    const session = {
+      id: "session-1",
+      surface: "discord",
+      slackThreadTs: "1234.0000",
+      discordMessageId: "M0",
+    };
+
+    setupDb(
+      [notification],
+      [makeUser({ discordUserId: "D123", discordDmChannelId: "DM1", phone: null })],
+      [session],  // session lookup
+      [],         // insert discord delivery
+      [],         // contact methods
+      [],         // update notification status
+    );
+
+    await deliverNotification("notif-1");
+
+    expect(mockSendSlackDM.fn).not.toHaveBeenCalled();
+    expect(mockSendDiscordDM.fn).toHaveBeenCalledWith(
+      expect.objectContaining({
+        channelId: "DM1",
+        shortCode: "ABC",
+        replyToMessageId: "M0",
+      }),
+    );
+  });
+
+  it("skips SMS below the user's SMS priority threshold", async () => {
+    setupDb(
+      [makeNotification({ priority: 2 })],
+      [makeUser({ slackUserId: null, preferences: { smsMinPriority: 4 } })],
+    );
+
+    await deliverNotification("notif-1");
+
+    expect(mockSendSMS.fn).not.toHaveBeenCalled();
+  });
+
+  it("holds a notification during do-not-disturb", async () => {
+    const until = new Date(Date.now() + 60 * 60 * 1000);
+    setupDb(
+      [makeNotification({ priority: 3 })],
+      [makeUser({ dndUntil: until })],
+    );
+
+    await deliverNotification("notif-1");
+
+    expect(mockSendSlackDM.fn).not.toHaveBeenCalled();
+    expect(mockInngestSend.fn).toHaveBeenCalledWith({
+      name: "notification/held",
+      data: {
+        notificationId: "notif-1",
+        userId: "user-1",
+        until: until.toISOString(),
+      },
+    });
+  });
+
+  it("lets P5 break through do-not-disturb", async () => {
+    setupDb(
+      [makeNotification({ priority: 5 })],
+      [makeUser({ dndUntil: new Date(Date.now() + 60 * 60 * 1000) })],
+      [],   // insert slack delivery
+      [],   // insert sms delivery
+      [],   // update notification status
+    );
+
+    await deliverNotification("notif-1");
+
+    expect(mockSendSlackDM.fn).toHaveBeenCalled();
+  });
+
+  it("delivers to verified email and webhook contact methods", async () => {
+    const email = { channel: "email", address: "me@example.com", secret: null };
+    const webhook = { channel: "webhook", address: "https://example.com/hook", secret: "s3cret" };
+    mockSendToContact.fn = vi.fn().mockResolvedValue({ externalId: "em-1" });
+
+    setupDb(
+      [makeNotification()],
+      [makeUser({ slackUserId: null, phone: null })],
+      [email, webhook],   // contact methods
+      [],                 // insert email delivery
+      [],                 // insert webhook delivery
+      [],                 // update notification status
+    );
+
+    await deliverNotification("notif-1");
+
+    expect(mockSendToContact.fn).toHaveBeenCalledTimes(2);
+    expect(mockSendToContact.fn).toHaveBeenCalledWith(
+      email,
+      expect.objectContaining({ shortCode: "ABC" }),
+    );
+    expect(mockSendToContact.fn).toHaveBeenCalledWith(
+      webhook,
+      expect.objectContaining({ shortCode: "ABC" }),
+    );
+  });
+
   it("does not update status if no channel succeeds", async () => {
     setupDb(
       [makeNotification()],
@@ -0,0 +1,77 @@
+import { describe, it, expect, vi } from "vitest";
+
+vi.mock("@/db/schema", () => ({
+  DEFAULT_PREFERENCES: { smsMinPriority: 1, breakthroughPriority: 5 },
+}));
+
+import { activeHold, holdUntil } from "../quiet-hours";
+
+function makeUser(overrides: Record<string, any> = {}) {
+  return {
+    timezone: "UTC",
+    quietHoursStart: null,
+    quietHoursEnd: null,
+    dndUntil: null,
+    preferences: null,
+    ...overrides,
+  };
+}
+
+describe("activeHold", () => {
+  it("returns null with no DND or quiet hours", () => {
+    expect(activeHold(makeUser())).toBeNull();
+  });
+
+  it("holds until DND ends", () => {
+    const now = new Date("2025-01-01T12:00:00Z");
+    const dndUntil = new Date("2025-01-01T14:00:00Z");
+    expect(activeHold(makeUser({ dndUntil }), now)).toEqual({
+      reason: "dnd",
+      until: dndUntil,
+      breakthroughPriority: 5,
+    });
+  });
+
+  it("ignores an expired DND", () => {
+    const now = new Date("2025-01-01T12:00:00Z");
+    const dndUntil = new Date("2025-01-01T11:00:00Z");
+    expect(activeHold(makeUser({ dndUntil }), now)).toBeNull();
+  });
+
+  it("handles quiet hours that wrap midnight in the user's timezone", () => {
+    const user = makeUser({
+      timezone: "America/Los_Angeles",
+      quietHoursStart: "22:00:00",
+      quietHoursEnd: "07:00:00",
+    });
+
+    // 23:30 in Los Angeles (UTC-8 in January).
+    const late = new Date("2025-01-02T07:30:00Z");
+    expect(activeHold(user, late)).toMatchObject({
+      reason: "quiet-hours",
+      until: new Date("2025-01-02T15:00:00Z"),
+    });
+
+    // 12:00 in Los Angeles is outside quiet hours.
+    const noon = new Date("2025-01-01T20:00:00Z");
+    expect(activeHold(user, noon)).toBeNull();
+  });
+});
+
+describe("holdUntil", () => {
+  const user = makeUser({ dndUntil: new Date("2025-01-01T14:00:00Z") });
+  const now = new Date("2025-01-01T12:00:00Z");
+
+  it("holds priorities below the breakthrough level", () => {
+    expect(holdUntil(user, 4, now)).toEqual(new Date("2025-01-01T14:00:00Z"));
+  });
+
+  it("lets P5 through by default", () => {
+    expect(holdUntil(user, 5, now)).toBeNull();
+  });
+
+  it("respects a lower breakthrough priority", () => {
+    const relaxed = { ...user, preferences: { breakthroughPriority: 3 } };
+    expect(holdUntil(relaxed, 3, now)).toBeNull();
+  });
+});
@@ -0,0 +1,28 @@
+import { describe, it, expect } from "vitest";
+import { chooseSurface, connectedSurfaces } from "../surface";
+
+const teams = { serviceUrl: "https://smba.example", conversationId: "a:1" };
+
+describe("connectedSurfaces", () => {
+  it("lists linked chat apps in preference order", () => {
+    expect(
+      connectedSurfaces({ slackUserId: "U1", discordUserId: "D1", teamsConversation: teams })
+    ).toEqual(["slack", "discord", "teams"]);
+    expect(connectedSurfaces({ slackUserId: null })).toEqual([]);
+  });
+});
+
+describe("chooseSurface", () => {
+  it("uses the session's surface when connected", () => {
+    expect(chooseSurface({ slackUserId: "U1", discordUserId: "D1" }, "discord")).toBe("discord");
+  });
+
+  it("falls back to the first connected surface", () => {
+    expect(chooseSurface({ slackUserId: "U1" }, "teams")).toBe("slack");
+    expect(chooseSurface({ slackUserId: null, teamsConversation: teams })).toBe("teams");
+  });
+
+  it("returns null without any chat app", () => {
+    expect(chooseSurface({ slackUserId: null }, "slack")).toBeNull();
+  });
+});
@@ -0,0 +1,137 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+import crypto from "crypto";
+
+const { mockLookup } = vi.hoisted(() => ({
+  mockLookup: { fn: async (..._args: any[]): Promise<any> => [] },
+}));
+
+vi.mock("dns/promises", () => ({
+  default: { lookup: (...args: any[]) => mockLookup.fn(...args) },
+}));
+
+import {
+  signPayload,
+  isPublicAddress,
+  checkWebhookURL,
+  sendWebhook,
+  WebhookError,
+} from "../webhook";
+
+describe("signPayload", () => {
+  it("signs timestamp and body with HMAC-SHA256", () => {
+    const body = JSON.stringify({ event: "ping" });
+    const expected = crypto
+      .createHmac("sha256", "secret")
+      .update(`1700000000.${body}`)
+      .digest("hex");
+
+    expect(signPayload("secret", body, 1700000000)).toBe(
+      `t=1700000000,v1=${expected}`
+    );
+  });
+
+  it("matches the Go verifier's test vector", () => {
+    expect(signPayload("secret", '{"event":"ping"}', 1700000000)).toBe(
+      "t=1700000000,v1=4d39bd2442f073b6bc62e95d0297ce25475582a17389ab860abdc778fe1d9f77"
+    );
+  });
+
+  it("changes when the body changes", () => {
+    expect(signPayload("secret", "a", 1)).not.toBe(signPayload("secret", "b", 1));
+  });
+});
+
+describe("isPublicAddress", () => {
+  it.each([
+    "127.0.0.1",
+    "10.1.2.3",
+    "172.16.0.1",
+    "172.31.255.255",
+    "192.168.1.1",
+    "169.254.169.254",
+    "100.64.0.1",
+    "0.0.0.0",
+    "224.0.0.1",
+    "::1",
+    "::",
+    "fd00::1",
+    "fe80::1",
+    "::ffff:127.0.0.1",
+    "::ffff:a9fe:a9fe",
+    "not an ip",
+  ])("rejects %s", (ip) => {
+    expect(isPublicAddress(ip)).toBe(false);
+  });
+
+  it.each(["93.184.216.34", "172.32.0.1", "8.8.8.8", "2606:4700::1111", "::ffff:8.8.8.8"])(
+    "accepts %s",
+    (ip) => {
+      expect(isPublicAddress(ip)).toBe(true);
+    },
+  );
+});
+
+describe("checkWebhookURL", () => {
+  beforeEach(() => {
+    mockLookup.fn = vi.fn().mockResolvedValue([{ address: "93.184.216.34", family: 4 }]);
+  });
+
+  it("accepts https URLs on public hosts", async () => {
+    const url = await checkWebhookURL("https://example.com/hook");
+    expect(url.toString()).toBe("https://example.com/hook");
+    expect(mockLookup.fn).toHaveBeenCalledWith("example.com", { all: true });
+  });
+
+  it("requires https", async () => {
+    await expect(checkWebhookURL("http://example.com/hook")).rejects.toThrow("must use https");
+  });
+
+  it("rejects private and metadata addresses", async () => {
+    await expect(checkWebhookURL("https://169.254.169.254/latest")).rejects.toThrow(
+      "not a public address",
+    );
+    await expect(checkWebhookURL("https://[::1]/hook")).rejects.toThrow("not a public address");
+  });
+
+  it("rejects hosts that resolve to a private address", async () => {
+    mockLookup.fn = vi.fn().mockResolvedValue([
+      { address: "93.184.216.34", family: 4 },
+      { address: "10.0.0.5", family: 4 },
+    ]);
+    await expect(checkWebhookURL("https://internal.example.com")).rejects.toThrow(
+      "not a public address",
+    );
+  });
+
+  it("rejects hosts that don't resolve", async () => {
+    mockLookup.fn = vi.fn().mockRejectedValue(new Error("ENOTFOUND"));
+    await expect(checkWebhookURL("https://nope.invalid")).rejects.toThrow("Can't resolve");
+  });
+});
+
+describe("sendWebhook", () => {
+  beforeEach(() => {
+    mockLookup.fn = vi.fn().mockResolvedValue([{ address: "93.184.216.34", family: 4 }]);
+  });
+
+  it("checks the address again before sending", async () => {
+    const fetchFn = vi.fn();
+    vi.stubGlobal("fetch", fetchFn);
+    mockLookup.fn = vi.fn().mockResolvedValue([{ address: "127.0.0.1", family: 4 }]);
+
+    await expect(
+      sendWebhook({ url: "https://example.com/hook", secret: null, event: "ping", data: {} }),
+    ).rejects.toThrow("not a public address");
+    expect(fetchFn).not.toHaveBeenCalled();
+  });
+
+  it("does not follow redirects", async () => {
+    const fetchFn = vi.fn().mockResolvedValue({ ok: false, status: 302 });
+    vi.stubGlobal("fetch", fetchFn);
+
+    await expect(
+      sendWebhook({ url: "https://example.com/hook", secret: null, event: "ping", data: {} }),
+    ).rejects.toBeInstanceOf(WebhookError);
+    expect(fetchFn.mock.calls[0][1].redirect).toBe("manual");
+  });
+});
@@ -0,0 +1,57 @@
+import { sendNotificationEmail } from "./email";
+import { sendWebhook } from "./webhook";
+
+interface ContactMethod {
+  channel: string;
+  address: string;
+  secret: string | null;
+}
+
+interface NotificationPayload {
+  id: string;
+  shortCode: string;
+  message: string;
+  priority: number;
+  options: string[] | null;
+  tags?: string[] | null;
+  context?: unknown;
+}
+
+/**
+ * Send a notification to an email or webhook contact method. Returns an
+ * external ID for the delivery record when the channel provides one.
+ */
+export async function sendToContactMethod(
+  method: ContactMethod,
+  notification: NotificationPayload
+): Promise<{ externalId?: string }> {
+  switch (method.channel) {
+    case "email": {
+      const result = await sendNotificationEmail({
+        to: method.address,
+        message: notification.message,
+        shortCode: notification.shortCode,
+        options: notification.options ?? undefined,
+      });
+      return { externalId: result.id };
+    }
+    case "webhook":
+      await sendWebhook({
+        url: method.address,
+        secret: method.secret,
+        event: "notification.created",
+        data: {
+          id: notification.id,
+          shortCode: notification.shortCode,
+          message: notification.message,
+          priority: notification.priority,
+          options: notification.options ?? [],
+          tags: notification.tags ?? [],
+          context: notification.context ?? null,
+        },
+      });
+      return {};
+    default:
+      throw new Error(`Unsupported contact channel: ${method.channel}`);
+  }
+}
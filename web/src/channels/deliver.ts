@@ -1,15 +1,31 @@
 import { db } from "@/db";
-import { notifications, deliveries, users, agentSessions } from "@/db/schema";
-import { eq } from "drizzle-orm";
+import {
+  notifications,
+  deliveries,
+  users,
+  agentSessions,
+  contactMethods,
+  DEFAULT_PREFERENCES,
+} from "@/db/schema";
+import { eq, and, inArray, isNotNull } from "drizzle-orm";
 import { sendSlackDM } from "./slack";
 import { sendSMS } from "./twilio";
+import { sendToContactMethod } from "./contact";
+import { openDiscordDM, sendDiscordDM } from "./discord";
+import { sendTeamsNotification } from "./teams";
+import { chooseSurface } from "./surface";
+import { holdUntil } from "./quiet-hours";
+import { inngest } from "@/inngest/client";
 
 /**
  * Deliver a notification to the user via their configured channels.
- * Tries Slack first, falls back to SMS.
- * If the notification belongs to a session, routes into a Slack thread.
+ * Posts to one chat app (Slack, Discord or Teams, per the session's surface),
+ * then SMS, then any verified email and webhook contact methods.
+ * If the notification belongs to a session, routes into the session's thread.
  * The first message in a session becomes the thread parent; subsequent
  * messages are posted as replies.
+ * During do-not-disturb or quiet hours, notifications below the user's
+ * breakthrough priority stay pending and are delivered when the hold ends.
  */
 export async function deliverNotification(notificationId: string) {
   const [notification] = await db
@@ -26,24 +42,50 @@ export async function deliverNotification(notificationId: string) {
 
   if (!user) return;
 
-  const channels: string[] = [];
+  const heldUntil = holdUntil(user, notification.priority);
+  if (heldUntil) {
+    await inngest
+      .send({
+        name: "notification/held",
+        data: {
+          notificationId,
+          userId: notification.userId,
+          until: heldUntil.toISOString(),
+        },
+      })
+      .catch((err: unknown) => {
+        console.warn("Inngest send failed (held notification not scheduled):", err);
+      });
+    return;
+  }
 
-  // Try Slack
-  if (user.slackUserId) {
-    try {
-      let threadTs: string | undefined;
+  const channels: string[] = [];
 
-      // Session-aware thread routing: reuse an existing thread if one exists.
-      if (notification.sessionId) {
-        const [session] = await db
+  const session = notification.sessionId
+    ? (
+        await db
           .select()
           .from(agentSessions)
-          .where(eq(agentSessions.id, notification.sessionId));
+          .where(eq(agentSessions.id, notification.sessionId))
+      )[0]
+    : undefined;
 
-        if (session?.slackThreadTs) {
-          threadTs = session.slackThreadTs;
-        }
-      }
+  // Follow-ups name the question they continue.
+  let replyToShortCode: string | undefined;
+  if (notification.parentId) {
+    const [parent] = await db
+      .select({ shortCode: notifications.shortCode })
+      .from(notifications)
+      .where(eq(notifications.id, notification.parentId));
+    replyToShortCode = parent?.shortCode;
+  }
+
+  // Post to one chat app: the session's chosen surface, else the first linked.
+  const surface = chooseSurface(user, session?.surface);
+  if (surface === "slack" && user.slackUserId) {
+    try {
+      // Session-aware thread routing: reuse an existing thread if one exists.
+      const threadTs = session?.slackThreadTs ?? undefined;
 
       // Send the message. Without threadTs this becomes a top-level DM
       // (and the first message in a new session thread).
@@ -55,24 +97,27 @@ export async function deliverNotification(notificationId: string) {
         notificationId: notification.id,
         threadTs,
         teamId: user.slackTeamId ?? undefined,
+        expiresAt: notification.expiresAt ?? undefined,
+        defaultOption: notification.defaultOption ?? undefined,
+        replyToShortCode,
       });
 
       // If this was the first message for the session, save its ts as the
       // thread parent so subsequent messages become replies.
       if (notification.sessionId && !threadTs) {
-        const [session] = await db
+        const [current] = await db
           .select()
           .from(agentSessions)
           .where(eq(agentSessions.id, notification.sessionId));
 
-        if (session && !session.slackThreadTs) {
+        if (current && !current.slackThreadTs) {
           await db
             .update(agentSessions)
             .set({
               slackThreadTs: result.ts,
               slackChannelId: result.channel,
             })
-            .where(eq(agentSessions.id, session.id));
+            .where(eq(agentSessions.id, current.id));
         }
       }
 
@@ -94,10 +139,29 @@ export async function deliverNotification(notificationId: string) {
         error: String(err),
       });
     }
+  } else if (surface === "discord" || surface === "teams") {
+    try {
+      if (surface === "discord") {
+        await deliverViaDiscord(notification, user, session, replyToShortCode);
+      } else {
+        await deliverViaTeams(notification, user, session, replyToShortCode);
+      }
+      channels.push(surface);
+    } catch (err) {
+      console.error(`${surface} delivery failed:`, err);
+      await db.insert(deliveries).values({
+        notificationId: notification.id,
+        channel: surface,
+        status: "failed",
+        error: String(err),
+      });
+    }
   }
 
-  // Try SMS
-  if (user.phone) {
+  // Try SMS, unless the user only wants texts for higher priorities.
+  const smsMinPriority =
+    user.preferences?.smsMinPriority ?? DEFAULT_PREFERENCES.smsMinPriority;
+  if (user.phone && notification.priority >= smsMinPriority) {
     try {
       const result = await sendSMS({
         to: user.phone,
@@ -120,6 +184,40 @@ export async function deliverNotification(notificationId: string) {
     }
   }
 
+  // Verified email addresses and webhooks get every notification.
+  const methods = await db
+    .select()
+    .from(contactMethods)
+    .where(
+      and(
+        eq(contactMethods.userId, user.id),
+        inArray(contactMethods.channel, ["email", "webhook"]),
+        isNotNull(contactMethods.verifiedAt)
+      )
+    );
+  for (const method of methods) {
+    try {
+      const result = await sendToContactMethod(method, notification);
+      await db.insert(deliveries).values({
+        notificationId: notification.id,
+        channel: method.channel,
+        status: "sent",
+        externalId: result.externalId,
+        metadata: { address: method.address },
+      });
+      channels.push(method.channel);
+    } catch (err) {
+      console.error(`${method.channel} delivery failed:`, err);
+      await db.insert(deliveries).values({
+        notificationId: notification.id,
+        channel: method.channel,
+        status: "failed",
+        error: String(err),
+        metadata: { address: method.address },
+      });
+    }
+  }
+
   // Update notification status if any channel succeeded
   if (channels.length > 0) {
     await db
@@ -128,3 +226,90 @@ export async function deliverNotification(notificationId: string) {
       .where(eq(notifications.id, notification.id));
   }
 }
+
+type Notification = Pick<
+  typeof notifications.$inferSelect,
+  "id" | "shortCode" | "message" | "options" | "sessionId"
+>;
+type User = typeof users.$inferSelect;
+type Session = typeof agentSessions.$inferSelect;
+
+/**
+ * DM a notification on Discord. Messages in a session reply to the session's
+ * first message, since Discord DMs have no threads.
+ */
+export async function deliverViaDiscord(
+  notification: Notification,
+  user: User,
+  session: Session | undefined,
+  replyToShortCode?: string
+) {
+  if (!user.discordUserId) throw new Error("Discord is not connected");
+
+  let channelId = user.discordDmChannelId;
+  if (!channelId) {
+    channelId = await openDiscordDM(user.discordUserId);
+    await db
+      .update(users)
+      .set({ discordDmChannelId: channelId })
+      .where(eq(users.id, user.id));
+  }
+
+  const result = await sendDiscordDM({
+    channelId,
+    message: notification.message,
+    shortCode: notification.shortCode,
+    notificationId: notification.id,
+    options: notification.options ?? undefined,
+    replyToMessageId: session?.discordMessageId ?? undefined,
+    replyToShortCode,
+  });
+
+  if (session && !session.discordMessageId) {
+    await db
+      .update(agentSessions)
+      .set({ discordMessageId: result.messageId })
+      .where(eq(agentSessions.id, session.id));
+  }
+
+  await db.insert(deliveries).values({
+    notificationId: notification.id,
+    channel: "discord",
+    status: "sent",
+    externalId: result.messageId,
+    metadata: { channel: result.channelId },
+  });
+}
+
+/** Post a notification to the user's Teams chat with the bot. */
+export async function deliverViaTeams(
+  notification: Notification,
+  user: User,
+  session: Session | undefined,
+  replyToShortCode?: string
+) {
+  if (!user.teamsConversation) throw new Error("Teams is not connected");
+
+  const result = await sendTeamsNotification({
+    conversation: user.teamsConversation,
+    message: notification.message,
+    shortCode: notification.shortCode,
+    options: notification.options ?? undefined,
+    replyToId: session?.teamsActivityId ?? undefined,
+    replyToShortCode,
+  });
+
+  if (session && !session.teamsActivityId && result.activityId) {
+    await db
+      .update(agentSessions)
+      .set({ teamsActivityId: result.activityId })
+      .where(eq(agentSessions.id, session.id));
+  }
+
+  await db.insert(deliveries).values({
+    notificationId: notification.id,
+    channel: "teams",
+    status: "sent",
+    externalId: result.activityId,
+  });
+}
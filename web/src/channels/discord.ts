@@ -0,0 +1,105 @@
+const DISCORD_API = "https://discord.com/api/v10";
+
+// Discord allows five buttons per row and five rows per message.
+const MAX_BUTTONS = 25;
+
+async function discordFetch<T>(
+  path: string,
+  init: { method: string; body?: unknown }
+): Promise<T> {
+  const token = process.env.DISCORD_BOT_TOKEN;
+  if (!token) throw new Error("Discord bot token not configured");
+
+  const res = await fetch(`${DISCORD_API}${path}`, {
+    method: init.method,
+    headers: {
+      Authorization: `Bot ${token}`,
+      "Content-Type": "application/json",
+    },
+    body: init.body === undefined ? undefined : JSON.stringify(init.body),
+  });
+  if (!res.ok) {
+    throw new Error(`Discord API ${path} failed: ${res.status} ${await res.text()}`);
+  }
+  return (await res.json()) as T;
+}
+
+/** Open (or reuse) the bot's DM channel with a user. */
+export async function openDiscordDM(discordUserId: string): Promise<string> {
+  const channel = await discordFetch<{ id: string }>("/users/@me/channels", {
+    method: "POST",
+    body: { recipient_id: discordUserId },
+  });
+  return channel.id;
+}
+
+/** Buttons for a notification's options; custom IDs carry the option index. */
+export function discordOptionButtons(notificationId: string, options: string[]) {
+  const buttons = options.slice(0, MAX_BUTTONS).map((option, index) => ({
+    type: 2, // button
+    style: 1, // primary
+    label: option.slice(0, 80),
+    custom_id: `respond:${notificationId}:${index}`,
+  }));
+  const rows = [];
+  for (let i = 0; i < buttons.length; i += 5) {
+    rows.push({ type: 1, components: buttons.slice(i, i + 5) });
+  }
+  return rows;
+}
+
+interface DiscordDMOptions {
+  channelId: string;
+  message: string;
+  shortCode: string;
+  notificationId: string;
+  options?: string[];
+  /** Reply to this message so a session reads as one conversation. */
+  replyToMessageId?: string;
+  replyToShortCode?: string;
+}
+
+export async function sendDiscordDM({
+  channelId,
+  message,
+  shortCode,
+  notificationId,
+  options,
+  replyToMessageId,
+  replyToShortCode,
+}: DiscordDMOptions): Promise<{ messageId: string; channelId: string }> {
+  let content = `**[${shortCode}]** ${message}`;
+  if (replyToShortCode) content = `↪ re ${replyToShortCode}\n${content}`;
+  content += `\n\n_Reply with \`/reply ${shortCode} <your response>\`_`;
+
+  const result = await discordFetch<{ id: string; channel_id: string }>(
+    `/channels/${channelId}/messages`,
+    {
+      method: "POST",
+      body: {
+        content,
+        components:
+          options && options.length > 0
+            ? discordOptionButtons(notificationId, options)
+            : [],
+        ...(replyToMessageId && {
+          message_reference: {
+            message_id: replyToMessageId,
+            fail_if_not_exists: false,
+          },
+        }),
+      },
+    }
+  );
+  return { messageId: result.id, channelId: result.channel_id };
+}
+
+export async function postDiscordMessage(
+  channelId: string,
+  content: string
+): Promise<void> {
+  await discordFetch(`/channels/${channelId}/messages`, {
+    method: "POST",
+    body: { content },
+  });
+}
@@ -0,0 +1,65 @@
+const RESEND_API_URL = "https://api.resend.com/emails";
+
+interface EmailOptions {
+  to: string;
+  subject: string;
+  text: string;
+}
+
+/** Send a plain-text email through Resend. */
+export async function sendEmail({
+  to,
+  subject,
+  text,
+}: EmailOptions): Promise<{ id: string }> {
+  const apiKey = process.env.RESEND_API_KEY;
+  if (!apiKey) throw new Error("Email credentials not configured");
+
+  const res = await fetch(RESEND_API_URL, {
+    method: "POST",
+    headers: {
+      Authorization: `Bearer ${apiKey}`,
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify({
+      from: process.env.EMAIL_FROM ?? "AgentDuty <notifications@agentduty.dev>",
+      to,
+      subject,
+      text,
+    }),
+  });
+  if (!res.ok) {
+    throw new Error(`Email send failed: ${res.status} ${await res.text()}`);
+  }
+  const body = (await res.json()) as { id: string };
+  return { id: body.id };
+}
+
+interface NotificationEmailOptions {
+  to: string;
+  message: string;
+  shortCode: string;
+  options?: string[];
+}
+
+export async function sendNotificationEmail({
+  to,
+  message,
+  shortCode,
+  options,
+}: NotificationEmailOptions): Promise<{ id: string }> {
+  let text = message;
+  if (options && options.length > 0) {
+    text += "\n\nOptions:";
+    options.forEach((option, index) => {
+      text += `\n${index + 1}. ${option}`;
+    });
+  }
+  text += `\n\nRespond with: agentduty respond ${shortCode} "<your response>"`;
+
+  const firstLine = message.split("\n")[0];
+  const subject =
+    firstLine.length > 80 ? `${firstLine.slice(0, 77)}...` : firstLine;
+
+  return sendEmail({ to, subject: `[${shortCode}] ${subject}`, text });
+}
@@ -0,0 +1,68 @@
+import crypto from "crypto";
+import { inngest } from "@/inngest/client";
+import type { notifications, responses } from "@/db/schema";
+
+/** Lifecycle events webhook subscriptions can receive. */
+export const WEBHOOK_EVENTS = [
+  "notification.created",
+  "notification.updated",
+  "notification.retracted",
+  "notification.archived",
+  "notification.expired",
+  "response.created",
+] as const;
+export type WebhookEvent = (typeof WEBHOOK_EVENTS)[number];
+
+type Notification = typeof notifications.$inferSelect;
+type ResponseRow = typeof responses.$inferSelect;
+
+export function notificationPayload(n: Notification) {
+  return {
+    id: n.id,
+    shortCode: n.shortCode,
+    message: n.message,
+    priority: n.priority,
+    status: n.status,
+    tags: n.tags ?? [],
+    options: n.options ?? [],
+    context: n.context ?? null,
+    createdAt: new Date(n.createdAt).toISOString(),
+  };
+}
+
+export function responsePayload(
+  n: Notification,
+  r: Pick<ResponseRow, "channel" | "text" | "selectedOption">
+) {
+  return {
+    notification: notificationPayload(n),
+    response: {
+      channel: r.channel,
+      text: r.text ?? null,
+      selectedOption: r.selectedOption ?? null,
+    },
+  };
+}
+
+/** The Inngest event that fans a lifecycle event out to subscriptions. */
+export function webhookEvent(
+  userId: string,
+  event: WebhookEvent,
+  data: Record<string, unknown>
+) {
+  return {
+    name: "webhook/event" as const,
+    data: { id: crypto.randomUUID(), userId, event, data },
+  };
+}
+
+/** Queue a lifecycle event for the user's webhook subscriptions (non-blocking). */
+export function emitWebhookEvent(
+  userId: string,
+  event: WebhookEvent,
+  data: Record<string, unknown>
+) {
+  inngest.send(webhookEvent(userId, event, data)).catch((err: unknown) => {
+    console.warn(`Inngest send failed (${event} webhook skipped):`, err);
+  });
+}
@@ -0,0 +1,86 @@
+import { DEFAULT_PREFERENCES, type NotificationPreferences } from "@/db/schema";
+
+export interface HoldSettings {
+  timezone: string | null;
+  quietHoursStart: string | null;
+  quietHoursEnd: string | null;
+  dndUntil: Date | null;
+  preferences: NotificationPreferences | null;
+}
+
+export interface Hold {
+  reason: "dnd" | "quiet-hours";
+  until: Date;
+  breakthroughPriority: number;
+}
+
+/** Parse "HH:MM" or "HH:MM:SS" into minutes after midnight. */
+function clockToMinutes(value: string): number {
+  const [h, m] = value.split(":").map(Number);
+  return h * 60 + m;
+}
+
+/** Minutes after local midnight in the given IANA timezone. */
+function localMinutes(now: Date, timezone: string): number {
+  const parts = new Intl.DateTimeFormat("en-US", {
+    timeZone: timezone,
+    hour: "2-digit",
+    minute: "2-digit",
+    hourCycle: "h23",
+  }).formatToParts(now);
+  const hour = Number(parts.find((p) => p.type === "hour")?.value ?? 0);
+  const minute = Number(parts.find((p) => p.type === "minute")?.value ?? 0);
+  return hour * 60 + minute;
+}
+
+/**
+ * Work out whether pushes to this user are currently held by do-not-disturb
+ * or quiet hours, ignoring priority. Quiet hours may wrap midnight
+ * (22:00-07:00). When both apply, the later end wins.
+ */
+export function activeHold(user: HoldSettings, now = new Date()): Hold | null {
+  const breakthroughPriority =
+    user.preferences?.breakthroughPriority ??
+    DEFAULT_PREFERENCES.breakthroughPriority;
+  let hold: Hold | null = null;
+
+  if (user.dndUntil && user.dndUntil > now) {
+    hold = { reason: "dnd", until: user.dndUntil, breakthroughPriority };
+  }
+
+  if (user.quietHoursStart && user.quietHoursEnd) {
+    const start = clockToMinutes(user.quietHoursStart);
+    const end = clockToMinutes(user.quietHoursEnd);
+    const current = localMinutes(now, user.timezone || "UTC");
+    const inside =
+      start <= end
+        ? current >= start && current < end
+        : current >= start || current < end;
+
+    if (inside) {
+      const minutesLeft = (end - current + 24 * 60) % (24 * 60);
+      const until = new Date(
+        Math.floor(now.getTime() / 60000) * 60000 + minutesLeft * 60000
+      );
+      if (!hold || until > hold.until) {
+        hold = { reason: "quiet-hours", until, breakthroughPriority };
+      }
+    }
+  }
+
+  return hold;
+}
+
+/**
+ * The time until which a notification of this priority must wait before
+ * being pushed, or null to deliver now.
+ */
+export function holdUntil(
+  user: HoldSettings,
+  priority: number,
+  now = new Date()
+): Date | null {
+  const hold = activeHold(user, now);
+  if (!hold || priority >= hold.breakthroughPriority) return null;
+  return hold.until;
+}
@@ -0,0 +1,91 @@
+import { db } from "@/db";
+import { notifications, responses, agentSessions } from "@/db/schema";
+import { eq, and, asc, gt, sql } from "drizzle-orm";
+
+export const MAX_RESPONSE_EVENTS = 100;
+
+/** A response joined with the notification and session it answers. */
+export interface ResponseEvent {
+  id: string;
+  notificationId: string;
+  shortCode: string;
+  message: string;
+  priority: number;
+  tags: string[];
+  sessionKey: string | null;
+  workspace: string | null;
+  channel: string;
+  text: string | null;
+  selectedOption: string | null;
+  auto: boolean;
+  createdAt: Date;
+}
+
+const UUID = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i;
+
+/** Whether value can be a response id, for validating resume cursors. */
+export function isResponseId(value: string): boolean {
+  return UUID.test(value);
+}
+
+/**
+ * Responses to the user's notifications, oldest first. Used by
+ * `agentduty listen`, both over the stream and when polling.
+ *
+ * Resume with `afterId`, the last response seen: rows are compared with that
+ * response's stored (created_at, id), which keeps the microseconds a JS Date
+ * would drop, so nothing is repeated or skipped. `after` is a timestamp for
+ * starting out before any response has been seen.
+ */
+export async function listResponseEvents(
+  userId: string,
+  opts: {
+    after?: Date;
+    afterId?: string;
+    sessionKey?: string;
+    limit?: number;
+  } = {}
+): Promise<ResponseEvent[]> {
+  const limit = Math.min(opts.limit ?? MAX_RESPONSE_EVENTS, MAX_RESPONSE_EVENTS);
+
+  const rows = await db
+    .select({
+      response: responses,
+      notification: notifications,
+      session: agentSessions,
+    })
+    .from(responses)
+    .innerJoin(notifications, eq(responses.notificationId, notifications.id))
+    .leftJoin(agentSessions, eq(notifications.sessionId, agentSessions.id))
+    .where(
+      and(
+        eq(notifications.userId, userId),
+        opts.afterId
+          ? sql`(${responses.createdAt}, ${responses.id}) > (select ${responses.createdAt}, ${responses.id} from ${responses} where ${responses.id} = ${opts.afterId})`
+          : opts.after
+            ? gt(responses.createdAt, opts.after)
+            : undefined,
+        opts.sessionKey
+          ? eq(agentSessions.sessionKey, opts.sessionKey)
+          : undefined
+      )
+    )
+    .orderBy(asc(responses.createdAt), asc(responses.id))
+    .limit(limit);
+
+  return rows.map(({ response, notification, session }) => ({
+    id: response.id,
+    notificationId: notification.id,
+    shortCode: notification.shortCode,
+    message: notification.message,
+    priority: notification.priority,
+    tags: notification.tags ?? [],
+    sessionKey: session?.sessionKey ?? null,
+    workspace: session?.workspace ?? null,
+    channel: response.channel,
+    text: response.text,
+    selectedOption: response.selectedOption,
+    auto: response.auto,
+    createdAt: response.createdAt,
+  }));
+}
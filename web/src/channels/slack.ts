@@ -92,22 +92,44 @@ interface SlackDMOptions {
   notificationId: string;
   threadTs?: string;
   teamId?: string;
+  expiresAt?: Date;
+  defaultOption?: string;
+  replyToShortCode?: string;
 }
 
-export async function sendSlackDM({
-  slackUserId,
+/**
+ * Render a deadline as a Slack date token so each viewer sees it in their
+ * own timezone. Slack has no live countdown, so this is the closest we get.
+ */
+function deadlineText(expiresAt: Date, defaultOption?: string): string {
+  const unix = Math.floor(expiresAt.getTime() / 1000);
+  const when = `<!date^${unix}^{date_short_pretty} at {time}|${expiresAt.toISOString()}>`;
+  return defaultOption
+    ? `:hourglass_flowing_sand: Expires ${when} — defaults to *${defaultOption}*`
+    : `:hourglass_flowing_sand: Expires ${when}`;
+}
+
+type NotificationBlockOptions = Omit<SlackDMOptions, "slackUserId" | "teamId">;
+
+/**
+ * Build the blocks and fallback text for a notification message. Shared by
+ * the initial post and later edits so both render identically.
+ */
+function buildNotificationBlocks({
   message,
   shortCode,
   options,
   notificationId,
   threadTs,
-  teamId,
-}: SlackDMOptions): Promise<{ ts: string; channel: string }> {
-  const slack = teamId ? await getSlackForTeam(teamId) : getSlack();
+  expiresAt,
+  defaultOption,
+  replyToShortCode,
+}: NotificationBlockOptions): { text: string; blocks: KnownBlock[] } {
   const slackMessage = markdownToMrkdwn(message);
+  const replyPrefix = replyToShortCode ? `_↳ re ${replyToShortCode}_ ` : "";
   const displayText = threadTs
-    ? slackMessage
-    : `*[${shortCode}]* ${slackMessage}`;
+    ? `${replyPrefix}${slackMessage}`
+    : `*[${shortCode}]* ${replyPrefix}${slackMessage}`;
   const blocks: KnownBlock[] = [
     {
       type: "section",
@@ -148,11 +170,32 @@ export async function sendSlackDM({
     });
   }
 
+  if (expiresAt) {
+    blocks.push({
+      type: "context",
+      elements: [{ type: "mrkdwn", text: deadlineText(expiresAt, defaultOption) }],
+    });
+  }
+
+  return {
+    text: threadTs ? slackMessage : `[${shortCode}] ${slackMessage}`,
+    blocks,
+  };
+}
+
+export async function sendSlackDM({
+  slackUserId,
+  teamId,
+  ...rest
+}: SlackDMOptions): Promise<{ ts: string; channel: string }> {
+  const slack = teamId ? await getSlackForTeam(teamId) : getSlack();
+  const { text, blocks } = buildNotificationBlocks(rest);
+
   const result = await slack.chat.postMessage({
     channel: slackUserId,
-    text: threadTs ? slackMessage : `[${shortCode}] ${slackMessage}`,
+    text,
     blocks,
-    ...(threadTs ? { thread_ts: threadTs } : {}),
+    ...(rest.threadTs ? { thread_ts: rest.threadTs } : {}),
   });
 
   return {
@@ -161,6 +204,24 @@ export async function sendSlackDM({
   };
 }
 
+/**
+ * Re-render a previously sent notification after the agent edited it.
+ */
+export async function editSlackNotification(
+  channel: string,
+  ts: string,
+  opts: NotificationBlockOptions & { teamId?: string }
+): Promise<void> {
+  const { teamId, ...rest } = opts;
+  const slack = teamId ? await getSlackForTeam(teamId) : getSlack();
+  const { text, blocks } = buildNotificationBlocks(rest);
+  blocks.push({
+    type: "context",
+    elements: [{ type: "mrkdwn", text: "_(edited)_" }],
+  });
+  await slack.chat.update({ channel, ts, text, blocks });
+}
+
 export async function sendSlackThreadHeader(
   slackUserId: string,
   message: string,
@@ -228,3 +289,140 @@ export async function updateSlackMessage(
     ],
   });
 }
+
+export async function markSlackMessageExpired(
+  channel: string,
+  ts: string,
+  shortCode: string,
+  message: string,
+  defaultOption: string | null
+): Promise<void> {
+  const slackMessage = markdownToMrkdwn(message);
+  const outcome = defaultOption
+    ? `Expired — defaulted to *${defaultOption}*`
+    : "Expired without a response";
+  await getSlack().chat.update({
+    channel,
+    ts,
+    text: `[${shortCode}] ${slackMessage}`,
+    blocks: [
+      {
+        type: "section",
+        text: {
+          type: "mrkdwn",
+          text: `*[${shortCode}]* ${slackMessage}`,
+        },
+      },
+      {
+        type: "context",
+        elements: [{ type: "mrkdwn", text: outcome }],
+      },
+    ],
+  });
+}
+
+export async function markSlackMessageRetracted(
+  channel: string,
+  ts: string,
+  shortCode: string,
+  message: string,
+  reason: string | null
+): Promise<void> {
+  const slackMessage = markdownToMrkdwn(message);
+  const outcome = reason
+    ? `Retracted by the agent: ${reason}`
+    : "Retracted by the agent";
+  await getSlack().chat.update({
+    channel,
+    ts,
+    text: `[${shortCode}] ${slackMessage}`,
+    blocks: [
+      {
+        type: "section",
+        text: {
+          type: "mrkdwn",
+          text: `~*[${shortCode}]* ${slackMessage}~`.slice(0, 3000),
+        },
+      },
+      {
+        type: "context",
+        elements: [{ type: "mrkdwn", text: outcome }],
+      },
+    ],
+  });
+}
+
+const PROGRESS_BAR_WIDTH = 20;
+
+/**
+ * Render a progress line: key, optional bar and percentage, and message.
+ */
+function progressText(
+  key: string,
+  message: string,
+  percent: number | null,
+  done: boolean
+): string {
+  const icon = done ? ":white_check_mark:" : ":hourglass_flowing_sand:";
+  let line = `${icon} *${key}*`;
+  if (percent != null) {
+    const clamped = Math.max(0, Math.min(100, percent));
+    const filled = Math.round((clamped / 100) * PROGRESS_BAR_WIDTH);
+    line += ` \`${"▓".repeat(filled)}${"░".repeat(PROGRESS_BAR_WIDTH - filled)}\` ${clamped}%`;
+  }
+  return `${line}\n${markdownToMrkdwn(message)}`.slice(0, 3000);
+}
+
+interface SlackProgressOptions {
+  key: string;
+  message: string;
+  percent: number | null;
+  done: boolean;
+}
+
+export async function postSlackProgress(
+  slackUserId: string,
+  threadTs: string | undefined,
+  teamId: string | undefined,
+  progress: SlackProgressOptions
+): Promise<{ ts: string; channel: string }> {
+  const slack = teamId ? await getSlackForTeam(teamId) : getSlack();
+  const text = progressText(
+    progress.key,
+    progress.message,
+    progress.percent,
+    progress.done
+  );
+  const result = await slack.chat.postMessage({
+    channel: slackUserId,
+    text,
+    blocks: [{ type: "section", text: { type: "mrkdwn", text } }],
+    ...(threadTs ? { thread_ts: threadTs } : {}),
+  });
+
+  return {
+    ts: result.ts!,
+    channel: result.channel!,
+  };
+}
+
+export async function updateSlackProgress(
+  channel: string,
+  ts: string,
+  teamId: string | undefined,
+  progress: SlackProgressOptions
+): Promise<void> {
+  const slack = teamId ? await getSlackForTeam(teamId) : getSlack();
+  const text = progressText(
+    progress.key,
+    progress.message,
+    progress.percent,
+    progress.done
+  );
+  await slack.chat.update({
+    channel,
+    ts,
+    text,
+    blocks: [{ type: "section", text: { type: "mrkdwn", text } }],
+  });
+}
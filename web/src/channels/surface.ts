@@ -0,0 +1,37 @@
+/** Chat apps a session's thread can live in. */
+export const CHAT_SURFACES = ["slack", "discord", "teams"] as const;
+export type ChatSurface = (typeof CHAT_SURFACES)[number];
+
+export function isChatSurface(value: string): value is ChatSurface {
+  return (CHAT_SURFACES as readonly string[]).includes(value);
+}
+
+interface SurfaceUser {
+  slackUserId: string | null;
+  discordUserId?: string | null;
+  teamsConversation?: unknown;
+}
+
+export function connectedSurfaces(user: SurfaceUser): ChatSurface[] {
+  const surfaces: ChatSurface[] = [];
+  if (user.slackUserId) surfaces.push("slack");
+  if (user.discordUserId) surfaces.push("discord");
+  if (user.teamsConversation) surfaces.push("teams");
+  return surfaces;
+}
+
+/**
+ * Pick the chat surface for a notification: the session's choice when that
+ * surface is still connected, otherwise the first connected one (Slack,
+ * then Discord, then Teams). Returns null when no chat app is connected.
+ */
+export function chooseSurface(
+  user: SurfaceUser,
+  preferred?: string | null
+): ChatSurface | null {
+  const connected = connectedSurfaces(user);
+  if (preferred && isChatSurface(preferred) && connected.includes(preferred)) {
+    return preferred;
+  }
+  return connected[0] ?? null;
+}
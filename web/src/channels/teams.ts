@@ -0,0 +1,92 @@
+import type { TeamsConversation } from "@/db/schema";
+
+const TOKEN_URL =
+  "https://login.microsoftonline.com/botframework.com/oauth2/v2.0/token";
+
+let cachedToken: { value: string; expiresAt: number } | null = null;
+
+async function getBotToken(): Promise<string> {
+  if (cachedToken && cachedToken.expiresAt > Date.now() + 60_000) {
+    return cachedToken.value;
+  }
+
+  const appId = process.env.MICROSOFT_APP_ID;
+  const password = process.env.MICROSOFT_APP_PASSWORD;
+  if (!appId || !password) throw new Error("Teams bot credentials not configured");
+
+  const res = await fetch(TOKEN_URL, {
+    method: "POST",
+    headers: { "Content-Type": "application/x-www-form-urlencoded" },
+    body: new URLSearchParams({
+      grant_type: "client_credentials",
+      client_id: appId,
+      client_secret: password,
+      scope: "https://api.botframework.com/.default",
+    }),
+  });
+  if (!res.ok) throw new Error(`Teams token request failed: ${res.status}`);
+
+  const body = (await res.json()) as { access_token: string; expires_in: number };
+  cachedToken = {
+    value: body.access_token,
+    expiresAt: Date.now() + body.expires_in * 1000,
+  };
+  return body.access_token;
+}
+
+/** Post a text activity into a Teams conversation. */
+export async function postTeamsMessage(
+  conversation: TeamsConversation,
+  text: string,
+  replyToId?: string
+): Promise<{ activityId: string }> {
+  const base = conversation.serviceUrl.replace(/\/$/, "");
+  const path = replyToId
+    ? `/v3/conversations/${encodeURIComponent(conversation.conversationId)}/activities/${encodeURIComponent(replyToId)}`
+    : `/v3/conversations/${encodeURIComponent(conversation.conversationId)}/activities`;
+
+  const res = await fetch(`${base}${path}`, {
+    method: "POST",
+    headers: {
+      Authorization: `Bearer ${await getBotToken()}`,
+      "Content-Type": "application/json",
+    },
+    body: JSON.stringify({ type: "message", textFormat: "markdown", text }),
+  });
+  if (!res.ok) {
+    throw new Error(`Teams send failed: ${res.status} ${await res.text()}`);
+  }
+  const body = (await res.json()) as { id?: string };
+  return { activityId: body.id ?? "" };
+}
+
+interface TeamsNotificationOptions {
+  conversation: TeamsConversation;
+  message: string;
+  shortCode: string;
+  options?: string[];
+  replyToId?: string;
+  replyToShortCode?: string;
+}
+
+export async function sendTeamsNotification({
+  conversation,
+  message,
+  shortCode,
+  options,
+  replyToId,
+  replyToShortCode,
+}: TeamsNotificationOptions): Promise<{ activityId: string }> {
+  let text = `**[${shortCode}]** ${message}`;
+  if (replyToShortCode) text = `↪ re ${replyToShortCode}\n\n${text}`;
+  if (options && options.length > 0) {
+    text += "\n\nReply with:";
+    options.forEach((option, index) => {
+      text += `\n\n${index + 1}. ${option}`;
+    });
+    text += `\n\nOr reply "${shortCode} <your response>"`;
+  } else {
+    text += `\n\nReply "${shortCode} <your response>"`;
+  }
+  return postTeamsMessage(conversation, text, replyToId);
+}
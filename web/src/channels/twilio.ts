@@ -51,3 +51,19 @@ export async function sendSMS({
 
   return { sid: result.sid };
 }
+
+export async function sendVerificationSMS({
+  to,
+  code,
+}: {
+  to: string;
+  code: string;
+}): Promise<{ sid: string }> {
+  const result = await getClient().messages.create({
+    body: `Your AgentDuty verification code is ${code}. It expires in 10 minutes.`,
+    to,
+    from: process.env.TWILIO_FROM_NUMBER!,
+  });
+
+  return { sid: result.sid };
+}
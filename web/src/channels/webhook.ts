@@ -0,0 +1,159 @@
+import crypto from "crypto";
+import dns from "dns/promises";
+import net from "net";
+
+export const SIGNATURE_HEADER = "AgentDuty-Signature";
+export const EVENT_HEADER = "AgentDuty-Event";
+export const DELIVERY_HEADER = "AgentDuty-Delivery";
+
+/**
+ * Sign a webhook body. The header value is "t=<unix seconds>,v1=<hex>",
+ * where v1 is HMAC-SHA256 over "<t>.<body>" with the shared secret, so
+ * receivers can reject both forged and replayed requests.
+ */
+export function signPayload(
+  secret: string,
+  body: string,
+  timestamp: number = Math.floor(Date.now() / 1000)
+): string {
+  const mac = crypto
+    .createHmac("sha256", secret)
+    .update(`${timestamp}.${body}`)
+    .digest("hex");
+  return `t=${timestamp},v1=${mac}`;
+}
+
+/** A webhook endpoint answered with a non-2xx status. */
+export class WebhookError extends Error {
+  constructor(public status: number) {
+    super(`Webhook returned ${status}`);
+  }
+}
+
+/** A webhook URL that isn't allowed: not https, or not a public host. */
+export class WebhookURLError extends Error {}
+
+function isPublicIPv4(ip: string): boolean {
+  const [a, b, c] = ip.split(".").map(Number);
+  return !(
+    a === 0 ||
+    a === 10 ||
+    a === 127 ||
+    (a === 100 && b >= 64 && b <= 127) || // carrier-grade NAT
+    (a === 169 && b === 254) || // link-local, including cloud metadata
+    (a === 172 && b >= 16 && b <= 31) ||
+    (a === 192 && b === 168) ||
+    (a === 192 && b === 0 && (c === 0 || c === 2)) ||
+    (a === 198 && (b === 18 || b === 19)) ||
+    a >= 224 // multicast and reserved
+  );
+}
+
+/**
+ * Whether ip is a public unicast address, i.e. not loopback, private,
+ * link-local or otherwise special. IPv4-mapped IPv6 addresses are checked
+ * as IPv4.
+ */
+export function isPublicAddress(ip: string): boolean {
+  if (net.isIPv4(ip)) return isPublicIPv4(ip);
+  if (!net.isIPv6(ip)) return false;
+
+  const addr = ip.toLowerCase();
+  const mapped = addr.match(/^::ffff:(\d+\.\d+\.\d+\.\d+)$/);
+  if (mapped) return isPublicIPv4(mapped[1]);
+  const mappedHex = addr.match(/^::ffff:([0-9a-f]{1,4}):([0-9a-f]{1,4})$/);
+  if (mappedHex) {
+    const hi = parseInt(mappedHex[1], 16);
+    const lo = parseInt(mappedHex[2], 16);
+    return isPublicIPv4(`${hi >> 8}.${hi & 255}.${lo >> 8}.${lo & 255}`);
+  }
+  if (addr === "::" || addr === "::1") return false;
+  const first = parseInt(addr.split(":")[0] || "0", 16);
+  return !(
+    (first & 0xfe00) === 0xfc00 || // unique local
+    (first & 0xffc0) === 0xfe80 || // link-local
+    (first & 0xff00) === 0xff00 // multicast
+  );
+}
+
+/**
+ * Check that a webhook URL is https and that its host resolves only to
+ * public addresses, so webhooks can't be pointed at our own network or the
+ * cloud metadata service. Called when a webhook is saved and again before
+ * every request, since DNS can change in between.
+ */
+export async function checkWebhookURL(raw: string): Promise<URL> {
+  let url: URL;
+  try {
+    url = new URL(raw);
+  } catch {
+    throw new WebhookURLError(`Invalid URL: ${raw}`);
+  }
+  if (url.protocol !== "https:") {
+    throw new WebhookURLError("Webhook URL must use https");
+  }
+
+  const host = url.hostname.replace(/^\[|\]$/g, "");
+  let addresses: string[];
+  if (net.isIP(host)) {
+    addresses = [host];
+  } else {
+    try {
+      addresses = (await dns.lookup(host, { all: true })).map((a) => a.address);
+    } catch {
+      throw new WebhookURLError(`Can't resolve webhook host ${host}`);
+    }
+  }
+  if (addresses.length === 0 || !addresses.every(isPublicAddress)) {
+    throw new WebhookURLError(`Webhook host ${host} is not a public address`);
+  }
+  return url;
+}
+
+interface WebhookOptions {
+  url: string;
+  secret: string | null;
+  event: string;
+  data: Record<string, unknown>;
+  /** Stable ID for this event, so receivers can drop retried duplicates. */
+  id?: string;
+}
+
+/**
+ * POST a signed event to a webhook; non-2xx responses throw. Redirects are
+ * not followed, since they could lead to an address checkWebhookURL rejects.
+ */
+export async function sendWebhook({
+  url,
+  secret,
+  event,
+  data,
+  id = crypto.randomUUID(),
+}: WebhookOptions): Promise<{ status: number }> {
+  const body = JSON.stringify({
+    id,
+    event,
+    sentAt: new Date().toISOString(),
+    data,
+  });
+  const headers: Record<string, string> = {
+    "Content-Type": "application/json",
+    "User-Agent": "AgentDuty-Webhook/1",
+    [EVENT_HEADER]: event,
+    [DELIVERY_HEADER]: id,
+  };
+  if (secret) headers[SIGNATURE_HEADER] = signPayload(secret, body);
+
+  const target = await checkWebhookURL(url);
+  const res = await fetch(target.toString(), {
+    method: "POST",
+    headers,
+    body,
+    redirect: "manual",
+    signal: AbortSignal.timeout(10_000),
+  });
+  if (!res.ok) {
+    throw new WebhookError(res.status);
+  }
+  return { status: res.status };
+}
@@ -8,9 +8,19 @@ import {
   jsonb,
   time,
   pgEnum,
+  unique,
+  type AnyPgColumn,
 } from "drizzle-orm/pg-core";
 
-export const channelEnum = pgEnum("channel", ["slack", "sms", "web"]);
+export const channelEnum = pgEnum("channel", [
+  "slack",
+  "sms",
+  "web",
+  "email",
+  "webhook",
+  "discord",
+  "teams",
+]);
 
 export const notificationStatusEnum = pgEnum("notification_status", [
   "pending",
@@ -18,6 +28,7 @@ export const notificationStatusEnum = pgEnum("notification_status", [
   "responded",
   "expired",
   "archived",
+  "retracted",
 ]);
 
 export const deliveryStatusEnum = pgEnum("delivery_status", [
@@ -27,6 +38,29 @@ export const deliveryStatusEnum = pgEnum("delivery_status", [
   "failed",
 ]);
 
+/**
+ * Per-user delivery preferences. Missing keys fall back to the defaults in
+ * DEFAULT_PREFERENCES.
+ */
+export interface NotificationPreferences {
+  /** Only text notifications at or above this priority. */
+  smsMinPriority?: number;
+  /** Notifications at or above this priority ignore DND and quiet hours. */
+  breakthroughPriority?: number;
+}
+
+export const DEFAULT_PREFERENCES: Required<NotificationPreferences> = {
+  smsMinPriority: 1,
+  breakthroughPriority: 5,
+};
+
+/** Where the Teams bot can post proactive messages to a user. */
+export interface TeamsConversation {
+  serviceUrl: string;
+  conversationId: string;
+  tenantId?: string;
+}
+
 export const users = pgTable("users", {
   id: uuid("id").primaryKey().defaultRandom(),
   email: text("email").notNull().unique(),
@@ -36,14 +70,73 @@ export const users = pgTable("users", {
   slackTeamId: text("slack_team_id"),
   slackLinkCode: text("slack_link_code"),
   slackLinkCodeExpiresAt: timestamp("slack_link_code_expires_at"),
+  discordUserId: text("discord_user_id"),
+  discordDmChannelId: text("discord_dm_channel_id"),
+  discordLinkCode: text("discord_link_code"),
+  discordLinkCodeExpiresAt: timestamp("discord_link_code_expires_at"),
+  teamsUserId: text("teams_user_id"),
+  teamsConversation: jsonb("teams_conversation").$type<TeamsConversation>(),
+  teamsLinkCode: text("teams_link_code"),
+  teamsLinkCodeExpiresAt: timestamp("teams_link_code_expires_at"),
   timezone: text("timezone").default("UTC"),
   quietHoursStart: time("quiet_hours_start"),
   quietHoursEnd: time("quiet_hours_end"),
+  preferences: jsonb("preferences").$type<NotificationPreferences>(),
+  dndUntil: timestamp("dnd_until"),
   workosUserId: text("workos_user_id").unique(),
   createdAt: timestamp("created_at").defaultNow().notNull(),
   updatedAt: timestamp("updated_at").defaultNow().notNull(),
 });
 
+/**
+ * Where a user can be reached besides Slack: a phone for SMS, an email
+ * address, or a webhook URL. A method only receives notifications once
+ * verifiedAt is set.
+ */
+export const contactMethods = pgTable("contact_methods", {
+  id: uuid("id").primaryKey().defaultRandom(),
+  userId: uuid("user_id")
+    .notNull()
+    .references(() => users.id),
+  channel: channelEnum("channel").notNull(),
+  address: text("address").notNull(),
+  secret: text("secret"),
+  verificationCodeHash: text("verification_code_hash"),
+  verificationExpiresAt: timestamp("verification_expires_at"),
+  verificationAttempts: integer("verification_attempts").default(0).notNull(),
+  verifiedAt: timestamp("verified_at"),
+  createdAt: timestamp("created_at").defaultNow().notNull(),
+});
+
+/**
+ * Every verification code sent by SMS or email, kept to throttle how often
+ * one user, or anyone, can make us text or mail an address.
+ */
+export const verificationSends = pgTable("verification_sends", {
+  id: uuid("id").primaryKey().defaultRandom(),
+  userId: uuid("user_id")
+    .notNull()
+    .references(() => users.id),
+  channel: channelEnum("channel").notNull(),
+  address: text("address").notNull(),
+  createdAt: timestamp("created_at").defaultNow().notNull(),
+});
+
+/** Outbound webhooks that receive notification lifecycle events. */
+export const webhookSubscriptions = pgTable("webhook_subscriptions", {
+  id: uuid("id").primaryKey().defaultRandom(),
+  userId: uuid("user_id")
+    .notNull()
+    .references(() => users.id),
+  url: text("url").notNull(),
+  secret: text("secret").notNull(),
+  events: text("events").array().notNull(),
+  lastDeliveryAt: timestamp("last_delivery_at"),
+  lastStatus: integer("last_status"),
+  lastError: text("last_error"),
+  createdAt: timestamp("created_at").defaultNow().notNull(),
+});
+
 export const apiKeys = pgTable("api_keys", {
   id: uuid("id").primaryKey().defaultRandom(),
   userId: uuid("user_id")
@@ -97,9 +190,36 @@ export const agentSessions = pgTable("agent_sessions", {
   workspace: text("workspace"),
   slackThreadTs: text("slack_thread_ts"),
   slackChannelId: text("slack_channel_id"),
+  /** Chat surface for this session's thread; null means the first connected. */
+  surface: text("surface"),
+  discordMessageId: text("discord_message_id"),
+  teamsActivityId: text("teams_activity_id"),
+  /** Last heartbeat from a running `agentduty poll`; cleared when it exits. */
+  pollHeartbeatAt: timestamp("poll_heartbeat_at"),
   createdAt: timestamp("created_at").defaultNow().notNull(),
 });
 
+export const sessionProgress = pgTable("session_progress", {
+  id: uuid("id").primaryKey().defaultRandom(),
+  userId: uuid("user_id")
+    .notNull()
+    .references(() => users.id),
+  sessionId: uuid("session_id")
+    .notNull()
+    .references(() => agentSessions.id),
+  key: text("key").notNull(),
+  message: text("message").notNull(),
+  percent: integer("percent"),
+  slackTs: text("slack_ts"),
+  slackChannelId: text("slack_channel_id"),
+  completedAt: timestamp("completed_at"),
+  createdAt: timestamp("created_at").defaultNow().notNull(),
+  updatedAt: timestamp("updated_at").defaultNow().notNull(),
+}, (t) => [
+  // One line per key in a session; upserts rely on it.
+  unique("session_progress_session_id_key_unique").on(t.sessionId, t.key),
+]);
+
 export const notifications = pgTable("notifications", {
   id: uuid("id").primaryKey().defaultRandom(),
   shortCode: text("short_code").notNull().unique(),
@@ -107,6 +227,7 @@ export const notifications = pgTable("notifications", {
     .notNull()
     .references(() => users.id),
   sessionId: uuid("session_id").references(() => agentSessions.id),
+  parentId: uuid("parent_id").references((): AnyPgColumn => notifications.id),
   message: text("message").notNull(),
   priority: integer("priority").notNull().default(3),
   context: jsonb("context"),
@@ -116,6 +237,13 @@ export const notifications = pgTable("notifications", {
   currentEscalationStep: integer("current_escalation_step").default(0),
   policyId: uuid("policy_id").references(() => escalationPolicies.id),
   snoozedUntil: timestamp("snoozed_until"),
+  expiresAt: timestamp("expires_at"),
+  defaultOption: text("default_option"),
+  retractReason: text("retract_reason"),
+  editedAt: timestamp("edited_at"),
+  dedupKey: text("dedup_key"),
+  repeatCount: integer("repeat_count").notNull().default(1),
+  lastRepeatedAt: timestamp("last_repeated_at"),
   createdAt: timestamp("created_at").defaultNow().notNull(),
   updatedAt: timestamp("updated_at").defaultNow().notNull(),
 });
@@ -153,8 +281,22 @@ export const responses = pgTable("responses", {
   text: text("text"),
   selectedOption: text("selected_option"),
   externalId: text("external_id"),
+  auto: boolean("auto").default(false).notNull(),
   responderId: uuid("responder_id")
     .notNull()
     .references(() => users.id),
   createdAt: timestamp("created_at").defaultNow().notNull(),
 });
+
+/** Emoji reactions an agent added to a response (`agentduty react`). */
+export const reactions = pgTable("reactions", {
+  id: uuid("id").primaryKey().defaultRandom(),
+  responseId: uuid("response_id")
+    .notNull()
+    .references(() => responses.id),
+  userId: uuid("user_id")
+    .notNull()
+    .references(() => users.id),
+  emoji: text("emoji").notNull(),
+  createdAt: timestamp("created_at").defaultNow().notNull(),
+});
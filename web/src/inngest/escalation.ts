@@ -7,10 +7,14 @@ import {
   users,
   escalationPolicies,
   agentSessions,
+  contactMethods,
 } from "@/db/schema";
-import { eq, and, asc } from "drizzle-orm";
+import { eq, and, asc, isNotNull } from "drizzle-orm";
 import { sendSlackDM } from "@/channels/slack";
 import { sendSMS } from "@/channels/twilio";
+import { holdUntil } from "@/channels/quiet-hours";
+import { sendToContactMethod } from "@/channels/contact";
+import { deliverViaDiscord, deliverViaTeams } from "@/channels/deliver";
 
 async function getSessionThreadTs(
   sessionId: string | null
@@ -31,6 +35,14 @@ export const escalateNotification = inngest.createFunction(
         event: "notification/responded",
         match: "data.notificationId",
       },
+      {
+        event: "notification/retracted",
+        match: "data.notificationId",
+      },
+      {
+        event: "notification/expired",
+        match: "data.notificationId",
+      },
     ],
   },
   { event: "notification/created" },
@@ -65,6 +77,10 @@ export const escalateNotification = inngest.createFunction(
             options: notification.options ?? undefined,
             notificationId: notification.id,
             threadTs,
+            expiresAt: notification.expiresAt
+              ? new Date(notification.expiresAt)
+              : undefined,
+            defaultOption: notification.defaultOption ?? undefined,
           });
 
           await db.insert(deliveries).values({
@@ -99,6 +115,24 @@ export const escalateNotification = inngest.createFunction(
       return { delivered: true };
     }
 
+    // Don't start escalating during do-not-disturb or quiet hours unless the
+    // priority breaks through.
+    const heldUntil = await step.run("check-hold", async () => {
+      const [user] = await db
+        .select()
+        .from(users)
+        .where(eq(users.id, notification.userId));
+      if (!user) return null;
+      return holdUntil(user, notification.priority)?.toISOString() ?? null;
+    });
+    if (heldUntil) {
+      await step.waitForEvent("wait-for-hold", {
+        event: "user/dnd-cleared",
+        if: `async.data.userId == "${notification.userId}"`,
+        timeout: new Date(heldUntil),
+      });
+    }
+
     // Fetch escalation steps ordered by step_order
     const steps = await step.run("fetch-steps", async () => {
       return db
@@ -135,6 +169,10 @@ export const escalateNotification = inngest.createFunction(
             options: notification.options ?? undefined,
             notificationId: notification.id,
             threadTs,
+            expiresAt: notification.expiresAt
+              ? new Date(notification.expiresAt)
+              : undefined,
+            defaultOption: notification.defaultOption ?? undefined,
           });
 
           await db.insert(deliveries).values({
@@ -158,6 +196,45 @@ export const escalateNotification = inngest.createFunction(
             status: "sent",
             externalId: result.sid,
           });
+        } else if (
+          (escalationStep.channel === "discord" && user.discordUserId) ||
+          (escalationStep.channel === "teams" && user.teamsConversation)
+        ) {
+          const [session] = notification.sessionId
+            ? await db
+                .select()
+                .from(agentSessions)
+                .where(eq(agentSessions.id, notification.sessionId))
+            : [];
+          if (escalationStep.channel === "discord") {
+            await deliverViaDiscord(notification, user, session);
+          } else {
+            await deliverViaTeams(notification, user, session);
+          }
+        } else if (
+          escalationStep.channel === "email" ||
+          escalationStep.channel === "webhook"
+        ) {
+          const methods = await db
+            .select()
+            .from(contactMethods)
+            .where(
+              and(
+                eq(contactMethods.userId, user.id),
+                eq(contactMethods.channel, escalationStep.channel),
+                isNotNull(contactMethods.verifiedAt)
+              )
+            );
+          for (const method of methods) {
+            const result = await sendToContactMethod(method, notification);
+            await db.insert(deliveries).values({
+              notificationId: notification.id,
+              channel: method.channel,
+              status: "sent",
+              externalId: result.externalId,
+              metadata: { address: method.address },
+            });
+          }
         }
 
         await db
@@ -0,0 +1,114 @@
+import { inngest } from "./client";
+import { db } from "@/db";
+import { notifications, responses, deliveries } from "@/db/schema";
+import { eq, and, inArray, sql } from "drizzle-orm";
+import { markSlackMessageExpired } from "@/channels/slack";
+import { notificationPayload, webhookEvent } from "@/channels/events";
+
+/**
+ * Enforce a notification deadline. Sleeps until expiresAt; if nobody has
+ * answered by then, marks the notification expired and records the default
+ * option, if any, as an automatic response. Waiting CLIs see the expired
+ * status. A human response cancels the run.
+ */
+export const expireNotification = inngest.createFunction(
+  {
+    id: "expire-notification",
+    cancelOn: [
+      {
+        event: "notification/responded",
+        match: "data.notificationId",
+      },
+      {
+        event: "notification/retracted",
+        match: "data.notificationId",
+      },
+    ],
+  },
+  { event: "notification/expiry.scheduled" },
+  async ({ event, step }) => {
+    const { notificationId, expiresAt } = event.data;
+
+    await step.sleepUntil("wait-for-deadline", expiresAt);
+
+    const outcome = await step.run("apply-default", async () => {
+      // Expire only if nobody has answered, and record the default in the
+      // same transaction, so a reply racing the deadline or a retried step
+      // can't leave two answers.
+      const notification = await db.transaction(async (tx) => {
+        const [expired] = await tx
+          .update(notifications)
+          .set({ status: "expired", updatedAt: new Date() })
+          .where(
+            and(
+              eq(notifications.id, notificationId),
+              inArray(notifications.status, ["pending", "delivered"]),
+              sql`not exists (select 1 from ${responses} where ${responses.notificationId} = ${notifications.id})`
+            )
+          )
+          .returning();
+        if (!expired) return null;
+
+        if (expired.defaultOption) {
+          await tx.insert(responses).values({
+            notificationId: expired.id,
+            channel: "web",
+            selectedOption: expired.defaultOption,
+            auto: true,
+            responderId: expired.userId,
+          });
+        }
+        return expired;
+      });
+
+      if (!notification) return { skipped: "already answered or closed" };
+
+      const [delivery] = await db
+        .select()
+        .from(deliveries)
+        .where(
+          and(
+            eq(deliveries.notificationId, notification.id),
+            eq(deliveries.channel, "slack")
+          )
+        );
+
+      const metadata = delivery?.metadata as { channel?: string } | null;
+      if (delivery?.externalId && metadata?.channel) {
+        await markSlackMessageExpired(
+          metadata.channel,
+          delivery.externalId,
+          notification.shortCode,
+          notification.message,
+          notification.defaultOption
+        ).catch((err) =>
+          console.error("Failed to update Slack message:", err)
+        );
+      }
+
+      return {
+        expired: true,
+        defaultOption: notification.defaultOption,
+        userId: notification.userId,
+        payload: notificationPayload(notification),
+      };
+    });
+
+    if ("expired" in outcome) {
+      // Stop any escalation still in flight for this notification.
+      await step.sendEvent("cancel-escalation", {
+        name: "notification/expired",
+        data: { notificationId },
+      });
+      await step.sendEvent(
+        "emit-webhook",
+        webhookEvent(outcome.userId, "notification.expired", {
+          ...outcome.payload,
+          defaultOption: outcome.defaultOption,
+        })
+      );
+    }
+
+    return outcome;
+  }
+);
@@ -1,3 +1,11 @@
 import { escalateNotification } from "./escalation";
+import { expireNotification } from "./expiry";
+import { releaseHeldNotification } from "./release-held";
+import { deliverWebhookEvent } from "./webhooks";
 
-export const functions = [escalateNotification];
+export const functions = [
+  escalateNotification,
+  expireNotification,
+  releaseHeldNotification,
+  deliverWebhookEvent,
+];
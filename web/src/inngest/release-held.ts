@@ -0,0 +1,56 @@
+import { inngest } from "./client";
+import { db } from "@/db";
+import { notifications } from "@/db/schema";
+import { eq } from "drizzle-orm";
+import { deliverNotification } from "@/channels/deliver";
+
+/**
+ * Deliver a notification that arrived during do-not-disturb or quiet hours
+ * once the hold ends, or as soon as the user turns do-not-disturb off.
+ * Delivery re-checks the hold, so an extended DND or quiet hours that are
+ * still running simply schedule another release.
+ */
+export const releaseHeldNotification = inngest.createFunction(
+  {
+    id: "release-held-notification",
+    cancelOn: [
+      {
+        event: "notification/responded",
+        match: "data.notificationId",
+      },
+      {
+        event: "notification/retracted",
+        match: "data.notificationId",
+      },
+      {
+        event: "notification/expired",
+        match: "data.notificationId",
+      },
+    ],
+  },
+  { event: "notification/held" },
+  async ({ event, step }) => {
+    const { notificationId, userId, until } = event.data;
+
+    await step.waitForEvent("wait-for-hold", {
+      event: "user/dnd-cleared",
+      if: `async.data.userId == "${userId}"`,
+      timeout: new Date(until),
+    });
+
+    return step.run("deliver", async () => {
+      const [notification] = await db
+        .select()
+        .from(notifications)
+        .where(eq(notifications.id, notificationId));
+
+      if (!notification) return { skipped: "not found" };
+      if (notification.status !== "pending") {
+        return { skipped: notification.status };
+      }
+
+      await deliverNotification(notificationId);
+      return { released: true };
+    });
+  }
+);
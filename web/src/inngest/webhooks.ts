@@ -0,0 +1,57 @@
+import { inngest } from "./client";
+import { db } from "@/db";
+import { webhookSubscriptions } from "@/db/schema";
+import { eq } from "drizzle-orm";
+import { sendWebhook } from "@/channels/webhook";
+
+/**
+ * Deliver a lifecycle event to every subscription that wants it. Each
+ * subscription is its own step, so one failing endpoint is retried without
+ * resending to the others; every attempt records its outcome.
+ */
+export const deliverWebhookEvent = inngest.createFunction(
+  { id: "deliver-webhook-event" },
+  { event: "webhook/event" },
+  async ({ event, step }) => {
+    const { id, userId, event: name, data } = event.data;
+
+    const subscriptions = await step.run("fetch-subscriptions", async () => {
+      const rows = await db
+        .select()
+        .from(webhookSubscriptions)
+        .where(eq(webhookSubscriptions.userId, userId));
+      return rows.filter(
+        (s) => s.events.includes(name) || s.events.includes("*")
+      );
+    });
+
+    await Promise.all(
+      subscriptions.map((sub) =>
+        step.run(`deliver-${sub.id}`, async () => {
+          try {
+            const { status } = await sendWebhook({
+              url: sub.url,
+              secret: sub.secret,
+              event: name,
+              data,
+              id,
+            });
+            await db
+              .update(webhookSubscriptions)
+              .set({ lastDeliveryAt: new Date(), lastStatus: status, lastError: null })
+              .where(eq(webhookSubscriptions.id, sub.id));
+            return { status };
+          } catch (err) {
+            await db
+              .update(webhookSubscriptions)
+              .set({ lastDeliveryAt: new Date(), lastError: String(err) })
+              .where(eq(webhookSubscriptions.id, sub.id));
+            throw err;
+          }
+        })
+      )
+    );
+
+    return { delivered: subscriptions.length };
+  }
+);
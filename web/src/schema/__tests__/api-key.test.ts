@@ -34,13 +34,17 @@ vi.mock("@/db/schema", () => {
   return {
     apiKeys: table("apiKeys"),
     users: table("users"),
+    contactMethods: table("contactMethods"),
     notifications: table("notifications"),
     responses: table("responses"),
+    reactions: table("reactions"),
     deliveries: table("deliveries"),
     agentSessions: table("agentSessions"),
     escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
     priorityRoutes: table("priorityRoutes"),
     slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
   };
 });
 
@@ -54,6 +58,9 @@ vi.mock("drizzle-orm", () => ({
   isNull: () => {},
   lte: () => {},
   gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
   sql: () => {},
 }));
 
@@ -74,6 +81,7 @@ vi.mock("@/channels/slack", () => ({
 
 vi.mock("@/channels/twilio", () => ({
   sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: () => Promise.resolve({ sid: "SM124" }),
 }));
 
 vi.mock("jose", () => ({
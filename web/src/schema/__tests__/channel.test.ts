@@ -0,0 +1,391 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const { mockChain, setupDb, mockSendVerificationSMS, mockFetch } = vi.hoisted(() => {
+  let dbResults: any[][] = [];
+  let dbCallIndex = 0;
+
+  const chain: any = {};
+  const methods = [
+    "select", "from", "where", "update", "set", "insert",
+    "values", "delete", "returning", "orderBy", "limit",
+  ];
+  for (const m of methods) {
+    chain[m] = (..._args: any[]) => chain;
+  }
+  chain.then = (resolve: any, reject?: any) => {
+    const result = dbResults[dbCallIndex] ?? [];
+    dbCallIndex++;
+    return Promise.resolve(result).then(resolve, reject);
+  };
+
+  function setupDb(...results: any[][]) {
+    dbResults = results;
+    dbCallIndex = 0;
+  }
+
+  const mockSendVerificationSMS = { fn: async (..._args: any[]): Promise<any> => ({ sid: "SM124" }) };
+  const mockFetch = { fn: async (..._args: any[]): Promise<any> => ({ ok: true, status: 200 }) };
+
+  return { mockChain: chain, setupDb, mockSendVerificationSMS, mockFetch };
+});
+
+vi.mock("@/db", () => ({ db: mockChain }));
+
+vi.mock("@/db/schema", () => {
+  const table = (name: string) =>
+    new Proxy({}, { get: (_, p) => `${name}.${String(p)}` });
+  return {
+    apiKeys: table("apiKeys"),
+    users: table("users"),
+    contactMethods: table("contactMethods"),
+    notifications: table("notifications"),
+    responses: table("responses"),
+    reactions: table("reactions"),
+    deliveries: table("deliveries"),
+    agentSessions: table("agentSessions"),
+    escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
+    priorityRoutes: table("priorityRoutes"),
+    slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
+    verificationSends: table("verificationSends"),
+  };
+});
+
+vi.mock("drizzle-orm", () => ({
+  eq: () => {},
+  and: () => {},
+  or: () => {},
+  desc: () => {},
+  asc: () => {},
+  inArray: () => {},
+  isNull: () => {},
+  lte: () => {},
+  gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
+  sql: () => {},
+}));
+
+vi.mock("@/inngest/client", () => ({
+  inngest: { send: () => Promise.resolve() },
+}));
+
+vi.mock("@/channels/deliver", () => ({
+  deliverNotification: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/slack", () => ({
+  sendSlackDM: () => Promise.resolve({ ts: "ts-1", channel: "C123" }),
+  updateSlackMessage: () => Promise.resolve(),
+  addSlackReaction: () => Promise.resolve(),
+  getSlackForTeam: () => Promise.resolve({}),
+}));
+
+vi.mock("@/channels/twilio", () => ({
+  sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: (...args: any[]) => mockSendVerificationSMS.fn(...args),
+}));
+
+vi.mock("dns/promises", () => ({
+  default: { lookup: async () => [{ address: "93.184.216.34", family: 4 }] },
+}));
+
+vi.mock("jose", () => ({
+  createRemoteJWKSet: () => () => {},
+  jwtVerify: async () => ({ payload: {} }),
+}));
+
+vi.mock("@/auth/workos", () => ({
+  workos: { userManagement: { getUser: async () => ({}) } },
+  WORKOS_CLIENT_ID: "test_client_id",
+}));
+
+import crypto from "crypto";
+import { executeGraphQL } from "@/schema/execute";
+
+function sha256(s: string) {
+  return crypto.createHash("sha256").update(s).digest("hex");
+}
+
+function makeUser(overrides: Record<string, any> = {}) {
+  return {
+    id: "user-1",
+    email: "me@example.com",
+    slackUserId: null,
+    phone: null,
+    ...overrides,
+  };
+}
+
+function makeMethod(overrides: Record<string, any> = {}) {
+  return {
+    id: "cm-1",
+    userId: "user-1",
+    channel: "sms",
+    address: "+14155550123",
+    secret: null,
+    verificationCodeHash: sha256("123456"),
+    verificationExpiresAt: new Date(Date.now() + 60_000),
+    verificationAttempts: 0,
+    verifiedAt: null,
+    createdAt: new Date("2025-01-01T00:00:00Z"),
+    ...overrides,
+  };
+}
+
+describe("channels query", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(`query { channels { channel } }`, {
+      userId: null,
+    });
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("lists slack and contact methods with verification state", async () => {
+    setupDb(
+      [makeUser({ slackUserId: "U123" })],
+      [
+        makeMethod({ channel: "webhook", address: "https://example.com/hook", verifiedAt: new Date() }),
+        makeMethod(),
+      ],
+    );
+
+    const result = await executeGraphQL(
+      `query { channels { channel address verified } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.channels).toEqual([
+      { channel: "slack", address: "U123", verified: true },
+      { channel: "sms", address: "+14155550123", verified: false },
+      { channel: "webhook", address: "https://example.com/hook", verified: true },
+    ]);
+  });
+});
+
+describe("connectSms mutation", () => {
+  beforeEach(() => {
+    setupDb();
+    mockSendVerificationSMS.fn = vi.fn().mockResolvedValue({ sid: "SM124" });
+  });
+
+  it("rejects numbers outside international format", async () => {
+    const result = await executeGraphQL(
+      `mutation { connectSms(phone: "415-555-0123") { channel } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("international format");
+    expect(mockSendVerificationSMS.fn).not.toHaveBeenCalled();
+  });
+
+  it("texts a verification code to the number", async () => {
+    setupDb(
+      [{ byUser: "0", toAddress: "0", last: null }], // recent sends
+      [],               // record the send
+      [],               // delete previous sms method
+      [makeMethod()],   // insert
+    );
+
+    const result = await executeGraphQL(
+      `mutation { connectSms(phone: "+1 (415) 555-0123") { channel address verified } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.connectSms).toEqual({
+      channel: "sms",
+      address: "+14155550123",
+      verified: false,
+    });
+    expect(mockSendVerificationSMS.fn).toHaveBeenCalledWith({
+      to: "+14155550123",
+      code: expect.stringMatching(/^\d{6}$/),
+    });
+  });
+
+  it("makes the user wait between codes", async () => {
+    setupDb([{ byUser: "1", toAddress: "1", last: new Date(Date.now() - 20_000) }]);
+
+    const result = await executeGraphQL(
+      `mutation { connectSms(phone: "+14155550123") { channel } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toMatch(/wait \d+s before asking for another/);
+    expect(mockSendVerificationSMS.fn).not.toHaveBeenCalled();
+  });
+
+  it("stops after the daily limit for the number", async () => {
+    setupDb([{ byUser: "0", toAddress: "5", last: new Date(Date.now() - 3_600_000) }]);
+
+    const result = await executeGraphQL(
+      `mutation { connectSms(phone: "+14155550123") { channel } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("verification codes a day");
+    expect(mockSendVerificationSMS.fn).not.toHaveBeenCalled();
+  });
+});
+
+describe("verifyChannel mutation", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("rejects unknown channels", async () => {
+    const result = await executeGraphQL(
+      `mutation { verifyChannel(channel: "pager", code: "123456") { verified } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("Unknown channel");
+  });
+
+  it("rejects a wrong code", async () => {
+    setupDb(
+      [makeMethod()],                               // lookup
+      [makeMethod({ verificationAttempts: 1 })],    // count the attempt
+    );
+
+    const result = await executeGraphQL(
+      `mutation { verifyChannel(channel: "sms", code: "000000") { verified } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe("Incorrect verification code (4 attempts left)");
+  });
+
+  it("asks for a new code after the last wrong guess", async () => {
+    setupDb(
+      [makeMethod({ verificationAttempts: 4 })],
+      [makeMethod({ verificationAttempts: 5 })],
+    );
+
+    const result = await executeGraphQL(
+      `mutation { verifyChannel(channel: "sms", code: "000000") { verified } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("connect sms' again");
+  });
+
+  it("locks the code once the attempts are used up", async () => {
+    setupDb(
+      [makeMethod({ verificationAttempts: 5 })],
+      [],   // the guarded update matches nothing
+    );
+
+    const result = await executeGraphQL(
+      `mutation { verifyChannel(channel: "sms", code: "123456") { verified } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("Too many incorrect codes");
+  });
+
+  it("rejects an expired code", async () => {
+    setupDb([makeMethod({ verificationExpiresAt: new Date(Date.now() - 1000) })]);
+
+    const result = await executeGraphQL(
+      `mutation { verifyChannel(channel: "sms", code: "123456") { verified } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("expired");
+  });
+
+  it("verifies with the right code", async () => {
+    setupDb(
+      [makeMethod()],                               // lookup
+      [makeMethod({ verificationAttempts: 1 })],    // count the attempt
+      [makeMethod({ verifiedAt: new Date() })],     // update
+      [],                                           // users.phone
+    );
+
+    const result = await executeGraphQL(
+      `mutation { verifyChannel(channel: "sms", code: "123456") { verified } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.verifyChannel).toEqual({ verified: true });
+  });
+});
+
+describe("connectWebhook mutation", () => {
+  beforeEach(() => {
+    setupDb();
+    mockFetch.fn = vi.fn().mockResolvedValue({ ok: true, status: 200 });
+    vi.stubGlobal("fetch", (...args: any[]) => mockFetch.fn(...args));
+  });
+
+  it("requires a long enough secret", async () => {
+    const result = await executeGraphQL(
+      `mutation { connectWebhook(url: "https://example.com/hook", secret: "short") { verified } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("at least 16 characters");
+  });
+
+  it("rejects plain http and private hosts", async () => {
+    for (const url of ["http://example.com/hook", "https://127.0.0.1/hook", "https://169.254.169.254/"]) {
+      const result = await executeGraphQL(
+        `mutation { connectWebhook(url: "${url}", secret: "0123456789abcdef") { verified } }`,
+        { userId: "user-1" },
+      );
+      expect(result.errors![0].message).toMatch(/https|public address/);
+    }
+    expect(mockFetch.fn).not.toHaveBeenCalled();
+  });
+
+  it("sends a signed ping and marks the webhook verified", async () => {
+    setupDb(
+      [],   // delete previous webhook
+      [makeMethod({ channel: "webhook", address: "https://example.com/hook", verifiedAt: new Date() })],
+    );
+
+    const result = await executeGraphQL(
+      `mutation { connectWebhook(url: "https://example.com/hook", secret: "0123456789abcdef") { channel verified } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.connectWebhook).toEqual({ channel: "webhook", verified: true });
+    const [url, init] = (mockFetch.fn as any).mock.calls[0];
+    expect(url).toBe("https://example.com/hook");
+    expect(JSON.parse(init.body).event).toBe("ping");
+    expect(init.headers["AgentDuty-Signature"]).toMatch(/^t=\d+,v1=[0-9a-f]{64}$/);
+  });
+});
+
+describe("disconnectChannel mutation", () => {
+  it("rejects unknown channels", async () => {
+    setupDb();
+    const result = await executeGraphQL(
+      `mutation { disconnectChannel(channel: "pager") }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("Unknown channel");
+  });
+});
+
+describe("setSessionSurface mutation", () => {
+  const mutation = `mutation { setSessionSurface(sessionKey: "s1", surface: "discord") }`;
+
+  it("rejects a surface that isn't connected", async () => {
+    setupDb([makeUser({ slackUserId: "U1" })]);
+    const result = await executeGraphQL(mutation, { userId: "user-1" });
+    expect(result.errors![0].message).toContain("discord is not connected");
+  });
+
+  it("moves the session to a connected surface", async () => {
+    setupDb(
+      [makeUser({ discordUserId: "D1" })], // user
+      [{ id: "sess-1" }],                  // existing session
+      [],                                  // update surface
+    );
+    const result = await executeGraphQL(mutation, { userId: "user-1" });
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.setSessionSurface).toBe("discord");
+  });
+});
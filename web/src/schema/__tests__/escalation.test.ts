@@ -0,0 +1,287 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const { mockChain, setupDb } = vi.hoisted(() => {
+  let dbResults: any[][] = [];
+  let dbCallIndex = 0;
+
+  const chain: any = {};
+  const methods = [
+    "select", "from", "where", "update", "set", "insert",
+    "values", "delete", "returning", "orderBy", "limit",
+  ];
+  for (const m of methods) {
+    chain[m] = (..._args: any[]) => chain;
+  }
+  chain.then = (resolve: any, reject?: any) => {
+    const result = dbResults[dbCallIndex] ?? [];
+    dbCallIndex++;
+    return Promise.resolve(result).then(resolve, reject);
+  };
+
+  function setupDb(...results: any[][]) {
+    dbResults = results;
+    dbCallIndex = 0;
+  }
+
+  return { mockChain: chain, setupDb };
+});
+
+vi.mock("@/db", () => ({ db: mockChain }));
+
+vi.mock("@/db/schema", () => {
+  const table = (name: string) =>
+    new Proxy({}, { get: (_, p) => `${name}.${String(p)}` });
+  return {
+    notifications: table("notifications"),
+    responses: table("responses"),
+    reactions: table("reactions"),
+    deliveries: table("deliveries"),
+    agentSessions: table("agentSessions"),
+    escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
+    priorityRoutes: table("priorityRoutes"),
+    users: table("users"),
+    contactMethods: table("contactMethods"),
+    apiKeys: table("apiKeys"),
+    slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
+  };
+});
+
+vi.mock("drizzle-orm", () => ({
+  eq: () => {},
+  and: () => {},
+  or: () => {},
+  desc: () => {},
+  asc: () => {},
+  inArray: () => {},
+  isNull: () => {},
+  lte: () => {},
+  gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
+  sql: () => {},
+}));
+
+vi.mock("@/inngest/client", () => ({
+  inngest: { send: () => Promise.resolve() },
+}));
+
+vi.mock("@/channels/deliver", () => ({
+  deliverNotification: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/slack", () => ({
+  sendSlackDM: () => Promise.resolve({ ts: "ts-1", channel: "C123" }),
+  updateSlackMessage: () => Promise.resolve(),
+  addSlackReaction: () => Promise.resolve(),
+  getSlackForTeam: () => Promise.resolve({}),
+  editSlackNotification: () => Promise.resolve(),
+  markSlackMessageRetracted: () => Promise.resolve(),
+  postSlackProgress: () => Promise.resolve({ ts: "ts-2", channel: "C123" }),
+  updateSlackProgress: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/twilio", () => ({
+  sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: () => Promise.resolve({ sid: "SM124" }),
+}));
+
+vi.mock("jose", () => ({
+  createRemoteJWKSet: () => () => {},
+  jwtVerify: async () => ({ payload: {} }),
+}));
+
+vi.mock("@/auth/workos", () => ({
+  workos: { userManagement: { getUser: async () => ({}) } },
+  WORKOS_CLIENT_ID: "test_client_id",
+}));
+
+import { executeGraphQL } from "@/schema/execute";
+
+function makePolicy(overrides: Record<string, any> = {}) {
+  return {
+    id: "11111111-1111-1111-1111-111111111111",
+    userId: "user-1",
+    name: "urgent",
+    isDefault: false,
+    createdAt: new Date("2025-01-01T00:00:00Z"),
+    ...overrides,
+  };
+}
+
+function makeStep(stepOrder: number, channel: string, delaySeconds: number) {
+  return {
+    id: `step-${stepOrder}`,
+    policyId: "11111111-1111-1111-1111-111111111111",
+    stepOrder,
+    channel,
+    delaySeconds,
+  };
+}
+
+describe("escalationPolicies query", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(
+      `query { escalationPolicies { id } }`,
+      { userId: null },
+    );
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("returns policies with steps and routed priorities", async () => {
+    setupDb(
+      [makePolicy()],                                      // policies
+      [makeStep(0, "slack", 0), makeStep(1, "sms", 300)], // steps
+      [{ priority: 5 }, { priority: 4 }],                  // routes
+    );
+
+    const result = await executeGraphQL(
+      `query {
+        escalationPolicies { name steps { channel delaySeconds } priorities }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.escalationPolicies).toEqual([
+      {
+        name: "urgent",
+        steps: [
+          { channel: "slack", delaySeconds: 0 },
+          { channel: "sms", delaySeconds: 300 },
+        ],
+        priorities: [4, 5],
+      },
+    ]);
+  });
+});
+
+describe("createEscalationPolicy mutation", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("creates a policy with ordered steps", async () => {
+    setupDb(
+      [],              // name clash lookup
+      [makePolicy()],  // insert policy returning
+      [],              // delete old steps
+      [makeStep(0, "slack", 0), makeStep(1, "sms", 300)], // insert steps returning
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        createEscalationPolicy(name: "urgent", steps: [
+          { channel: "slack", delaySeconds: 0 },
+          { channel: "sms", delaySeconds: 300 }
+        ]) { name steps { stepOrder channel } }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.createEscalationPolicy.steps).toEqual([
+      { stepOrder: 0, channel: "slack" },
+      { stepOrder: 1, channel: "sms" },
+    ]);
+  });
+
+  it("rejects an unknown channel", async () => {
+    const result = await executeGraphQL(
+      `mutation {
+        createEscalationPolicy(name: "x", steps: [{ channel: "pager", delaySeconds: 0 }]) { id }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toContain('unknown channel "pager"');
+  });
+
+  it("rejects a duplicate name", async () => {
+    setupDb([makePolicy()]);
+
+    const result = await executeGraphQL(
+      `mutation {
+        createEscalationPolicy(name: "urgent", steps: [{ channel: "slack", delaySeconds: 0 }]) { id }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toBe("Escalation policy already exists: urgent");
+  });
+});
+
+describe("updateEscalationPolicy mutation", () => {
+  it("can stop a policy being the default", async () => {
+    setupDb(
+      [makePolicy({ isDefault: true })],  // policy lookup
+      [makePolicy({ isDefault: false })], // update returning
+    );
+
+    const result = await executeGraphQL(
+      `mutation { updateEscalationPolicy(id: "urgent", isDefault: false) { isDefault } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.updateEscalationPolicy).toEqual({ isDefault: false });
+  });
+});
+
+describe("deleteEscalationPolicy mutation", () => {
+  it("refuses while a priority still routes to the policy", async () => {
+    setupDb(
+      [makePolicy()],    // policy lookup
+      [{ priority: 5 }], // routes
+    );
+
+    const result = await executeGraphQL(
+      `mutation { deleteEscalationPolicy(id: "urgent") }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toContain("still routed for P5");
+  });
+});
+
+describe("setPriorityRoute mutation", () => {
+  it("rejects an out-of-range priority", async () => {
+    const result = await executeGraphQL(
+      `mutation { setPriorityRoute(priority: 9, policy: "urgent") { priority } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toBe("priority must be between 1 and 5");
+  });
+
+  it("updates an existing route", async () => {
+    setupDb(
+      [makePolicy()],                                  // policy lookup
+      [{ id: "route-1", priority: 5, policyId: "old" }], // existing route
+      [{ id: "route-1", priority: 5, policyId: makePolicy().id }], // update returning
+      [makePolicy()],                                  // policy field resolve
+    );
+
+    const result = await executeGraphQL(
+      `mutation { setPriorityRoute(priority: 5, policy: "urgent") { priority policy { name } } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.setPriorityRoute).toEqual({
+      priority: 5,
+      policy: { name: "urgent" },
+    });
+  });
+});
@@ -0,0 +1,250 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const { mockChain, setupDb } = vi.hoisted(() => {
+  let dbResults: any[][] = [];
+  let dbCallIndex = 0;
+
+  const chain: any = {};
+  const methods = [
+    "select", "from", "where", "update", "set", "insert",
+    "values", "delete", "returning", "orderBy", "limit",
+    "innerJoin", "leftJoin", "selectDistinctOn",
+  ];
+  for (const m of methods) {
+    chain[m] = (..._args: any[]) => chain;
+  }
+  chain.then = (resolve: any, reject?: any) => {
+    const result = dbResults[dbCallIndex] ?? [];
+    dbCallIndex++;
+    return Promise.resolve(result).then(resolve, reject);
+  };
+
+  function setupDb(...results: any[][]) {
+    dbResults = results;
+    dbCallIndex = 0;
+  }
+
+  return { mockChain: chain, setupDb };
+});
+
+vi.mock("@/db", () => ({ db: mockChain }));
+
+vi.mock("@/db/schema", () => {
+  const table = (name: string) =>
+    new Proxy({}, { get: (_, p) => `${name}.${String(p)}` });
+  return {
+    apiKeys: table("apiKeys"),
+    users: table("users"),
+    contactMethods: table("contactMethods"),
+    webhookSubscriptions: table("webhookSubscriptions"),
+    notifications: table("notifications"),
+    responses: table("responses"),
+    reactions: table("reactions"),
+    deliveries: table("deliveries"),
+    agentSessions: table("agentSessions"),
+    escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
+    priorityRoutes: table("priorityRoutes"),
+    slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
+  };
+});
+
+vi.mock("drizzle-orm", () => ({
+  eq: () => {},
+  and: () => {},
+  or: () => {},
+  desc: () => {},
+  asc: () => {},
+  inArray: () => {},
+  isNull: () => {},
+  lte: () => {},
+  gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
+  sql: () => {},
+}));
+
+vi.mock("@/inngest/client", () => ({
+  inngest: { send: () => Promise.resolve() },
+}));
+
+vi.mock("@/channels/deliver", () => ({
+  deliverNotification: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/slack", () => ({
+  sendSlackDM: () => Promise.resolve({ ts: "ts-1", channel: "C123" }),
+  updateSlackMessage: () => Promise.resolve(),
+  addSlackReaction: () => Promise.resolve(),
+  getSlackForTeam: () => Promise.resolve({}),
+}));
+
+vi.mock("@/channels/twilio", () => ({
+  sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: () => Promise.resolve({ sid: "SM124" }),
+}));
+
+vi.mock("jose", () => ({
+  createRemoteJWKSet: () => () => {},
+  jwtVerify: async () => ({ payload: {} }),
+}));
+
+vi.mock("@/auth/workos", () => ({
+  workos: { userManagement: { getUser: async () => ({}) } },
+  WORKOS_CLIENT_ID: "test_client_id",
+}));
+
+import { executeGraphQL } from "@/schema/execute";
+
+function makeNotification(overrides: Record<string, any> = {}) {
+  return {
+    id: "n1",
+    shortCode: "ABC",
+    userId: "user-1",
+    sessionId: "s1",
+    parentId: null,
+    message: "Deploy?",
+    priority: 3,
+    context: null,
+    tags: [],
+    options: null,
+    status: "pending",
+    currentEscalationStep: null,
+    policyId: null,
+    snoozedUntil: null,
+    expiresAt: null,
+    defaultOption: null,
+    retractReason: null,
+    editedAt: null,
+    dedupKey: null,
+    repeatCount: 1,
+    lastRepeatedAt: null,
+    createdAt: new Date("2025-01-01T00:00:00Z"),
+    updatedAt: new Date("2025-01-01T00:00:00Z"),
+    ...overrides,
+  };
+}
+
+function makeSession(overrides: Record<string, any> = {}) {
+  return {
+    id: "s1",
+    userId: "user-1",
+    sessionKey: "key-1",
+    workspace: "/repo/b",
+    pollHeartbeatAt: null,
+    createdAt: new Date("2025-01-01T00:00:00Z"),
+    ...overrides,
+  };
+}
+
+describe("inbox query", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(`query { inbox { sessionKey } }`, {
+      userId: null,
+    });
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("groups open questions by session, ordered by workspace", async () => {
+    const a1 = makeNotification({ id: "a1", shortCode: "A1", sessionId: "s2", createdAt: new Date("2025-01-01T01:00:00Z") });
+    const b1 = makeNotification({ id: "b1", shortCode: "B1" });
+    const b2 = makeNotification({ id: "b2", shortCode: "B2", createdAt: new Date("2025-01-01T02:00:00Z") });
+    const loose = makeNotification({ id: "x1", shortCode: "X1", sessionId: null });
+    const answered = makeNotification({ id: "b3", shortCode: "B3", status: "responded", createdAt: new Date("2025-01-01T03:00:00Z") });
+
+    setupDb(
+      [b1, a1, b2, loose],                          // active notifications
+      [
+        makeSession(),
+        makeSession({ id: "s2", sessionKey: "key-2", workspace: "/repo/a", pollHeartbeatAt: new Date() }),
+      ],                                            // sessions
+      [answered, a1],                               // latest per session
+    );
+
+    const result = await executeGraphQL(
+      `query {
+        inbox {
+          sessionKey workspace polling pendingCount oldestPendingAt
+          lastMessage { shortCode }
+          notifications { shortCode }
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.inbox).toEqual([
+      {
+        sessionKey: "key-2",
+        workspace: "/repo/a",
+        polling: true,
+        pendingCount: 1,
+        oldestPendingAt: "2025-01-01T01:00:00.000Z",
+        lastMessage: { shortCode: "A1" },
+        notifications: [{ shortCode: "A1" }],
+      },
+      {
+        sessionKey: "key-1",
+        workspace: "/repo/b",
+        polling: false,
+        pendingCount: 2,
+        oldestPendingAt: "2025-01-01T00:00:00.000Z",
+        lastMessage: { shortCode: "B3" },
+        notifications: [{ shortCode: "B1" }, { shortCode: "B2" }],
+      },
+      {
+        sessionKey: null,
+        workspace: null,
+        polling: false,
+        pendingCount: 1,
+        oldestPendingAt: "2025-01-01T00:00:00.000Z",
+        lastMessage: { shortCode: "X1" },
+        notifications: [{ shortCode: "X1" }],
+      },
+    ]);
+  });
+
+  it("treats a stale heartbeat as not polling", async () => {
+    setupDb(
+      [makeNotification()],
+      [makeSession({ pollHeartbeatAt: new Date(Date.now() - 10 * 60 * 1000) })],
+      [makeNotification()],
+    );
+
+    const result = await executeGraphQL(`query { inbox { polling } }`, {
+      userId: "user-1",
+    });
+    expect(result.data?.inbox).toEqual([{ polling: false }]);
+  });
+});
+
+describe("reportPolling mutation", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(
+      `mutation { reportPolling(sessionKey: "key-1", active: true) }`,
+      { userId: null },
+    );
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("records a heartbeat for an existing session", async () => {
+    setupDb([makeSession()], []);
+
+    const result = await executeGraphQL(
+      `mutation { reportPolling(sessionKey: "key-1", active: true) }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.reportPolling).toBe(true);
+  });
+});
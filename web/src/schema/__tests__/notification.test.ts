@@ -1,13 +1,13 @@
 import { describe, it, expect, vi, beforeEach } from "vitest";
 
-const { mockChain, setupDb } = vi.hoisted(() => {
+const { mockChain, setupDb, mockEditSlack } = vi.hoisted(() => {
   let dbResults: any[][] = [];
   let dbCallIndex = 0;
 
   const chain: any = {};
   const methods = [
     "select", "from", "where", "update", "set", "insert",
-    "values", "delete", "returning", "orderBy", "limit",
+    "values", "delete", "returning", "orderBy", "limit", "leftJoin",
   ];
   for (const m of methods) {
     chain[m] = (..._args: any[]) => chain;
@@ -23,7 +23,9 @@ const { mockChain, setupDb } = vi.hoisted(() => {
     dbCallIndex = 0;
   }
 
-  return { mockChain: chain, setupDb };
+  const mockEditSlack = { fn: async (..._args: any[]) => {} };
+
+  return { mockChain: chain, setupDb, mockEditSlack };
 });
 
 vi.mock("@/db", () => ({ db: mockChain }));
@@ -34,13 +36,17 @@ vi.mock("@/db/schema", () => {
   return {
     notifications: table("notifications"),
     responses: table("responses"),
+    reactions: table("reactions"),
     deliveries: table("deliveries"),
     agentSessions: table("agentSessions"),
     escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
     priorityRoutes: table("priorityRoutes"),
     users: table("users"),
+    contactMethods: table("contactMethods"),
     apiKeys: table("apiKeys"),
     slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
   };
 });
 
@@ -54,6 +60,9 @@ vi.mock("drizzle-orm", () => ({
   isNull: () => {},
   lte: () => {},
   gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
   sql: () => {},
 }));
 
@@ -70,10 +79,15 @@ vi.mock("@/channels/slack", () => ({
   updateSlackMessage: () => Promise.resolve(),
   addSlackReaction: () => Promise.resolve(),
   getSlackForTeam: () => Promise.resolve({}),
+  editSlackNotification: (...args: any[]) => mockEditSlack.fn(...args),
+  markSlackMessageRetracted: () => Promise.resolve(),
+  postSlackProgress: () => Promise.resolve({ ts: "ts-2", channel: "C123" }),
+  updateSlackProgress: () => Promise.resolve(),
 }));
 
 vi.mock("@/channels/twilio", () => ({
   sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: () => Promise.resolve({ sid: "SM124" }),
 }));
 
 vi.mock("jose", () => ({
@@ -95,6 +109,7 @@ function makeNotification(overrides: Record<string, any> = {}) {
     shortCode: "ABC",
     userId: "user-1",
     sessionId: null,
+    parentId: null,
     message: "Test notification",
     priority: 3,
     context: null,
@@ -104,6 +119,13 @@ function makeNotification(overrides: Record<string, any> = {}) {
     currentEscalationStep: null,
     policyId: null,
     snoozedUntil: null,
+    expiresAt: null,
+    defaultOption: null,
+    retractReason: null,
+    editedAt: null,
+    dedupKey: null,
+    repeatCount: 1,
+    lastRepeatedAt: null,
     createdAt: new Date("2025-01-01T00:00:00Z"),
     updatedAt: new Date("2025-01-01T00:00:00Z"),
     ...overrides,
@@ -175,6 +197,165 @@ describe("createNotification", () => {
     expect(result.errors).toBeUndefined();
     expect(result.data?.createNotification.id).toBe("notif-1");
   });
+
+  it("stores a deadline and default option", async () => {
+    const created = makeNotification({
+      expiresAt: new Date("2025-01-01T00:20:00Z"),
+      defaultOption: "Skip",
+    });
+
+    setupDb(
+      [],         // priorityRoutes lookup
+      [],         // default escalation policy lookup
+      [created],  // insert notification returning
+      [created],  // re-fetch after delivery
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        createNotification(
+          message: "Deploy?", options: ["Deploy", "Skip"],
+          expiresInSeconds: 1200, defaultOption: "Skip"
+        ) {
+          expiresAt defaultOption
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.createNotification).toMatchObject({
+      expiresAt: "2025-01-01T00:20:00.000Z",
+      defaultOption: "Skip",
+    });
+  });
+
+  it("links a follow-up to its parent in the parent's session", async () => {
+    const parent = makeNotification({ id: "parent-1", sessionId: "session-1" });
+    const created = makeNotification({
+      id: "notif-2",
+      sessionId: "session-1",
+      parentId: "parent-1",
+    });
+
+    setupDb(
+      [parent],   // parent lookup by short code
+      [],         // priorityRoutes lookup
+      [],         // default escalation policy lookup
+      [created],  // insert notification returning
+      [created],  // re-fetch
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        createNotification(message: "Follow-up", replyTo: "ABC", sessionKey: "other") {
+          id parentId sessionId
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.createNotification).toMatchObject({
+      parentId: "parent-1",
+      sessionId: "session-1",
+    });
+  });
+
+  it("collapses a repeat with the same dedup key into a counter", async () => {
+    const existing = makeNotification({ dedupKey: "build-failed" });
+    const repeated = makeNotification({
+      dedupKey: "build-failed",
+      repeatCount: 2,
+      lastRepeatedAt: new Date("2025-01-01T00:05:00Z"),
+    });
+
+    setupDb(
+      [existing], // open notification with the same dedup key
+      [repeated], // bump repeat counter returning
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        createNotification(message: "Build failed", dedupKey: "build-failed") {
+          id repeatCount lastRepeatedAt
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.createNotification).toMatchObject({
+      id: "notif-1",
+      repeatCount: 2,
+      lastRepeatedAt: "2025-01-01T00:05:00.000Z",
+    });
+  });
+
+  it("creates a new notification when no duplicate is open", async () => {
+    const created = makeNotification({ dedupKey: "build-failed" });
+
+    setupDb(
+      [],         // dedup lookup (none open)
+      [],         // priorityRoutes lookup
+      [],         // default escalation policy lookup
+      [created],  // insert notification returning
+      [created],  // re-fetch
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        createNotification(message: "Build failed", dedupKey: "build-failed") {
+          id repeatCount
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.createNotification).toMatchObject({ repeatCount: 1 });
+  });
+
+  it("rejects a reply to an unknown notification", async () => {
+    setupDb([]);
+
+    const result = await executeGraphQL(
+      `mutation { createNotification(message: "x", replyTo: "ZZZ") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toBe("Notification not found: ZZZ");
+  });
+
+  it("rejects a default option without a deadline", async () => {
+    const result = await executeGraphQL(
+      `mutation { createNotification(message: "x", defaultOption: "Skip") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toBe(
+      "defaultOption requires expiresInSeconds",
+    );
+  });
+
+  it("rejects a default option that isn't one of the options", async () => {
+    const result = await executeGraphQL(
+      `mutation {
+        createNotification(
+          message: "x", options: ["Deploy", "Wait"], defaultOption: "Skip",
+          expiresInSeconds: 600
+        ) { id }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toBe(
+      'defaultOption "Skip" is not one of the options',
+    );
+  });
 });
 
 describe("respondToNotification", () => {
@@ -233,6 +414,181 @@ describe("respondToNotification", () => {
   });
 });
 
+describe("updateNotification", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("edits the message and marks it edited", async () => {
+    const notification = makeNotification({ status: "delivered" });
+    const updated = makeNotification({
+      status: "delivered",
+      message: "Tests pass now, deploy?",
+      editedAt: new Date("2025-01-01T00:05:00Z"),
+    });
+
+    setupDb(
+      [notification], // findNotificationByIdOrShortCode
+      [],             // responses check
+      [updated],      // update returning
+      [],             // slack delivery lookup
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        updateNotification(id: "ABC", message: "Tests pass now, deploy?") {
+          message editedAt
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.updateNotification).toMatchObject({
+      message: "Tests pass now, deploy?",
+      editedAt: "2025-01-01T00:05:00.000Z",
+    });
+  });
+
+  it("re-renders the Slack message for the user's workspace and keeps the reply prefix", async () => {
+    const notification = makeNotification({ status: "delivered", parentId: "n-0" });
+    const updated = makeNotification({
+      status: "delivered",
+      parentId: "n-0",
+      message: "Deploy now?",
+    });
+    mockEditSlack.fn = vi.fn().mockResolvedValue(undefined);
+
+    setupDb(
+      [notification], // findNotificationByIdOrShortCode
+      [],             // responses check
+      [updated],      // update returning
+      [{ externalId: "ts-1", metadata: { channel: "C123" } }], // slack delivery
+      [{ slackTeamId: "T1" }], // user's slack team
+      [{ shortCode: "PAR" }],  // parent short code
+    );
+
+    const result = await executeGraphQL(
+      `mutation { updateNotification(id: "ABC", message: "Deploy now?") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(mockEditSlack.fn).toHaveBeenCalledWith(
+      "C123",
+      "ts-1",
+      expect.objectContaining({
+        teamId: "T1",
+        replyToShortCode: "PAR",
+        message: "Deploy now?",
+      }),
+    );
+  });
+
+  it("refuses to edit a notification that has a response", async () => {
+    setupDb(
+      [makeNotification({ status: "responded" })],
+      [{ id: "resp-1" }], // responses check
+    );
+
+    const result = await executeGraphQL(
+      `mutation { updateNotification(id: "ABC", message: "x") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors![0].message).toBe(
+      "Cannot update a notification that has a response",
+    );
+  });
+
+  it("refuses new options that drop the default option", async () => {
+    setupDb([makeNotification({ status: "delivered", defaultOption: "Skip" })]);
+
+    const result = await executeGraphQL(
+      `mutation { updateNotification(id: "ABC", options: ["Deploy", "Wait"]) { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors![0].message).toBe(
+      'The new options must keep the default option "Skip"',
+    );
+  });
+
+  it("refuses to edit a retracted notification", async () => {
+    setupDb([makeNotification({ status: "retracted" })]);
+
+    const result = await executeGraphQL(
+      `mutation { updateNotification(id: "ABC", message: "x") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeDefined();
+    expect(result.errors![0].message).toBe(
+      "Cannot update a retracted notification",
+    );
+  });
+});
+
+describe("retractNotification", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("marks the notification retracted with a reason", async () => {
+    const notification = makeNotification({ status: "delivered" });
+    const retracted = makeNotification({
+      status: "retracted",
+      retractReason: "tests pass now",
+    });
+
+    setupDb(
+      [notification], // findNotificationByIdOrShortCode
+      [retracted],    // update returning
+      [],             // slack delivery lookup
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        retractNotification(id: "ABC", reason: "tests pass now") {
+          status retractReason
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.retractNotification).toMatchObject({
+      status: "retracted",
+      retractReason: "tests pass now",
+    });
+  });
+
+  it("refuses to retract a notification that was already answered", async () => {
+    setupDb([makeNotification({ status: "responded" })]);
+
+    const result = await executeGraphQL(
+      `mutation { retractNotification(id: "ABC") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors![0].message).toBe(
+      "Cannot retract a responded notification",
+    );
+  });
+
+  it("returns null for nonexistent notification", async () => {
+    setupDb([]);
+
+    const result = await executeGraphQL(
+      `mutation { retractNotification(id: "gone") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.retractNotification).toBeNull();
+  });
+});
+
 describe("archiveNotification", () => {
   beforeEach(() => {
     setupDb();
@@ -278,7 +634,11 @@ describe("archiveAllNotifications", () => {
   });
 
   it("returns count of archived notifications", async () => {
-    setupDb([{ id: "a" }, { id: "b" }, { id: "c" }]);
+    setupDb([
+      makeNotification({ id: "a", status: "archived" }),
+      makeNotification({ id: "b", status: "archived" }),
+      makeNotification({ id: "c", status: "archived" }),
+    ]);
 
     const result = await executeGraphQL(
       `mutation { archiveAllNotifications }`,
@@ -300,6 +660,272 @@ describe("archiveAllNotifications", () => {
   });
 });
 
+describe("notificationPage", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(
+      `query { notificationPage { nextCursor } }`,
+      { userId: null },
+    );
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("rejects unknown statuses and sorts", async () => {
+    let result = await executeGraphQL(
+      `query { notificationPage(statuses: ["pending", "lost"]) { nextCursor } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain('Unknown status "lost"');
+
+    result = await executeGraphQL(
+      `query { notificationPage(sort: "random") { nextCursor } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain('Unknown sort "random"');
+  });
+
+  it("rejects out-of-range priorities and limits", async () => {
+    let result = await executeGraphQL(
+      `query { notificationPage(minPriority: 6) { nextCursor } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe("Priority filters must be between 1 and 5");
+
+    result = await executeGraphQL(
+      `query { notificationPage(limit: 500) { nextCursor } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe("limit must be between 1 and 200");
+  });
+
+  it("returns a cursor only when another page exists", async () => {
+    setupDb([
+      makeNotification({ id: "n1" }),
+      makeNotification({ id: "n2" }),
+      makeNotification({ id: "n3" }),
+    ]);
+
+    const result = await executeGraphQL(
+      `query { notificationPage(limit: 2) { notifications { id } nextCursor } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    const page = result.data?.notificationPage as any;
+    expect(page.notifications.map((n: any) => n.id)).toEqual(["n1", "n2"]);
+    expect(page.nextCursor).toEqual(expect.any(String));
+
+    setupDb([makeNotification({ id: "n3" })]);
+    const next = await executeGraphQL(
+      `query { notificationPage(limit: 2, cursor: ${JSON.stringify(page.nextCursor)}) { notifications { id } nextCursor } }`,
+      { userId: "user-1" },
+    );
+    expect(next.errors).toBeUndefined();
+    expect((next.data?.notificationPage as any).nextCursor).toBeNull();
+  });
+
+  it("rejects a cursor from a different sort", async () => {
+    setupDb([makeNotification({ id: "n1" }), makeNotification({ id: "n2" })]);
+    const first = await executeGraphQL(
+      `query { notificationPage(limit: 1) { nextCursor } }`,
+      { userId: "user-1" },
+    );
+    const cursor = (first.data?.notificationPage as any).nextCursor;
+
+    const result = await executeGraphQL(
+      `query { notificationPage(sort: "priority", cursor: ${JSON.stringify(cursor)}) { nextCursor } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain("Invalid cursor");
+  });
+});
+
+describe("notificationThread", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("returns null for an unknown notification", async () => {
+    setupDb([]);
+    const result = await executeGraphQL(
+      `query { notificationThread(id: "ZZZ") { sessionKey } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.notificationThread).toBeNull();
+  });
+
+  it("returns the whole session the notification belongs to", async () => {
+    setupDb(
+      [makeNotification({ id: "n2", shortCode: "BBB", sessionId: "sess-1" })],
+      [{ id: "sess-1", sessionKey: "key-1", workspace: "/repo", createdAt: new Date("2025-01-01T00:00:00Z") }],
+      [
+        makeNotification({ id: "n1", shortCode: "AAA", sessionId: "sess-1" }),
+        makeNotification({ id: "n2", shortCode: "BBB", sessionId: "sess-1" }),
+      ],
+    );
+
+    const result = await executeGraphQL(
+      `query { notificationThread(id: "BBB") { sessionKey workspace notifications { shortCode } } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.notificationThread).toEqual({
+      sessionKey: "key-1",
+      workspace: "/repo",
+      notifications: [{ shortCode: "AAA" }, { shortCode: "BBB" }],
+    });
+  });
+
+  it("walks up to the root of a sessionless reply thread", async () => {
+    const at = (minute: number) => new Date(Date.UTC(2025, 0, 1, 0, minute));
+    const root = makeNotification({ id: "n1", shortCode: "AAA", createdAt: at(0) });
+    const reply = makeNotification({ id: "n2", shortCode: "BBB", parentId: "n1", createdAt: at(1) });
+    const sibling = makeNotification({ id: "n4", shortCode: "DDD", parentId: "n1", createdAt: at(3) });
+    const asked = makeNotification({ id: "n3", shortCode: "CCC", parentId: "n2", createdAt: at(2) });
+    setupDb(
+      [asked],           // findNotificationByIdOrShortCode
+      [reply],           // parent of CCC
+      [root],            // parent of BBB
+      [reply, sibling],  // children of AAA
+      [asked],           // children of BBB and DDD
+      [],                // children of CCC
+    );
+
+    const result = await executeGraphQL(
+      `query { notificationThread(id: "CCC") { sessionKey notifications { shortCode } } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.notificationThread).toEqual({
+      sessionKey: "AAA",
+      notifications: [
+        { shortCode: "AAA" },
+        { shortCode: "BBB" },
+        { shortCode: "CCC" },
+        { shortCode: "DDD" },
+      ],
+    });
+  });
+});
+
+describe("sessionHistories", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("rejects an invalid since", async () => {
+    const result = await executeGraphQL(
+      `query { sessionHistories(since: "last week") { sessionKey } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe("Invalid timestamp: last week");
+  });
+
+  it("rejects an invalid page cursor", async () => {
+    const result = await executeGraphQL(
+      `query { sessionHistories(since: "2025-01-01T00:00:00Z", before: "nope") { sessionKey } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe("Invalid session id: nope");
+  });
+
+  it("returns each session with its notifications and responses", async () => {
+    const session = {
+      id: "sess-1",
+      sessionKey: "key-1",
+      workspace: "/repo",
+      createdAt: new Date("2025-01-01T00:00:00Z"),
+    };
+    setupDb(
+      [session],
+      [
+        makeNotification({ id: "n1", shortCode: "AAA", sessionId: "sess-1" }),
+        makeNotification({ id: "n2", shortCode: "BBB", sessionId: "other" }),
+      ],
+      [
+        {
+          id: "r1",
+          notificationId: "n1",
+          channel: "slack",
+          text: "yes",
+          selectedOption: null,
+          auto: false,
+          responderId: "user-1",
+          createdAt: new Date("2025-01-01T00:01:00Z"),
+        },
+      ],
+      [{ id: "user-1", name: null, email: "dev@example.com" }],
+      [{ responseId: "r1", emoji: "thumbsup", createdAt: new Date("2025-01-01T00:02:00Z") }],
+    );
+
+    const result = await executeGraphQL(
+      `query {
+        sessionHistories(since: "2025-01-01T00:00:00Z") {
+          sessionKey workspace
+          notifications {
+            shortCode
+            responses { text responder reactions { emoji } }
+          }
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.sessionHistories).toEqual([
+      {
+        sessionKey: "key-1",
+        workspace: "/repo",
+        notifications: [
+          {
+            shortCode: "AAA",
+            responses: [
+              {
+                text: "yes",
+                responder: "dev@example.com",
+                reactions: [{ emoji: "thumbsup" }],
+              },
+            ],
+          },
+        ],
+      },
+    ]);
+  });
+});
+
+describe("threadHistories", () => {
+  it("groups notifications outside any session by reply thread", async () => {
+    setupDb([
+      makeNotification({ id: "n1", shortCode: "AAA", createdAt: new Date("2025-01-01T00:00:00Z") }),
+      makeNotification({ id: "n2", shortCode: "BBB", createdAt: new Date("2025-01-01T00:01:00Z") }),
+      makeNotification({ id: "n3", shortCode: "CCC", parentId: "n1", createdAt: new Date("2025-01-01T00:02:00Z") }),
+      makeNotification({ id: "n4", shortCode: "DDD", parentId: "n3", createdAt: new Date("2025-01-01T00:03:00Z") }),
+    ]);
+
+    const result = await executeGraphQL(
+      `query {
+        threadHistories(since: "2025-01-01T00:00:00Z") {
+          sessionKey notifications { shortCode }
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.threadHistories).toEqual([
+      {
+        sessionKey: "AAA",
+        notifications: [{ shortCode: "AAA" }, { shortCode: "CCC" }, { shortCode: "DDD" }],
+      },
+      { sessionKey: "BBB", notifications: [{ shortCode: "BBB" }] },
+    ]);
+  });
+});
+
 describe("activeFeed", () => {
   beforeEach(() => {
     setupDb();
@@ -309,7 +935,10 @@ describe("activeFeed", () => {
     const pending = makeNotification({ id: "n1", status: "pending" });
     const delivered = makeNotification({ id: "n2", status: "delivered" });
 
-    setupDb([pending, delivered]);
+    setupDb([
+      { notification: pending, session: null },
+      { notification: delivered, session: null },
+    ]);
 
     const result = await executeGraphQL(
       `query { activeFeed { id status } }`,
@@ -320,6 +949,30 @@ describe("activeFeed", () => {
     expect(result.data?.activeFeed).toHaveLength(2);
   });
 
+  it("includes the session key and workspace without a query per row", async () => {
+    setupDb([
+      {
+        notification: makeNotification({ id: "n1", sessionId: "sess-1" }),
+        session: { sessionKey: "key-1", workspace: "/repo" },
+      },
+      {
+        notification: makeNotification({ id: "n2", sessionId: null }),
+        session: null,
+      },
+    ]);
+
+    const result = await executeGraphQL(
+      `query { activeFeed { id sessionKey workspace } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.activeFeed).toEqual([
+      { id: "n1", sessionKey: "key-1", workspace: "/repo" },
+      { id: "n2", sessionKey: null, workspace: null },
+    ]);
+  });
+
   it("requires authentication", async () => {
     const result = await executeGraphQL(
       `query { activeFeed { id } }`,
@@ -331,6 +984,41 @@ describe("activeFeed", () => {
   });
 });
 
+describe("tagNotifications", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires at least one tag", async () => {
+    const result = await executeGraphQL(
+      `mutation { tagNotifications(ids: ["notif-1"], tags: [" "]) { id } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors?.[0].message).toBe("At least one tag is required");
+  });
+
+  it("merges new tags into each notification's existing tags", async () => {
+    setupDb(
+      [
+        makeNotification({ id: "n1", tags: ["deploy"] }),
+        makeNotification({ id: "n2", tags: null }),
+      ],
+      [makeNotification({ id: "n1", tags: ["deploy", "later"] })],
+      [makeNotification({ id: "n2", tags: ["later"] })],
+    );
+
+    const result = await executeGraphQL(
+      `mutation { tagNotifications(ids: ["n1", "n2"], tags: ["later", "deploy"]) { id tags } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.tagNotifications).toEqual([
+      { id: "n1", tags: ["deploy", "later"] },
+      { id: "n2", tags: ["later"] },
+    ]);
+  });
+});
+
 describe("snoozeNotification", () => {
   beforeEach(() => {
     setupDb();
@@ -359,4 +1047,107 @@ describe("snoozeNotification", () => {
     expect(result.errors).toBeUndefined();
     expect(result.data?.snoozeNotification.snoozedUntil).toBeTruthy();
   });
+
+  it("snoozes until a given time", async () => {
+    const until = new Date(Date.now() + 2 * 60 * 60_000);
+    setupDb(
+      [makeNotification()],
+      [makeNotification({ snoozedUntil: until })],
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        snoozeNotification(id: "notif-1", until: "${until.toISOString()}") {
+          snoozedUntil
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.snoozeNotification.snoozedUntil).toBe(until.toISOString());
+  });
+
+  it("rejects a time in the past", async () => {
+    const result = await executeGraphQL(
+      `mutation {
+        snoozeNotification(id: "notif-1", until: "2020-01-01T00:00:00Z") { id }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors?.[0].message).toBe("Snooze must end in the future");
+  });
+
+  it("requires minutes or until", async () => {
+    const result = await executeGraphQL(
+      `mutation { snoozeNotification(id: "notif-1") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors?.[0].message).toBe("Pass minutes or until");
+  });
+
+  it("caps snoozes at 30 days", async () => {
+    const result = await executeGraphQL(
+      `mutation { snoozeNotification(id: "notif-1", minutes: 50000) { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors?.[0].message).toBe("Snooze can be at most 30 days");
+  });
+});
+
+describe("unsnoozeNotification", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("clears snoozedUntil", async () => {
+    setupDb(
+      [makeNotification({ snoozedUntil: new Date(Date.now() + 60_000) })],
+      [makeNotification()],
+    );
+
+    const result = await executeGraphQL(
+      `mutation { unsnoozeNotification(id: "notif-1") { id snoozedUntil } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.unsnoozeNotification).toEqual({
+      id: "notif-1",
+      snoozedUntil: null,
+    });
+  });
+
+  it("returns null for an unknown notification", async () => {
+    setupDb([]);
+
+    const result = await executeGraphQL(
+      `mutation { unsnoozeNotification(id: "nope") { id } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.data?.unsnoozeNotification).toBeNull();
+  });
+});
+
+describe("snoozedNotifications", () => {
+  it("lists snoozed notifications", async () => {
+    const until = new Date(Date.now() + 60 * 60_000);
+    setupDb([
+      { notification: makeNotification({ snoozedUntil: until }), session: null },
+    ]);
+
+    const result = await executeGraphQL(
+      `query { snoozedNotifications { id snoozedUntil } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.snoozedNotifications).toEqual([
+      { id: "notif-1", snoozedUntil: until.toISOString() },
+    ]);
+  });
 });
@@ -0,0 +1,217 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const { mockChain, setupDb, calls } = vi.hoisted(() => {
+  let dbResults: any[][] = [];
+  let dbCallIndex = 0;
+  const calls: Record<string, any[][]> = {};
+
+  const chain: any = {};
+  const methods = [
+    "select", "from", "where", "update", "set", "insert", "values",
+    "delete", "returning", "orderBy", "limit", "innerJoin", "leftJoin",
+    "onConflictDoUpdate",
+  ];
+  for (const m of methods) {
+    chain[m] = (...args: any[]) => {
+      (calls[m] ??= []).push(args);
+      return chain;
+    };
+  }
+  chain.then = (resolve: any, reject?: any) => {
+    const result = dbResults[dbCallIndex] ?? [];
+    dbCallIndex++;
+    return Promise.resolve(result).then(resolve, reject);
+  };
+
+  function setupDb(...results: any[][]) {
+    dbResults = results;
+    dbCallIndex = 0;
+    for (const m of Object.keys(calls)) delete calls[m];
+  }
+
+  return { mockChain: chain, setupDb, calls };
+});
+
+vi.mock("@/db", () => ({ db: mockChain }));
+
+vi.mock("@/db/schema", () => {
+  const table = (name: string) =>
+    new Proxy({}, { get: (_, p) => `${name}.${String(p)}` });
+  return {
+    notifications: table("notifications"),
+    responses: table("responses"),
+    reactions: table("reactions"),
+    deliveries: table("deliveries"),
+    agentSessions: table("agentSessions"),
+    escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
+    priorityRoutes: table("priorityRoutes"),
+    users: table("users"),
+    contactMethods: table("contactMethods"),
+    apiKeys: table("apiKeys"),
+    slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
+    webhookSubscriptions: table("webhookSubscriptions"),
+  };
+});
+
+vi.mock("drizzle-orm", () => ({
+  eq: () => {},
+  and: () => {},
+  or: () => {},
+  desc: () => {},
+  asc: () => {},
+  inArray: () => {},
+  isNull: () => {},
+  lte: () => {},
+  gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
+  sql: () => {},
+}));
+
+vi.mock("@/inngest/client", () => ({
+  inngest: { send: () => Promise.resolve() },
+}));
+
+vi.mock("@/channels/deliver", () => ({
+  deliverNotification: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/slack", () => ({
+  sendSlackDM: () => Promise.resolve({ ts: "ts-1", channel: "C123" }),
+  updateSlackMessage: () => Promise.resolve(),
+  addSlackReaction: () => Promise.resolve(),
+  getSlackForTeam: () => Promise.resolve({}),
+  editSlackNotification: () => Promise.resolve(),
+  markSlackMessageRetracted: () => Promise.resolve(),
+  postSlackProgress: () => Promise.resolve({ ts: "ts-2", channel: "C123" }),
+  updateSlackProgress: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/twilio", () => ({
+  sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: () => Promise.resolve({ sid: "SM124" }),
+}));
+
+vi.mock("jose", () => ({
+  createRemoteJWKSet: () => () => {},
+  jwtVerify: async () => ({ payload: {} }),
+}));
+
+vi.mock("@/auth/workos", () => ({
+  workos: { userManagement: { getUser: async () => ({}) } },
+  WORKOS_CLIENT_ID: "test_client_id",
+}));
+
+import { executeGraphQL } from "@/schema/execute";
+
+const session = { id: "sess-1", sessionKey: "key-1", workspace: "/repo", slackThreadTs: null };
+
+function makeProgress(overrides: Record<string, any> = {}) {
+  return {
+    id: "prog-1",
+    userId: "user-1",
+    sessionId: "sess-1",
+    key: "build",
+    message: "Compiling",
+    percent: 40,
+    slackTs: null,
+    slackChannelId: null,
+    completedAt: null,
+    createdAt: new Date("2025-01-01T00:00:00Z"),
+    updatedAt: new Date("2025-01-01T00:05:00Z"),
+    ...overrides,
+  };
+}
+
+describe("upsertProgress mutation", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(
+      `mutation { upsertProgress(sessionKey: "key-1", key: "build", message: "x") { id } }`,
+      { userId: null },
+    );
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("rejects a percent out of range", async () => {
+    const result = await executeGraphQL(
+      `mutation { upsertProgress(sessionKey: "key-1", key: "build", message: "x", percent: 120) { id } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe("percent must be between 0 and 100");
+  });
+
+  it("upserts on the session and key", async () => {
+    setupDb(
+      [session],        // session lookup
+      [makeProgress()], // insert ... on conflict returning
+      [{ id: "user-1", slackUserId: null }],
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        upsertProgress(sessionKey: "key-1", key: "build", message: "Compiling", percent: 40) {
+          key message percent sessionKey workspace
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.upsertProgress).toEqual({
+      key: "build",
+      message: "Compiling",
+      percent: 40,
+      sessionKey: "key-1",
+      workspace: "/repo",
+    });
+    const [conflict] = calls.onConflictDoUpdate[0];
+    expect(conflict.target).toEqual(["sessionProgress.sessionId", "sessionProgress.key"]);
+    expect(conflict.set).toMatchObject({ message: "Compiling", percent: 40, completedAt: null });
+  });
+
+  it("keeps the existing percent when an update leaves it out", async () => {
+    setupDb(
+      [session],
+      [makeProgress({ message: "Linking" })],
+      [{ id: "user-1", slackUserId: null }],
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        upsertProgress(sessionKey: "key-1", key: "build", message: "Linking") { message percent }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.upsertProgress).toEqual({ message: "Linking", percent: 40 });
+    const [conflict] = calls.onConflictDoUpdate[0];
+    expect(conflict.set).not.toHaveProperty("percent");
+  });
+
+  it("marks the line done", async () => {
+    setupDb(
+      [session],
+      [makeProgress({ completedAt: new Date("2025-01-01T00:10:00Z") })],
+      [{ id: "user-1", slackUserId: null }],
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        upsertProgress(sessionKey: "key-1", key: "build", message: "Built", done: true) { completedAt }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    const [conflict] = calls.onConflictDoUpdate[0];
+    expect(conflict.set.completedAt).toBeInstanceOf(Date);
+  });
+});
@@ -0,0 +1,191 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const { mockChain, setupDb } = vi.hoisted(() => {
+  let dbResults: any[][] = [];
+  let dbCallIndex = 0;
+
+  const chain: any = {};
+  const methods = [
+    "select", "from", "where", "update", "set", "insert",
+    "values", "delete", "returning", "orderBy", "limit",
+    "innerJoin", "leftJoin",
+  ];
+  for (const m of methods) {
+    chain[m] = (..._args: any[]) => chain;
+  }
+  chain.then = (resolve: any, reject?: any) => {
+    const result = dbResults[dbCallIndex] ?? [];
+    dbCallIndex++;
+    return Promise.resolve(result).then(resolve, reject);
+  };
+
+  function setupDb(...results: any[][]) {
+    dbResults = results;
+    dbCallIndex = 0;
+  }
+
+  return { mockChain: chain, setupDb };
+});
+
+vi.mock("@/db", () => ({ db: mockChain }));
+
+vi.mock("@/db/schema", () => {
+  const table = (name: string) =>
+    new Proxy({}, { get: (_, p) => `${name}.${String(p)}` });
+  return {
+    apiKeys: table("apiKeys"),
+    users: table("users"),
+    contactMethods: table("contactMethods"),
+    webhookSubscriptions: table("webhookSubscriptions"),
+    notifications: table("notifications"),
+    responses: table("responses"),
+    reactions: table("reactions"),
+    deliveries: table("deliveries"),
+    agentSessions: table("agentSessions"),
+    escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
+    priorityRoutes: table("priorityRoutes"),
+    slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
+  };
+});
+
+vi.mock("drizzle-orm", () => ({
+  eq: () => {},
+  and: () => {},
+  or: () => {},
+  desc: () => {},
+  asc: () => {},
+  inArray: () => {},
+  isNull: () => {},
+  lte: () => {},
+  gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
+  sql: () => {},
+}));
+
+vi.mock("@/inngest/client", () => ({
+  inngest: { send: () => Promise.resolve() },
+}));
+
+vi.mock("@/channels/deliver", () => ({
+  deliverNotification: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/slack", () => ({
+  sendSlackDM: () => Promise.resolve({ ts: "ts-1", channel: "C123" }),
+  updateSlackMessage: () => Promise.resolve(),
+  addSlackReaction: () => Promise.resolve(),
+  getSlackForTeam: () => Promise.resolve({}),
+}));
+
+vi.mock("@/channels/twilio", () => ({
+  sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: () => Promise.resolve({ sid: "SM124" }),
+}));
+
+vi.mock("jose", () => ({
+  createRemoteJWKSet: () => () => {},
+  jwtVerify: async () => ({ payload: {} }),
+}));
+
+vi.mock("@/auth/workos", () => ({
+  workos: { userManagement: { getUser: async () => ({}) } },
+  WORKOS_CLIENT_ID: "test_client_id",
+}));
+
+import { executeGraphQL } from "@/schema/execute";
+
+function makeRow(overrides: Record<string, any> = {}) {
+  return {
+    response: {
+      id: "resp-1",
+      notificationId: "notif-1",
+      channel: "slack",
+      text: "ship it",
+      selectedOption: null,
+      auto: false,
+      responderId: "user-1",
+      createdAt: new Date("2025-01-01T00:05:00Z"),
+    },
+    notification: {
+      id: "notif-1",
+      shortCode: "ABC",
+      message: "Deploy?",
+      priority: 4,
+      tags: ["deploy"],
+    },
+    session: { sessionKey: "sess-1", workspace: "/repo" },
+    ...overrides,
+  };
+}
+
+describe("responseEvents query", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(`query { responseEvents { id } }`, {
+      userId: null,
+    });
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("rejects an invalid cursor", async () => {
+    const result = await executeGraphQL(
+      `query { responseEvents(after: "yesterday-ish") { id } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe("Invalid timestamp: yesterday-ish");
+  });
+
+  it("rejects a resume id that isn't a response id", async () => {
+    const result = await executeGraphQL(
+      `query { responseEvents(afterId: "2025-01-01T00:00:00Z") { id } }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toBe(
+      "Invalid response id: 2025-01-01T00:00:00Z",
+    );
+  });
+
+  it("flattens responses with their notification and session", async () => {
+    setupDb([makeRow(), makeRow({ session: null })]);
+
+    const result = await executeGraphQL(
+      `query {
+        responseEvents(after: "2025-01-01T00:00:00Z") {
+          id shortCode priority tags sessionKey workspace text createdAt
+        }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.responseEvents).toEqual([
+      {
+        id: "resp-1",
+        shortCode: "ABC",
+        priority: 4,
+        tags: ["deploy"],
+        sessionKey: "sess-1",
+        workspace: "/repo",
+        text: "ship it",
+        createdAt: "2025-01-01T00:05:00.000Z",
+      },
+      {
+        id: "resp-1",
+        shortCode: "ABC",
+        priority: 4,
+        tags: ["deploy"],
+        sessionKey: null,
+        workspace: null,
+        text: "ship it",
+        createdAt: "2025-01-01T00:05:00.000Z",
+      },
+    ]);
+  });
+});
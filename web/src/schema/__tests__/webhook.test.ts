@@ -0,0 +1,245 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const { mockChain, setupDb, mockFetch } = vi.hoisted(() => {
+  let dbResults: any[][] = [];
+  let dbCallIndex = 0;
+
+  const chain: any = {};
+  const methods = [
+    "select", "from", "where", "update", "set", "insert",
+    "values", "delete", "returning", "orderBy", "limit",
+  ];
+  for (const m of methods) {
+    chain[m] = (..._args: any[]) => chain;
+  }
+  chain.then = (resolve: any, reject?: any) => {
+    const result = dbResults[dbCallIndex] ?? [];
+    dbCallIndex++;
+    return Promise.resolve(result).then(resolve, reject);
+  };
+
+  function setupDb(...results: any[][]) {
+    dbResults = results;
+    dbCallIndex = 0;
+  }
+
+  const mockFetch = { fn: async (..._args: any[]): Promise<any> => ({ ok: true, status: 200 }) };
+
+  return { mockChain: chain, setupDb, mockFetch };
+});
+
+vi.mock("@/db", () => ({ db: mockChain }));
+
+vi.mock("@/db/schema", () => {
+  const table = (name: string) =>
+    new Proxy({}, { get: (_, p) => `${name}.${String(p)}` });
+  return {
+    apiKeys: table("apiKeys"),
+    users: table("users"),
+    contactMethods: table("contactMethods"),
+    webhookSubscriptions: table("webhookSubscriptions"),
+    notifications: table("notifications"),
+    responses: table("responses"),
+    reactions: table("reactions"),
+    deliveries: table("deliveries"),
+    agentSessions: table("agentSessions"),
+    escalationPolicies: table("escalationPolicies"),
+    escalationSteps: table("escalationSteps"),
+    priorityRoutes: table("priorityRoutes"),
+    slackInstallations: table("slackInstallations"),
+    sessionProgress: table("sessionProgress"),
+  };
+});
+
+vi.mock("drizzle-orm", () => ({
+  eq: () => {},
+  and: () => {},
+  or: () => {},
+  desc: () => {},
+  asc: () => {},
+  inArray: () => {},
+  isNull: () => {},
+  lte: () => {},
+  gt: () => {},
+  gte: () => {},
+  ilike: () => {},
+  arrayContains: () => {},
+  sql: () => {},
+}));
+
+vi.mock("@/inngest/client", () => ({
+  inngest: { send: () => Promise.resolve() },
+}));
+
+vi.mock("@/channels/deliver", () => ({
+  deliverNotification: () => Promise.resolve(),
+}));
+
+vi.mock("@/channels/slack", () => ({
+  sendSlackDM: () => Promise.resolve({ ts: "ts-1", channel: "C123" }),
+  updateSlackMessage: () => Promise.resolve(),
+  addSlackReaction: () => Promise.resolve(),
+  getSlackForTeam: () => Promise.resolve({}),
+}));
+
+vi.mock("@/channels/twilio", () => ({
+  sendSMS: () => Promise.resolve({ sid: "SM123" }),
+  sendVerificationSMS: () => Promise.resolve({ sid: "SM124" }),
+}));
+
+vi.mock("dns/promises", () => ({
+  default: { lookup: async () => [{ address: "93.184.216.34", family: 4 }] },
+}));
+
+vi.mock("jose", () => ({
+  createRemoteJWKSet: () => () => {},
+  jwtVerify: async () => ({ payload: {} }),
+}));
+
+vi.mock("@/auth/workos", () => ({
+  workos: { userManagement: { getUser: async () => ({}) } },
+  WORKOS_CLIENT_ID: "test_client_id",
+}));
+
+import { executeGraphQL } from "@/schema/execute";
+
+function makeSubscription(overrides: Record<string, any> = {}) {
+  return {
+    id: "wh-1",
+    userId: "user-1",
+    url: "https://example.com/hook",
+    secret: "whsec_0123456789abcdef",
+    events: ["notification.created"],
+    lastDeliveryAt: null,
+    lastStatus: null,
+    lastError: null,
+    createdAt: new Date("2025-01-01T00:00:00Z"),
+    ...overrides,
+  };
+}
+
+describe("webhookSubscriptions query", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("requires authentication", async () => {
+    const result = await executeGraphQL(`query { webhookSubscriptions { id } }`, {
+      userId: null,
+    });
+    expect(result.errors![0].message).toBe("Unauthorized");
+  });
+
+  it("never returns the signing secret", async () => {
+    setupDb([makeSubscription()]);
+
+    const result = await executeGraphQL(
+      `query { webhookSubscriptions { id url events secret } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.webhookSubscriptions).toEqual([
+      {
+        id: "wh-1",
+        url: "https://example.com/hook",
+        events: ["notification.created"],
+        secret: null,
+      },
+    ]);
+  });
+});
+
+describe("createWebhookSubscription mutation", () => {
+  beforeEach(() => {
+    setupDb();
+  });
+
+  it("rejects unknown events", async () => {
+    const result = await executeGraphQL(
+      `mutation {
+        createWebhookSubscription(url: "https://example.com/hook", events: ["notification.exploded"]) { id }
+      }`,
+      { userId: "user-1" },
+    );
+    expect(result.errors![0].message).toContain('Unknown event "notification.exploded"');
+  });
+
+  it("rejects plain http and private hosts", async () => {
+    for (const [url, message] of [
+      ["http://example.com/hook", "Webhook URL must use https"],
+      ["https://10.0.0.5/hook", "Webhook host 10.0.0.5 is not a public address"],
+      ["https://169.254.169.254/latest", "Webhook host 169.254.169.254 is not a public address"],
+    ]) {
+      const result = await executeGraphQL(
+        `mutation {
+          createWebhookSubscription(url: "${url}", events: ["*"]) { id }
+        }`,
+        { userId: "user-1" },
+      );
+      expect(result.errors![0].message).toBe(message);
+    }
+  });
+
+  it("returns the secret once on create", async () => {
+    setupDb(
+      [],                     // existing subscriptions
+      [makeSubscription({ events: ["response.created", "notification.archived"] })],
+    );
+
+    const result = await executeGraphQL(
+      `mutation {
+        createWebhookSubscription(
+          url: "https://example.com/hook",
+          events: ["response.created", "notification.archived"]
+        ) { id events secret }
+      }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.createWebhookSubscription).toEqual({
+      id: "wh-1",
+      events: ["response.created", "notification.archived"],
+      secret: "whsec_0123456789abcdef",
+    });
+  });
+});
+
+describe("testWebhookSubscription mutation", () => {
+  beforeEach(() => {
+    setupDb();
+    vi.stubGlobal("fetch", (...args: any[]) => mockFetch.fn(...args));
+  });
+
+  it("reports a successful signed ping", async () => {
+    mockFetch.fn = vi.fn().mockResolvedValue({ ok: true, status: 204 });
+    setupDb([makeSubscription()], []);
+
+    const result = await executeGraphQL(
+      `mutation { testWebhookSubscription(id: "wh-1") { ok status error } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.data?.testWebhookSubscription).toEqual({ ok: true, status: 204, error: null });
+    const [, init] = (mockFetch.fn as any).mock.calls[0];
+    expect(init.headers["AgentDuty-Event"]).toBe("ping");
+    expect(init.headers["AgentDuty-Signature"]).toMatch(/^t=\d+,v1=[0-9a-f]{64}$/);
+  });
+
+  it("reports the endpoint's error status", async () => {
+    mockFetch.fn = vi.fn().mockResolvedValue({ ok: false, status: 500 });
+    setupDb([makeSubscription()], []);
+
+    const result = await executeGraphQL(
+      `mutation { testWebhookSubscription(id: "wh-1") { ok status error } }`,
+      { userId: "user-1" },
+    );
+
+    expect(result.data?.testWebhookSubscription).toEqual({
+      ok: false,
+      status: 500,
+      error: "Webhook returned 500",
+    });
+  });
+});
@@ -0,0 +1,570 @@
+import crypto from "crypto";
+import builder from "./builder";
+import { db } from "@/db";
+import {
+  users,
+  contactMethods,
+  agentSessions,
+  verificationSends,
+} from "@/db/schema";
+import { eq, and, or, gte, sql } from "drizzle-orm";
+import { sendVerificationSMS } from "@/channels/twilio";
+import { sendEmail } from "@/channels/email";
+import { checkWebhookURL, sendWebhook } from "@/channels/webhook";
+import {
+  CHAT_SURFACES,
+  connectedSurfaces,
+  isChatSurface,
+} from "@/channels/surface";
+
+const VERIFICATION_TTL_MS = 10 * 60 * 1000;
+/** Wrong guesses allowed per code before a new one has to be requested. */
+const MAX_VERIFICATION_ATTEMPTS = 5;
+const E164_RE = /^\+[1-9]\d{6,14}$/;
+const EMAIL_RE = /^[^\s@]+@[^\s@]+\.[^\s@]+$/;
+const MIN_SECRET_LENGTH = 16;
+/** Least time between two codes to one user or to one address. */
+const RESEND_COOLDOWN_MS = 60 * 1000;
+/** Codes one user may request, and one address may receive, per day. */
+const MAX_SENDS_PER_DAY = 5;
+
+const CONNECTABLE = [
+  "slack",
+  "discord",
+  "teams",
+  "sms",
+  "email",
+  "webhook",
+] as const;
+type Connectable = (typeof CONNECTABLE)[number];
+
+interface Channel {
+  channel: Connectable;
+  address: string;
+  verified: boolean;
+  verifiedAt: Date | null;
+}
+
+const ChannelType = builder.objectRef<Channel>("DeliveryChannel");
+
+ChannelType.implement({
+  description: "A place notifications can be delivered to.",
+  fields: (t) => ({
+    channel: t.exposeString("channel"),
+    address: t.exposeString("address"),
+    verified: t.exposeBoolean("verified"),
+    verifiedAt: t.string({
+      nullable: true,
+      resolve: (c) => c.verifiedAt?.toISOString() ?? null,
+    }),
+  }),
+});
+
+function hashCode(code: string): string {
+  return crypto.createHash("sha256").update(code).digest("hex");
+}
+
+/** Compare a submitted code with the stored hash in constant time. */
+function codeMatches(code: string, hash: string): boolean {
+  const got = Buffer.from(hashCode(code), "hex");
+  const want = Buffer.from(hash, "hex");
+  return got.length === want.length && crypto.timingSafeEqual(got, want);
+}
+
+function generateCode(): string {
+  return crypto.randomInt(0, 1_000_000).toString().padStart(6, "0");
+}
+
+function toChannel(row: typeof contactMethods.$inferSelect): Channel {
+  return {
+    channel: row.channel as Connectable,
+    address: row.address,
+    verified: row.verifiedAt != null,
+    verifiedAt: row.verifiedAt,
+  };
+}
+
+/**
+ * Replace the user's contact method for a channel. Each user has at most one
+ * phone, email and webhook; connecting again starts over.
+ */
+async function replaceContactMethod(
+  userId: string,
+  values: Omit<typeof contactMethods.$inferInsert, "userId">
+) {
+  await db
+    .delete(contactMethods)
+    .where(
+      and(
+        eq(contactMethods.userId, userId),
+        eq(contactMethods.channel, values.channel)
+      )
+    );
+  const [row] = await db
+    .insert(contactMethods)
+    .values({ userId, ...values })
+    .returning();
+  return row;
+}
+
+/**
+ * Refuse another verification code too soon after the last one, or past the
+ * daily limit, for this user or for this address whoever asks; otherwise
+ * record the send. Texts cost money, and codes mustn't flood a stranger.
+ */
+async function throttleVerification(
+  userId: string,
+  channel: "sms" | "email",
+  address: string
+) {
+  const now = Date.now();
+  const [recent] = await db
+    .select({
+      byUser: sql<number>`count(*) filter (where ${verificationSends.userId} = ${userId})`,
+      toAddress: sql<number>`count(*) filter (where ${verificationSends.address} = ${address})`,
+      last: sql<Date | null>`max(${verificationSends.createdAt})`,
+    })
+    .from(verificationSends)
+    .where(
+      and(
+        gte(verificationSends.createdAt, new Date(now - 24 * 60 * 60 * 1000)),
+        or(
+          eq(verificationSends.userId, userId),
+          and(
+            eq(verificationSends.channel, channel),
+            eq(verificationSends.address, address)
+          )
+        )
+      )
+    );
+
+  if (recent?.last) {
+    const wait = new Date(recent.last).getTime() + RESEND_COOLDOWN_MS - now;
+    if (wait > 0) {
+      throw new Error(
+        `A code was just sent; wait ${Math.ceil(wait / 1000)}s before asking for another`
+      );
+    }
+  }
+  if (
+    Number(recent?.byUser ?? 0) >= MAX_SENDS_PER_DAY ||
+    Number(recent?.toAddress ?? 0) >= MAX_SENDS_PER_DAY
+  ) {
+    throw new Error(
+      `No more than ${MAX_SENDS_PER_DAY} verification codes a day; try again tomorrow`
+    );
+  }
+
+  await db.insert(verificationSends).values({ userId, channel, address });
+}
+
+function pendingVerification() {
+  const code = generateCode();
+  return {
+    code,
+    verificationCodeHash: hashCode(code),
+    verificationExpiresAt: new Date(Date.now() + VERIFICATION_TTL_MS),
+    verificationAttempts: 0,
+  };
+}
+
+builder.queryField("channels", (t) =>
+  t.field({
+    type: [ChannelType],
+    description: "Connected delivery channels and whether each is verified.",
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const [user] = await db
+        .select()
+        .from(users)
+        .where(eq(users.id, ctx.userId));
+      if (!user) throw new Error("User not found");
+
+      const rows = await db
+        .select()
+        .from(contactMethods)
+        .where(eq(contactMethods.userId, ctx.userId));
+
+      const channels: Channel[] = [];
+      if (user.slackUserId) {
+        channels.push({
+          channel: "slack",
+          address: user.slackUserId,
+          verified: true,
+          verifiedAt: null,
+        });
+      }
+      if (user.discordUserId) {
+        channels.push({
+          channel: "discord",
+          address: user.discordUserId,
+          verified: true,
+          verifiedAt: null,
+        });
+      }
+      if (user.teamsConversation && user.teamsUserId) {
+        channels.push({
+          channel: "teams",
+          address: user.teamsUserId,
+          verified: true,
+          verifiedAt: null,
+        });
+      }
+      // Phones set before verification existed count as verified.
+      if (user.phone && !rows.some((r) => r.channel === "sms")) {
+        channels.push({
+          channel: "sms",
+          address: user.phone,
+          verified: true,
+          verifiedAt: null,
+        });
+      }
+      for (const row of rows) channels.push(toChannel(row));
+
+      return channels.sort(
+        (a, b) => CONNECTABLE.indexOf(a.channel) - CONNECTABLE.indexOf(b.channel)
+      );
+    },
+  })
+);
+
+builder.mutationField("connectSms", (t) =>
+  t.field({
+    type: ChannelType,
+    description:
+      "Start connecting a phone number. A verification code is texted to it; confirm with verifyChannel.",
+    args: {
+      phone: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const phone = args.phone.replace(/[\s().-]/g, "");
+      if (!E164_RE.test(phone)) {
+        throw new Error(
+          `Phone must be in international format, e.g. +14155550123 (got ${args.phone})`
+        );
+      }
+
+      await throttleVerification(ctx.userId, "sms", phone);
+      const { code, ...pending } = pendingVerification();
+      const row = await replaceContactMethod(ctx.userId, {
+        channel: "sms",
+        address: phone,
+        ...pending,
+      });
+      await sendVerificationSMS({ to: phone, code });
+      return toChannel(row);
+    },
+  })
+);
+
+builder.mutationField("connectEmail", (t) =>
+  t.field({
+    type: ChannelType,
+    description:
+      "Connect an email address. The account email is verified immediately; any other address gets a verification code.",
+    args: {
+      address: t.arg.string({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const [user] = await db
+        .select()
+        .from(users)
+        .where(eq(users.id, ctx.userId));
+      if (!user) throw new Error("User not found");
+
+      const address = (args.address ?? user.email).trim().toLowerCase();
+      if (!EMAIL_RE.test(address)) {
+        throw new Error(`Invalid email address: ${address}`);
+      }
+
+      if (address === user.email.toLowerCase()) {
+        const row = await replaceContactMethod(ctx.userId, {
+          channel: "email",
+          address,
+          verifiedAt: new Date(),
+        });
+        return toChannel(row);
+      }
+
+      await throttleVerification(ctx.userId, "email", address);
+      const { code, ...pending } = pendingVerification();
+      const row = await replaceContactMethod(ctx.userId, {
+        channel: "email",
+        address,
+        ...pending,
+      });
+      await sendEmail({
+        to: address,
+        subject: "Your AgentDuty verification code",
+        text: `Your AgentDuty verification code is ${code}. It expires in 10 minutes.`,
+      });
+      return toChannel(row);
+    },
+  })
+);
+
+builder.mutationField("connectWebhook", (t) =>
+  t.field({
+    type: ChannelType,
+    description:
+      "Connect a webhook. A signed ping is sent; the webhook is verified if it answers with a 2xx status.",
+    args: {
+      url: t.arg.string({ required: true }),
+      secret: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const url = await checkWebhookURL(args.url);
+      if (args.secret.length < MIN_SECRET_LENGTH) {
+        throw new Error(
+          `Webhook secret must be at least ${MIN_SECRET_LENGTH} characters`
+        );
+      }
+
+      let verifiedAt: Date | null = null;
+      try {
+        await sendWebhook({
+          url: url.toString(),
+          secret: args.secret,
+          event: "ping",
+          data: {},
+        });
+        verifiedAt = new Date();
+      } catch (err) {
+        console.warn("Webhook ping failed:", err);
+      }
+
+      const row = await replaceContactMethod(ctx.userId, {
+        channel: "webhook",
+        address: url.toString(),
+        secret: args.secret,
+        verifiedAt,
+      });
+      return toChannel(row);
+    },
+  })
+);
+
+builder.mutationField("verifyChannel", (t) =>
+  t.field({
+    type: ChannelType,
+    description: "Confirm a phone or email with the code sent to it.",
+    args: {
+      channel: t.arg.string({ required: true }),
+      code: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const channel = args.channel as Connectable;
+      if (!CONNECTABLE.includes(channel)) {
+        throw new Error(
+          `Unknown channel "${args.channel}" (expected ${CONNECTABLE.join(", ")})`
+        );
+      }
+
+      const [row] = await db
+        .select()
+        .from(contactMethods)
+        .where(
+          and(
+            eq(contactMethods.userId, ctx.userId),
+            eq(contactMethods.channel, channel)
+          )
+        );
+      if (!row) throw new Error(`No ${args.channel} channel to verify`);
+      if (row.verifiedAt) return toChannel(row);
+
+      if (
+        !row.verificationCodeHash ||
+        !row.verificationExpiresAt ||
+        row.verificationExpiresAt < new Date()
+      ) {
+        throw new Error(
+          `Verification code expired. Run 'agentduty connect ${args.channel}' again.`
+        );
+      }
+
+      // Count the attempt before checking it, in one statement, so parallel
+      // guesses can't get past the limit.
+      const [attempt] = await db
+        .update(contactMethods)
+        .set({
+          verificationAttempts: sql`${contactMethods.verificationAttempts} + 1`,
+        })
+        .where(
+          and(
+            eq(contactMethods.id, row.id),
+            sql`${contactMethods.verificationAttempts} < ${MAX_VERIFICATION_ATTEMPTS}`
+          )
+        )
+        .returning();
+      if (!attempt) {
+        throw new Error(
+          `Too many incorrect codes. Run 'agentduty connect ${args.channel}' again for a new one.`
+        );
+      }
+      if (!codeMatches(args.code.trim(), row.verificationCodeHash)) {
+        const left = MAX_VERIFICATION_ATTEMPTS - attempt.verificationAttempts;
+        if (left <= 0) {
+          throw new Error(
+            `Incorrect verification code. Run 'agentduty connect ${args.channel}' again for a new one.`
+          );
+        }
+        throw new Error(
+          `Incorrect verification code (${left} ${left === 1 ? "attempt" : "attempts"} left)`
+        );
+      }
+
+      const [updated] = await db
+        .update(contactMethods)
+        .set({
+          verifiedAt: new Date(),
+          verificationCodeHash: null,
+          verificationExpiresAt: null,
+        })
+        .where(eq(contactMethods.id, row.id))
+        .returning();
+
+      // SMS delivery and inbound replies key off users.phone.
+      if (row.channel === "sms") {
+        await db
+          .update(users)
+          .set({ phone: row.address, updatedAt: new Date() })
+          .where(eq(users.id, ctx.userId));
+      }
+
+      return toChannel(updated);
+    },
+  })
+);
+
+builder.mutationField("disconnectChannel", (t) =>
+  t.field({
+    type: "Boolean",
+    description:
+      "Stop delivering to a channel (slack, discord, teams, sms, email or webhook).",
+    args: {
+      channel: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const channel = args.channel as Connectable;
+      if (!CONNECTABLE.includes(channel)) {
+        throw new Error(
+          `Unknown channel "${args.channel}" (expected ${CONNECTABLE.join(", ")})`
+        );
+      }
+
+      if (channel === "slack") {
+        await db
+          .update(users)
+          .set({ slackUserId: null, slackTeamId: null, updatedAt: new Date() })
+          .where(eq(users.id, ctx.userId));
+        return true;
+      }
+      if (channel === "discord") {
+        await db
+          .update(users)
+          .set({
+            discordUserId: null,
+            discordDmChannelId: null,
+            updatedAt: new Date(),
+          })
+          .where(eq(users.id, ctx.userId));
+        return true;
+      }
+      if (channel === "teams") {
+        await db
+          .update(users)
+          .set({
+            teamsUserId: null,
+            teamsConversation: null,
+            updatedAt: new Date(),
+          })
+          .where(eq(users.id, ctx.userId));
+        return true;
+      }
+
+      if (channel === "sms") {
+        await db
+          .update(users)
+          .set({ phone: null, updatedAt: new Date() })
+          .where(eq(users.id, ctx.userId));
+      }
+      await db
+        .delete(contactMethods)
+        .where(
+          and(
+            eq(contactMethods.userId, ctx.userId),
+            eq(contactMethods.channel, channel)
+          )
+        );
+      return true;
+    },
+  })
+);
+
+builder.mutationField("setSessionSurface", (t) =>
+  t.field({
+    type: "String",
+    description:
+      "Choose which chat app (slack, discord or teams) a session's thread goes to. Returns the surface.",
+    args: {
+      sessionKey: t.arg.string({ required: true }),
+      surface: t.arg.string({ required: true }),
+      workspace: t.arg.string({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      if (!isChatSurface(args.surface)) {
+        throw new Error(
+          `Unknown surface "${args.surface}" (expected ${CHAT_SURFACES.join(", ")})`
+        );
+      }
+
+      const [user] = await db
+        .select()
+        .from(users)
+        .where(eq(users.id, ctx.userId));
+      if (!user) throw new Error("User not found");
+      if (!connectedSurfaces(user).includes(args.surface)) {
+        throw new Error(
+          `${args.surface} is not connected; run \`agentduty connect ${args.surface}\` first`
+        );
+      }
+
+      const [existing] = await db
+        .select()
+        .from(agentSessions)
+        .where(
+          and(
+            eq(agentSessions.sessionKey, args.sessionKey),
+            eq(agentSessions.userId, ctx.userId)
+          )
+        );
+
+      if (existing) {
+        await db
+          .update(agentSessions)
+          .set({ surface: args.surface })
+          .where(eq(agentSessions.id, existing.id));
+      } else {
+        await db.insert(agentSessions).values({
+          userId: ctx.userId,
+          sessionKey: args.sessionKey,
+          workspace: args.workspace,
+          surface: args.surface,
+        });
+      }
+      return args.surface;
+    },
+  })
+);
@@ -1,4 +1,25 @@
 import builder from "./builder";
+import { db } from "@/db";
+import {
+  escalationPolicies,
+  escalationSteps,
+  priorityRoutes,
+  notifications,
+} from "@/db/schema";
+import { eq, and, asc } from "drizzle-orm";
+
+const UUID_RE = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i;
+
+const STEP_CHANNELS = [
+  "slack",
+  "sms",
+  "web",
+  "email",
+  "webhook",
+  "discord",
+  "teams",
+] as const;
+type StepChannel = (typeof STEP_CHANNELS)[number];
 
 const EscalationStepType = builder.objectRef<{
   id: string;
@@ -44,9 +65,354 @@ EscalationPolicyType.implement({
     }),
     steps: t.field({
       type: [EscalationStepType],
-      resolve: (policy) => policy.steps ?? [],
+      resolve: async (policy) =>
+        policy.steps ??
+        db
+          .select()
+          .from(escalationSteps)
+          .where(eq(escalationSteps.policyId, policy.id))
+          .orderBy(asc(escalationSteps.stepOrder)),
+    }),
+    priorities: t.field({
+      type: ["Int"],
+      resolve: async (policy) => {
+        const routes = await db
+          .select()
+          .from(priorityRoutes)
+          .where(eq(priorityRoutes.policyId, policy.id));
+        return routes.map((r) => r.priority).sort((a, b) => a - b);
+      },
     }),
   }),
 });
 
-export { EscalationPolicyType, EscalationStepType };
+const PriorityRouteType = builder.objectRef<{
+  priority: number;
+  policyId: string;
+}>("PriorityRoute");
+
+PriorityRouteType.implement({
+  fields: (t) => ({
+    priority: t.exposeInt("priority"),
+    policy: t.field({
+      type: EscalationPolicyType,
+      nullable: true,
+      resolve: async (route) => {
+        const [policy] = await db
+          .select()
+          .from(escalationPolicies)
+          .where(eq(escalationPolicies.id, route.policyId));
+        return policy ?? null;
+      },
+    }),
+  }),
+});
+
+const EscalationStepInput = builder.inputType("EscalationStepInput", {
+  fields: (t) => ({
+    channel: t.string({ required: true }),
+    delaySeconds: t.int({ required: true }),
+  }),
+});
+
+function findPolicy(idOrName: string, userId: string) {
+  const idFilter = UUID_RE.test(idOrName)
+    ? eq(escalationPolicies.id, idOrName)
+    : eq(escalationPolicies.name, idOrName);
+
+  return db
+    .select()
+    .from(escalationPolicies)
+    .where(and(idFilter, eq(escalationPolicies.userId, userId)));
+}
+
+function validateSteps(
+  steps: Array<{ channel: string; delaySeconds: number }>
+): Array<{ channel: StepChannel; delaySeconds: number }> {
+  if (steps.length === 0) {
+    throw new Error("An escalation policy needs at least one step");
+  }
+  return steps.map((s, i) => {
+    if (!STEP_CHANNELS.includes(s.channel as StepChannel)) {
+      throw new Error(
+        `Step ${i + 1}: unknown channel "${s.channel}" (expected ${STEP_CHANNELS.join(", ")})`
+      );
+    }
+    if (s.delaySeconds < 0) {
+      throw new Error(`Step ${i + 1}: delaySeconds must not be negative`);
+    }
+    return { channel: s.channel as StepChannel, delaySeconds: s.delaySeconds };
+  });
+}
+
+async function replaceSteps(
+  policyId: string,
+  steps: Array<{ channel: StepChannel; delaySeconds: number }>
+) {
+  await db.delete(escalationSteps).where(eq(escalationSteps.policyId, policyId));
+  return db
+    .insert(escalationSteps)
+    .values(
+      steps.map((s, i) => ({
+        policyId,
+        stepOrder: i,
+        channel: s.channel,
+        delaySeconds: s.delaySeconds,
+      }))
+    )
+    .returning();
+}
+
+async function clearDefault(userId: string) {
+  await db
+    .update(escalationPolicies)
+    .set({ isDefault: false })
+    .where(
+      and(
+        eq(escalationPolicies.userId, userId),
+        eq(escalationPolicies.isDefault, true)
+      )
+    );
+}
+
+builder.queryField("escalationPolicies", (t) =>
+  t.field({
+    type: [EscalationPolicyType],
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      return db
+        .select()
+        .from(escalationPolicies)
+        .where(eq(escalationPolicies.userId, ctx.userId))
+        .orderBy(asc(escalationPolicies.name));
+    },
+  })
+);
+
+builder.queryField("escalationPolicy", (t) =>
+  t.field({
+    type: EscalationPolicyType,
+    nullable: true,
+    args: {
+      id: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      const [policy] = await findPolicy(args.id, ctx.userId);
+      return policy ?? null;
+    },
+  })
+);
+
+builder.queryField("priorityRoutes", (t) =>
+  t.field({
+    type: [PriorityRouteType],
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      return db
+        .select()
+        .from(priorityRoutes)
+        .where(eq(priorityRoutes.userId, ctx.userId))
+        .orderBy(asc(priorityRoutes.priority));
+    },
+  })
+);
+
+builder.mutationField("createEscalationPolicy", (t) =>
+  t.field({
+    type: EscalationPolicyType,
+    args: {
+      name: t.arg.string({ required: true }),
+      steps: t.arg({ type: [EscalationStepInput], required: true }),
+      isDefault: t.arg.boolean({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      const steps = validateSteps(args.steps);
+
+      const [existing] = await findPolicy(args.name, ctx.userId);
+      if (existing) {
+        throw new Error(`Escalation policy already exists: ${args.name}`);
+      }
+
+      if (args.isDefault) await clearDefault(ctx.userId);
+
+      const [policy] = await db
+        .insert(escalationPolicies)
+        .values({
+          userId: ctx.userId,
+          name: args.name,
+          isDefault: args.isDefault ?? false,
+        })
+        .returning();
+
+      return { ...policy, steps: await replaceSteps(policy.id, steps) };
+    },
+  })
+);
+
+builder.mutationField("updateEscalationPolicy", (t) =>
+  t.field({
+    type: EscalationPolicyType,
+    nullable: true,
+    args: {
+      id: t.arg.string({ required: true }),
+      name: t.arg.string({ required: false }),
+      steps: t.arg({ type: [EscalationStepInput], required: false }),
+      isDefault: t.arg.boolean({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      if (args.name == null && args.steps == null && args.isDefault == null) {
+        throw new Error("Nothing to update: pass name, steps or isDefault");
+      }
+      const steps = args.steps ? validateSteps(args.steps) : null;
+
+      const [policy] = await findPolicy(args.id, ctx.userId);
+      if (!policy) return null;
+
+      let updated = policy;
+      if (args.name != null && args.name !== policy.name) {
+        const [clash] = await findPolicy(args.name, ctx.userId);
+        if (clash) {
+          throw new Error(`Escalation policy already exists: ${args.name}`);
+        }
+        [updated] = await db
+          .update(escalationPolicies)
+          .set({ name: args.name })
+          .where(eq(escalationPolicies.id, policy.id))
+          .returning();
+      }
+      if (args.isDefault != null && args.isDefault !== policy.isDefault) {
+        if (args.isDefault) await clearDefault(ctx.userId);
+        [updated] = await db
+          .update(escalationPolicies)
+          .set({ isDefault: args.isDefault })
+          .where(eq(escalationPolicies.id, policy.id))
+          .returning();
+      }
+
+      if (steps) {
+        return { ...updated, steps: await replaceSteps(policy.id, steps) };
+      }
+      return updated;
+    },
+  })
+);
+
+builder.mutationField("deleteEscalationPolicy", (t) =>
+  t.field({
+    type: "Boolean",
+    args: {
+      id: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const [policy] = await findPolicy(args.id, ctx.userId);
+      if (!policy) return false;
+
+      const routes = await db
+        .select()
+        .from(priorityRoutes)
+        .where(eq(priorityRoutes.policyId, policy.id));
+      if (routes.length > 0) {
+        const priorities = routes.map((r) => `P${r.priority}`).join(", ");
+        throw new Error(
+          `Escalation policy ${policy.name} is still routed for ${priorities}; reassign those priorities first`
+        );
+      }
+
+      // Past notifications keep their history but stop pointing at the policy.
+      await db
+        .update(notifications)
+        .set({ policyId: null })
+        .where(eq(notifications.policyId, policy.id));
+      await db
+        .delete(escalationSteps)
+        .where(eq(escalationSteps.policyId, policy.id));
+      await db
+        .delete(escalationPolicies)
+        .where(eq(escalationPolicies.id, policy.id));
+      return true;
+    },
+  })
+);
+
+builder.mutationField("setDefaultEscalationPolicy", (t) =>
+  t.field({
+    type: EscalationPolicyType,
+    nullable: true,
+    args: {
+      id: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const [policy] = await findPolicy(args.id, ctx.userId);
+      if (!policy) return null;
+
+      await clearDefault(ctx.userId);
+      const [updated] = await db
+        .update(escalationPolicies)
+        .set({ isDefault: true })
+        .where(eq(escalationPolicies.id, policy.id))
+        .returning();
+      return updated;
+    },
+  })
+);
+
+builder.mutationField("setPriorityRoute", (t) =>
+  t.field({
+    type: PriorityRouteType,
+    nullable: true,
+    args: {
+      priority: t.arg.int({ required: true }),
+      policy: t.arg.string({ required: false }),
+    },
+    description:
+      "Route a priority to a policy, or clear the route (fall back to the default policy) when policy is omitted.",
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      if (args.priority < 1 || args.priority > 5) {
+        throw new Error("priority must be between 1 and 5");
+      }
+
+      const routeFilter = and(
+        eq(priorityRoutes.userId, ctx.userId),
+        eq(priorityRoutes.priority, args.priority)
+      );
+
+      if (!args.policy) {
+        await db.delete(priorityRoutes).where(routeFilter);
+        return null;
+      }
+
+      const [policy] = await findPolicy(args.policy, ctx.userId);
+      if (!policy) throw new Error(`Escalation policy not found: ${args.policy}`);
+
+      const [existing] = await db.select().from(priorityRoutes).where(routeFilter);
+      if (existing) {
+        const [updated] = await db
+          .update(priorityRoutes)
+          .set({ policyId: policy.id })
+          .where(eq(priorityRoutes.id, existing.id))
+          .returning();
+        return updated;
+      }
+
+      const [created] = await db
+        .insert(priorityRoutes)
+        .values({
+          userId: ctx.userId,
+          priority: args.priority,
+          policyId: policy.id,
+        })
+        .returning();
+      return created;
+    },
+  })
+);
+
+export { EscalationPolicyType, EscalationStepType, PriorityRouteType };
@@ -0,0 +1,179 @@
+import builder from "./builder";
+import { db } from "@/db";
+import { notifications, agentSessions } from "@/db/schema";
+import { eq, and, or, asc, desc, inArray, gt } from "drizzle-orm";
+import { NotificationType } from "./notification";
+
+// A poll that hasn't sent a heartbeat for this long is assumed dead.
+const POLL_HEARTBEAT_TTL_MS = 2 * 60 * 1000;
+
+type Notification = typeof notifications.$inferSelect;
+
+interface InboxSession {
+  sessionKey: string | null;
+  workspace: string | null;
+  polling: boolean;
+  lastMessage: Notification | null;
+  active: Notification[];
+}
+
+const InboxSessionType = builder.objectRef<InboxSession>("InboxSession");
+
+InboxSessionType.implement({
+  description:
+    "One agent session's open questions. Notifications sent without a session share a group with a null sessionKey.",
+  fields: (t) => ({
+    sessionKey: t.exposeString("sessionKey", { nullable: true }),
+    workspace: t.exposeString("workspace", { nullable: true }),
+    polling: t.exposeBoolean("polling", {
+      description: "Whether an agentduty poll is running for this session.",
+    }),
+    pendingCount: t.int({ resolve: (s) => s.active.length }),
+    oldestPendingAt: t.string({
+      nullable: true,
+      resolve: (s) => s.active[0]?.createdAt.toISOString() ?? null,
+    }),
+    lastMessage: t.field({
+      type: NotificationType,
+      nullable: true,
+      description: "The session's most recent notification, answered or not.",
+      resolve: (s) => s.lastMessage,
+    }),
+    notifications: t.field({
+      type: [NotificationType],
+      description: "Unanswered notifications, oldest first.",
+      resolve: (s) => s.active,
+    }),
+  }),
+});
+
+builder.queryField("inbox", (t) =>
+  t.field({
+    type: [InboxSessionType],
+    description:
+      "Unanswered notifications grouped by session, plus sessions with a poll running. Ordered by workspace, then oldest question.",
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const active = await db
+        .select()
+        .from(notifications)
+        .where(
+          and(
+            eq(notifications.userId, ctx.userId),
+            inArray(notifications.status, ["pending", "delivered"])
+          )
+        )
+        .orderBy(asc(notifications.createdAt));
+
+      const activeSessionIds = [
+        ...new Set(active.flatMap((n) => (n.sessionId ? [n.sessionId] : []))),
+      ];
+      const heartbeatCutoff = new Date(Date.now() - POLL_HEARTBEAT_TTL_MS);
+      const listening = gt(agentSessions.pollHeartbeatAt, heartbeatCutoff);
+
+      const sessions = await db
+        .select()
+        .from(agentSessions)
+        .where(
+          and(
+            eq(agentSessions.userId, ctx.userId),
+            activeSessionIds.length > 0
+              ? or(inArray(agentSessions.id, activeSessionIds), listening)
+              : listening
+          )
+        );
+
+      // The newest notification in each session, for "last message".
+      const latest =
+        sessions.length > 0
+          ? await db
+              .selectDistinctOn([notifications.sessionId])
+              .from(notifications)
+              .where(
+                inArray(
+                  notifications.sessionId,
+                  sessions.map((s) => s.id)
+                )
+              )
+              .orderBy(notifications.sessionId, desc(notifications.createdAt))
+          : [];
+      const latestBySession = new Map(latest.map((n) => [n.sessionId, n]));
+
+      const groups: InboxSession[] = sessions.map((session) => ({
+        sessionKey: session.sessionKey,
+        workspace: session.workspace,
+        polling:
+          !!session.pollHeartbeatAt &&
+          session.pollHeartbeatAt > heartbeatCutoff,
+        lastMessage: latestBySession.get(session.id) ?? null,
+        active: active.filter((n) => n.sessionId === session.id),
+      }));
+
+      const unsessioned = active.filter((n) => !n.sessionId);
+      if (unsessioned.length > 0) {
+        groups.push({
+          sessionKey: null,
+          workspace: null,
+          polling: false,
+          lastMessage: unsessioned[unsessioned.length - 1],
+          active: unsessioned,
+        });
+      }
+
+      // Sessions without a question sort last within their workspace.
+      const oldest = (g: InboxSession) =>
+        g.active[0]?.createdAt.getTime() ?? Number.MAX_SAFE_INTEGER;
+      return groups.sort((a, b) => {
+        if (a.workspace !== b.workspace) {
+          if (a.workspace == null) return 1;
+          if (b.workspace == null) return -1;
+          return a.workspace.localeCompare(b.workspace);
+        }
+        return oldest(a) - oldest(b);
+      });
+    },
+  })
+);
+
+builder.mutationField("reportPolling", (t) =>
+  t.field({
+    type: "Boolean",
+    description:
+      "Heartbeat from a running agentduty poll (active: true), or its exit (active: false).",
+    args: {
+      sessionKey: t.arg.string({ required: true }),
+      workspace: t.arg.string({ required: false }),
+      active: t.arg.boolean({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const pollHeartbeatAt = args.active ? new Date() : null;
+      const [existing] = await db
+        .select()
+        .from(agentSessions)
+        .where(
+          and(
+            eq(agentSessions.sessionKey, args.sessionKey),
+            eq(agentSessions.userId, ctx.userId)
+          )
+        );
+
+      if (existing) {
+        await db
+          .update(agentSessions)
+          .set({ pollHeartbeatAt })
+          .where(eq(agentSessions.id, existing.id));
+      } else if (args.active) {
+        await db.insert(agentSessions).values({
+          userId: ctx.userId,
+          sessionKey: args.sessionKey,
+          workspace: args.workspace,
+          pollHeartbeatAt,
+        });
+      }
+      return args.active;
+    },
+  })
+);
@@ -6,5 +6,9 @@ import "./notification";
 import "./response";
 import "./escalation";
 import "./api-key";
+import "./progress";
+import "./channel";
+import "./webhook";
+import "./inbox";
 
 export const schema = builder.toSchema();
@@ -0,0 +1,64 @@
+/**
+ * Per-request batching for field resolvers that would otherwise run one
+ * query per parent object. Keys asked for while a request is resolving one
+ * level of the result are collected and fetched with a single query once
+ * that level has been walked. Results are cached for the rest of the
+ * request, which is identified by its context object.
+ */
+export function createLoader<K, V>(
+  fetch: (keys: K[]) => Promise<Map<K, V>>
+): (ctx: object, key: K) => Promise<V | undefined> {
+  const perRequest = new WeakMap<object, (key: K) => Promise<V | undefined>>();
+  return (ctx, key) => {
+    let load = perRequest.get(ctx);
+    if (!load) {
+      load = batcher(fetch);
+      perRequest.set(ctx, load);
+    }
+    return load(key);
+  };
+}
+
+function batcher<K, V>(
+  fetch: (keys: K[]) => Promise<Map<K, V>>
+): (key: K) => Promise<V | undefined> {
+  const cache = new Map<K, Promise<V | undefined>>();
+  let pending: {
+    key: K;
+    resolve: (v: V | undefined) => void;
+    reject: (err: unknown) => void;
+  }[] = [];
+
+  const dispatch = () => {
+    const batch = pending;
+    pending = [];
+    fetch([...new Set(batch.map((p) => p.key))]).then(
+      (found) => batch.forEach((p) => p.resolve(found.get(p.key))),
+      (err) => batch.forEach((p) => p.reject(err))
+    );
+  };
+
+  return (key) => {
+    const cached = cache.get(key);
+    if (cached) return cached;
+    const promise = new Promise<V | undefined>((resolve, reject) => {
+      // Wait for the resolvers still queued on this tick to ask too.
+      if (pending.length === 0) setImmediate(dispatch);
+      pending.push({ key, resolve, reject });
+    });
+    cache.set(key, promise);
+    return promise;
+  };
+}
+
+/** Group rows into a map of lists by key, keeping their order. */
+export function groupBy<K, T>(rows: T[], keyOf: (row: T) => K): Map<K, T[]> {
+  const groups = new Map<K, T[]>();
+  for (const row of rows) {
+    const key = keyOf(row);
+    const list = groups.get(key);
+    if (list) list.push(row);
+    else groups.set(key, [row]);
+  }
+  return groups;
+}
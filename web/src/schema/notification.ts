@@ -8,12 +8,39 @@ import {
   agentSessions,
   escalationPolicies,
   priorityRoutes,
+  reactions,
+  users,
 } from "@/db/schema";
-import { eq, and, or, desc, asc, inArray, isNull, lte, sql } from "drizzle-orm";
+import {
+  eq,
+  and,
+  or,
+  desc,
+  asc,
+  inArray,
+  isNull,
+  lte,
+  gt,
+  gte,
+  ilike,
+  arrayContains,
+  sql,
+} from "drizzle-orm";
 import { inngest } from "@/inngest/client";
 import { ResponseType } from "./response";
+import { createLoader, groupBy } from "./loader";
 import { deliverNotification } from "@/channels/deliver";
-import { addSlackReaction } from "@/channels/slack";
+import { CHAT_SURFACES, isChatSurface } from "@/channels/surface";
+import {
+  emitWebhookEvent,
+  notificationPayload,
+  responsePayload,
+} from "@/channels/events";
+import {
+  addSlackReaction,
+  editSlackNotification,
+  markSlackMessageRetracted,
+} from "@/channels/slack";
 
 const UUID_RE = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i;
 
@@ -28,6 +55,34 @@ function findNotificationByIdOrShortCode(id: string, userId: string) {
     .where(and(idFilter, eq(notifications.userId, userId)));
 }
 
+/**
+ * Locate the Slack message a notification was delivered as, if any.
+ */
+async function findSlackMessage(notificationId: string) {
+  const [delivery] = await db
+    .select()
+    .from(deliveries)
+    .where(
+      and(
+        eq(deliveries.notificationId, notificationId),
+        eq(deliveries.channel, "slack")
+      )
+    );
+  const metadata = delivery?.metadata as
+    | { channel?: string; threadTs?: string }
+    | null
+    | undefined;
+  if (!delivery?.externalId || !metadata?.channel) return null;
+  return {
+    channel: metadata.channel,
+    ts: delivery.externalId,
+    threadTs: metadata.threadTs,
+  };
+}
+
+// How long a dedup key collapses repeats when the caller doesn't say.
+const DEFAULT_DEDUP_WINDOW_SECONDS = 10 * 60;
+
 function generateShortCode(): string {
   const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789";
   const bytes = crypto.randomBytes(3);
@@ -38,11 +93,62 @@ function generateShortCode(): string {
   return code;
 }
 
+interface SessionInfo {
+  sessionKey: string;
+  workspace: string | null;
+}
+
+/**
+ * The session a notification belongs to. List queries join it in up front
+ * (see withSession); anything else looks it up per notification.
+ */
+async function sessionOf(n: {
+  sessionId: string | null;
+  session?: SessionInfo | null;
+}): Promise<SessionInfo | null> {
+  if (n.session !== undefined) return n.session;
+  if (!n.sessionId) return null;
+  const [session] = await db
+    .select({
+      sessionKey: agentSessions.sessionKey,
+      workspace: agentSessions.workspace,
+    })
+    .from(agentSessions)
+    .where(eq(agentSessions.id, n.sessionId));
+  return session ?? null;
+}
+
+/** Columns for a notification list that carries each row's session. */
+const withSessionColumns = {
+  notification: notifications,
+  session: {
+    sessionKey: agentSessions.sessionKey,
+    workspace: agentSessions.workspace,
+  },
+};
+
+function withSession(rows: {
+  notification: typeof notifications.$inferSelect;
+  session: SessionInfo | null;
+}[]) {
+  return rows.map(({ notification, session }) => ({ ...notification, session }));
+}
+
+const loadResponses = createLoader(async (notificationIds: string[]) => {
+  const rows = await db
+    .select()
+    .from(responses)
+    .where(inArray(responses.notificationId, notificationIds))
+    .orderBy(asc(responses.createdAt));
+  return groupBy(rows, (r) => r.notificationId);
+});
+
 const NotificationType = builder.objectRef<{
   id: string;
   shortCode: string;
   userId: string;
   sessionId: string | null;
+  parentId: string | null;
   message: string;
   priority: number;
   context: unknown;
@@ -52,8 +158,17 @@ const NotificationType = builder.objectRef<{
   currentEscalationStep: number | null;
   policyId: string | null;
   snoozedUntil: Date | null;
+  expiresAt: Date | null;
+  defaultOption: string | null;
+  retractReason: string | null;
+  editedAt: Date | null;
+  dedupKey: string | null;
+  repeatCount: number;
+  lastRepeatedAt: Date | null;
   createdAt: Date;
   updatedAt: Date;
+  /** Set by list queries that join the session in. */
+  session?: SessionInfo | null;
 }>("Notification");
 
 NotificationType.implement({
@@ -62,6 +177,7 @@ NotificationType.implement({
     shortCode: t.exposeString("shortCode"),
     userId: t.exposeString("userId"),
     sessionId: t.exposeString("sessionId", { nullable: true }),
+    parentId: t.exposeString("parentId", { nullable: true }),
     message: t.exposeString("message"),
     priority: t.exposeInt("priority"),
     context: t.string({
@@ -79,20 +195,41 @@ NotificationType.implement({
       nullable: true,
       resolve: (n) => (n.snoozedUntil ? n.snoozedUntil.toISOString() : null),
     }),
+    expiresAt: t.string({
+      nullable: true,
+      resolve: (n) => (n.expiresAt ? n.expiresAt.toISOString() : null),
+    }),
+    defaultOption: t.exposeString("defaultOption", { nullable: true }),
+    retractReason: t.exposeString("retractReason", { nullable: true }),
+    editedAt: t.string({
+      nullable: true,
+      resolve: (n) => (n.editedAt ? n.editedAt.toISOString() : null),
+    }),
+    dedupKey: t.exposeString("dedupKey", { nullable: true }),
+    repeatCount: t.exposeInt("repeatCount"),
+    lastRepeatedAt: t.string({
+      nullable: true,
+      resolve: (n) =>
+        n.lastRepeatedAt ? n.lastRepeatedAt.toISOString() : null,
+    }),
     createdAt: t.string({
       resolve: (n) => n.createdAt.toISOString(),
     }),
     updatedAt: t.string({
       resolve: (n) => n.updatedAt.toISOString(),
     }),
+    sessionKey: t.string({
+      nullable: true,
+      resolve: async (n) => (await sessionOf(n))?.sessionKey ?? null,
+    }),
+    workspace: t.string({
+      nullable: true,
+      resolve: async (n) => (await sessionOf(n))?.workspace ?? null,
+    }),
     responses: t.field({
       type: [ResponseType],
-      resolve: async (notification) => {
-        return db
-          .select()
-          .from(responses)
-          .where(eq(responses.notificationId, notification.id));
-      },
+      resolve: async (notification, _args, ctx) =>
+        (await loadResponses(ctx, notification.id)) ?? [],
     }),
   }),
 });
@@ -115,48 +252,284 @@ builder.queryField("notification", (t) =>
   })
 );
 
+const NOTIFICATION_STATUSES = [
+  "pending",
+  "delivered",
+  "responded",
+  "expired",
+  "archived",
+  "retracted",
+] as const;
+type NotificationStatus = (typeof NOTIFICATION_STATUSES)[number];
+
+const NOTIFICATION_SORTS = ["newest", "oldest", "priority"] as const;
+type NotificationSort = (typeof NOTIFICATION_SORTS)[number];
+
+const MAX_PAGE_SIZE = 200;
+
+/**
+ * Position after the last row of a page, opaque to clients. Only the row's
+ * id is kept: the query compares against that row's stored sort key, since
+ * a JS Date would drop the microseconds Postgres keeps in created_at.
+ */
+interface PageCursor {
+  sort: NotificationSort;
+  id: string;
+}
+
+function encodeCursor(sort: NotificationSort, n: { id: string }): string {
+  const cursor: PageCursor = { sort, id: n.id };
+  return Buffer.from(JSON.stringify(cursor)).toString("base64url");
+}
+
+function decodeCursor(value: string, sort: NotificationSort): PageCursor {
+  let cursor: PageCursor;
+  try {
+    cursor = JSON.parse(Buffer.from(value, "base64url").toString());
+  } catch {
+    throw new Error("Invalid cursor");
+  }
+  if (cursor?.sort !== sort || typeof cursor.id !== "string") {
+    throw new Error("Invalid cursor (was it from a query with a different sort?)");
+  }
+  return cursor;
+}
+
+function escapeLike(value: string): string {
+  return value.replace(/[\\%_]/g, (c) => `\\${c}`);
+}
+
+interface NotificationFilter {
+  status?: string | null;
+  statuses?: string[] | null;
+  minPriority?: number | null;
+  maxPriority?: number | null;
+  tags?: string[] | null;
+  sessionKey?: string | null;
+  workspace?: string | null;
+  since?: string | null;
+  search?: string | null;
+  sort?: string | null;
+  limit?: number | null;
+  cursor?: string | null;
+}
+
+/**
+ * Find a user's notifications matching the filter, one page at a time.
+ * Pages are keyset-paginated on the sort key, so new notifications arriving
+ * between requests don't shift later pages.
+ */
+async function findNotifications(userId: string, filter: NotificationFilter) {
+  const statuses = [
+    ...(filter.status ? [filter.status] : []),
+    ...(filter.statuses ?? []),
+  ];
+  for (const status of statuses) {
+    if (!NOTIFICATION_STATUSES.includes(status as NotificationStatus)) {
+      throw new Error(
+        `Unknown status "${status}" (expected ${NOTIFICATION_STATUSES.join(", ")})`
+      );
+    }
+  }
+
+  const sort = (filter.sort ?? "newest") as NotificationSort;
+  if (!NOTIFICATION_SORTS.includes(sort)) {
+    throw new Error(
+      `Unknown sort "${filter.sort}" (expected ${NOTIFICATION_SORTS.join(", ")})`
+    );
+  }
+
+  for (const p of [filter.minPriority, filter.maxPriority]) {
+    if (p != null && (p < 1 || p > 5)) {
+      throw new Error("Priority filters must be between 1 and 5");
+    }
+  }
+  if (filter.limit != null && (filter.limit < 1 || filter.limit > MAX_PAGE_SIZE)) {
+    throw new Error(`limit must be between 1 and ${MAX_PAGE_SIZE}`);
+  }
+
+  const conditions = [eq(notifications.userId, userId)];
+
+  if (statuses.length > 0) {
+    conditions.push(
+      inArray(notifications.status, statuses as NotificationStatus[])
+    );
+  }
+  if (filter.minPriority != null) {
+    conditions.push(gte(notifications.priority, filter.minPriority));
+  }
+  if (filter.maxPriority != null) {
+    conditions.push(lte(notifications.priority, filter.maxPriority));
+  }
+  if (filter.tags?.length) {
+    conditions.push(arrayContains(notifications.tags, filter.tags));
+  }
+  if (filter.sessionKey != null || filter.workspace != null) {
+    const sessionConditions = [eq(agentSessions.userId, userId)];
+    if (filter.sessionKey != null) {
+      sessionConditions.push(eq(agentSessions.sessionKey, filter.sessionKey));
+    }
+    if (filter.workspace != null) {
+      sessionConditions.push(eq(agentSessions.workspace, filter.workspace));
+    }
+    conditions.push(
+      inArray(
+        notifications.sessionId,
+        db
+          .select({ id: agentSessions.id })
+          .from(agentSessions)
+          .where(and(...sessionConditions))
+      )
+    );
+  }
+  if (filter.since != null) {
+    const since = new Date(filter.since);
+    if (isNaN(since.getTime())) {
+      throw new Error(`Invalid timestamp: ${filter.since}`);
+    }
+    conditions.push(gte(notifications.createdAt, since));
+  }
+  if (filter.search) {
+    conditions.push(
+      ilike(notifications.message, `%${escapeLike(filter.search)}%`)
+    );
+  }
+
+  if (filter.cursor) {
+    const c = decodeCursor(filter.cursor, sort);
+    // Inside the subquery "notifications" is the cursor row, outside it's
+    // the row being filtered.
+    if (sort === "oldest") {
+      conditions.push(
+        sql`(${notifications.createdAt}, ${notifications.id}) > (select ${notifications.createdAt}, ${notifications.id} from ${notifications} where ${notifications.id} = ${c.id})`
+      );
+    } else if (sort === "priority") {
+      conditions.push(
+        sql`(${notifications.priority}, ${notifications.createdAt}, ${notifications.id}) < (select ${notifications.priority}, ${notifications.createdAt}, ${notifications.id} from ${notifications} where ${notifications.id} = ${c.id})`
+      );
+    } else {
+      conditions.push(
+        sql`(${notifications.createdAt}, ${notifications.id}) < (select ${notifications.createdAt}, ${notifications.id} from ${notifications} where ${notifications.id} = ${c.id})`
+      );
+    }
+  }
+
+  const order =
+    sort === "oldest"
+      ? [asc(notifications.createdAt), asc(notifications.id)]
+      : sort === "priority"
+        ? [
+            desc(notifications.priority),
+            desc(notifications.createdAt),
+            desc(notifications.id),
+          ]
+        : [desc(notifications.createdAt), desc(notifications.id)];
+
+  const query = db
+    .select()
+    .from(notifications)
+    .where(and(...conditions))
+    .orderBy(...order);
+
+  if (filter.limit == null) {
+    return { notifications: await query, nextCursor: null };
+  }
+
+  // Fetch one extra row to learn whether another page exists.
+  const rows = await query.limit(filter.limit + 1);
+  const page = rows.slice(0, filter.limit);
+  return {
+    notifications: page,
+    nextCursor:
+      rows.length > filter.limit
+        ? encodeCursor(sort, page[page.length - 1])
+        : null,
+  };
+}
+
+const NotificationPageType = builder.objectRef<{
+  notifications: Array<typeof notifications.$inferSelect>;
+  nextCursor: string | null;
+}>("NotificationPage");
+
+NotificationPageType.implement({
+  fields: (t) => ({
+    notifications: t.field({
+      type: [NotificationType],
+      resolve: (page) => page.notifications,
+    }),
+    nextCursor: t.exposeString("nextCursor", {
+      nullable: true,
+      description: "Pass as cursor to fetch the next page; null on the last page.",
+    }),
+  }),
+});
+
 builder.queryField("notifications", (t) =>
   t.field({
     type: [NotificationType],
     args: {
       status: t.arg.string({ required: false }),
+      statuses: t.arg.stringList({ required: false }),
+      minPriority: t.arg.int({ required: false }),
+      maxPriority: t.arg.int({ required: false }),
+      tags: t.arg.stringList({ required: false }),
+      sessionKey: t.arg.string({ required: false }),
+      workspace: t.arg.string({ required: false }),
+      since: t.arg.string({ required: false }),
+      search: t.arg.string({ required: false }),
+      sort: t.arg.string({ required: false }),
+      limit: t.arg.int({ required: false }),
     },
     resolve: async (_parent, args, ctx) => {
       if (!ctx.userId) throw new Error("Unauthorized");
-      const conditions = [eq(notifications.userId, ctx.userId)];
-
-      if (args.status) {
-        conditions.push(
-          eq(
-            notifications.status,
-            args.status as
-              | "pending"
-              | "delivered"
-              | "responded"
-              | "expired"
-          )
-        );
-      }
+      const page = await findNotifications(ctx.userId, args);
+      return page.notifications;
+    },
+  })
+);
 
-      return db
-        .select()
-        .from(notifications)
-        .where(and(...conditions))
-        .orderBy(desc(notifications.createdAt));
+builder.queryField("notificationPage", (t) =>
+  t.field({
+    type: NotificationPageType,
+    description:
+      "Filtered notifications, one page at a time. Tags must all match; search is a case-insensitive substring of the message.",
+    args: {
+      statuses: t.arg.stringList({ required: false }),
+      minPriority: t.arg.int({ required: false }),
+      maxPriority: t.arg.int({ required: false }),
+      tags: t.arg.stringList({ required: false }),
+      sessionKey: t.arg.string({ required: false }),
+      workspace: t.arg.string({ required: false }),
+      since: t.arg.string({ required: false }),
+      search: t.arg.string({ required: false }),
+      sort: t.arg.string({ required: false }),
+      limit: t.arg.int({ required: false }),
+      cursor: t.arg.string({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      return findNotifications(ctx.userId, { ...args, limit: args.limit ?? 50 });
     },
   })
 );
 
 const SessionHistoryType = builder.objectRef<{
   sessionId: string;
+  sessionKey: string;
   workspace: string | null;
+  createdAt: Date;
   notifications: Array<typeof notifications.$inferSelect>;
 }>("SessionHistory");
 
 SessionHistoryType.implement({
   fields: (t) => ({
     sessionId: t.exposeString("sessionId"),
+    sessionKey: t.exposeString("sessionKey"),
     workspace: t.exposeString("workspace", { nullable: true }),
+    createdAt: t.string({
+      resolve: (session) => session.createdAt.toISOString(),
+    }),
     notifications: t.field({
       type: [NotificationType],
       resolve: (session) => session.notifications,
@@ -194,13 +567,246 @@ builder.queryField("sessionHistory", (t) =>
 
       return {
         sessionId: session.id,
+        sessionKey: session.sessionKey,
         workspace: session.workspace,
+        createdAt: session.createdAt,
         notifications: sessionNotifications,
       };
     },
   })
 );
 
+type NotificationRow = typeof notifications.$inferSelect;
+
+/**
+ * The reply thread a sessionless notification is part of: everything under
+ * its root, found by walking up the parents and then down the follow-ups,
+ * oldest first with the root at the head.
+ */
+async function replyThread(
+  notification: NotificationRow,
+  userId: string
+): Promise<NotificationRow[]> {
+  let root = notification;
+  const ancestors = new Set([root.id]);
+  while (root.parentId && !ancestors.has(root.parentId)) {
+    const [parent] = await db
+      .select()
+      .from(notifications)
+      .where(
+        and(eq(notifications.userId, userId), eq(notifications.id, root.parentId))
+      );
+    if (!parent) break;
+    ancestors.add(parent.id);
+    root = parent;
+  }
+
+  const followUps: NotificationRow[] = [];
+  const seen = new Set([root.id]);
+  let frontier = [root.id];
+  while (frontier.length > 0) {
+    const children = await db
+      .select()
+      .from(notifications)
+      .where(
+        and(
+          eq(notifications.userId, userId),
+          inArray(notifications.parentId, frontier)
+        )
+      )
+      .orderBy(asc(notifications.createdAt));
+    const fresh = children.filter((c) => !seen.has(c.id));
+    for (const c of fresh) seen.add(c.id);
+    followUps.push(...fresh);
+    frontier = fresh.map((c) => c.id);
+  }
+
+  followUps.sort((a, b) => a.createdAt.getTime() - b.createdAt.getTime());
+  return [root, ...followUps];
+}
+
+builder.queryField("notificationThread", (t) =>
+  t.field({
+    type: SessionHistoryType,
+    nullable: true,
+    description:
+      "The whole conversation a notification belongs to: its session, or the reply thread it is part of when it has none.",
+    args: {
+      id: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const [notification] = await findNotificationByIdOrShortCode(
+        args.id,
+        ctx.userId
+      );
+      if (!notification) return null;
+
+      if (!notification.sessionId) {
+        const thread = await replyThread(notification, ctx.userId);
+        const root = thread[0];
+        return {
+          sessionId: root.id,
+          sessionKey: root.shortCode,
+          workspace: null,
+          createdAt: root.createdAt,
+          notifications: thread,
+        };
+      }
+
+      const [session] = await db
+        .select()
+        .from(agentSessions)
+        .where(eq(agentSessions.id, notification.sessionId));
+      const sessionNotifications = await db
+        .select()
+        .from(notifications)
+        .where(eq(notifications.sessionId, notification.sessionId))
+        .orderBy(asc(notifications.createdAt));
+
+      return {
+        sessionId: notification.sessionId,
+        sessionKey: session?.sessionKey ?? notification.shortCode,
+        workspace: session?.workspace ?? null,
+        createdAt: session?.createdAt ?? notification.createdAt,
+        notifications: sessionNotifications,
+      };
+    },
+  })
+);
+
+const MAX_EXPORT_SESSIONS = 200;
+
+builder.queryField("sessionHistories", (t) =>
+  t.field({
+    type: [SessionHistoryType],
+    description: `Full history of every session with a notification since the given time, newest session first, one page of at most ${MAX_EXPORT_SESSIONS}. Pass the last sessionId as before for the next page; a short page is the last.`,
+    args: {
+      since: t.arg.string({ required: true }),
+      before: t.arg.string({ required: false }),
+      limit: t.arg.int({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const since = new Date(args.since);
+      if (isNaN(since.getTime())) {
+        throw new Error(`Invalid timestamp: ${args.since}`);
+      }
+      if (args.before != null && !UUID_RE.test(args.before)) {
+        throw new Error(`Invalid session id: ${args.before}`);
+      }
+      const limit = Math.min(
+        Math.max(args.limit ?? MAX_EXPORT_SESSIONS, 1),
+        MAX_EXPORT_SESSIONS
+      );
+
+      const conditions = [
+        eq(agentSessions.userId, ctx.userId),
+        inArray(
+          agentSessions.id,
+          db
+            .select({ id: notifications.sessionId })
+            .from(notifications)
+            .where(
+              and(
+                eq(notifications.userId, ctx.userId),
+                gte(notifications.createdAt, since)
+              )
+            )
+        ),
+      ];
+      if (args.before != null) {
+        conditions.push(
+          sql`(${agentSessions.createdAt}, ${agentSessions.id}) < (select ${agentSessions.createdAt}, ${agentSessions.id} from ${agentSessions} where ${agentSessions.id} = ${args.before})`
+        );
+      }
+
+      const sessions = await db
+        .select()
+        .from(agentSessions)
+        .where(and(...conditions))
+        .orderBy(desc(agentSessions.createdAt), desc(agentSessions.id))
+        .limit(limit);
+
+      if (sessions.length === 0) return [];
+
+      const sessionNotifications = await db
+        .select()
+        .from(notifications)
+        .where(
+          inArray(
+            notifications.sessionId,
+            sessions.map((s) => s.id)
+          )
+        )
+        .orderBy(asc(notifications.createdAt));
+
+      return sessions.map((session) => ({
+        sessionId: session.id,
+        sessionKey: session.sessionKey,
+        workspace: session.workspace,
+        createdAt: session.createdAt,
+        notifications: sessionNotifications.filter(
+          (n) => n.sessionId === session.id
+        ),
+      }));
+    },
+  })
+);
+
+builder.queryField("threadHistories", (t) =>
+  t.field({
+    type: [SessionHistoryType],
+    description:
+      "Reply threads outside any session with a notification since the given time, each shaped like a session named after its root, oldest first.",
+    args: {
+      since: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const since = new Date(args.since);
+      if (isNaN(since.getTime())) {
+        throw new Error(`Invalid timestamp: ${args.since}`);
+      }
+
+      const rows = await db
+        .select()
+        .from(notifications)
+        .where(
+          and(
+            eq(notifications.userId, ctx.userId),
+            isNull(notifications.sessionId),
+            gte(notifications.createdAt, since)
+          )
+        )
+        .orderBy(asc(notifications.createdAt));
+
+      // A notification whose parent is older than since starts its thread.
+      const byId = new Map(rows.map((n) => [n.id, n]));
+      const rootOf = (n: NotificationRow) => {
+        const seen = new Set<string>();
+        while (n.parentId && byId.has(n.parentId) && !seen.has(n.id)) {
+          seen.add(n.id);
+          n = byId.get(n.parentId)!;
+        }
+        return n;
+      };
+      const threads = groupBy(rows, (n) => rootOf(n).id);
+
+      return [...threads.values()].map((thread) => ({
+        sessionId: thread[0].id,
+        sessionKey: thread[0].shortCode,
+        workspace: null,
+        createdAt: thread[0].createdAt,
+        notifications: thread,
+      }));
+    },
+  })
+);
+
 builder.mutationField("createNotification", (t) =>
   t.field({
     type: NotificationType,
@@ -212,16 +818,58 @@ builder.mutationField("createNotification", (t) =>
       tags: t.arg.stringList({ required: false }),
       sessionKey: t.arg.string({ required: false }),
       workspace: t.arg.string({ required: false }),
+      expiresInSeconds: t.arg.int({ required: false }),
+      defaultOption: t.arg.string({ required: false }),
+      replyTo: t.arg.string({ required: false }),
+      dedupKey: t.arg.string({ required: false }),
+      dedupWindowSeconds: t.arg.int({ required: false }),
+      surface: t.arg.string({ required: false }),
     },
     resolve: async (_parent, args, ctx) => {
       if (!ctx.userId) throw new Error("Unauthorized");
 
+      if (args.surface != null && !isChatSurface(args.surface)) {
+        throw new Error(
+          `Unknown surface "${args.surface}" (expected ${CHAT_SURFACES.join(", ")})`
+        );
+      }
+
+      if (args.dedupWindowSeconds != null && args.dedupWindowSeconds <= 0) {
+        throw new Error("dedupWindowSeconds must be positive");
+      }
+      if (args.defaultOption && !args.expiresInSeconds) {
+        throw new Error("defaultOption requires expiresInSeconds");
+      }
+      if (args.defaultOption && !args.options?.includes(args.defaultOption)) {
+        throw new Error(
+          `defaultOption "${args.defaultOption}" is not one of the options`
+        );
+      }
+      if (args.expiresInSeconds != null && args.expiresInSeconds <= 0) {
+        throw new Error("expiresInSeconds must be positive");
+      }
+
       const priority = args.priority ?? 3;
       const shortCode = generateShortCode();
+      const expiresAt = args.expiresInSeconds
+        ? new Date(Date.now() + args.expiresInSeconds * 1000)
+        : null;
 
-      // Find or create session if sessionKey provided
+      // A follow-up lives in its parent's session so the thread stays intact.
+      let parentId: string | null = null;
       let sessionId: string | null = null;
-      if (args.sessionKey) {
+      if (args.replyTo) {
+        const [parent] = await findNotificationByIdOrShortCode(
+          args.replyTo,
+          ctx.userId
+        );
+        if (!parent) throw new Error(`Notification not found: ${args.replyTo}`);
+        parentId = parent.id;
+        sessionId = parent.sessionId;
+      }
+
+      // Find or create session if sessionKey provided
+      if (args.sessionKey && !sessionId) {
         const [existing] = await db
           .select()
           .from(agentSessions)
@@ -234,6 +882,12 @@ builder.mutationField("createNotification", (t) =>
 
         if (existing) {
           sessionId = existing.id;
+          if (args.surface && existing.surface !== args.surface) {
+            await db
+              .update(agentSessions)
+              .set({ surface: args.surface })
+              .where(eq(agentSessions.id, existing.id));
+          }
         } else {
           const [session] = await db
             .insert(agentSessions)
@@ -241,12 +895,52 @@ builder.mutationField("createNotification", (t) =>
               userId: ctx.userId,
               sessionKey: args.sessionKey,
               workspace: args.workspace,
+              surface: args.surface,
             })
             .returning({ id: agentSessions.id });
           sessionId = session.id;
         }
       }
 
+      // A repeat of a still-open notification in the same session bumps its
+      // counter instead of paging the user again.
+      if (args.dedupKey) {
+        const windowSeconds =
+          args.dedupWindowSeconds ?? DEFAULT_DEDUP_WINDOW_SECONDS;
+        const [duplicate] = await db
+          .select()
+          .from(notifications)
+          .where(
+            and(
+              eq(notifications.userId, ctx.userId),
+              eq(notifications.dedupKey, args.dedupKey),
+              sessionId
+                ? eq(notifications.sessionId, sessionId)
+                : isNull(notifications.sessionId),
+              inArray(notifications.status, ["pending", "delivered"]),
+              gt(
+                notifications.createdAt,
+                new Date(Date.now() - windowSeconds * 1000)
+              )
+            )
+          )
+          .orderBy(desc(notifications.createdAt))
+          .limit(1);
+
+        if (duplicate) {
+          const [repeated] = await db
+            .update(notifications)
+            .set({
+              repeatCount: sql`${notifications.repeatCount} + 1`,
+              lastRepeatedAt: new Date(),
+              updatedAt: new Date(),
+            })
+            .where(eq(notifications.id, duplicate.id))
+            .returning();
+          return repeated;
+        }
+      }
+
       // Find escalation policy based on priority
       let policyId: string | null = null;
       const [route] = await db
@@ -283,6 +977,7 @@ builder.mutationField("createNotification", (t) =>
           shortCode,
           userId: ctx.userId,
           sessionId,
+          parentId,
           message: args.message,
           priority,
           context: args.context ? JSON.parse(args.context) : null,
@@ -290,6 +985,9 @@ builder.mutationField("createNotification", (t) =>
           options: args.options ?? [],
           status: "pending",
           policyId,
+          expiresAt,
+          defaultOption: args.defaultOption ?? null,
+          dedupKey: args.dedupKey ?? null,
         })
         .returning();
 
@@ -306,12 +1004,34 @@ builder.mutationField("createNotification", (t) =>
           .catch(() => {});
       }
 
+      // Schedule the deadline; the expiry function records the default
+      // response unless a human answers first.
+      if (expiresAt) {
+        inngest
+          .send({
+            name: "notification/expiry.scheduled",
+            data: {
+              notificationId: notification.id,
+              expiresAt: expiresAt.toISOString(),
+            },
+          })
+          .catch((err: unknown) => {
+            console.warn("Inngest send failed (expiry not scheduled):", err);
+          });
+      }
+
       // Re-fetch to return updated status/channels.
       const [updated] = await db
         .select()
         .from(notifications)
         .where(eq(notifications.id, notification.id));
 
+      emitWebhookEvent(
+        ctx.userId,
+        "notification.created",
+        notificationPayload(updated ?? notification)
+      );
+
       return updated ?? notification;
     },
   })
@@ -364,6 +1084,190 @@ builder.mutationField("respondToNotification", (t) =>
           console.warn("Inngest send failed (cancellation skipped):", err);
         });
 
+      emitWebhookEvent(
+        ctx.userId,
+        "response.created",
+        responsePayload(updated, {
+          channel: "web",
+          text: args.text ?? null,
+          selectedOption: args.selectedOption ?? null,
+        })
+      );
+
+      return updated;
+    },
+  })
+);
+
+builder.mutationField("updateNotification", (t) =>
+  t.field({
+    type: NotificationType,
+    nullable: true,
+    args: {
+      id: t.arg.string({ required: true }),
+      message: t.arg.string({ required: false }),
+      options: t.arg.stringList({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      if (args.message == null && args.options == null) {
+        throw new Error("Nothing to update: pass message or options");
+      }
+
+      const [notification] = await findNotificationByIdOrShortCode(
+        args.id,
+        ctx.userId
+      );
+
+      if (!notification) return null;
+
+      if (["archived", "expired", "retracted"].includes(notification.status)) {
+        throw new Error(`Cannot update a ${notification.status} notification`);
+      }
+
+      // The deadline records the default, so it must stay one of the options.
+      if (
+        args.options != null &&
+        notification.defaultOption &&
+        !args.options.includes(notification.defaultOption)
+      ) {
+        throw new Error(
+          `The new options must keep the default option "${notification.defaultOption}"`
+        );
+      }
+
+      // An edit after the human answered would change the question under
+      // their answer.
+      const [answered] = await db
+        .select({ id: responses.id })
+        .from(responses)
+        .where(eq(responses.notificationId, notification.id))
+        .limit(1);
+      if (answered) {
+        throw new Error("Cannot update a notification that has a response");
+      }
+
+      const [updated] = await db
+        .update(notifications)
+        .set({
+          message: args.message ?? notification.message,
+          options: args.options ?? notification.options,
+          editedAt: new Date(),
+          updatedAt: new Date(),
+        })
+        .where(eq(notifications.id, notification.id))
+        .returning();
+
+      const slackMessage = await findSlackMessage(notification.id);
+      if (slackMessage) {
+        const [user] = await db
+          .select({ slackTeamId: users.slackTeamId })
+          .from(users)
+          .where(eq(users.id, ctx.userId));
+        let replyToShortCode: string | undefined;
+        if (updated.parentId) {
+          const [parent] = await db
+            .select({ shortCode: notifications.shortCode })
+            .from(notifications)
+            .where(eq(notifications.id, updated.parentId));
+          replyToShortCode = parent?.shortCode;
+        }
+        await editSlackNotification(slackMessage.channel, slackMessage.ts, {
+          teamId: user?.slackTeamId ?? undefined,
+          replyToShortCode,
+          message: updated.message,
+          shortCode: updated.shortCode,
+          options: updated.options ?? undefined,
+          notificationId: updated.id,
+          threadTs: slackMessage.threadTs,
+          expiresAt: updated.expiresAt ?? undefined,
+          defaultOption: updated.defaultOption ?? undefined,
+        }).catch((err) =>
+          console.error("Failed to update Slack message:", err)
+        );
+      }
+
+      emitWebhookEvent(
+        ctx.userId,
+        "notification.updated",
+        notificationPayload(updated)
+      );
+
+      return updated;
+    },
+  })
+);
+
+builder.mutationField("retractNotification", (t) =>
+  t.field({
+    type: NotificationType,
+    nullable: true,
+    args: {
+      id: t.arg.string({ required: true }),
+      reason: t.arg.string({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const [notification] = await findNotificationByIdOrShortCode(
+        args.id,
+        ctx.userId
+      );
+
+      if (!notification) return null;
+
+      // Only an open question can be withdrawn; one that was answered,
+      // expired or archived already has its outcome.
+      if (notification.status !== "pending" && notification.status !== "delivered") {
+        throw new Error(`Cannot retract a ${notification.status} notification`);
+      }
+
+      const [updated] = await db
+        .update(notifications)
+        .set({
+          status: "retracted",
+          retractReason: args.reason ?? null,
+          updatedAt: new Date(),
+        })
+        .where(
+          and(
+            eq(notifications.id, notification.id),
+            inArray(notifications.status, ["pending", "delivered"])
+          )
+        )
+        .returning();
+      if (!updated) {
+        throw new Error("Cannot retract a notification that was just answered or closed");
+      }
+
+      // Stop escalation and any pending deadline (non-blocking)
+      inngest
+        .send({
+          name: "notification/retracted",
+          data: { notificationId: notification.id },
+        })
+        .catch((err: unknown) => {
+          console.warn("Inngest send failed (cancellation skipped):", err);
+        });
+
+      const slackMessage = await findSlackMessage(notification.id);
+      if (slackMessage) {
+        await markSlackMessageRetracted(
+          slackMessage.channel,
+          slackMessage.ts,
+          notification.shortCode,
+          notification.message,
+          args.reason ?? null
+        ).catch((err) =>
+          console.error("Failed to update Slack message:", err)
+        );
+      }
+
+      emitWebhookEvent(ctx.userId, "notification.retracted", {
+        ...notificationPayload(updated),
+        reason: args.reason ?? null,
+      });
+
       return updated;
     },
   })
@@ -436,6 +1340,11 @@ builder.mutationField("addReaction", (t) =>
 
       const emoji = args.emoji.replace(/^:|:$/g, "");
       await addSlackReaction(metadata.channel, response.externalId, emoji);
+      await db.insert(reactions).values({
+        responseId: response.id,
+        userId: ctx.userId,
+        emoji,
+      });
       return true;
     },
   })
@@ -447,9 +1356,10 @@ builder.queryField("activeFeed", (t) =>
     resolve: async (_parent, _args, ctx) => {
       if (!ctx.userId) throw new Error("Unauthorized");
 
-      return db
-        .select()
+      const rows = await db
+        .select(withSessionColumns)
         .from(notifications)
+        .leftJoin(agentSessions, eq(notifications.sessionId, agentSessions.id))
         .where(
           and(
             eq(notifications.userId, ctx.userId),
@@ -461,6 +1371,32 @@ builder.queryField("activeFeed", (t) =>
           )
         )
         .orderBy(desc(notifications.priority), asc(notifications.createdAt));
+      return withSession(rows);
+    },
+  })
+);
+
+const MAX_SNOOZE_MS = 30 * 24 * 60 * 60 * 1000;
+
+builder.queryField("snoozedNotifications", (t) =>
+  t.field({
+    type: [NotificationType],
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const rows = await db
+        .select(withSessionColumns)
+        .from(notifications)
+        .leftJoin(agentSessions, eq(notifications.sessionId, agentSessions.id))
+        .where(
+          and(
+            eq(notifications.userId, ctx.userId),
+            inArray(notifications.status, ["pending", "delivered"]),
+            gt(notifications.snoozedUntil, sql`now()`)
+          )
+        )
+        .orderBy(asc(notifications.snoozedUntil));
+      return withSession(rows);
     },
   })
 );
@@ -471,11 +1407,34 @@ builder.mutationField("snoozeNotification", (t) =>
     nullable: true,
     args: {
       id: t.arg.string({ required: true }),
-      minutes: t.arg.int({ required: true }),
+      minutes: t.arg.int(),
+      until: t.arg.string(),
     },
     resolve: async (_parent, args, ctx) => {
       if (!ctx.userId) throw new Error("Unauthorized");
 
+      let snoozedUntil: Date;
+      if (args.until != null) {
+        if (args.minutes != null) {
+          throw new Error("Pass minutes or until, not both");
+        }
+        snoozedUntil = new Date(args.until);
+        if (isNaN(snoozedUntil.getTime())) {
+          throw new Error("until must be an ISO 8601 time");
+        }
+      } else if (args.minutes != null) {
+        if (args.minutes <= 0) throw new Error("minutes must be positive");
+        snoozedUntil = new Date(Date.now() + args.minutes * 60 * 1000);
+      } else {
+        throw new Error("Pass minutes or until");
+      }
+      if (snoozedUntil.getTime() <= Date.now()) {
+        throw new Error("Snooze must end in the future");
+      }
+      if (snoozedUntil.getTime() - Date.now() > MAX_SNOOZE_MS) {
+        throw new Error("Snooze can be at most 30 days");
+      }
+
       const [notification] = await findNotificationByIdOrShortCode(
         args.id,
         ctx.userId
@@ -483,7 +1442,6 @@ builder.mutationField("snoozeNotification", (t) =>
 
       if (!notification) return null;
 
-      const snoozedUntil = new Date(Date.now() + args.minutes * 60 * 1000);
       const [updated] = await db
         .update(notifications)
         .set({ snoozedUntil, updatedAt: new Date() })
@@ -495,6 +1453,34 @@ builder.mutationField("snoozeNotification", (t) =>
   })
 );
 
+builder.mutationField("unsnoozeNotification", (t) =>
+  t.field({
+    type: NotificationType,
+    nullable: true,
+    args: {
+      id: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const [notification] = await findNotificationByIdOrShortCode(
+        args.id,
+        ctx.userId
+      );
+
+      if (!notification) return null;
+
+      const [updated] = await db
+        .update(notifications)
+        .set({ snoozedUntil: null, updatedAt: new Date() })
+        .where(eq(notifications.id, notification.id))
+        .returning();
+
+      return updated;
+    },
+  })
+);
+
 builder.mutationField("archiveNotification", (t) =>
   t.field({
     type: NotificationType,
@@ -518,6 +1504,12 @@ builder.mutationField("archiveNotification", (t) =>
         .where(eq(notifications.id, notification.id))
         .returning();
 
+      emitWebhookEvent(
+        ctx.userId,
+        "notification.archived",
+        notificationPayload(updated)
+      );
+
       return updated;
     },
   })
@@ -538,11 +1530,67 @@ builder.mutationField("archiveAllNotifications", (t) =>
             inArray(notifications.status, ["pending", "delivered"])
           )
         )
-        .returning({ id: notifications.id });
+        .returning();
+
+      for (const archived of result) {
+        emitWebhookEvent(
+          ctx.userId,
+          "notification.archived",
+          notificationPayload(archived)
+        );
+      }
 
       return result.length;
     },
   })
 );
 
+const MAX_TAG_BATCH = 100;
+
+builder.mutationField("tagNotifications", (t) =>
+  t.field({
+    type: [NotificationType],
+    description:
+      "Add tags to several notifications at once. Existing tags are kept; unknown IDs are ignored.",
+    args: {
+      ids: t.arg.stringList({ required: true }),
+      tags: t.arg.stringList({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const tags = [...new Set(args.tags.map((tag) => tag.trim()))].filter(
+        Boolean
+      );
+      if (tags.length === 0) throw new Error("At least one tag is required");
+      if (args.ids.length > MAX_TAG_BATCH) {
+        throw new Error(`At most ${MAX_TAG_BATCH} notifications per call`);
+      }
+      if (args.ids.length === 0) return [];
+
+      const found = await db
+        .select()
+        .from(notifications)
+        .where(
+          and(
+            eq(notifications.userId, ctx.userId),
+            inArray(notifications.id, args.ids)
+          )
+        );
+
+      const updated = [];
+      for (const notification of found) {
+        const merged = [...new Set([...(notification.tags ?? []), ...tags])];
+        const [row] = await db
+          .update(notifications)
+          .set({ tags: merged, updatedAt: new Date() })
+          .where(eq(notifications.id, notification.id))
+          .returning();
+        updated.push(row);
+      }
+      return updated;
+    },
+  })
+);
+
 export { NotificationType };
@@ -0,0 +1,180 @@
+import builder from "./builder";
+import { db } from "@/db";
+import { sessionProgress, agentSessions, users } from "@/db/schema";
+import { eq, and, gt, isNull, desc } from "drizzle-orm";
+import { postSlackProgress, updateSlackProgress } from "@/channels/slack";
+
+// Progress lines untouched for this long drop out of the feed.
+const PROGRESS_STALE_MS = 6 * 60 * 60 * 1000;
+
+const ProgressType = builder.objectRef<{
+  id: string;
+  key: string;
+  message: string;
+  percent: number | null;
+  sessionKey: string;
+  workspace: string | null;
+  completedAt: Date | null;
+  updatedAt: Date;
+}>("Progress");
+
+ProgressType.implement({
+  fields: (t) => ({
+    id: t.exposeString("id"),
+    key: t.exposeString("key"),
+    message: t.exposeString("message"),
+    percent: t.exposeInt("percent", { nullable: true }),
+    sessionKey: t.exposeString("sessionKey"),
+    workspace: t.exposeString("workspace", { nullable: true }),
+    completedAt: t.string({
+      nullable: true,
+      resolve: (p) => (p.completedAt ? p.completedAt.toISOString() : null),
+    }),
+    updatedAt: t.string({
+      resolve: (p) => p.updatedAt.toISOString(),
+    }),
+  }),
+});
+
+builder.queryField("activeProgress", (t) =>
+  t.field({
+    type: [ProgressType],
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const rows = await db
+        .select({ progress: sessionProgress, session: agentSessions })
+        .from(sessionProgress)
+        .innerJoin(agentSessions, eq(sessionProgress.sessionId, agentSessions.id))
+        .where(
+          and(
+            eq(sessionProgress.userId, ctx.userId),
+            isNull(sessionProgress.completedAt),
+            gt(sessionProgress.updatedAt, new Date(Date.now() - PROGRESS_STALE_MS))
+          )
+        )
+        .orderBy(desc(sessionProgress.updatedAt));
+
+      return rows.map(({ progress, session }) => ({
+        ...progress,
+        sessionKey: session.sessionKey,
+        workspace: session.workspace,
+      }));
+    },
+  })
+);
+
+builder.mutationField("upsertProgress", (t) =>
+  t.field({
+    type: ProgressType,
+    args: {
+      sessionKey: t.arg.string({ required: true }),
+      workspace: t.arg.string({ required: false }),
+      key: t.arg.string({ required: true }),
+      message: t.arg.string({ required: true }),
+      percent: t.arg.int({ required: false }),
+      done: t.arg.boolean({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      if (args.percent != null && (args.percent < 0 || args.percent > 100)) {
+        throw new Error("percent must be between 0 and 100");
+      }
+
+      let [session] = await db
+        .select()
+        .from(agentSessions)
+        .where(
+          and(
+            eq(agentSessions.sessionKey, args.sessionKey),
+            eq(agentSessions.userId, ctx.userId)
+          )
+        );
+
+      if (!session) {
+        [session] = await db
+          .insert(agentSessions)
+          .values({
+            userId: ctx.userId,
+            sessionKey: args.sessionKey,
+            workspace: args.workspace,
+          })
+          .returning();
+      }
+
+      // One line per key: a concurrent first update can't create a second
+      // row, and leaving out percent keeps the one already shown.
+      const now = new Date();
+      const done = args.done ?? false;
+      const values = {
+        message: args.message,
+        completedAt: done ? now : null,
+        updatedAt: now,
+        ...(args.percent != null && { percent: args.percent }),
+      };
+
+      let [progress] = await db
+        .insert(sessionProgress)
+        .values({
+          userId: ctx.userId,
+          sessionId: session.id,
+          key: args.key,
+          ...values,
+        })
+        .onConflictDoUpdate({
+          target: [sessionProgress.sessionId, sessionProgress.key],
+          set: values,
+        })
+        .returning();
+
+      // Mirror into Slack: edit the existing message rather than posting a
+      // new one for every step.
+      const [user] = await db
+        .select()
+        .from(users)
+        .where(eq(users.id, ctx.userId));
+
+      if (user?.slackUserId) {
+        const slackProgress = {
+          key: progress.key,
+          message: progress.message,
+          percent: progress.percent,
+          done,
+        };
+        const teamId = user.slackTeamId ?? undefined;
+        try {
+          if (progress.slackTs && progress.slackChannelId) {
+            await updateSlackProgress(
+              progress.slackChannelId,
+              progress.slackTs,
+              teamId,
+              slackProgress
+            );
+          } else {
+            const result = await postSlackProgress(
+              user.slackUserId,
+              session.slackThreadTs ?? undefined,
+              teamId,
+              slackProgress
+            );
+            [progress] = await db
+              .update(sessionProgress)
+              .set({ slackTs: result.ts, slackChannelId: result.channel })
+              .where(eq(sessionProgress.id, progress.id))
+              .returning();
+          }
+        } catch (err) {
+          console.error("Slack progress update failed:", err);
+        }
+      }
+
+      return {
+        ...progress,
+        sessionKey: session.sessionKey,
+        workspace: session.workspace,
+      };
+    },
+  })
+);
+
+export { ProgressType };
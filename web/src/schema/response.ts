@@ -1,4 +1,45 @@
 import builder from "./builder";
+import { db } from "@/db";
+import { users, reactions } from "@/db/schema";
+import { asc, inArray } from "drizzle-orm";
+import { createLoader, groupBy } from "./loader";
+import {
+  isResponseId,
+  listResponseEvents,
+  MAX_RESPONSE_EVENTS,
+  type ResponseEvent,
+} from "@/channels/response-feed";
+
+const ReactionType = builder.objectRef<{
+  emoji: string;
+  createdAt: Date;
+}>("Reaction");
+
+ReactionType.implement({
+  fields: (t) => ({
+    emoji: t.exposeString("emoji"),
+    createdAt: t.string({
+      resolve: (r) => r.createdAt.toISOString(),
+    }),
+  }),
+});
+
+const loadResponder = createLoader(async (ids: string[]) => {
+  const rows = await db
+    .select({ id: users.id, name: users.name, email: users.email })
+    .from(users)
+    .where(inArray(users.id, ids));
+  return new Map(rows.map((u) => [u.id, u.name ?? u.email]));
+});
+
+const loadReactions = createLoader(async (responseIds: string[]) => {
+  const rows = await db
+    .select()
+    .from(reactions)
+    .where(inArray(reactions.responseId, responseIds))
+    .orderBy(asc(reactions.createdAt));
+  return groupBy(rows, (r) => r.responseId);
+});
 
 const ResponseType = builder.objectRef<{
   id: string;
@@ -6,6 +47,7 @@ const ResponseType = builder.objectRef<{
   channel: string;
   text: string | null;
   selectedOption: string | null;
+  auto: boolean;
   responderId: string;
   createdAt: Date;
 }>("Response");
@@ -17,11 +59,83 @@ ResponseType.implement({
     channel: t.exposeString("channel"),
     text: t.exposeString("text", { nullable: true }),
     selectedOption: t.exposeString("selectedOption", { nullable: true }),
+    auto: t.exposeBoolean("auto"),
     responderId: t.exposeString("responderId"),
+    responder: t.string({
+      nullable: true,
+      description: "Name (or email) of whoever responded.",
+      resolve: async (r, _args, ctx) =>
+        (await loadResponder(ctx, r.responderId)) ?? null,
+    }),
+    reactions: t.field({
+      type: [ReactionType],
+      resolve: async (r, _args, ctx) =>
+        (await loadReactions(ctx, r.id)) ?? [],
+    }),
     createdAt: t.string({
       resolve: (r) => r.createdAt.toISOString(),
     }),
   }),
 });
 
+const ResponseEventType = builder.objectRef<ResponseEvent>("ResponseEvent");
+
+ResponseEventType.implement({
+  description: "A response together with the notification it answers.",
+  fields: (t) => ({
+    id: t.exposeString("id"),
+    notificationId: t.exposeString("notificationId"),
+    shortCode: t.exposeString("shortCode"),
+    message: t.exposeString("message"),
+    priority: t.exposeInt("priority"),
+    tags: t.exposeStringList("tags"),
+    sessionKey: t.exposeString("sessionKey", { nullable: true }),
+    workspace: t.exposeString("workspace", { nullable: true }),
+    channel: t.exposeString("channel"),
+    text: t.exposeString("text", { nullable: true }),
+    selectedOption: t.exposeString("selectedOption", { nullable: true }),
+    auto: t.exposeBoolean("auto"),
+    createdAt: t.string({
+      resolve: (r) => r.createdAt.toISOString(),
+    }),
+  }),
+});
+
+builder.queryField("responseEvents", (t) =>
+  t.field({
+    type: [ResponseEventType],
+    description: `Responses after the given response id, or else created after the given timestamp, oldest first (at most ${MAX_RESPONSE_EVENTS}).`,
+    args: {
+      after: t.arg.string({ required: false }),
+      afterId: t.arg.string({ required: false }),
+      sessionKey: t.arg.string({ required: false }),
+      limit: t.arg.int({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      let after: Date | undefined;
+      if (args.after != null) {
+        after = new Date(args.after);
+        if (isNaN(after.getTime())) {
+          throw new Error(`Invalid timestamp: ${args.after}`);
+        }
+      }
+      if (args.afterId != null && !isResponseId(args.afterId)) {
+        throw new Error(`Invalid response id: ${args.afterId}`);
+      }
+      if (args.limit != null && args.limit < 1) {
+        throw new Error("limit must be positive");
+      }
+
+      return listResponseEvents(ctx.userId, {
+        after,
+        afterId: args.afterId ?? undefined,
+        sessionKey: args.sessionKey ?? undefined,
+        limit: args.limit ?? undefined,
+      });
+    },
+  })
+);
+
 export { ResponseType };
@@ -1,8 +1,14 @@
 import crypto from "crypto";
 import builder from "./builder";
 import { db } from "@/db";
-import { users } from "@/db/schema";
+import {
+  users,
+  DEFAULT_PREFERENCES,
+  type NotificationPreferences,
+} from "@/db/schema";
 import { eq } from "drizzle-orm";
+import { activeHold, type Hold } from "@/channels/quiet-hours";
+import { inngest } from "@/inngest/client";
 
 const UserType = builder.objectRef<{
   id: string;
@@ -14,9 +20,45 @@ const UserType = builder.objectRef<{
   timezone: string | null;
   quietHoursStart: string | null;
   quietHoursEnd: string | null;
+  preferences: NotificationPreferences | null;
+  dndUntil: Date | null;
   createdAt: Date;
 }>("User");
 
+const PreferencesType = builder.objectRef<Required<NotificationPreferences>>(
+  "NotificationPreferences"
+);
+
+PreferencesType.implement({
+  fields: (t) => ({
+    smsMinPriority: t.exposeInt("smsMinPriority"),
+    breakthroughPriority: t.exposeInt("breakthroughPriority"),
+  }),
+});
+
+const HoldType = builder.objectRef<Hold>("DoNotDisturb");
+
+HoldType.implement({
+  description:
+    "Why pushes are currently held: an explicit do-not-disturb or quiet hours.",
+  fields: (t) => ({
+    reason: t.exposeString("reason"),
+    until: t.string({ resolve: (h) => h.until.toISOString() }),
+    breakthroughPriority: t.exposeInt("breakthroughPriority"),
+  }),
+});
+
+const HH_MM_RE = /^([01]\d|2[0-3]):[0-5]\d$/;
+
+function isValidTimezone(tz: string): boolean {
+  try {
+    new Intl.DateTimeFormat("en-US", { timeZone: tz });
+    return true;
+  } catch {
+    return false;
+  }
+}
+
 UserType.implement({
   fields: (t) => ({
     id: t.exposeString("id"),
@@ -28,6 +70,19 @@ UserType.implement({
     timezone: t.exposeString("timezone", { nullable: true }),
     quietHoursStart: t.exposeString("quietHoursStart", { nullable: true }),
     quietHoursEnd: t.exposeString("quietHoursEnd", { nullable: true }),
+    preferences: t.field({
+      type: PreferencesType,
+      resolve: (user) => ({ ...DEFAULT_PREFERENCES, ...user.preferences }),
+    }),
+    dndUntil: t.string({
+      nullable: true,
+      resolve: (user) => user.dndUntil?.toISOString() ?? null,
+    }),
+    doNotDisturb: t.field({
+      type: HoldType,
+      nullable: true,
+      resolve: (user) => activeHold(user),
+    }),
     createdAt: t.string({
       resolve: (user) => user.createdAt.toISOString(),
     }),
@@ -49,26 +104,73 @@ builder.queryField("me", (t) =>
   })
 );
 
+// 15-minute, single-use codes the user DMs to a chat bot to link accounts.
+const LINK_CODE_TTL_MS = 15 * 60 * 1000;
+
+function generateLinkCode(): string {
+  const chars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"; // no ambiguous chars
+  const bytes = crypto.randomBytes(6);
+  let code = "LINK-";
+  for (let i = 0; i < 6; i++) {
+    code += chars[bytes[i] % chars.length];
+  }
+  return code;
+}
+
 builder.mutationField("generateSlackLinkCode", (t) =>
   t.field({
     type: "String",
     resolve: async (_parent, _args, ctx) => {
       if (!ctx.userId) throw new Error("Unauthorized");
 
-      const chars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"; // no ambiguous chars
-      const bytes = crypto.randomBytes(6);
-      let code = "LINK-";
-      for (let i = 0; i < 6; i++) {
-        code += chars[bytes[i] % chars.length];
-      }
+      const code = generateLinkCode();
+      await db
+        .update(users)
+        .set({
+          slackLinkCode: code,
+          slackLinkCodeExpiresAt: new Date(Date.now() + LINK_CODE_TTL_MS),
+          updatedAt: new Date(),
+        })
+        .where(eq(users.id, ctx.userId));
+
+      return code;
+    },
+  })
+);
 
-      const expiresAt = new Date(Date.now() + 15 * 60 * 1000); // 15 minutes
+builder.mutationField("generateDiscordLinkCode", (t) =>
+  t.field({
+    type: "String",
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
 
+      const code = generateLinkCode();
       await db
         .update(users)
         .set({
-          slackLinkCode: code,
-          slackLinkCodeExpiresAt: expiresAt,
+          discordLinkCode: code,
+          discordLinkCodeExpiresAt: new Date(Date.now() + LINK_CODE_TTL_MS),
+          updatedAt: new Date(),
+        })
+        .where(eq(users.id, ctx.userId));
+
+      return code;
+    },
+  })
+);
+
+builder.mutationField("generateTeamsLinkCode", (t) =>
+  t.field({
+    type: "String",
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const code = generateLinkCode();
+      await db
+        .update(users)
+        .set({
+          teamsLinkCode: code,
+          teamsLinkCodeExpiresAt: new Date(Date.now() + LINK_CODE_TTL_MS),
           updatedAt: new Date(),
         })
         .where(eq(users.id, ctx.userId));
@@ -92,4 +194,152 @@ builder.queryField("slackConnected", (t) =>
   })
 );
 
+builder.queryField("discordConnected", (t) =>
+  t.field({
+    type: "Boolean",
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      const [user] = await db
+        .select({ discordUserId: users.discordUserId })
+        .from(users)
+        .where(eq(users.id, ctx.userId));
+      return !!user?.discordUserId;
+    },
+  })
+);
+
+builder.queryField("teamsConnected", (t) =>
+  t.field({
+    type: "Boolean",
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      const [user] = await db
+        .select({ teamsConversation: users.teamsConversation })
+        .from(users)
+        .where(eq(users.id, ctx.userId));
+      return !!user?.teamsConversation;
+    },
+  })
+);
+
+builder.mutationField("updateSettings", (t) =>
+  t.field({
+    type: UserType,
+    description:
+      "Update timezone, quiet hours and delivery preferences. Omitted arguments are left unchanged; clearQuietHours removes quiet hours.",
+    args: {
+      timezone: t.arg.string({ required: false }),
+      quietHoursStart: t.arg.string({ required: false }),
+      quietHoursEnd: t.arg.string({ required: false }),
+      clearQuietHours: t.arg.boolean({ required: false }),
+      smsMinPriority: t.arg.int({ required: false }),
+      breakthroughPriority: t.arg.int({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      if (args.timezone != null && !isValidTimezone(args.timezone)) {
+        throw new Error(`Unknown timezone: ${args.timezone}`);
+      }
+      if ((args.quietHoursStart == null) !== (args.quietHoursEnd == null)) {
+        throw new Error("quietHoursStart and quietHoursEnd must be set together");
+      }
+      for (const value of [args.quietHoursStart, args.quietHoursEnd]) {
+        if (value != null && !HH_MM_RE.test(value)) {
+          throw new Error(`Quiet hours must be HH:MM, got ${value}`);
+        }
+      }
+      if (args.clearQuietHours && args.quietHoursStart != null) {
+        throw new Error("clearQuietHours cannot be combined with quiet hours");
+      }
+      if (
+        args.smsMinPriority != null &&
+        (args.smsMinPriority < 1 || args.smsMinPriority > 5)
+      ) {
+        throw new Error("smsMinPriority must be between 1 and 5");
+      }
+      if (
+        args.breakthroughPriority != null &&
+        (args.breakthroughPriority < 1 || args.breakthroughPriority > 5)
+      ) {
+        throw new Error("breakthroughPriority must be between 1 and 5");
+      }
+
+      const [user] = await db
+        .select()
+        .from(users)
+        .where(eq(users.id, ctx.userId));
+      if (!user) throw new Error("User not found");
+
+      const updates: Partial<typeof users.$inferInsert> = {
+        updatedAt: new Date(),
+      };
+      if (args.timezone != null) updates.timezone = args.timezone;
+      if (args.quietHoursStart != null) {
+        updates.quietHoursStart = args.quietHoursStart;
+        updates.quietHoursEnd = args.quietHoursEnd;
+      }
+      if (args.clearQuietHours) {
+        updates.quietHoursStart = null;
+        updates.quietHoursEnd = null;
+      }
+      if (args.smsMinPriority != null || args.breakthroughPriority != null) {
+        updates.preferences = {
+          ...user.preferences,
+          ...(args.smsMinPriority != null && {
+            smsMinPriority: args.smsMinPriority,
+          }),
+          ...(args.breakthroughPriority != null && {
+            breakthroughPriority: args.breakthroughPriority,
+          }),
+        };
+      }
+
+      const [updated] = await db
+        .update(users)
+        .set(updates)
+        .where(eq(users.id, ctx.userId))
+        .returning();
+      return updated;
+    },
+  })
+);
+
+builder.mutationField("setDoNotDisturb", (t) =>
+  t.field({
+    type: UserType,
+    description:
+      "Hold pushes below the breakthrough priority for the given minutes; 0 turns do-not-disturb off.",
+    args: {
+      minutes: t.arg.int({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+      if (args.minutes < 0) throw new Error("minutes must not be negative");
+
+      const [updated] = await db
+        .update(users)
+        .set({
+          dndUntil:
+            args.minutes > 0
+              ? new Date(Date.now() + args.minutes * 60 * 1000)
+              : null,
+          updatedAt: new Date(),
+        })
+        .where(eq(users.id, ctx.userId))
+        .returning();
+
+      // Release anything held by the DND that just ended.
+      if (args.minutes === 0) {
+        inngest
+          .send({ name: "user/dnd-cleared", data: { userId: ctx.userId } })
+          .catch((err: unknown) => {
+            console.warn("Inngest send failed (held notifications not released):", err);
+          });
+      }
+      return updated;
+    },
+  })
+);
+
 export { UserType };
@@ -0,0 +1,206 @@
+import crypto from "crypto";
+import builder from "./builder";
+import { db } from "@/db";
+import { webhookSubscriptions } from "@/db/schema";
+import { eq, and } from "drizzle-orm";
+import { checkWebhookURL, sendWebhook, WebhookError } from "@/channels/webhook";
+import { WEBHOOK_EVENTS } from "@/channels/events";
+
+const MIN_SECRET_LENGTH = 16;
+const MAX_SUBSCRIPTIONS = 20;
+
+const SubscriptionType = builder.objectRef<{
+  id: string;
+  url: string;
+  events: string[];
+  lastDeliveryAt: Date | null;
+  lastStatus: number | null;
+  lastError: string | null;
+  createdAt: Date;
+  /** Only set when the subscription was just created. */
+  secret?: string;
+}>("WebhookSubscription");
+
+SubscriptionType.implement({
+  fields: (t) => ({
+    id: t.exposeString("id"),
+    url: t.exposeString("url"),
+    events: t.exposeStringList("events"),
+    secret: t.string({
+      nullable: true,
+      description: "Signing secret; only returned when the subscription is created.",
+      resolve: (s) => s.secret ?? null,
+    }),
+    lastDeliveryAt: t.string({
+      nullable: true,
+      resolve: (s) => s.lastDeliveryAt?.toISOString() ?? null,
+    }),
+    lastStatus: t.exposeInt("lastStatus", { nullable: true }),
+    lastError: t.exposeString("lastError", { nullable: true }),
+    createdAt: t.string({ resolve: (s) => s.createdAt.toISOString() }),
+  }),
+});
+
+const TestResultType = builder.objectRef<{
+  ok: boolean;
+  status: number | null;
+  error: string | null;
+}>("WebhookTestResult");
+
+TestResultType.implement({
+  fields: (t) => ({
+    ok: t.exposeBoolean("ok"),
+    status: t.exposeInt("status", { nullable: true }),
+    error: t.exposeString("error", { nullable: true }),
+  }),
+});
+
+function withoutSecret<T extends { secret: string }>(row: T) {
+  const { secret: _secret, ...rest } = row;
+  return rest;
+}
+
+async function findSubscription(id: string, userId: string) {
+  const [sub] = await db
+    .select()
+    .from(webhookSubscriptions)
+    .where(
+      and(eq(webhookSubscriptions.id, id), eq(webhookSubscriptions.userId, userId))
+    );
+  return sub;
+}
+
+builder.queryField("webhookEvents", (t) =>
+  t.stringList({
+    description: "Event names webhook subscriptions can receive.",
+    resolve: () => [...WEBHOOK_EVENTS],
+  })
+);
+
+builder.queryField("webhookSubscriptions", (t) =>
+  t.field({
+    type: [SubscriptionType],
+    resolve: async (_parent, _args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const rows = await db
+        .select()
+        .from(webhookSubscriptions)
+        .where(eq(webhookSubscriptions.userId, ctx.userId));
+      return rows.map(withoutSecret);
+    },
+  })
+);
+
+builder.mutationField("createWebhookSubscription", (t) =>
+  t.field({
+    type: SubscriptionType,
+    description:
+      'Subscribe a URL to lifecycle events ("*" for all). Without a secret one is generated; it is only returned here.',
+    args: {
+      url: t.arg.string({ required: true }),
+      events: t.arg.stringList({ required: true }),
+      secret: t.arg.string({ required: false }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const url = await checkWebhookURL(args.url);
+
+      const events = [...new Set(args.events.map((e) => e.trim()))];
+      if (events.length === 0) throw new Error("At least one event is required");
+      for (const e of events) {
+        if (e !== "*" && !(WEBHOOK_EVENTS as readonly string[]).includes(e)) {
+          throw new Error(
+            `Unknown event "${e}" (expected ${WEBHOOK_EVENTS.join(", ")} or *)`
+          );
+        }
+      }
+
+      if (args.secret != null && args.secret.length < MIN_SECRET_LENGTH) {
+        throw new Error(
+          `Webhook secret must be at least ${MIN_SECRET_LENGTH} characters`
+        );
+      }
+      const secret =
+        args.secret ?? `whsec_${crypto.randomBytes(24).toString("hex")}`;
+
+      const existing = await db
+        .select({ id: webhookSubscriptions.id })
+        .from(webhookSubscriptions)
+        .where(eq(webhookSubscriptions.userId, ctx.userId));
+      if (existing.length >= MAX_SUBSCRIPTIONS) {
+        throw new Error(`At most ${MAX_SUBSCRIPTIONS} webhook subscriptions`);
+      }
+
+      const [created] = await db
+        .insert(webhookSubscriptions)
+        .values({ userId: ctx.userId, url: url.toString(), secret, events })
+        .returning();
+      return created;
+    },
+  })
+);
+
+builder.mutationField("deleteWebhookSubscription", (t) =>
+  t.field({
+    type: "Boolean",
+    args: {
+      id: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const sub = await findSubscription(args.id, ctx.userId);
+      if (!sub) throw new Error(`Webhook subscription not found: ${args.id}`);
+
+      await db
+        .delete(webhookSubscriptions)
+        .where(eq(webhookSubscriptions.id, sub.id));
+      return true;
+    },
+  })
+);
+
+builder.mutationField("testWebhookSubscription", (t) =>
+  t.field({
+    type: TestResultType,
+    description: "Send a signed ping event now and report how the endpoint answered.",
+    args: {
+      id: t.arg.string({ required: true }),
+    },
+    resolve: async (_parent, args, ctx) => {
+      if (!ctx.userId) throw new Error("Unauthorized");
+
+      const sub = await findSubscription(args.id, ctx.userId);
+      if (!sub) throw new Error(`Webhook subscription not found: ${args.id}`);
+
+      let result: { ok: boolean; status: number | null; error: string | null };
+      try {
+        const { status } = await sendWebhook({
+          url: sub.url,
+          secret: sub.secret,
+          event: "ping",
+          data: { subscriptionId: sub.id },
+        });
+        result = { ok: true, status, error: null };
+      } catch (err) {
+        result = {
+          ok: false,
+          status: err instanceof WebhookError ? err.status : null,
+          error: err instanceof Error ? err.message : String(err),
+        };
+      }
+
+      await db
+        .update(webhookSubscriptions)
+        .set({
+          lastDeliveryAt: new Date(),
+          lastStatus: result.status,
+          lastError: result.error,
+        })
+        .where(eq(webhookSubscriptions.id, sub.id));
+      return result;
+    },
+  })
+);
@@ -0,0 +1,149 @@
+import { describe, it, expect, vi, beforeEach } from "vitest";
+
+const { mockChain, setupDb, mockRecordResponse, mockParseInbound } = vi.hoisted(() => {
+  let dbResults: any[][] = [];
+  let dbCallIndex = 0;
+
+  const chain: any = {};
+  const methods = [
+    "select", "from", "where", "update", "set", "insert",
+    "values", "delete", "returning", "orderBy", "limit",
+  ];
+  for (const m of methods) {
+    chain[m] = (..._args: any[]) => chain;
+  }
+  chain.then = (resolve: any, reject?: any) => {
+    const result = dbResults[dbCallIndex] ?? [];
+    dbCallIndex++;
+    return Promise.resolve(result).then(resolve, reject);
+  };
+
+  function setupDb(...results: any[][]) {
+    dbResults = results;
+    dbCallIndex = 0;
+  }
+
+  const mockRecordResponse = { fn: async (..._args: any[]) => {} };
+  const mockParseInbound = { fn: async (..._args: any[]): Promise<any> => ({ type: "noActive" }) };
+
+  return { mockChain: chain, setupDb, mockRecordResponse, mockParseInbound };
+});
+
+vi.mock("@/db", () => ({ db: mockChain }));
+
+vi.mock("@/db/schema", () => {
+  const table = (name: string) =>
+    new Proxy({}, { get: (_, p) => `${name}.${String(p)}` });
+  return {
+    notifications: table("notifications"),
+    users: table("users"),
+  };
+});
+
+vi.mock("drizzle-orm", () => ({
+  eq: () => {},
+  and: () => {},
+  gt: () => {},
+}));
+
+vi.mock("@/channels/discord", () => ({
+  openDiscordDM: () => Promise.resolve("DM1"),
+}));
+
+vi.mock("@/webhooks/record-response", () => ({
+  recordResponse: (...args: any[]) => mockRecordResponse.fn(...args),
+}));
+
+vi.mock("@/webhooks/parse-inbound", () => ({
+  parseInboundMessage: (...args: any[]) => mockParseInbound.fn(...args),
+}));
+
+import { handleDiscordInteraction } from "../discord";
+
+const user = { id: "user-1", email: "me@example.com", discordUserId: "D123" };
+
+describe("handleDiscordInteraction", () => {
+  beforeEach(() => {
+    setupDb();
+    mockRecordResponse.fn = vi.fn().mockResolvedValue(undefined);
+    mockParseInbound.fn = vi.fn().mockResolvedValue({ type: "noActive" });
+  });
+
+  it("answers pings", async () => {
+    expect(await handleDiscordInteraction({ type: 1, id: "i1" })).toEqual({ type: 1 });
+  });
+
+  it("links an account with a valid code", async () => {
+    setupDb(
+      [user],   // link code lookup
+      [],       // update user
+    );
+
+    const response = await handleDiscordInteraction({
+      type: 2,
+      id: "i1",
+      user: { id: "D123" },
+      data: { name: "link", options: [{ name: "code", value: "link-abc234" }] },
+    });
+
+    expect(response.data?.content).toContain("Linked!");
+  });
+
+  it("rejects an expired link code", async () => {
+    setupDb([]);
+
+    const response = await handleDiscordInteraction({
+      type: 2,
+      id: "i1",
+      member: { user: { id: "D123" } },
+      data: { name: "link", options: [{ name: "code", value: "LINK-ABC234" }] },
+    });
+
+    expect(response.data?.content).toContain("Invalid or expired");
+  });
+
+  it("records /reply text as a response", async () => {
+    const notification = { id: "notif-1", shortCode: "ABC" };
+    mockParseInbound.fn = vi.fn().mockResolvedValue({
+      type: "shortCode",
+      notification,
+      text: "ship it",
+    });
+    setupDb([user]);
+
+    const response = await handleDiscordInteraction({
+      type: 2,
+      id: "i1",
+      user: { id: "D123" },
+      data: { name: "reply", options: [{ name: "text", value: "ABC ship it" }] },
+    });
+
+    expect(mockParseInbound.fn).toHaveBeenCalledWith("ABC ship it", "user-1");
+    expect(mockRecordResponse.fn).toHaveBeenCalledWith(notification, "user-1", "discord", "ship it");
+    expect(response.data?.content).toBe("Response recorded.");
+  });
+
+  it("records option buttons and clears them from the message", async () => {
+    const notification = { id: "notif-1", userId: "user-1", options: ["Yes", "No"] };
+    setupDb(
+      [user],           // user lookup
+      [notification],   // notification lookup
+    );
+
+    const response = await handleDiscordInteraction({
+      type: 3,
+      id: "i1",
+      user: { id: "D123" },
+      data: { custom_id: "respond:notif-1:1" },
+      message: { id: "M1", content: "**[ABC]** Deploy?" },
+    });
+
+    expect(mockRecordResponse.fn).toHaveBeenCalledWith(
+      notification, "user-1", "discord", undefined, "No", "M1",
+    );
+    expect(response).toEqual({
+      type: 7,
+      data: { content: "**[ABC]** Deploy?\n\n✅ Selected: **No**", components: [] },
+    });
+  });
+});
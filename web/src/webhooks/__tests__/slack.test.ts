@@ -40,6 +40,7 @@ vi.mock("@/db/schema", () => {
   return {
     notifications: table("notifications"),
     responses: table("responses"),
+    reactions: table("reactions"),
     deliveries: table("deliveries"),
     users: table("users"),
     agentSessions: table("agentSessions"),
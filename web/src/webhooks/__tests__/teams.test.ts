@@ -0,0 +1,32 @@
+import { describe, it, expect, vi } from "vitest";
+
+vi.mock("@/db", () => ({ db: {} }));
+vi.mock("@/db/schema", () => ({ users: {} }));
+vi.mock("drizzle-orm", () => ({ eq: () => {}, and: () => {}, gt: () => {} }));
+vi.mock("@/channels/teams", () => ({ postTeamsMessage: () => Promise.resolve() }));
+vi.mock("@/webhooks/record-response", () => ({ recordResponse: () => Promise.resolve() }));
+vi.mock("@/webhooks/parse-inbound", () => ({ parseInboundMessage: () => Promise.resolve() }));
+
+import { sameServiceUrl } from "../teams";
+
+describe("sameServiceUrl", () => {
+  it("matches the same endpoint regardless of trailing slash or host case", () => {
+    expect(
+      sameServiceUrl("https://smba.trafficmanager.net/emea/", "https://SMBA.trafficmanager.net/emea")
+    ).toBe(true);
+  });
+
+  it("rejects a different endpoint", () => {
+    expect(
+      sameServiceUrl("https://smba.trafficmanager.net/emea/", "https://attacker.example/emea/")
+    ).toBe(false);
+    expect(
+      sameServiceUrl("https://smba.trafficmanager.net/emea/", "https://smba.trafficmanager.net/amer/")
+    ).toBe(false);
+  });
+
+  it("rejects a missing or malformed activity serviceUrl", () => {
+    expect(sameServiceUrl("https://smba.trafficmanager.net/emea/", undefined)).toBe(false);
+    expect(sameServiceUrl("https://smba.trafficmanager.net/emea/", "not a url")).toBe(false);
+  });
+});
@@ -0,0 +1,193 @@
+import { db } from "@/db";
+import { notifications, users } from "@/db/schema";
+import { eq, and, gt } from "drizzle-orm";
+import { parseInboundMessage } from "./parse-inbound";
+import { recordResponse } from "./record-response";
+import { openDiscordDM } from "@/channels/discord";
+
+// Interaction and callback types from the Discord API.
+const PING = 1;
+const APPLICATION_COMMAND = 2;
+const MESSAGE_COMPONENT = 3;
+
+const PONG = 1;
+const CHANNEL_MESSAGE = 4;
+const UPDATE_MESSAGE = 7;
+
+// Flag for replies only the invoking user can see.
+const EPHEMERAL = 1 << 6;
+
+export interface DiscordInteraction {
+  type: number;
+  id: string;
+  data?: {
+    name?: string;
+    custom_id?: string;
+    options?: Array<{ name: string; value: string }>;
+  };
+  // DMs carry `user`; guild interactions carry `member.user`.
+  user?: { id: string };
+  member?: { user: { id: string } };
+  message?: { id: string; content: string };
+}
+
+type InteractionResponse = { type: number; data?: Record<string, unknown> };
+
+function reply(content: string): InteractionResponse {
+  return { type: CHANNEL_MESSAGE, data: { content, flags: EPHEMERAL } };
+}
+
+function option(interaction: DiscordInteraction, name: string): string {
+  return (
+    interaction.data?.options?.find((o) => o.name === name)?.value ?? ""
+  ).trim();
+}
+
+/**
+ * Handle a Discord interaction: the /link and /reply slash commands and
+ * option buttons on notification messages.
+ */
+export async function handleDiscordInteraction(
+  interaction: DiscordInteraction
+): Promise<InteractionResponse> {
+  if (interaction.type === PING) return { type: PONG };
+
+  const discordUserId = interaction.user?.id ?? interaction.member?.user.id;
+  if (!discordUserId) return reply("Could not identify your Discord account.");
+
+  if (interaction.type === APPLICATION_COMMAND) {
+    if (interaction.data?.name === "link") {
+      return handleLinkCommand(discordUserId, option(interaction, "code"));
+    }
+    if (interaction.data?.name === "reply") {
+      return handleReplyCommand(discordUserId, option(interaction, "text"));
+    }
+    return reply("Unknown command.");
+  }
+
+  if (interaction.type === MESSAGE_COMPONENT) {
+    return handleOptionButton(discordUserId, interaction);
+  }
+
+  return reply("Unsupported interaction.");
+}
+
+async function findUser(discordUserId: string) {
+  const [user] = await db
+    .select()
+    .from(users)
+    .where(eq(users.discordUserId, discordUserId));
+  return user;
+}
+
+async function handleLinkCommand(
+  discordUserId: string,
+  code: string
+): Promise<InteractionResponse> {
+  const [user] = await db
+    .select()
+    .from(users)
+    .where(
+      and(
+        eq(users.discordLinkCode, code.toUpperCase()),
+        gt(users.discordLinkCodeExpiresAt!, new Date())
+      )
+    );
+
+  if (!user) {
+    return reply(
+      "Invalid or expired link code. Run `agentduty connect discord` to generate a new one."
+    );
+  }
+
+  const dmChannelId = await openDiscordDM(discordUserId);
+  await db
+    .update(users)
+    .set({
+      discordUserId,
+      discordDmChannelId: dmChannelId,
+      discordLinkCode: null,
+      discordLinkCodeExpiresAt: null,
+      updatedAt: new Date(),
+    })
+    .where(eq(users.id, user.id));
+
+  return reply(
+    `Linked! Your Discord account is now connected to ${user.email}. You'll receive notifications by DM.`
+  );
+}
+
+async function handleReplyCommand(
+  discordUserId: string,
+  text: string
+): Promise<InteractionResponse> {
+  const user = await findUser(discordUserId);
+  if (!user) {
+    return reply(
+      "I don't recognize your Discord account. Run `agentduty connect discord` and use `/link` with the code."
+    );
+  }
+
+  const result = await parseInboundMessage(text, user.id);
+  switch (result.type) {
+    case "shortCode":
+    case "freeform":
+      await recordResponse(result.notification, user.id, "discord", result.text);
+      return reply("Response recorded.");
+    case "optionSelect":
+      await recordResponse(
+        result.notification,
+        user.id,
+        "discord",
+        undefined,
+        result.selectedOption
+      );
+      return reply(`Selected: ${result.selectedOption}`);
+    case "invalidOption":
+      return reply("Invalid option number.");
+    case "notFound":
+      return reply(`No active notification found with code ${result.shortCode}.`);
+    case "noActive":
+      return reply("No active notification to respond to.");
+  }
+}
+
+async function handleOptionButton(
+  discordUserId: string,
+  interaction: DiscordInteraction
+): Promise<InteractionResponse> {
+  // custom_id: respond:{notificationId}:{index}
+  const match = interaction.data?.custom_id?.match(/^respond:([^:]+):(\d+)$/);
+  if (!match) return reply("Unknown button.");
+
+  const user = await findUser(discordUserId);
+  if (!user) return reply("I don't recognize your Discord account.");
+
+  const [notification] = await db
+    .select()
+    .from(notifications)
+    .where(
+      and(eq(notifications.id, match[1]), eq(notifications.userId, user.id))
+    );
+  if (!notification) return reply("Notification not found.");
+
+  const selected = notification.options?.[parseInt(match[2], 10)];
+  if (!selected) return reply("Invalid option.");
+
+  await recordResponse(
+    notification,
+    user.id,
+    "discord",
+    undefined,
+    selected,
+    interaction.message?.id
+  );
+
+  return {
+    type: UPDATE_MESSAGE,
+    data: {
+      content: `${interaction.message?.content ?? ""}\n\n✅ Selected: **${selected}**`,
+      components: [],
+    },
+  };
+}
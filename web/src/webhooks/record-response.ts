@@ -2,11 +2,12 @@ import { db } from "@/db";
 import { notifications, responses } from "@/db/schema";
 import { eq } from "drizzle-orm";
 import { inngest } from "@/inngest/client";
+import { emitWebhookEvent, responsePayload } from "@/channels/events";
 
 export async function recordResponse(
   notification: typeof notifications.$inferSelect,
   responderId: string,
-  channel: "slack" | "sms",
+  channel: "slack" | "sms" | "discord" | "teams",
   text?: string,
   selectedOption?: string,
   externalId?: string
@@ -32,4 +33,13 @@ export async function recordResponse(
       data: { notificationId: notification.id },
     })
     .catch(() => {});
+
+  emitWebhookEvent(
+    notification.userId,
+    "response.created",
+    responsePayload(
+      { ...notification, status: "responded" },
+      { channel, text: text ?? null, selectedOption: selectedOption ?? null }
+    )
+  );
 }
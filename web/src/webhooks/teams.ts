@@ -0,0 +1,147 @@
+import { db } from "@/db";
+import { users, type TeamsConversation } from "@/db/schema";
+import { eq, and, gt } from "drizzle-orm";
+import { parseInboundMessage } from "./parse-inbound";
+import { recordResponse } from "./record-response";
+import { postTeamsMessage } from "@/channels/teams";
+
+export interface TeamsActivity {
+  type: string;
+  id: string;
+  text?: string;
+  serviceUrl: string;
+  from: { id: string; aadObjectId?: string };
+  conversation: { id: string; tenantId?: string };
+  channelData?: { tenant?: { id: string } };
+}
+
+/**
+ * Whether two Bot Framework service URLs are the same endpoint. Teams is not
+ * consistent about the trailing slash or the host's case.
+ */
+export function sameServiceUrl(a: string, b: string | undefined): boolean {
+  if (!b) return false;
+  const norm = (u: string) => {
+    try {
+      const url = new URL(u);
+      return url.origin + url.pathname.replace(/\/+$/, "");
+    } catch {
+      return null;
+    }
+  };
+  const na = norm(a);
+  return na !== null && na === norm(b);
+}
+
+/** Drop the bot's @mention and any markup Teams wraps around the text. */
+function plainText(text: string): string {
+  return text
+    .replace(/<at>.*?<\/at>/g, "")
+    .replace(/<[^>]+>/g, "")
+    .replace(/&nbsp;/g, " ")
+    .trim();
+}
+
+/**
+ * Handle a message sent to the Teams bot: a link code from
+ * `agentduty connect teams`, or a reply to a notification.
+ */
+export async function handleTeamsActivity(activity: TeamsActivity) {
+  if (activity.type !== "message" || !activity.text) return;
+
+  const conversation: TeamsConversation = {
+    serviceUrl: activity.serviceUrl,
+    conversationId: activity.conversation.id,
+    tenantId: activity.conversation.tenantId ?? activity.channelData?.tenant?.id,
+  };
+  const text = plainText(activity.text);
+
+  const linkMatch = text.match(/^LINK-[A-Z0-9]{6}$/i);
+  if (linkMatch) {
+    await handleTeamsLinkCode(activity, conversation, linkMatch[0].toUpperCase());
+    return;
+  }
+
+  const [user] = await db
+    .select()
+    .from(users)
+    .where(eq(users.teamsUserId, activity.from.id));
+
+  if (!user) {
+    await postTeamsMessage(
+      conversation,
+      "I don't recognize your Teams account. To link it, run `agentduty connect teams` in your terminal and send me the code."
+    );
+    return;
+  }
+
+  const result = await parseInboundMessage(text, user.id);
+  switch (result.type) {
+    case "shortCode":
+    case "freeform":
+      await recordResponse(result.notification, user.id, "teams", result.text, undefined, activity.id);
+      break;
+    case "optionSelect":
+      await recordResponse(
+        result.notification,
+        user.id,
+        "teams",
+        undefined,
+        result.selectedOption,
+        activity.id
+      );
+      break;
+    case "invalidOption":
+      await postTeamsMessage(conversation, "Invalid option number. Please try again.");
+      break;
+    case "notFound":
+      await postTeamsMessage(
+        conversation,
+        `No active notification found with code ${result.shortCode}.`
+      );
+      break;
+    case "noActive":
+      // Silently ignore — the user might just be chatting
+      break;
+  }
+}
+
+async function handleTeamsLinkCode(
+  activity: TeamsActivity,
+  conversation: TeamsConversation,
+  code: string
+) {
+  const [user] = await db
+    .select()
+    .from(users)
+    .where(
+      and(
+        eq(users.teamsLinkCode, code),
+        gt(users.teamsLinkCodeExpiresAt!, new Date())
+      )
+    );
+
+  if (!user) {
+    await postTeamsMessage(
+      conversation,
+      "Invalid or expired link code. Run `agentduty connect teams` to generate a new one."
+    );
+    return;
+  }
+
+  await db
+    .update(users)
+    .set({
+      teamsUserId: activity.from.id,
+      teamsConversation: conversation,
+      teamsLinkCode: null,
+      teamsLinkCodeExpiresAt: null,
+      updatedAt: new Date(),
+    })
+    .where(eq(users.id, user.id));
+
+  await postTeamsMessage(
+    conversation,
+    `Linked! Your Teams account is now connected to ${user.email}. You'll receive notifications here.`
+  );
+}